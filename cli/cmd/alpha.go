@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newCmdAlpha() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alpha",
+		Short: "Experimental commands",
+		Long: `Experimental commands.
+
+These commands are under development. Their flags and output may change in
+future releases.`,
+	}
+
+	cmd.AddCommand(newCmdAlphaConformance())
+	cmd.AddCommand(newCmdAlphaLoad())
+
+	return cmd
+}
@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
 	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
@@ -13,6 +17,7 @@ import (
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/pkg/version"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
 )
 
 const (
@@ -21,18 +26,42 @@ const (
 	failStatus      = "[FAIL]"
 	errorStatus     = "[ERROR]"
 	versionCheckURL = "https://versioncheck.linkerd.io/version.json"
+
+	checkHintBaseURL = "https://linkerd.io/checks/"
+
+	basicOutput = "basic"
+	shortOutput = "short"
+)
+
+var (
+	nonAnchorChars = regexp.MustCompile("[^a-z0-9]+")
+	spinnerFrames  = []rune(`|/-\`)
 )
 
 type checkOptions struct {
 	versionOverride string
+	output          string
 }
 
 func newCheckOptions() *checkOptions {
 	return &checkOptions{
 		versionOverride: "",
+		output:          basicOutput,
 	}
 }
 
+func (o *checkOptions) validate() error {
+	if o.output != basicOutput && o.output != shortOutput && !isJSONOutput(o.output) {
+		return fmt.Errorf("--output must be one of: %s, %s, %s, %sTEMPLATE", basicOutput, shortOutput, jsonOutput, jsonpathOutputPrefix)
+	}
+	if strings.HasPrefix(o.output, jsonpathOutputPrefix) {
+		if _, err := parseJSONPath(o.output); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func newCmdCheck() *cobra.Command {
 	options := newCheckOptions()
 
@@ -41,9 +70,19 @@ func newCmdCheck() *cobra.Command {
 		Short: "Check your Linkerd installation for potential problems.",
 		Long: `Check your Linkerd installation for potential problems. The check command will perform various checks of your
 local system, the Linkerd control plane, and connectivity between those. The process will exit with non-zero check if
-problems were found.`,
+problems were found.
+
+Checks are grouped by subsystem, and each failure links to troubleshooting
+hints. Use "-o short" to only print the checks that didn't pass, or "-o json"
+to print every check as JSON. As with kubectl, "-o jsonpath=TEMPLATE" prints
+only the fields of that JSON the template selects, e.g.
+-o jsonpath='{.checks[?(@.status!="OK")].description}'.`,
 		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
+			if err := options.validate(); err != nil {
+				fmt.Fprintln(os.Stderr, err.Error())
+				os.Exit(1)
+			}
 
 			kubeApi, err := k8s.NewAPI(kubeconfigPath)
 			if err != nil {
@@ -64,10 +103,18 @@ problems were found.`,
 				os.Exit(2)
 			}
 
+			podSecurityStatusChecker := k8s.NewPodSecurityStatusChecker(kubeApi, controlPlaneNamespace)
 			grpcStatusChecker := healthcheck.NewGrpcStatusChecker(apiClient)
 			versionStatusChecker := version.NewVersionStatusChecker(versionCheckURL, options.versionOverride, apiClient)
 
-			err = checkStatus(os.Stdout, kubeApi, grpcStatusChecker, versionStatusChecker)
+			checkers := []healthcheck.StatusChecker{kubeApi, podSecurityStatusChecker, grpcStatusChecker, versionStatusChecker}
+			if terminal.IsTerminal(int(os.Stdout.Fd())) && !isJSONOutput(options.output) {
+				for i, c := range checkers {
+					checkers[i] = &checkProgress{StatusChecker: c, w: os.Stdout}
+				}
+			}
+
+			err = checkStatus(os.Stdout, options.output, checkers...)
 			if err != nil {
 				os.Exit(2)
 			}
@@ -76,27 +123,54 @@ problems were found.`,
 
 	cmd.Args = cobra.NoArgs
 	cmd.PersistentFlags().StringVar(&options.versionOverride, "expected-version", options.versionOverride, "Overrides the version used when checking if Linkerd is running the latest version (mostly for testing)")
+	cmd.PersistentFlags().StringVarP(&options.output, "output", "o", options.output, fmt.Sprintf("Output format; one of: \"%s\", \"%s\" (only failed checks), \"%s\" or \"%sTEMPLATE\"", basicOutput, shortOutput, jsonOutput, jsonpathOutputPrefix))
 
 	return cmd
 }
 
-func checkStatus(w io.Writer, checkers ...healthcheck.StatusChecker) error {
+func checkStatus(w io.Writer, output string, checkers ...healthcheck.StatusChecker) error {
+	if isJSONOutput(output) {
+		return checkStatusJSON(w, output, checkers...)
+	}
+
+	var (
+		subsystem string
+		summary   []*checkSummary
+	)
 	prettyPrintResults := func(result *healthcheckPb.CheckResult) {
-		checkLabel := fmt.Sprintf("%s: %s", result.SubsystemName, result.CheckDescription)
+		if len(summary) == 0 || summary[len(summary)-1].subsystem != result.SubsystemName {
+			summary = append(summary, &checkSummary{subsystem: result.SubsystemName})
+		}
+		summary[len(summary)-1].add(result.Status)
+
+		if output == shortOutput && result.Status == healthcheckPb.CheckStatus_OK {
+			return
+		}
+
+		if result.SubsystemName != subsystem {
+			if subsystem != "" {
+				fmt.Fprintln(w, "")
+			}
+			subsystem = result.SubsystemName
+			fmt.Fprintln(w, subsystem)
+			fmt.Fprintln(w, strings.Repeat("-", len(subsystem)))
+		}
 
 		filler := ""
 		lineBreak := "\n"
-		for i := 0; i < lineWidth-len(checkLabel)-len(okStatus)-len(lineBreak); i++ {
+		for i := 0; i < lineWidth-len(result.CheckDescription)-len(okStatus)-len(lineBreak); i++ {
 			filler = filler + "."
 		}
 
 		switch result.Status {
 		case healthcheckPb.CheckStatus_OK:
-			fmt.Fprintf(w, "%s%s%s%s", checkLabel, filler, okStatus, lineBreak)
+			fmt.Fprintf(w, "%s%s%s%s", result.CheckDescription, filler, okStatus, lineBreak)
 		case healthcheckPb.CheckStatus_FAIL:
-			fmt.Fprintf(w, "%s%s%s  -- %s%s", checkLabel, filler, failStatus, result.FriendlyMessageToUser, lineBreak)
+			fmt.Fprintf(w, "%s%s%s%s", result.CheckDescription, filler, failStatus, lineBreak)
+			printCheckHint(w, result)
 		case healthcheckPb.CheckStatus_ERROR:
-			fmt.Fprintf(w, "%s%s%s -- %s%s", checkLabel, filler, errorStatus, result.FriendlyMessageToUser, lineBreak)
+			fmt.Fprintf(w, "%s%s%s%s", result.CheckDescription, filler, errorStatus, lineBreak)
+			printCheckHint(w, result)
 		}
 	}
 
@@ -107,6 +181,10 @@ func checkStatus(w io.Writer, checkers ...healthcheck.StatusChecker) error {
 
 	checkStatus := checker.PerformCheck(prettyPrintResults)
 
+	if subsystem != "" {
+		fmt.Fprintln(w, "")
+	}
+	printCheckSummary(w, summary)
 	fmt.Fprintln(w, "")
 
 	var err error
@@ -122,6 +200,135 @@ func checkStatus(w io.Writer, checkers ...healthcheck.StatusChecker) error {
 	return err
 }
 
+// checkJSON is the JSON output of check.
+type checkJSON struct {
+	Status string             `json:"status"`
+	Checks []*checkJSONResult `json:"checks"`
+}
+
+type checkJSONResult struct {
+	Subsystem   string `json:"subsystem"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	Message     string `json:"message,omitempty"`
+	Hint        string `json:"hint,omitempty"`
+}
+
+// checkStatusJSON runs the checks like checkStatus, but prints every result
+// as JSON once all the checks have run.
+func checkStatusJSON(w io.Writer, output string, checkers ...healthcheck.StatusChecker) error {
+	out := &checkJSON{Checks: []*checkJSONResult{}}
+	collectResults := func(result *healthcheckPb.CheckResult) {
+		jsonResult := &checkJSONResult{
+			Subsystem:   result.SubsystemName,
+			Description: result.CheckDescription,
+			Status:      result.Status.String(),
+		}
+		if result.Status != healthcheckPb.CheckStatus_OK {
+			jsonResult.Message = result.FriendlyMessageToUser
+			jsonResult.Hint = checkHintURL(result)
+		}
+		out.Checks = append(out.Checks, jsonResult)
+	}
+
+	checker := healthcheck.MakeHealthChecker()
+	for _, c := range checkers {
+		checker.Add(c)
+	}
+	checkStatus := checker.PerformCheck(collectResults)
+	out.Status = checkStatus.String()
+
+	if err := renderJSON(w, output, out); err != nil {
+		return err
+	}
+
+	switch checkStatus {
+	case healthcheckPb.CheckStatus_FAIL:
+		return errors.New("failed status check")
+	case healthcheckPb.CheckStatus_ERROR:
+		return errors.New("error during status check")
+	default:
+		return nil
+	}
+}
+
+type checkSummary struct {
+	subsystem string
+	ok        int
+	fail      int
+	err       int
+}
+
+func (s *checkSummary) add(status healthcheckPb.CheckStatus) {
+	switch status {
+	case healthcheckPb.CheckStatus_OK:
+		s.ok++
+	case healthcheckPb.CheckStatus_FAIL:
+		s.fail++
+	case healthcheckPb.CheckStatus_ERROR:
+		s.err++
+	}
+}
+
+func printCheckSummary(w io.Writer, summary []*checkSummary) {
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(tw, "SUBSYSTEM\tOK\tFAIL\tERROR")
+	for _, s := range summary {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", s.subsystem, s.ok, s.fail, s.err)
+	}
+	tw.Flush()
+}
+
+func printCheckHint(w io.Writer, result *healthcheckPb.CheckResult) {
+	if result.FriendlyMessageToUser != "" {
+		fmt.Fprintf(w, "    %s\n", result.FriendlyMessageToUser)
+	}
+	fmt.Fprintf(w, "    see %s for hints\n", checkHintURL(result))
+}
+
+// checkHintURL returns the location of the troubleshooting docs for a check,
+// anchored on its subsystem and description, e.g.
+// "kubernetes-api: can query the Kubernetes API" links to
+// "#kubernetes-api-can-query-the-kubernetes-api".
+func checkHintURL(result *healthcheckPb.CheckResult) string {
+	label := strings.ToLower(result.SubsystemName + " " + result.CheckDescription)
+	anchor := strings.Trim(nonAnchorChars.ReplaceAllString(label, "-"), "-")
+	return checkHintBaseURL + "#" + anchor
+}
+
+// checkProgress wraps a StatusChecker, showing a spinner while its checks run
+// so that slow checks against a remote cluster don't look like a hang. It
+// should only be used when w is a terminal.
+type checkProgress struct {
+	healthcheck.StatusChecker
+	w io.Writer
+}
+
+func (p *checkProgress) SelfCheck() []*healthcheckPb.CheckResult {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			fmt.Fprintf(p.w, "\r%c running checks...", spinnerFrames[i%len(spinnerFrames)])
+			select {
+			case <-ticker.C:
+			case <-done:
+				// Erase the spinner line before results are printed.
+				fmt.Fprint(p.w, "\r\033[K")
+				return
+			}
+		}
+	}()
+
+	results := p.StatusChecker.SelfCheck()
+	close(done)
+	<-stopped
+	return results
+}
+
 func statusCheckResultWasOk(w io.Writer) error {
 	fmt.Fprintln(w, "Status check results are [ok]")
 	return nil
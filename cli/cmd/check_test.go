@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"fmt"
 	"io/ioutil"
 	"testing"
 
@@ -10,41 +11,81 @@ import (
 )
 
 func TestCheckStatus(t *testing.T) {
-	t.Run("Prints expected output", func(t *testing.T) {
-		kubeApi := &k8s.MockKubeApi{}
-		kubeApi.SelfCheckResultsToReturn = []*healthcheckPb.CheckResult{
-			{
-				SubsystemName:         k8s.KubeapiSubsystemName,
-				CheckDescription:      k8s.KubeapiClientCheckDescription,
-				Status:                healthcheckPb.CheckStatus_FAIL,
-				FriendlyMessageToUser: "This should contain instructions for fail",
-			},
-			{
-				SubsystemName:         k8s.KubeapiSubsystemName,
-				CheckDescription:      k8s.KubeapiAccessCheckDescription,
-				Status:                healthcheckPb.CheckStatus_OK,
-				FriendlyMessageToUser: "This shouldn't be printed",
-			},
-			{
-				SubsystemName:         k8s.KubeapiSubsystemName,
-				CheckDescription:      k8s.KubeapiVersionCheckDescription,
-				Status:                healthcheckPb.CheckStatus_ERROR,
-				FriendlyMessageToUser: "This should contain instructions for err",
-			},
-		}
+	kubeApi := &k8s.MockKubeApi{}
+	kubeApi.SelfCheckResultsToReturn = []*healthcheckPb.CheckResult{
+		{
+			SubsystemName:         k8s.KubeapiSubsystemName,
+			CheckDescription:      k8s.KubeapiClientCheckDescription,
+			Status:                healthcheckPb.CheckStatus_FAIL,
+			FriendlyMessageToUser: "This should contain instructions for fail",
+		},
+		{
+			SubsystemName:         k8s.KubeapiSubsystemName,
+			CheckDescription:      k8s.KubeapiAccessCheckDescription,
+			Status:                healthcheckPb.CheckStatus_OK,
+			FriendlyMessageToUser: "This shouldn't be printed",
+		},
+		{
+			SubsystemName:         k8s.KubeapiSubsystemName,
+			CheckDescription:      k8s.KubeapiVersionCheckDescription,
+			Status:                healthcheckPb.CheckStatus_ERROR,
+			FriendlyMessageToUser: "This should contain instructions for err",
+		},
+		{
+			SubsystemName:    k8s.PodSecuritySubsystemName,
+			CheckDescription: k8s.PodSecurityEnforceCheckDescription,
+			Status:           healthcheckPb.CheckStatus_OK,
+		},
+	}
 
-		output := bytes.NewBufferString("")
-		checkStatus(output, kubeApi)
+	testCases := []struct {
+		output         string
+		goldenFileName string
+	}{
+		{basicOutput, "testdata/status_busy_output.golden"},
+		{shortOutput, "testdata/status_busy_output_short.golden"},
+	}
 
-		goldenFileBytes, err := ioutil.ReadFile("testdata/status_busy_output.golden")
-		if err != nil {
-			t.Fatalf("Unexpected error: %v", err)
-		}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Prints expected %s output", tc.output), func(t *testing.T) {
+			output := bytes.NewBufferString("")
+			checkStatus(output, tc.output, kubeApi)
+
+			goldenFileBytes, err := ioutil.ReadFile(tc.goldenFileName)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			expectedContent := string(goldenFileBytes)
 
-		expectedContent := string(goldenFileBytes)
+			if expectedContent != output.String() {
+				t.Fatalf("Expected function to render:\n%s\bbut got:\n%s", expectedContent, output)
+			}
+		})
+	}
 
-		if expectedContent != output.String() {
-			t.Fatalf("Expected function to render:\n%s\bbut got:\n%s", expectedContent, output)
+	t.Run("Prints the failed checks selected by a jsonpath template", func(t *testing.T) {
+		output := bytes.NewBufferString("")
+		err := checkStatus(output, jsonpathOutputPrefix+`{range .checks[?(@.status!="OK")]}{.status} {.description}: {.message}{"\n"}{end}`, kubeApi)
+		if err == nil {
+			t.Fatalf("Expected an error for failed checks")
+		}
+
+		expectedOutput := fmt.Sprintf("FAIL %s: This should contain instructions for fail\nERROR %s: This should contain instructions for err\n",
+			k8s.KubeapiClientCheckDescription, k8s.KubeapiVersionCheckDescription)
+		if output.String() != expectedOutput {
+			t.Fatalf("Expected function to render:\n%s\nbut got:\n%s", expectedOutput, output)
 		}
 	})
 }
+
+func TestCheckHintURL(t *testing.T) {
+	result := &healthcheckPb.CheckResult{
+		SubsystemName:    "linkerd-api[namespace]",
+		CheckDescription: "control plane namespace exists",
+	}
+	expected := "https://linkerd.io/checks/#linkerd-api-namespace-control-plane-namespace-exists"
+	if url := checkHintURL(result); url != expected {
+		t.Fatalf("Expected [%s], got [%s]", expected, url)
+	}
+}
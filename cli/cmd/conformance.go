@@ -0,0 +1,448 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/linkerd/linkerd2/controller/api/public"
+	"github.com/linkerd/linkerd2/controller/api/util"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/healthcheck"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+	appsV1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	conformanceSubsystemName   = "linkerd-conformance"
+	conformanceNamespacePrefix = "linkerd-conformance-"
+	conformanceServerName      = "conformance-server"
+	conformanceServerImage     = "buoyantio/bb:v0.0.1"
+	conformanceServerPort      = 8080
+
+	// conformanceMetricsDelay is how long to wait once the load job is done
+	// for Prometheus to scrape the proxies' latest metrics.
+	conformanceMetricsDelay = 10 * time.Second
+
+	// conformanceTapGracePeriod is how long to keep tapping once the load job
+	// is done, for events of its last requests to arrive.
+	conformanceTapGracePeriod = 5 * time.Second
+)
+
+type conformanceOptions struct {
+	rps           uint
+	duration      time.Duration
+	keepNamespace bool
+	output        string
+	*injectOptions
+}
+
+func newConformanceOptions() *conformanceOptions {
+	return &conformanceOptions{
+		rps:           10,
+		duration:      30 * time.Second,
+		keepNamespace: false,
+		output:        basicOutput,
+		injectOptions: newInjectOptions(),
+	}
+}
+
+func newCmdAlphaConformance() *cobra.Command {
+	options := newConformanceOptions()
+
+	cmd := &cobra.Command{
+		Use:   "conformance [flags]",
+		Short: "Run functional tests of the mesh in a temporary namespace",
+		Long: `Run functional tests of the mesh in a temporary namespace.
+
+  A meshed server and a meshed load job that sends it requests are deployed to
+  a new namespace, which is deleted once the tests are done. The tests check
+  that:
+
+  * meshed pods start and serve requests
+  * tap reports the requests to the server while they're sent
+  * the requests are reported in the server's metrics, and succeed
+  * the requests are sent over mTLS, if "--tls=optional" is set
+
+  Like "linkerd check", the process exits with a non-zero status if a test
+  didn't pass. Use "-o short" to only print the tests that didn't pass, or
+  "-o json" to print every test as JSON.`,
+		Example: `  # Test the mesh, including mTLS between meshed pods.
+  linkerd alpha conformance --tls=optional
+
+  # Send more requests for longer, and keep the namespace to investigate failures.
+  linkerd alpha conformance --rps 50 --duration 2m --keep-namespace`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := options.validate(); err != nil {
+				fmt.Fprintln(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+
+			clientset, err := k8s.NewClientSet(kubeconfigPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error with Kubernetes API: %s\n", err.Error())
+				statusCheckResultWasError(os.Stdout)
+				os.Exit(2)
+			}
+
+			checker := &conformanceChecker{
+				clientset: clientset,
+				apiClient: validatedPublicAPIClient(),
+				tapClient: validatedTapAPIClient(),
+				options:   options,
+			}
+
+			err = runInterruptible(func(ctx context.Context) error {
+				checker.ctx = ctx
+				var c healthcheck.StatusChecker = checker
+				if terminal.IsTerminal(int(os.Stdout.Fd())) && !isJSONOutput(options.output) {
+					c = &checkProgress{StatusChecker: c, w: os.Stdout}
+				}
+				return checkStatus(os.Stdout, options.output, c)
+			})
+			if err != nil {
+				os.Exit(2)
+			}
+		},
+	}
+
+	addProxyConfigFlags(cmd, options.proxyConfigOptions)
+	cmd.PersistentFlags().UintVar(&options.rps, "rps", options.rps, "Requests per second that the load job sends")
+	cmd.PersistentFlags().DurationVar(&options.duration, "duration", options.duration, "How long the load job sends requests for")
+	cmd.PersistentFlags().BoolVar(&options.keepNamespace, "keep-namespace", options.keepNamespace, "If present, the test namespace isn't deleted once the tests are done")
+	cmd.PersistentFlags().StringVarP(&options.output, "output", "o", options.output, fmt.Sprintf("Output format; one of: \"%s\", \"%s\" (only failed tests), \"%s\" or \"%sTEMPLATE\"", basicOutput, shortOutput, jsonOutput, jsonpathOutputPrefix))
+
+	return cmd
+}
+
+func (o *conformanceOptions) validate() error {
+	if o.output != basicOutput && o.output != shortOutput && !isJSONOutput(o.output) {
+		return fmt.Errorf("--output must be one of: %s, %s, %s, %sTEMPLATE", basicOutput, shortOutput, jsonOutput, jsonpathOutputPrefix)
+	}
+	if strings.HasPrefix(o.output, jsonpathOutputPrefix) {
+		if _, err := parseJSONPath(o.output); err != nil {
+			return err
+		}
+	}
+	if o.rps == 0 {
+		return fmt.Errorf("--rps must be greater than 0")
+	}
+	if o.duration < time.Second {
+		return fmt.Errorf("--duration must be at least 1s")
+	}
+	return o.proxyConfigOptions.validate()
+}
+
+// conformanceChecker runs the conformance tests as health checks, so that
+// their results are reported like those of "linkerd check".
+type conformanceChecker struct {
+	ctx       context.Context
+	clientset kubernetes.Interface
+	apiClient pb.ApiClient
+	tapClient public.TapAPIClient
+	options   *conformanceOptions
+}
+
+func (c *conformanceChecker) SelfCheck() []*healthcheckPb.CheckResult {
+	results := make([]*healthcheckPb.CheckResult, 0)
+
+	namespace := conformanceNamespacePrefix + strconv.FormatInt(time.Now().Unix(), 36)
+	_, err := c.clientset.CoreV1().Namespaces().Create(&v1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: namespace}})
+	if err != nil {
+		return append(results, conformanceErrorResult("can create a test namespace", err))
+	}
+	results = append(results, conformanceResult("can create a test namespace", nil))
+	if c.options.keepNamespace {
+		fmt.Fprintf(os.Stderr, "Keeping test namespace [%s]\n", namespace)
+	} else {
+		defer c.deleteNamespace(namespace)
+	}
+
+	err = c.deployServer(namespace)
+	results = append(results, conformanceResult("meshed server is ready", err))
+	if err != nil {
+		return results
+	}
+
+	load := &loadOptions{
+		namespace:     namespace,
+		rps:           c.options.rps,
+		duration:      c.options.duration,
+		path:          "/",
+		injectOptions: c.options.injectOptions,
+	}
+	url := fmt.Sprintf("http://%s.%s.svc.%s:%d/", conformanceServerName, namespace, c.options.clusterDomain, conformanceServerPort)
+	job, err := newLoadJob(namespace, url, load)
+	if err == nil {
+		job, err = c.clientset.BatchV1().Jobs(namespace).Create(job)
+	}
+	if err != nil {
+		return append(results, conformanceErrorResult("meshed client can send requests", err))
+	}
+
+	// tap while the load job sends its requests
+	tapCtx, cancelTap := context.WithTimeout(c.ctx, c.options.duration+loadStartTimeout)
+	defer cancelTap()
+	tapped := make(chan error, 1)
+	go func() {
+		tapped <- tapConformanceServer(tapCtx, c.tapClient, namespace)
+	}()
+
+	pod, err := waitForLoadJob(c.ctx, c.clientset, job, c.options.duration+loadStartTimeout)
+	if err != nil {
+		cancelTap()
+	} else {
+		// no more requests are sent once the load job is done
+		timer := time.AfterFunc(conformanceTapGracePeriod, cancelTap)
+		defer timer.Stop()
+	}
+	results = append(results, conformanceResult("meshed client can send requests", err))
+	results = append(results, conformanceResult("tap reports live requests", <-tapped))
+	if err != nil {
+		return results
+	}
+
+	select {
+	case <-time.After(conformanceMetricsDelay):
+	case <-c.ctx.Done():
+		return results
+	}
+
+	req, err := util.BuildStatSummaryRequest(util.StatSummaryRequestParams{
+		TimeWindow:    loadTimeWindow(c.options.duration + conformanceMetricsDelay),
+		Namespace:     namespace,
+		ResourceType:  k8s.Deployment,
+		ResourceName:  conformanceServerName,
+		FromNamespace: namespace,
+		FromType:      k8s.Pod,
+		FromName:      pod,
+	})
+	if err == nil {
+		var resp *pb.StatSummaryResponse
+		resp, err = requestStatSummary(c.apiClient, req)
+		if err == nil {
+			return append(results, conformanceStatResults(resp, c.options.enableTLS())...)
+		}
+	}
+	return append(results, conformanceErrorResult("metrics are reported for meshed requests", err))
+}
+
+// deployServer creates the meshed server and its service in namespace, and
+// waits for the server to be ready.
+func (c *conformanceChecker) deployServer(namespace string) error {
+	deploy, svc, err := newConformanceServer(namespace, c.options.injectOptions)
+	if err != nil {
+		return err
+	}
+	if _, err := c.clientset.AppsV1().Deployments(namespace).Create(deploy); err != nil {
+		return fmt.Errorf("error creating deployment [%s/%s]: %v", namespace, deploy.Name, err)
+	}
+	if _, err := c.clientset.CoreV1().Services(namespace).Create(svc); err != nil {
+		return fmt.Errorf("error creating service [%s/%s]: %v", namespace, svc.Name, err)
+	}
+	return waitForDeployment(c.ctx, c.clientset, deploy, loadStartTimeout)
+}
+
+func (c *conformanceChecker) deleteNamespace(namespace string) {
+	propagation := metaV1.DeletePropagationBackground
+	err := c.clientset.CoreV1().Namespaces().Delete(namespace, &metaV1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error deleting test namespace [%s]: %v\n", namespace, err)
+	}
+}
+
+// newConformanceServer returns a deployment of an HTTP server, with the
+// Linkerd proxy injected, and the service that selects it.
+func newConformanceServer(namespace string, options *injectOptions) (*appsV1.Deployment, *v1.Service, error) {
+	replicas := int32(1)
+	labels := map[string]string{"app": conformanceServerName}
+
+	deploy := &appsV1.Deployment{
+		TypeMeta: metaV1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      conformanceServerName,
+			Namespace: namespace,
+		},
+		Spec: appsV1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metaV1.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metaV1.ObjectMeta{Labels: labels},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Name:  conformanceServerName,
+							Image: conformanceServerImage,
+							Args: []string{
+								"terminus",
+								"--h1-server-port", strconv.Itoa(conformanceServerPort),
+								"--response-text", "conformance",
+							},
+							Ports: []v1.ContainerPort{{ContainerPort: conformanceServerPort}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := yaml.Marshal(deploy)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err = injectResource(b, os.Stderr, options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error injecting conformance server: %v", err)
+	}
+	var injected appsV1.Deployment
+	if err := yaml.Unmarshal(b, &injected); err != nil {
+		return nil, nil, err
+	}
+
+	svc := &v1.Service{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      conformanceServerName,
+			Namespace: namespace,
+		},
+		Spec: v1.ServiceSpec{
+			Selector: labels,
+			Ports: []v1.ServicePort{
+				{
+					Name:       "http",
+					Port:       conformanceServerPort,
+					TargetPort: intstr.FromInt(conformanceServerPort),
+				},
+			},
+		},
+	}
+
+	return &injected, svc, nil
+}
+
+// waitForDeployment waits for every replica of deploy to be ready.
+func waitForDeployment(ctx context.Context, clientset kubernetes.Interface, deploy *appsV1.Deployment, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(loadPollInterval)
+	defer ticker.Stop()
+
+	for {
+		current, err := clientset.AppsV1().Deployments(deploy.Namespace).Get(deploy.Name, metaV1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("error getting deployment [%s/%s]: %v", deploy.Namespace, deploy.Name, err)
+		}
+		if current.Spec.Replicas != nil && current.Status.ReadyReplicas >= *current.Spec.Replicas {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("deployment [%s/%s] was not ready within %s", deploy.Namespace, deploy.Name, timeout)
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+// tapConformanceServer waits for tap to report a request to the conformance
+// server in namespace.
+func tapConformanceServer(ctx context.Context, client public.TapAPIClient, namespace string) error {
+	req, err := util.BuildTapByResourceRequest(util.TapRequestParams{
+		Resource:  k8s.Deployment + "/" + conformanceServerName,
+		Namespace: namespace,
+		MaxRps:    1.0,
+	})
+	if err != nil {
+		return err
+	}
+
+	stream, err := client.TapByResource(ctx, req)
+	if err != nil {
+		return err
+	}
+	if _, err := stream.Recv(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("no requests were tapped")
+		}
+		return err
+	}
+	return nil
+}
+
+// conformanceStatResults checks the stats of the load job's requests to the
+// conformance server in resp.
+func conformanceStatResults(resp *pb.StatSummaryResponse, tls bool) []*healthcheckPb.CheckResult {
+	var stats *pb.BasicStats
+	for _, table := range resp.GetOk().GetStatTables() {
+		for _, row := range table.GetPodGroup().GetRows() {
+			if row.Stats != nil {
+				stats = row.Stats
+			}
+		}
+	}
+
+	if stats == nil || stats.SuccessCount+stats.FailureCount == 0 {
+		return []*healthcheckPb.CheckResult{
+			conformanceResult("metrics are reported for meshed requests", fmt.Errorf("no requests from the load job to the server were reported")),
+		}
+	}
+	total := stats.SuccessCount + stats.FailureCount
+
+	results := []*healthcheckPb.CheckResult{conformanceResult("metrics are reported for meshed requests", nil)}
+
+	var err error
+	if stats.FailureCount > 0 {
+		err = fmt.Errorf("%d of %d requests failed", stats.FailureCount, total)
+	}
+	results = append(results, conformanceResult("meshed requests succeed", err))
+
+	if tls {
+		err = nil
+		if stats.TlsRequestCount < total {
+			err = fmt.Errorf("%d of %d requests were not sent over TLS", total-stats.TlsRequestCount, total)
+		}
+		results = append(results, conformanceResult("meshed requests use mTLS", err))
+	}
+
+	return results
+}
+
+// conformanceResult is a passing result for the test described by
+// description if err is nil, and a failing one otherwise.
+func conformanceResult(description string, err error) *healthcheckPb.CheckResult {
+	result := &healthcheckPb.CheckResult{
+		Status:           healthcheckPb.CheckStatus_OK,
+		SubsystemName:    conformanceSubsystemName,
+		CheckDescription: description,
+	}
+	if err != nil {
+		result.Status = healthcheckPb.CheckStatus_FAIL
+		result.FriendlyMessageToUser = err.Error()
+	}
+	return result
+}
+
+// conformanceErrorResult is the result of a test that couldn't be run.
+func conformanceErrorResult(description string, err error) *healthcheckPb.CheckResult {
+	return &healthcheckPb.CheckResult{
+		Status:                healthcheckPb.CheckStatus_ERROR,
+		SubsystemName:         conformanceSubsystemName,
+		CheckDescription:      description,
+		FriendlyMessageToUser: err.Error(),
+	}
+}
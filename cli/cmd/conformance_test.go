@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	"github.com/linkerd/linkerd2/controller/api/public/publictest"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	appsV1 "k8s.io/api/apps/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConformanceOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		modify        func(*conformanceOptions)
+		expectedError string
+	}{
+		{func(*conformanceOptions) {}, ""},
+		{func(o *conformanceOptions) { o.output = jsonOutput }, ""},
+		{func(o *conformanceOptions) { o.output = "wide" }, "--output must be one of: basic, short, json, jsonpath=TEMPLATE"},
+		{func(o *conformanceOptions) { o.rps = 0 }, "--rps must be greater than 0"},
+		{func(o *conformanceOptions) { o.duration = time.Millisecond }, "--duration must be at least 1s"},
+	}
+
+	for _, tc := range testCases {
+		options := newConformanceOptions()
+		tc.modify(options)
+
+		err := options.validate()
+		if tc.expectedError == "" {
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.expectedError {
+			t.Errorf("Expected error [%s] instead got [%s]", tc.expectedError, err)
+		}
+	}
+}
+
+func TestNewConformanceServer(t *testing.T) {
+	deploy, svc, err := newConformanceServer("linkerd-conformance-abc", newInjectOptions())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if deploy.Namespace != "linkerd-conformance-abc" || svc.Namespace != "linkerd-conformance-abc" {
+		t.Errorf("Expected the server to be in namespace linkerd-conformance-abc, got [%s] and [%s]", deploy.Namespace, svc.Namespace)
+	}
+
+	injected := false
+	for _, c := range deploy.Spec.Template.Spec.Containers {
+		injected = injected || c.Name == "linkerd-proxy"
+	}
+	if !injected {
+		t.Errorf("Expected the server to be injected with the proxy, got containers %v", deploy.Spec.Template.Spec.Containers)
+	}
+
+	if !labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(deploy.Spec.Template.Labels)) {
+		t.Errorf("Expected the service selector %v to select the server's pods, labeled %v", svc.Spec.Selector, deploy.Spec.Template.Labels)
+	}
+	if port := svc.Spec.Ports[0].Port; port != conformanceServerPort {
+		t.Errorf("Expected service port %d, got %d", conformanceServerPort, port)
+	}
+}
+
+func TestWaitForDeployment(t *testing.T) {
+	replicas := int32(1)
+	deploy := func(ready int32) *appsV1.Deployment {
+		return &appsV1.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Name: conformanceServerName, Namespace: "linkerd-conformance-abc"},
+			Spec:       appsV1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsV1.DeploymentStatus{ReadyReplicas: ready},
+		}
+	}
+
+	t.Run("Returns once the replicas are ready", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(deploy(1))
+
+		if err := waitForDeployment(context.Background(), clientset, deploy(1), time.Second); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if the replicas aren't ready in time", func(t *testing.T) {
+		loadPollInterval = time.Millisecond
+		defer func() { loadPollInterval = 2 * time.Second }()
+		clientset := fake.NewSimpleClientset(deploy(0))
+
+		err := waitForDeployment(context.Background(), clientset, deploy(0), 10*time.Millisecond)
+		expectedError := "deployment [linkerd-conformance-abc/conformance-server] was not ready within 10ms"
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+}
+
+func TestTapConformanceServer(t *testing.T) {
+	t.Run("Returns once a request is tapped", func(t *testing.T) {
+		client := publictest.NewMockApiClient()
+		client.SetTapScript(publictest.Event(&pb.TapEvent{}))
+
+		if err := tapConformanceServer(context.Background(), client, "linkerd-conformance-abc"); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		req := client.Requests()[0].(*pb.TapByResourceRequest)
+		if resource := req.GetTarget().GetResource(); resource.Type != k8s.Deployment || resource.Name != conformanceServerName {
+			t.Fatalf("Expected a tap of the conformance server, got %+v", resource)
+		}
+	})
+
+	t.Run("Returns an error if no request is tapped", func(t *testing.T) {
+		client := publictest.NewMockApiClient()
+		client.SetTapScript(publictest.Block())
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := tapConformanceServer(ctx, client, "linkerd-conformance-abc")
+		expectedError := "no requests were tapped"
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+}
+
+func TestConformanceStatResults(t *testing.T) {
+	statuses := func(results []*healthcheckPb.CheckResult) map[string]string {
+		got := make(map[string]string)
+		for _, result := range results {
+			got[result.CheckDescription] = result.Status.String()
+			if result.FriendlyMessageToUser != "" {
+				got[result.CheckDescription] += ": " + result.FriendlyMessageToUser
+			}
+		}
+		return got
+	}
+
+	t.Run("Passes if every request succeeded over mTLS", func(t *testing.T) {
+		response := public.GenStatSummaryResponse(conformanceServerName, k8s.Deployment, "linkerd-conformance-abc", nil)
+
+		expected := map[string]string{
+			"metrics are reported for meshed requests": "OK",
+			"meshed requests succeed":                  "OK",
+			"meshed requests use mTLS":                 "OK",
+		}
+		if got := statuses(conformanceStatResults(&response, true)); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("Expected results %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("Fails if requests failed or weren't sent over TLS", func(t *testing.T) {
+		response := public.GenStatSummaryResponse(conformanceServerName, k8s.Deployment, "linkerd-conformance-abc", nil)
+		stats := response.GetOk().StatTables[0].GetPodGroup().Rows[0].Stats
+		stats.FailureCount = 7
+
+		expected := map[string]string{
+			"metrics are reported for meshed requests": "OK",
+			"meshed requests succeed":                  "FAIL: 7 of 130 requests failed",
+			"meshed requests use mTLS":                 "FAIL: 7 of 130 requests were not sent over TLS",
+		}
+		if got := statuses(conformanceStatResults(&response, true)); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("Expected results %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("Fails if no requests were reported", func(t *testing.T) {
+		response := public.GenStatSummaryResponse(conformanceServerName, k8s.Deployment, "linkerd-conformance-abc", nil)
+		response.GetOk().StatTables[0].GetPodGroup().Rows[0].Stats = nil
+
+		expected := map[string]string{
+			"metrics are reported for meshed requests": "FAIL: no requests from the load job to the server were reported",
+		}
+		if got := statuses(conformanceStatResults(&response, false)); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("Expected results %v, got %v", expected, got)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -118,7 +119,7 @@ func runInjectCmd(inputs []io.Reader, errWriter, outWriter io.Writer, options *i
 	postInjectBuf := &bytes.Buffer{}
 
 	for _, input := range inputs {
-		err := InjectYAML(input, postInjectBuf, options)
+		err := InjectYAML(input, postInjectBuf, errWriter, options)
 		if err != nil {
 			fmt.Fprintf(errWriter, "Error injecting linkerd proxy: %v\n", err)
 			return 1
@@ -141,6 +142,14 @@ func injectObjectMeta(t *metaV1.ObjectMeta, k8sLabels map[string]string, options
 	}
 	t.Annotations[k8s.CreatedByAnnotation] = k8s.CreatedByAnnotationValue()
 	t.Annotations[k8s.ProxyVersionAnnotation] = options.linkerdVersion
+	for _, container := range []string{"linkerd-init", "linkerd-proxy"} {
+		if options.seccompProfile != "" {
+			t.Annotations[k8s.SeccompContainerAnnotationPrefix+container] = options.seccompProfile
+		}
+		if options.appArmorProfile != "" {
+			t.Annotations[k8s.AppArmorContainerAnnotationPrefix+container] = options.appArmorProfile
+		}
+	}
 
 	if t.Labels == nil {
 		t.Labels = make(map[string]string)
@@ -164,6 +173,7 @@ func injectPodSpec(t *v1.PodSpec, identity k8s.TLSIdentity, controlPlaneDNSNameO
 	}
 
 	f := false
+	readOnly := true
 	inboundSkipPorts := append(options.ignoreInboundPorts, options.proxyControlPort, options.proxyMetricsPort)
 	inboundSkipPortsStr := make([]string, len(inboundSkipPorts))
 	for i, p := range inboundSkipPorts {
@@ -204,7 +214,7 @@ func injectPodSpec(t *v1.PodSpec, identity k8s.TLSIdentity, controlPlaneDNSNameO
 			Privileged: &f,
 		},
 	}
-	controlPlaneDNS := fmt.Sprintf("proxy-api.%s.svc.cluster.local", controlPlaneNamespace)
+	controlPlaneDNS := fmt.Sprintf("proxy-api.%s.svc.%s", controlPlaneNamespace, options.clusterDomain)
 	if controlPlaneDNSNameOverride != "" {
 		controlPlaneDNS = controlPlaneDNSNameOverride
 	}
@@ -215,7 +225,12 @@ func injectPodSpec(t *v1.PodSpec, identity k8s.TLSIdentity, controlPlaneDNSNameO
 		ImagePullPolicy:          v1.PullPolicy(options.imagePullPolicy),
 		TerminationMessagePolicy: v1.TerminationMessageFallbackToLogsOnError,
 		SecurityContext: &v1.SecurityContext{
-			RunAsUser: &options.proxyUID,
+			RunAsUser:                &options.proxyUID,
+			AllowPrivilegeEscalation: &f,
+			ReadOnlyRootFilesystem:   &readOnly,
+			Capabilities: &v1.Capabilities{
+				Drop: []v1.Capability{v1.Capability("ALL")},
+			},
 		},
 		Ports: []v1.ContainerPort{
 			{
@@ -316,7 +331,9 @@ func injectPodSpec(t *v1.PodSpec, identity k8s.TLSIdentity, controlPlaneDNSNameO
 }
 
 // InjectYAML takes an input stream of YAML, outputting injected YAML to out.
-func InjectYAML(in io.Reader, out io.Writer, options *injectOptions) error {
+// Objects that are skipped because they can't be injected are reported to
+// report.
+func InjectYAML(in io.Reader, out, report io.Writer, options *injectOptions) error {
 	reader := yamlDecoder.NewYAMLReader(bufio.NewReaderSize(in, 4096))
 
 	// Iterate over all YAML objects in the input
@@ -330,7 +347,7 @@ func InjectYAML(in io.Reader, out io.Writer, options *injectOptions) error {
 			return err
 		}
 
-		result, err := injectResource(bytes, options)
+		result, err := injectResource(bytes, report, options)
 		if err != nil {
 			return err
 		}
@@ -342,7 +359,7 @@ func InjectYAML(in io.Reader, out io.Writer, options *injectOptions) error {
 	return nil
 }
 
-func injectList(b []byte, options *injectOptions) ([]byte, error) {
+func injectList(b []byte, report io.Writer, options *injectOptions) ([]byte, error) {
 	var sourceList v1.List
 	if err := yaml.Unmarshal(b, &sourceList); err != nil {
 		return nil, err
@@ -351,7 +368,7 @@ func injectList(b []byte, options *injectOptions) ([]byte, error) {
 	items := []runtime.RawExtension{}
 
 	for _, item := range sourceList.Items {
-		result, err := injectResource(item.Raw, options)
+		result, err := injectResource(item.Raw, report, options)
 		if err != nil {
 			return nil, err
 		}
@@ -371,7 +388,7 @@ func injectList(b []byte, options *injectOptions) ([]byte, error) {
 	return yaml.Marshal(sourceList)
 }
 
-func injectResource(bytes []byte, options *injectOptions) ([]byte, error) {
+func injectResource(bytes []byte, report io.Writer, options *injectOptions) ([]byte, error) {
 	// The Kuberentes API is versioned and each version has an API modeled
 	// with its own distinct Go types. If we tell `yaml.Unmarshal()` which
 	// version we support then it will provide a representation of that
@@ -382,6 +399,7 @@ func injectResource(bytes []byte, options *injectOptions) ([]byte, error) {
 	// ---------------------------------------
 	// Note: bytes is expected to be YAML and will only modify it when a
 	// supported type is found. Otherwise, it is returned unmodified.
+	// Supported objects that can't be injected are reported to report.
 
 	// Unmarshal the object enough to read the Kind field
 	var meta metaV1.TypeMeta
@@ -480,6 +498,27 @@ func injectResource(bytes []byte, options *injectOptions) ([]byte, error) {
 		podSpec = &statefulset.Spec.Template.Spec
 		objectMeta = &statefulset.Spec.Template.ObjectMeta
 
+	case "Rollout", "DeploymentConfig":
+		var workload podTemplateWorkload
+		if err := yaml.Unmarshal(bytes, &workload); err != nil {
+			return nil, err
+		}
+		// An Argo Rollout with a workloadRef takes its pod template from
+		// another workload, which is the one to inject.
+		if !workload.hasTemplate() {
+			fmt.Fprintf(report, "Skipped injecting %s/%s: no pod template\n", strings.ToLower(meta.Kind), workload.Name)
+			return bytes, nil
+		}
+
+		obj = &workload
+		if meta.Kind == "Rollout" {
+			k8sLabels[k8s.ProxyRolloutLabel] = workload.Name
+		} else {
+			k8sLabels[k8s.ProxyDeploymentConfigLabel] = workload.Name
+		}
+		podSpec = &workload.Spec.Template.Spec
+		objectMeta = &workload.Spec.Template.ObjectMeta
+
 	case "Pod":
 		var pod v1.Pod
 		if err := yaml.Unmarshal(bytes, &pod); err != nil {
@@ -494,7 +533,7 @@ func injectResource(bytes []byte, options *injectOptions) ([]byte, error) {
 		// Lists are a little different than the other types. There's no immediate
 		// pod template. Because of this, we do a recursive call for each element
 		// in the list (instead of just marshaling the injected pod template).
-		return injectList(bytes, options)
+		return injectList(bytes, report, options)
 
 	}
 
@@ -531,6 +570,57 @@ func injectResource(bytes []byte, options *injectOptions) ([]byte, error) {
 	return output, nil
 }
 
+// podTemplateWorkload is a workload whose Go type isn't vendored, such as an
+// Argo Rollout or an OpenShift DeploymentConfig, but that has a pod template
+// at spec.template like a Deployment. Only its metadata and pod template are
+// decoded; the rest of the object is output as it was read.
+type podTemplateWorkload struct {
+	metaV1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		Template v1.PodTemplateSpec `json:"template"`
+	} `json:"spec"`
+
+	object map[string]interface{}
+}
+
+func (w *podTemplateWorkload) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &w.object); err != nil {
+		return err
+	}
+	type typed podTemplateWorkload
+	return json.Unmarshal(b, (*typed)(w))
+}
+
+// hasTemplate reports whether the workload has a pod template at
+// spec.template.
+func (w *podTemplateWorkload) hasTemplate() bool {
+	spec, ok := w.object["spec"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, ok = spec["template"].(map[string]interface{})
+	return ok
+}
+
+func (w *podTemplateWorkload) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(w.Spec.Template)
+	if err != nil {
+		return nil, err
+	}
+	var template interface{}
+	if err := json.Unmarshal(b, &template); err != nil {
+		return nil, err
+	}
+
+	spec, ok := w.object["spec"].(map[string]interface{})
+	if !ok {
+		spec = map[string]interface{}{}
+		w.object["spec"] = spec
+	}
+	spec["template"] = template
+	return json.Marshal(w.object)
+}
+
 // walk walks the file tree rooted at path. path may be a file or a directory.
 // Creates a reader for each file found.
 func walk(path string) ([]io.Reader, error) {
@@ -19,6 +19,15 @@ func TestInjectYAML(t *testing.T) {
 	tlsOptions.linkerdVersion = "testinjectversion"
 	tlsOptions.tls = "optional"
 
+	clusterDomainOptions := newInjectOptions()
+	clusterDomainOptions.linkerdVersion = "testinjectversion"
+	clusterDomainOptions.clusterDomain = "example.com"
+
+	securityProfileOptions := newInjectOptions()
+	securityProfileOptions.linkerdVersion = "testinjectversion"
+	securityProfileOptions.seccompProfile = "runtime/default"
+	securityProfileOptions.appArmorProfile = "localhost/linkerd"
+
 	testCases := []struct {
 		inputFileName     string
 		goldenFileName    string
@@ -31,8 +40,12 @@ func TestInjectYAML(t *testing.T) {
 		{"inject_emojivoto_deployment_controller_name.input.yml", "inject_emojivoto_deployment_controller_name.golden.yml", defaultOptions},
 		{"inject_emojivoto_statefulset.input.yml", "inject_emojivoto_statefulset.golden.yml", defaultOptions},
 		{"inject_emojivoto_pod.input.yml", "inject_emojivoto_pod.golden.yml", defaultOptions},
+		{"inject_argo_rollout.input.yml", "inject_argo_rollout.golden.yml", defaultOptions},
+		{"inject_openshift_deploymentconfig.input.yml", "inject_openshift_deploymentconfig.golden.yml", defaultOptions},
 		{"inject_emojivoto_deployment.input.yml", "inject_emojivoto_deployment_tls.golden.yml", tlsOptions},
 		{"inject_emojivoto_pod.input.yml", "inject_emojivoto_pod_tls.golden.yml", tlsOptions},
+		{"inject_emojivoto_deployment.input.yml", "inject_emojivoto_deployment_cluster_domain.golden.yml", clusterDomainOptions},
+		{"inject_emojivoto_deployment.input.yml", "inject_emojivoto_deployment_security_profiles.golden.yml", securityProfileOptions},
 	}
 
 	for i, tc := range testCases {
@@ -46,7 +59,7 @@ func TestInjectYAML(t *testing.T) {
 
 			output := new(bytes.Buffer)
 
-			err = InjectYAML(read, output, tc.testInjectOptions)
+			err = InjectYAML(read, output, ioutil.Discard, tc.testInjectOptions)
 			if err != nil {
 				t.Errorf("Unexpected error injecting YAML: %v\n", err)
 			}
@@ -82,6 +95,12 @@ func TestRunInjectCmd(t *testing.T) {
 			stdOutGoldenFileName: "inject_gettest_deployment.good.golden.yml",
 			exitCode:             0,
 		},
+		{
+			inputFileName:        "inject_argo_rollout_workload_ref.input.yml",
+			stdErrGoldenFileName: "inject_argo_rollout_workload_ref.golden",
+			stdOutGoldenFileName: "inject_argo_rollout_workload_ref.golden.yml",
+			exitCode:             0,
+		},
 	}
 
 	for i, tc := range testCases {
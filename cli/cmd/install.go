@@ -1,17 +1,26 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 	"text/template"
+	"time"
 
+	"github.com/ghodss/yaml"
 	"github.com/linkerd/linkerd2/cli/install"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	uuid "github.com/satori/go.uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation"
+	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
 )
 
 type installConfig struct {
@@ -28,21 +37,73 @@ type installConfig struct {
 	CliVersion                  string
 	ControllerLogLevel          string
 	ControllerComponentLabel    string
+	ControllerNamespaceLabel    string
 	CreatedByAnnotation         string
 	ProxyAPIPort                uint
+	ProxyControlPort            uint
 	EnableTLS                   bool
 	TLSTrustAnchorConfigMapName string
+	ClusterDomain               string
+	WebNotifyMinSuccessRate     float64
+	WebNotifyMaxLatencyP99      time.Duration
+	WebWebhookSecretName        string
+	WebOIDCIssuerURL            string
+	WebOIDCClientID             string
+	WebOIDCSecretName           string
+	WebOIDCRedirectURL          string
+	WebOIDCReadOnlyGroups       string
+	WebOIDCTapGroups            string
+	WebOIDCAdminGroups          string
+	EnableNetworkPolicies       bool
+	PodLabels                   string
+	PodMetricLabels             []podMetricLabel
+	MetricsURL                  string
+}
+
+// podMetricLabel is a pod label that Prometheus copies onto proxy metrics,
+// from the meta label that its Kubernetes service discovery adds for it.
+type podMetricLabel struct {
+	MetaLabel string
+	Name      string
 }
 
 type installOptions struct {
-	controllerReplicas uint
-	webReplicas        uint
-	prometheusReplicas uint
-	controllerLogLevel string
+	controllerReplicas   uint
+	webReplicas          uint
+	prometheusReplicas   uint
+	controllerLogLevel   string
+	notifyMinSuccessRate float64
+	notifyMaxLatencyP99  time.Duration
+	webhookSecretName    string
+	oidcIssuerURL        string
+	oidcClientID         string
+	oidcSecretName       string
+	oidcRedirectURL      string
+	oidcReadOnlyGroups   string
+	oidcTapGroups        string
+	oidcAdminGroups      string
+	withNetworkPolicies  bool
+	uuid                 string
+	components           []string
+	output               string
+	outputDir            string
+	podLabels            []string
+	metricsURL           string
 	*proxyConfigOptions
 }
 
-const prometheusProxyOutboundCapacity = 10000
+const (
+	prometheusProxyOutboundCapacity = 10000
+
+	kustomizeOutput = "kustomize"
+
+	// namespaceComponent holds the resources that every component depends on,
+	// such as the control plane namespace. It's always rendered.
+	namespaceComponent = "namespace"
+)
+
+// installComponents are the components that --components can select.
+var installComponents = []string{"controller", "web", "prometheus", "grafana", "ca"}
 
 func newInstallOptions() *installOptions {
 	return &installOptions{
@@ -67,6 +128,9 @@ func newCmdInstall() *cobra.Command {
 				return err
 			}
 
+			if options.output == kustomizeOutput {
+				return renderKustomize(*config, options.outputDir, options)
+			}
 			return render(*config, os.Stdout, options)
 		},
 	}
@@ -76,6 +140,23 @@ func newCmdInstall() *cobra.Command {
 	cmd.PersistentFlags().UintVar(&options.webReplicas, "web-replicas", options.webReplicas, "Replicas of the web server to deploy")
 	cmd.PersistentFlags().UintVar(&options.prometheusReplicas, "prometheus-replicas", options.prometheusReplicas, "Replicas of prometheus to deploy")
 	cmd.PersistentFlags().StringVar(&options.controllerLogLevel, "controller-log-level", options.controllerLogLevel, "Log level for the controller and web components")
+	cmd.PersistentFlags().Float64Var(&options.notifyMinSuccessRate, "web-notify-min-success-rate", options.notifyMinSuccessRate, "Show a dashboard alert when a deployment's success rate falls below this percentage (0 disables)")
+	cmd.PersistentFlags().DurationVar(&options.notifyMaxLatencyP99, "web-notify-max-latency-p99", options.notifyMaxLatencyP99, "Show a dashboard alert when a deployment's P99 latency exceeds this duration (0 disables)")
+	cmd.PersistentFlags().StringVar(&options.webhookSecretName, "web-webhook-secret", options.webhookSecretName, "Secret in the control plane namespace whose \"notify-webhook-url\" key holds a Slack-compatible webhook URL that dashboard alerts are also delivered to, and whose \"audit-webhook-url\" key holds a URL that audit entries of dashboard actions, such as taps, are also delivered to; either key can be left out")
+	cmd.PersistentFlags().StringVar(&options.oidcIssuerURL, "web-oidc-issuer-url", options.oidcIssuerURL, "OpenID Connect issuer that dashboard users must log in with (disabled if empty)")
+	cmd.PersistentFlags().StringVar(&options.oidcClientID, "web-oidc-client-id", options.oidcClientID, "OpenID Connect client ID of the dashboard")
+	cmd.PersistentFlags().StringVar(&options.oidcSecretName, "web-oidc-secret", options.oidcSecretName, "Secret in the control plane namespace whose \"client-secret\" key holds the OpenID Connect client secret")
+	cmd.PersistentFlags().StringVar(&options.oidcRedirectURL, "web-oidc-redirect-url", options.oidcRedirectURL, "Dashboard URL of /auth/callback, as registered with the OpenID Connect provider")
+	cmd.PersistentFlags().StringVar(&options.oidcReadOnlyGroups, "web-oidc-read-only-groups", options.oidcReadOnlyGroups, "Comma-separated groups that may view the dashboard (any user who logs in if empty)")
+	cmd.PersistentFlags().StringVar(&options.oidcTapGroups, "web-oidc-tap-groups", options.oidcTapGroups, "Comma-separated groups that may also tap from the dashboard")
+	cmd.PersistentFlags().StringVar(&options.oidcAdminGroups, "web-oidc-admin-groups", options.oidcAdminGroups, "Comma-separated groups that may also tap and view the audit log from the dashboard")
+	cmd.PersistentFlags().StringVar(&options.uuid, "uuid", options.uuid, "Unique ID of this install, randomly generated if empty; set it to render identical output on every run")
+	cmd.PersistentFlags().StringSliceVar(&options.components, "components", options.components, fmt.Sprintf("Only output the resources of these components; one or more of: %s (all if empty)", strings.Join(installComponents, ", ")))
+	cmd.PersistentFlags().StringVarP(&options.output, "output", "o", options.output, fmt.Sprintf("Output format; \"%s\" writes a kustomization directory per component to --output-dir instead of a YAML stream", kustomizeOutput))
+	cmd.PersistentFlags().StringVar(&options.outputDir, "output-dir", options.outputDir, "Directory to write kustomize output to")
+	cmd.PersistentFlags().BoolVar(&options.withNetworkPolicies, "with-network-policies", options.withNetworkPolicies, "Output NetworkPolicies that restrict ingress to the control plane to the traffic it needs")
+	cmd.PersistentFlags().StringSliceVar(&options.podLabels, "pod-labels", options.podLabels, "Pod labels to add to proxy metrics and tap events, e.g. team,cost-center")
+	cmd.PersistentFlags().StringVar(&options.metricsURL, "metrics-url", options.metricsURL, "URL of a Prometheus-compatible query API, such as Thanos Query, that the public API reads metrics from instead of the bundled Prometheus")
 
 	return cmd
 }
@@ -84,6 +165,17 @@ func validateAndBuildConfig(options *installOptions) (*installConfig, error) {
 	if err := validate(options); err != nil {
 		return nil, err
 	}
+	installUUID := options.uuid
+	if installUUID == "" {
+		installUUID = uuid.NewV4().String()
+	}
+	podMetricLabels := make([]podMetricLabel, len(options.podLabels))
+	for i, key := range options.podLabels {
+		podMetricLabels[i] = podMetricLabel{
+			MetaLabel: k8s.PodLabelMetaLabel(key),
+			Name:      k8s.MetricLabelName(key),
+		}
+	}
 	return &installConfig{
 		Namespace:                   controlPlaneNamespace,
 		ControllerImage:             fmt.Sprintf("%s/controller:%s", options.dockerRegistry, options.linkerdVersion),
@@ -94,18 +186,51 @@ func validateAndBuildConfig(options *installOptions) (*installConfig, error) {
 		WebReplicas:                 options.webReplicas,
 		PrometheusReplicas:          options.prometheusReplicas,
 		ImagePullPolicy:             options.imagePullPolicy,
-		UUID:                        uuid.NewV4().String(),
+		UUID:                        installUUID,
 		CliVersion:                  k8s.CreatedByAnnotationValue(),
 		ControllerLogLevel:          options.controllerLogLevel,
 		ControllerComponentLabel:    k8s.ControllerComponentLabel,
+		ControllerNamespaceLabel:    k8s.ControllerNSLabel,
 		CreatedByAnnotation:         k8s.CreatedByAnnotation,
 		ProxyAPIPort:                options.proxyAPIPort,
+		ProxyControlPort:            options.proxyControlPort,
 		EnableTLS:                   options.enableTLS(),
 		TLSTrustAnchorConfigMapName: k8s.TLSTrustAnchorConfigMapName,
+		ClusterDomain:               options.clusterDomain,
+		WebNotifyMinSuccessRate:     options.notifyMinSuccessRate,
+		WebNotifyMaxLatencyP99:      options.notifyMaxLatencyP99,
+		WebWebhookSecretName:        options.webhookSecretName,
+		WebOIDCIssuerURL:            options.oidcIssuerURL,
+		WebOIDCClientID:             options.oidcClientID,
+		WebOIDCSecretName:           options.oidcSecretName,
+		WebOIDCRedirectURL:          options.oidcRedirectURL,
+		WebOIDCReadOnlyGroups:       options.oidcReadOnlyGroups,
+		WebOIDCTapGroups:            options.oidcTapGroups,
+		WebOIDCAdminGroups:          options.oidcAdminGroups,
+		EnableNetworkPolicies:       options.withNetworkPolicies,
+		PodLabels:                   strings.Join(options.podLabels, ","),
+		PodMetricLabels:             podMetricLabels,
+		MetricsURL:                  options.metricsURL,
 	}, nil
 }
 
 func render(config installConfig, w io.Writer, options *installOptions) error {
+	if len(options.components) == 0 {
+		return renderManifest(config, w, options)
+	}
+
+	resources, err := renderComponents(config, options)
+	if err != nil {
+		return err
+	}
+	for _, resource := range resources {
+		w.Write(resource.yaml)
+		w.Write([]byte("---\n"))
+	}
+	return nil
+}
+
+func renderManifest(config installConfig, w io.Writer, options *installOptions) error {
 	template, err := template.New("linkerd").Parse(install.Template)
 	if err != nil {
 		return err
@@ -125,18 +250,307 @@ func render(config installConfig, w io.Writer, options *installOptions) error {
 			return err
 		}
 	}
+	if config.EnableNetworkPolicies {
+		networkPolicyTemplate, err := template.New("linkerd").Parse(install.NetworkPolicyTemplate)
+		if err != nil {
+			return err
+		}
+		err = networkPolicyTemplate.Execute(buf, config)
+		if err != nil {
+			return err
+		}
+	}
 	injectOptions := newInjectOptions()
 	injectOptions.proxyConfigOptions = options.proxyConfigOptions
 
 	// Special case for linkerd-proxy running in the Prometheus pod.
 	injectOptions.proxyOutboundCapacity[config.PrometheusImage] = prometheusProxyOutboundCapacity
 
-	return InjectYAML(buf, w, injectOptions)
+	return InjectYAML(buf, w, os.Stderr, injectOptions)
+}
+
+// componentResource is a single rendered resource, and the component that it
+// belongs to.
+type componentResource struct {
+	component string
+	kind      string
+	name      string
+	yaml      []byte
+}
+
+// renderComponents renders the resources of the components selected by
+// options, and of the namespace component.
+func renderComponents(config installConfig, options *installOptions) ([]componentResource, error) {
+	buf := &bytes.Buffer{}
+	if err := renderManifest(config, buf, options); err != nil {
+		return nil, err
+	}
+
+	selected := map[string]bool{namespaceComponent: true}
+	for _, component := range installComponents {
+		selected[component] = len(options.components) == 0
+	}
+	for _, component := range options.components {
+		selected[component] = true
+	}
+
+	resources := []componentResource{}
+	reader := yamlDecoder.NewYAMLReader(bufio.NewReader(buf))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var meta struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name   string            `json:"name"`
+				Labels map[string]string `json:"labels"`
+			} `json:"metadata"`
+		}
+		if err := yaml.Unmarshal(doc, &meta); err != nil {
+			return nil, err
+		}
+		if meta.Kind == "" {
+			continue
+		}
+
+		component := componentOf(meta.Kind, meta.Metadata.Name, meta.Metadata.Labels, config.Namespace)
+		if !selected[component] {
+			continue
+		}
+		resources = append(resources, componentResource{
+			component: component,
+			kind:      meta.Kind,
+			name:      meta.Metadata.Name,
+			yaml:      trimTrailingComments(doc),
+		})
+	}
+	return resources, nil
+}
+
+// componentOf returns the component that a resource belongs to: the value of
+// its component label, or else the component in its name, as in
+// "linkerd-prometheus" or "linkerd-<namespace>-prometheus".
+func componentOf(kind, name string, labels map[string]string, namespace string) string {
+	if component := labels[k8s.ControllerComponentLabel]; component != "" {
+		return component
+	}
+	if kind != "Namespace" {
+		for _, component := range installComponents {
+			if name == "linkerd-"+component || name == fmt.Sprintf("linkerd-%s-%s", namespace, component) {
+				return component
+			}
+		}
+	}
+	return namespaceComponent
+}
+
+// trimTrailingComments drops the comments at the end of a rendered resource,
+// which introduce the next section of the template rather than describe this
+// resource.
+func trimTrailingComments(doc []byte) []byte {
+	lines := strings.Split(strings.TrimRight(string(doc), "\n"), "\n")
+	for len(lines) > 0 {
+		last := strings.TrimSpace(lines[len(lines)-1])
+		if last != "" && !strings.HasPrefix(last, "#") {
+			break
+		}
+		lines = lines[:len(lines)-1]
+	}
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+type kustomization struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Resources  []string `json:"resources"`
+}
+
+// renderKustomize writes a directory per component to dir, each with a file
+// per resource and a kustomization.yaml listing them, and a top-level
+// kustomization.yaml that includes every component.
+func renderKustomize(config installConfig, dir string, options *installOptions) error {
+	resources, err := renderComponents(config, options)
+	if err != nil {
+		return err
+	}
+
+	components := []string{}
+	files := map[string][]string{}
+	for _, resource := range resources {
+		if _, ok := files[resource.component]; !ok {
+			components = append(components, resource.component)
+		}
+
+		componentDir := filepath.Join(dir, resource.component)
+		if err := os.MkdirAll(componentDir, 0755); err != nil {
+			return err
+		}
+		file := strings.ToLower(fmt.Sprintf("%s-%s.yaml", resource.kind, resource.name))
+		if err := ioutil.WriteFile(filepath.Join(componentDir, file), resource.yaml, 0644); err != nil {
+			return err
+		}
+		files[resource.component] = append(files[resource.component], file)
+	}
+
+	for _, component := range components {
+		if err := writeKustomization(filepath.Join(dir, component), files[component]); err != nil {
+			return err
+		}
+	}
+	return writeKustomization(dir, components)
+}
+
+func writeKustomization(dir string, resources []string) error {
+	out, err := yaml.Marshal(kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Resources:  resources,
+	})
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "kustomization.yaml"), out, 0644)
 }
 
 func validate(options *installOptions) error {
 	if _, err := log.ParseLevel(options.controllerLogLevel); err != nil {
 		return fmt.Errorf("--controller-log-level must be one of: panic, fatal, error, warn, info, debug")
 	}
+	if options.notifyMinSuccessRate < 0 || options.notifyMinSuccessRate > 100 {
+		return fmt.Errorf("--web-notify-min-success-rate must be between 0 and 100")
+	}
+	if options.notifyMaxLatencyP99 < 0 {
+		return fmt.Errorf("--web-notify-max-latency-p99 must not be negative")
+	}
+	if options.metricsURL != "" {
+		if _, err := url.ParseRequestURI(options.metricsURL); err != nil {
+			return fmt.Errorf("--metrics-url must be a valid URL: %s", err)
+		}
+	}
+	if options.uuid != "" {
+		if _, err := uuid.FromString(options.uuid); err != nil {
+			return fmt.Errorf("--uuid must be a valid UUID: %s", err)
+		}
+	}
+	if err := validateComponents(options); err != nil {
+		return err
+	}
+	if err := validatePodLabels(options); err != nil {
+		return err
+	}
+	if err := validateOIDC(options); err != nil {
+		return err
+	}
 	return options.validate()
 }
+
+func validateOIDC(options *installOptions) error {
+	if options.oidcIssuerURL == "" {
+		if options.oidcClientID != "" || options.oidcSecretName != "" || options.oidcRedirectURL != "" ||
+			options.oidcReadOnlyGroups != "" || options.oidcTapGroups != "" || options.oidcAdminGroups != "" {
+			return fmt.Errorf("--web-oidc-issuer-url is required to configure OIDC login")
+		}
+		return nil
+	}
+	if _, err := url.ParseRequestURI(options.oidcIssuerURL); err != nil {
+		return fmt.Errorf("--web-oidc-issuer-url must be a valid URL: %s", err)
+	}
+	if options.oidcClientID == "" {
+		return fmt.Errorf("--web-oidc-client-id is required with --web-oidc-issuer-url")
+	}
+	if _, err := url.ParseRequestURI(options.oidcRedirectURL); err != nil {
+		return fmt.Errorf("--web-oidc-redirect-url must be a valid URL: %s", err)
+	}
+	return nil
+}
+
+// reservedMetricLabels are set on proxy metrics or tap events by Linkerd, and
+// can't be overridden by --pod-labels. Prometheus would rename the proxy's
+// own label to exported_<name>, which breaks the public API's queries.
+var reservedMetricLabels = map[string]bool{
+	"authority":             true,
+	"classification":        true,
+	"control_plane_ns":      true,
+	"daemonset":             true,
+	"deployment":            true,
+	"deploymentconfig":      true,
+	"direction":             true,
+	"error":                 true,
+	"grpc_status_code":      true,
+	"instance":              true,
+	"job":                   true,
+	"k8s_job":               true,
+	"le":                    true,
+	"namespace":             true,
+	"pod":                   true,
+	"pod_template_hash":     true,
+	"replicaset":            true,
+	"replicationcontroller": true,
+	"rollout":               true,
+	"service":               true,
+	"service_port":          true,
+	"serviceaccount":        true,
+	"statefulset":           true,
+	"status_code":           true,
+	"tls":                   true,
+}
+
+// reservedMetricLabelPrefix is the prefix of the labels that proxies set on
+// outbound metrics to describe their destination, such as dst_namespace.
+const reservedMetricLabelPrefix = "dst_"
+
+func validatePodLabels(options *installOptions) error {
+	keys := make(map[string]string)
+	for _, key := range options.podLabels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("--pod-labels must be label keys, got [%s]: %s", key, strings.Join(errs, "; "))
+		}
+		if strings.HasPrefix(key, "linkerd.io/") {
+			return fmt.Errorf("--pod-labels must not include linkerd.io labels, which are already added, got [%s]", key)
+		}
+		name := k8s.MetricLabelName(key)
+		if reservedMetricLabels[name] || strings.HasPrefix(name, reservedMetricLabelPrefix) {
+			return fmt.Errorf("--pod-labels must not include [%s], which would replace Linkerd's [%s] label", key, name)
+		}
+		if other, ok := keys[name]; ok && other != key {
+			return fmt.Errorf("--pod-labels must not include both [%s] and [%s], which are both added as [%s]", other, key, name)
+		}
+		keys[name] = key
+	}
+	return nil
+}
+
+func validateComponents(options *installOptions) error {
+	for _, component := range options.components {
+		known := false
+		for _, c := range installComponents {
+			known = known || component == c
+		}
+		if !known {
+			return fmt.Errorf("--components must be one or more of: %s", strings.Join(installComponents, ", "))
+		}
+		if component == "ca" && !options.enableTLS() {
+			return fmt.Errorf("the ca component requires --tls=%s", optionalTLS)
+		}
+	}
+
+	switch options.output {
+	case "":
+		if options.outputDir != "" {
+			return fmt.Errorf("--output-dir requires --output=%s", kustomizeOutput)
+		}
+	case kustomizeOutput:
+		if options.outputDir == "" {
+			return fmt.Errorf("--output=%s requires --output-dir", kustomizeOutput)
+		}
+	default:
+		return fmt.Errorf("--output must be blank or set to \"%s\"", kustomizeOutput)
+	}
+	return nil
+}
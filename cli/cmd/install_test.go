@@ -4,7 +4,12 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestRender(t *testing.T) {
@@ -34,10 +39,27 @@ func TestRender(t *testing.T) {
 		CliVersion:                  "CliVersion",
 		ControllerLogLevel:          "ControllerLogLevel",
 		ControllerComponentLabel:    "ControllerComponentLabel",
+		ControllerNamespaceLabel:    "ControllerNamespaceLabel",
 		CreatedByAnnotation:         "CreatedByAnnotation",
 		ProxyAPIPort:                123,
+		ProxyControlPort:            456,
 		EnableTLS:                   true,
 		TLSTrustAnchorConfigMapName: "TLSTrustAnchorConfigMapName",
+		ClusterDomain:               "ClusterDomain",
+		WebNotifyMinSuccessRate:     99.5,
+		WebNotifyMaxLatencyP99:      250 * time.Millisecond,
+		WebWebhookSecretName:        "WebWebhookSecretName",
+		WebOIDCIssuerURL:            "WebOIDCIssuerURL",
+		WebOIDCClientID:             "WebOIDCClientID",
+		WebOIDCSecretName:           "WebOIDCSecretName",
+		WebOIDCRedirectURL:          "WebOIDCRedirectURL",
+		WebOIDCReadOnlyGroups:       "WebOIDCReadOnlyGroups",
+		WebOIDCTapGroups:            "WebOIDCTapGroups",
+		WebOIDCAdminGroups:          "WebOIDCAdminGroups",
+		EnableNetworkPolicies:       true,
+		PodLabels:                   "PodLabels",
+		PodMetricLabels:             []podMetricLabel{{MetaLabel: "PodMetricLabelMetaLabel", Name: "PodMetricLabelName"}},
+		MetricsURL:                  "MetricsURL",
 	}
 
 	testCases := []struct {
@@ -69,3 +91,203 @@ func TestRender(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderWithUUID(t *testing.T) {
+	t.Run("Renders identical output for identical options", func(t *testing.T) {
+		options := newInstallOptions()
+		options.uuid = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+		options.tls = optionalTLS
+
+		var outputs []string
+		for i := 0; i < 2; i++ {
+			config, err := validateAndBuildConfig(options)
+			if err != nil {
+				t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+			}
+			var buf bytes.Buffer
+			if err := render(*config, &buf, options); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			outputs = append(outputs, buf.String())
+		}
+
+		if outputs[0] != outputs[1] {
+			diffCompare(t, outputs[1], outputs[0])
+		}
+	})
+
+	t.Run("Rejects an invalid UUID", func(t *testing.T) {
+		options := newInstallOptions()
+		options.uuid = "not-a-uuid"
+
+		if _, err := validateAndBuildConfig(options); err == nil {
+			t.Fatalf("Expected an error for an invalid UUID")
+		}
+	})
+}
+
+func TestRenderComponents(t *testing.T) {
+	defaultControlPlaneNamespace := controlPlaneNamespace
+	controlPlaneNamespace = "linkerd"
+	defer func() { controlPlaneNamespace = defaultControlPlaneNamespace }()
+
+	options := newInstallOptions()
+	options.uuid = "deaab91a-f4ab-448a-b7d1-c832a2fa0a60"
+	options.withNetworkPolicies = true
+
+	t.Run("Only outputs the selected components and the namespace", func(t *testing.T) {
+		options.components = []string{"web"}
+		config, err := validateAndBuildConfig(options)
+		if err != nil {
+			t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+		}
+		resources, err := renderComponents(*config, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var rendered []string
+		for _, resource := range resources {
+			rendered = append(rendered, fmt.Sprintf("%s/%s/%s", resource.component, resource.kind, resource.name))
+		}
+		expected := []string{
+			"namespace/Namespace/linkerd",
+			"web/Service/web",
+			"web/Deployment/web",
+			"namespace/NetworkPolicy/linkerd-default",
+			"web/NetworkPolicy/linkerd-web",
+		}
+		if !reflect.DeepEqual(rendered, expected) {
+			t.Fatalf("Expected resources %v, got %v", expected, rendered)
+		}
+	})
+
+	t.Run("Writes a kustomization per component", func(t *testing.T) {
+		options.components = nil
+		config, err := validateAndBuildConfig(options)
+		if err != nil {
+			t.Fatalf("Unexpected error from validateAndBuildConfig(): %v", err)
+		}
+		dir, err := ioutil.TempDir("", "linkerd-install")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer os.RemoveAll(dir)
+
+		if err := renderKustomize(*config, dir, options); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		top, err := ioutil.ReadFile(filepath.Join(dir, "kustomization.yaml"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, component := range []string{"namespace", "controller", "web", "prometheus", "grafana"} {
+			if !strings.Contains(string(top), "- "+component+"\n") {
+				t.Fatalf("Expected %s to be listed in kustomization.yaml, got:\n%s", component, top)
+			}
+		}
+
+		web, err := ioutil.ReadFile(filepath.Join(dir, "web", "kustomization.yaml"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(string(web), "- deployment-web.yaml\n") {
+			t.Fatalf("Expected the web deployment to be listed in web/kustomization.yaml, got:\n%s", web)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "web", "deployment-web.yaml")); err != nil {
+			t.Fatalf("Expected the web deployment to be written: %v", err)
+		}
+	})
+}
+
+func TestComponentOf(t *testing.T) {
+	testCases := []struct {
+		kind      string
+		name      string
+		labels    map[string]string
+		component string
+	}{
+		{"Deployment", "web", map[string]string{"linkerd.io/control-plane-component": "web"}, "web"},
+		{"ServiceAccount", "linkerd-prometheus", nil, "prometheus"},
+		{"ClusterRole", "linkerd-linkerd-controller", nil, "controller"},
+		{"Namespace", "linkerd", nil, "namespace"},
+		{"NetworkPolicy", "linkerd-default", nil, "namespace"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s/%s", tc.kind, tc.name), func(t *testing.T) {
+			if component := componentOf(tc.kind, tc.name, tc.labels, "linkerd"); component != tc.component {
+				t.Fatalf("Expected component [%s], got [%s]", tc.component, component)
+			}
+		})
+	}
+}
+
+func TestValidateComponents(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		modify func(*installOptions)
+		valid  bool
+	}{
+		{"Accepts known components", func(o *installOptions) { o.components = []string{"web", "grafana"} }, true},
+		{"Rejects unknown components", func(o *installOptions) { o.components = []string{"tap"} }, false},
+		{"Rejects the ca component without TLS", func(o *installOptions) { o.components = []string{"ca"} }, false},
+		{"Accepts the ca component with TLS", func(o *installOptions) { o.components = []string{"ca"}; o.tls = optionalTLS }, true},
+		{"Rejects unknown outputs", func(o *installOptions) { o.output = "json" }, false},
+		{"Rejects kustomize output without a directory", func(o *installOptions) { o.output = kustomizeOutput }, false},
+		{"Rejects a directory without kustomize output", func(o *installOptions) { o.outputDir = "out" }, false},
+		{"Accepts kustomize output to a directory", func(o *installOptions) { o.output = kustomizeOutput; o.outputDir = "out" }, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			options := newInstallOptions()
+			tc.modify(options)
+			err := validateComponents(options)
+			if tc.valid && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatalf("Expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestValidatePodLabels(t *testing.T) {
+	testCases := []struct {
+		podLabels []string
+		valid     bool
+	}{
+		{[]string{"team", "cost-center", "app.kubernetes.io/part-of"}, true},
+		{[]string{"not a label"}, false},
+		{[]string{"linkerd.io/proxy-deployment"}, false},
+		{[]string{"pod"}, false},
+		{[]string{"service-account"}, true},
+		{[]string{"service_port"}, false},
+		{[]string{"direction"}, false},
+		{[]string{"status-code"}, false},
+		{[]string{"le"}, false},
+		{[]string{"dst_team"}, false},
+		{[]string{"dst-team"}, false},
+		{[]string{"team-dst"}, true},
+		{[]string{"3scale.net/team"}, true},
+		{[]string{"team.name", "team_name"}, false},
+		{[]string{"team", "team"}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(strings.Join(tc.podLabels, ","), func(t *testing.T) {
+			options := newInstallOptions()
+			options.podLabels = tc.podLabels
+			err := validatePodLabels(options)
+			if tc.valid && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatalf("Expected an error, got nil")
+			}
+		})
+	}
+}
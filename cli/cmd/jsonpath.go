@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// jsonpathOutputPrefix precedes the template of the "jsonpath=TEMPLATE" output
+// format, as in kubectl, e.g. -o jsonpath='{.rows[*].successRate}'.
+const jsonpathOutputPrefix = "jsonpath="
+
+// isJSONOutput returns true if output is "json" or a jsonpath template.
+func isJSONOutput(output string) bool {
+	return output == jsonOutput || strings.HasPrefix(output, jsonpathOutputPrefix)
+}
+
+// parseJSONPath parses the template of a "jsonpath=TEMPLATE" output format.
+func parseJSONPath(output string) (*jsonpath.JSONPath, error) {
+	j := jsonpath.New("output")
+	if err := j.Parse(strings.TrimPrefix(output, jsonpathOutputPrefix)); err != nil {
+		return nil, fmt.Errorf("invalid jsonpath template: %s", err)
+	}
+	return j, nil
+}
+
+// renderJSON writes v as indented JSON if output is "json", or the values that
+// the template selects from that JSON if output is a jsonpath template. As in
+// kubectl, no newline is added after the template's output.
+func renderJSON(w io.Writer, output string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if output == jsonOutput {
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+
+	j, err := parseJSONPath(output)
+	if err != nil {
+		return err
+	}
+	// templates refer to the JSON field names, so they're applied to the
+	// decoded JSON rather than to v
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+	return j.Execute(w, data)
+}
@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/linkerd/linkerd2/controller/api/util"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	batchV1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	loadJobPrefix     = "linkerd-load-"
+	loadContainerName = "slow-cooker"
+	loadImage         = "buoyantio/slow_cooker:1.1.1"
+
+	// loadStartTimeout is how long the load job's pod has to start sending
+	// requests, including pulling its images.
+	loadStartTimeout = 5 * time.Minute
+)
+
+// loadPollInterval is how often the load job's pod is checked while it runs.
+var loadPollInterval = 2 * time.Second
+
+type loadOptions struct {
+	namespace string
+	rps       uint
+	duration  time.Duration
+	path      string
+	port      uint
+	*injectOptions
+}
+
+func newLoadOptions() *loadOptions {
+	return &loadOptions{
+		namespace:     "default",
+		rps:           10,
+		duration:      time.Minute,
+		path:          "/",
+		port:          0,
+		injectOptions: newInjectOptions(),
+	}
+}
+
+func newCmdAlphaLoad() *cobra.Command {
+	options := newLoadOptions()
+
+	cmd := &cobra.Command{
+		Use:   "load [flags] (RESOURCE)",
+		Short: "Send test traffic to a resource through the mesh",
+		Long: `Send test traffic to a resource through the mesh.
+
+  The RESOURCE argument is the deployment or service to send requests to:
+  (TYPE NAME | TYPE/NAME)
+
+  Examples:
+  * deploy/web
+  * svc web
+
+  A meshed job is started in the resource's namespace, and sends HTTP GET
+  requests to the resource's service at a fixed rate. Once the job is done, the
+  stats that Linkerd observed for its requests are printed and the job is
+  deleted.`,
+		Example: `  # Send 50 requests per second to the web deployment's /healthz path for two minutes.
+  linkerd alpha load deploy/web --rps 50 --duration 2m --path /healthz
+
+  # Send requests to port 8080 of the web service in the test namespace.
+  linkerd alpha load svc/web -n test --port 8080`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := util.BuildResource(options.namespace, args...)
+			if err != nil {
+				return err
+			}
+			if err := options.validate(target.Type); err != nil {
+				return err
+			}
+
+			clientset, err := k8s.NewClientSet(kubeconfigPath)
+			if err != nil {
+				return err
+			}
+
+			url, err := loadTargetURL(clientset, target.Type, target.Namespace, target.Name, options)
+			if err != nil {
+				return err
+			}
+
+			job, err := newLoadJob(target.Namespace, url, options)
+			if err != nil {
+				return err
+			}
+
+			return runInterruptible(func(ctx context.Context) error {
+				return runLoad(ctx, clientset, job, target.Type, target.Name, options)
+			})
+		},
+	}
+
+	addProxyConfigFlags(cmd, options.proxyConfigOptions)
+	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the specified resource")
+	cmd.PersistentFlags().UintVar(&options.rps, "rps", options.rps, "Requests per second to send")
+	cmd.PersistentFlags().DurationVar(&options.duration, "duration", options.duration, "How long to send requests for")
+	cmd.PersistentFlags().StringVar(&options.path, "path", options.path, "Path to send requests to")
+	cmd.PersistentFlags().UintVar(&options.port, "port", options.port, "Service port to send requests to; defaults to the service's first port")
+
+	return cmd
+}
+
+func (o *loadOptions) validate(resourceType string) error {
+	if resourceType != k8s.Deployment && resourceType != k8s.Service {
+		return fmt.Errorf("unsupported resource type [%s]; load can be sent to a %s or a %s", resourceType, k8s.Deployment, k8s.Service)
+	}
+	if o.rps == 0 {
+		return fmt.Errorf("--rps must be greater than 0")
+	}
+	if o.duration < time.Second {
+		return fmt.Errorf("--duration must be at least 1s")
+	}
+	if !strings.HasPrefix(o.path, "/") {
+		return fmt.Errorf("--path must start with /")
+	}
+	return o.proxyConfigOptions.validate()
+}
+
+// loadTargetURL returns the URL of path on the service named name, or on the
+// service that selects the pods of the deployment named name.
+func loadTargetURL(clientset kubernetes.Interface, resourceType, namespace, name string, options *loadOptions) (string, error) {
+	var svc *v1.Service
+	switch resourceType {
+	case k8s.Service:
+		var err error
+		svc, err = clientset.CoreV1().Services(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("error getting service [%s/%s]: %v", namespace, name, err)
+		}
+
+	case k8s.Deployment:
+		deploy, err := clientset.AppsV1().Deployments(namespace).Get(name, metaV1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("error getting deployment [%s/%s]: %v", namespace, name, err)
+		}
+		services, err := clientset.CoreV1().Services(namespace).List(metaV1.ListOptions{})
+		if err != nil {
+			return "", fmt.Errorf("error listing services in namespace [%s]: %v", namespace, err)
+		}
+		for i := range services.Items {
+			selector := services.Items[i].Spec.Selector
+			if len(selector) > 0 && labels.SelectorFromSet(selector).Matches(labels.Set(deploy.Spec.Template.Labels)) {
+				svc = &services.Items[i]
+				break
+			}
+		}
+		if svc == nil {
+			return "", fmt.Errorf("no service selects the pods of deployment [%s/%s]", namespace, name)
+		}
+	}
+
+	port := int32(options.port)
+	if port == 0 {
+		if len(svc.Spec.Ports) == 0 {
+			return "", fmt.Errorf("service [%s/%s] has no ports", namespace, svc.Name)
+		}
+		port = svc.Spec.Ports[0].Port
+	}
+
+	return fmt.Sprintf("http://%s.%s.svc.%s:%d%s", svc.Name, namespace, options.clusterDomain, port, options.path), nil
+}
+
+// newLoadJob returns a job that sends requests to url at options.rps for
+// options.duration, with the Linkerd proxy injected so that they go through
+// the mesh. The proxy keeps running once the requests are sent, so the job
+// itself never completes; the deadline ensures it's cleaned up regardless.
+func newLoadJob(namespace, url string, options *loadOptions) (*batchV1.Job, error) {
+	total := uint64(options.rps) * uint64(options.duration/time.Second)
+	backoffLimit := int32(0)
+	deadline := int64((options.duration + loadStartTimeout) / time.Second)
+
+	job := &batchV1.Job{
+		TypeMeta: metaV1.TypeMeta{Kind: "Job", APIVersion: "batch/v1"},
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      loadJobPrefix + strconv.FormatInt(time.Now().Unix(), 36),
+			Namespace: namespace,
+		},
+		Spec: batchV1.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &deadline,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					RestartPolicy: v1.RestartPolicyNever,
+					Containers: []v1.Container{
+						{
+							Name:  loadContainerName,
+							Image: loadImage,
+							Command: []string{
+								"slow_cooker",
+								"-qps", strconv.FormatUint(uint64(options.rps), 10),
+								"-concurrency", "1",
+								"-totalRequests", strconv.FormatUint(total, 10),
+								url,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	b, err := yaml.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+	b, err = injectResource(b, os.Stderr, options.injectOptions)
+	if err != nil {
+		return nil, fmt.Errorf("error injecting load job: %v", err)
+	}
+
+	var injected batchV1.Job
+	if err := yaml.Unmarshal(b, &injected); err != nil {
+		return nil, err
+	}
+	return &injected, nil
+}
+
+// runLoad creates job, waits for it to send its requests and prints the stats
+// of the requests. The job is deleted before returning, including when ctx is
+// canceled.
+func runLoad(ctx context.Context, clientset kubernetes.Interface, job *batchV1.Job, resourceType, resourceName string, options *loadOptions) error {
+	job, err := clientset.BatchV1().Jobs(job.Namespace).Create(job)
+	if err != nil {
+		return fmt.Errorf("error creating load job: %v", err)
+	}
+	defer deleteLoadJob(clientset, job)
+
+	fmt.Printf("Sending %d requests per second to %s/%s for %s from job/%s\n", options.rps, resourceType, resourceName, options.duration, job.Name)
+
+	pod, err := waitForLoadJob(ctx, clientset, job, options.duration+loadStartTimeout)
+	if err != nil {
+		return err
+	}
+
+	req, err := util.BuildStatSummaryRequest(util.StatSummaryRequestParams{
+		TimeWindow:    loadTimeWindow(options.duration),
+		Namespace:     job.Namespace,
+		ResourceType:  resourceType,
+		ResourceName:  resourceName,
+		FromNamespace: job.Namespace,
+		FromType:      k8s.Pod,
+		FromName:      pod,
+	})
+	if err != nil {
+		return err
+	}
+
+	output, err := requestStatsFromAPI(validatedPublicAPIClient(), req, newStatOptions())
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Print(output)
+	return err
+}
+
+// waitForLoadJob waits for the load container of job's pod to exit, and
+// returns the name of the pod.
+func waitForLoadJob(ctx context.Context, clientset kubernetes.Interface, job *batchV1.Job, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(loadPollInterval)
+	defer ticker.Stop()
+
+	for {
+		pods, err := clientset.CoreV1().Pods(job.Namespace).List(metaV1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(labels.Set{"job-name": job.Name}).String(),
+		})
+		if err != nil {
+			return "", fmt.Errorf("error listing pods of job [%s/%s]: %v", job.Namespace, job.Name, err)
+		}
+		for _, pod := range pods.Items {
+			for _, status := range pod.Status.ContainerStatuses {
+				if status.Name != loadContainerName || status.State.Terminated == nil {
+					continue
+				}
+				if code := status.State.Terminated.ExitCode; code != 0 {
+					return "", fmt.Errorf("load job [%s/%s] failed with exit code %d", job.Namespace, job.Name, code)
+				}
+				return pod.Name, nil
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return "", fmt.Errorf("load job [%s/%s] did not finish within %s", job.Namespace, job.Name, timeout)
+			}
+			return "", ctx.Err()
+		}
+	}
+}
+
+func deleteLoadJob(clientset kubernetes.Interface, job *batchV1.Job) {
+	propagation := metaV1.DeletePropagationBackground
+	err := clientset.BatchV1().Jobs(job.Namespace).Delete(job.Name, &metaV1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error deleting load job [%s/%s]: %v\n", job.Namespace, job.Name, err)
+	}
+}
+
+// loadTimeWindow returns a stat time window that covers duration, in whole
+// seconds since Prometheus doesn't accept durations with several units.
+func loadTimeWindow(duration time.Duration) string {
+	return fmt.Sprintf("%ds", int64(duration/time.Second))
+}
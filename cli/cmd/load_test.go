@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	appsV1 "k8s.io/api/apps/v1"
+	batchV1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLoadOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		resourceType  string
+		modify        func(*loadOptions)
+		expectedError string
+	}{
+		{k8s.Deployment, func(*loadOptions) {}, ""},
+		{k8s.Service, func(*loadOptions) {}, ""},
+		{k8s.Pod, func(*loadOptions) {}, "unsupported resource type [pod]; load can be sent to a deployment or a service"},
+		{k8s.Deployment, func(o *loadOptions) { o.rps = 0 }, "--rps must be greater than 0"},
+		{k8s.Deployment, func(o *loadOptions) { o.duration = time.Millisecond }, "--duration must be at least 1s"},
+		{k8s.Deployment, func(o *loadOptions) { o.path = "healthz" }, "--path must start with /"},
+	}
+
+	for _, tc := range testCases {
+		options := newLoadOptions()
+		tc.modify(options)
+
+		err := options.validate(tc.resourceType)
+		if tc.expectedError == "" {
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.expectedError {
+			t.Errorf("Expected error [%s] instead got [%s]", tc.expectedError, err)
+		}
+	}
+}
+
+func TestLoadTargetURL(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&appsV1.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web", Namespace: "emojivoto"},
+			Spec: appsV1.DeploymentSpec{
+				Template: v1.PodTemplateSpec{
+					ObjectMeta: metaV1.ObjectMeta{Labels: map[string]string{"app": "web-svc", "version": "v1"}},
+				},
+			},
+		},
+		&appsV1.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Name: "vote-bot", Namespace: "emojivoto"},
+		},
+		&v1.Service{
+			ObjectMeta: metaV1.ObjectMeta{Name: "emoji-svc", Namespace: "emojivoto"},
+			Spec: v1.ServiceSpec{
+				Selector: map[string]string{"app": "emoji-svc"},
+				Ports:    []v1.ServicePort{{Port: 8080}},
+			},
+		},
+		&v1.Service{
+			ObjectMeta: metaV1.ObjectMeta{Name: "web-svc", Namespace: "emojivoto"},
+			Spec: v1.ServiceSpec{
+				Selector: map[string]string{"app": "web-svc"},
+				Ports:    []v1.ServicePort{{Port: 80}, {Port: 9090}},
+			},
+		},
+	)
+
+	t.Run("Sends requests to the service that selects a deployment", func(t *testing.T) {
+		options := newLoadOptions()
+		options.path = "/healthz"
+
+		url, err := loadTargetURL(clientset, k8s.Deployment, "emojivoto", "web", options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := "http://web-svc.emojivoto.svc.cluster.local:80/healthz"
+		if url != expected {
+			t.Fatalf("Expected [%s], got [%s]", expected, url)
+		}
+	})
+
+	t.Run("Sends requests to a service port", func(t *testing.T) {
+		options := newLoadOptions()
+		options.port = 9090
+
+		url, err := loadTargetURL(clientset, k8s.Service, "emojivoto", "web-svc", options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := "http://web-svc.emojivoto.svc.cluster.local:9090/"
+		if url != expected {
+			t.Fatalf("Expected [%s], got [%s]", expected, url)
+		}
+	})
+
+	t.Run("Returns an error if no service selects a deployment", func(t *testing.T) {
+		_, err := loadTargetURL(clientset, k8s.Deployment, "emojivoto", "vote-bot", newLoadOptions())
+		expectedError := "no service selects the pods of deployment [emojivoto/vote-bot]"
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+}
+
+func TestNewLoadJob(t *testing.T) {
+	options := newLoadOptions()
+	options.rps = 50
+	options.duration = 2 * time.Minute
+
+	job, err := newLoadJob("emojivoto", "http://web-svc.emojivoto.svc.cluster.local:80/healthz", options)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if job.Namespace != "emojivoto" {
+		t.Errorf("Expected the job to be in namespace emojivoto, got [%s]", job.Namespace)
+	}
+	if *job.Spec.ActiveDeadlineSeconds != 420 {
+		t.Errorf("Expected a deadline of 420 seconds, got %d", *job.Spec.ActiveDeadlineSeconds)
+	}
+
+	containers := map[string]v1.Container{}
+	for _, c := range job.Spec.Template.Spec.Containers {
+		containers[c.Name] = c
+	}
+	if _, ok := containers["linkerd-proxy"]; !ok {
+		t.Errorf("Expected the job to be injected with the proxy, got containers %v", job.Spec.Template.Spec.Containers)
+	}
+	expectedCommand := []string{
+		"slow_cooker", "-qps", "50", "-concurrency", "1", "-totalRequests", "6000",
+		"http://web-svc.emojivoto.svc.cluster.local:80/healthz",
+	}
+	if command := containers[loadContainerName].Command; !reflect.DeepEqual(command, expectedCommand) {
+		t.Errorf("Expected command %v, got %v", expectedCommand, command)
+	}
+}
+
+func TestWaitForLoadJob(t *testing.T) {
+	job := &batchV1.Job{ObjectMeta: metaV1.ObjectMeta{Name: "linkerd-load-abc", Namespace: "emojivoto"}}
+	pod := func(exitCode int32) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      "linkerd-load-abc-xyz",
+				Namespace: "emojivoto",
+				Labels:    map[string]string{"job-name": "linkerd-load-abc"},
+			},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{
+					{Name: "linkerd-proxy", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+					{Name: loadContainerName, State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: exitCode}}},
+				},
+			},
+		}
+	}
+
+	t.Run("Returns the pod once the load container exits", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(pod(0))
+
+		name, err := waitForLoadJob(context.Background(), clientset, job, time.Second)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if name != "linkerd-load-abc-xyz" {
+			t.Fatalf("Expected pod [linkerd-load-abc-xyz], got [%s]", name)
+		}
+	})
+
+	t.Run("Returns an error if the load container fails", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(pod(1))
+
+		_, err := waitForLoadJob(context.Background(), clientset, job, time.Second)
+		expectedError := "load job [emojivoto/linkerd-load-abc] failed with exit code 1"
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Returns an error if the job doesn't finish in time", func(t *testing.T) {
+		loadPollInterval = time.Millisecond
+		defer func() { loadPollInterval = 2 * time.Second }()
+		clientset := fake.NewSimpleClientset()
+
+		_, err := waitForLoadJob(context.Background(), clientset, job, 10*time.Millisecond)
+		expectedError := "load job [emojivoto/linkerd-load-abc] did not finish within 10ms"
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+}
+
+func TestLoadTimeWindow(t *testing.T) {
+	for duration, expected := range map[time.Duration]string{
+		30 * time.Second:                 "30s",
+		2 * time.Minute:                  "120s",
+		time.Hour + 500*time.Millisecond: "3600s",
+	} {
+		if window := loadTimeWindow(duration); window != expected {
+			t.Errorf("Expected time window [%s] for %s, got [%s]", expected, duration, window)
+		}
+	}
+}
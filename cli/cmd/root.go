@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"regexp"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
@@ -17,11 +19,30 @@ import (
 	"github.com/spf13/cobra"
 )
 
-const defaultNamespace = "linkerd"
+const (
+	defaultNamespace = "linkerd"
+
+	// viaKubeAPI and viaPortForward are the supported values of the --via
+	// flag, which selects how the CLI reaches the public API.
+	viaKubeAPI     = "kube-api"
+	viaPortForward = "port-forward"
+
+	controllerDeployment = "controller"
+	publicAPIPort        = 8085
+
+	// interruptedExitCode is the exit code of a command ended by Ctrl-C,
+	// following the shell convention of 128 + SIGINT.
+	interruptedExitCode = 130
+
+	// interruptGracePeriod is how long an interrupted command has to clean up
+	// before the CLI exits anyway; a second Ctrl-C exits right away.
+	interruptGracePeriod = 5 * time.Second
+)
 
 var controlPlaneNamespace string
 var apiAddr string // An empty value means "use the Kubernetes configuration"
 var kubeconfigPath string
+var apiVia string
 var verbose bool
 
 var (
@@ -48,6 +69,10 @@ var RootCmd = &cobra.Command{
 			return fmt.Errorf("%s is not a valid namespace", controlPlaneNamespace)
 		}
 
+		if apiVia != viaKubeAPI && apiVia != viaPortForward {
+			return fmt.Errorf("--via must be one of: %s, %s", viaKubeAPI, viaPortForward)
+		}
+
 		return nil
 	},
 }
@@ -56,8 +81,11 @@ func init() {
 	RootCmd.PersistentFlags().StringVarP(&controlPlaneNamespace, "linkerd-namespace", "l", defaultNamespace, "Namespace in which Linkerd is installed")
 	RootCmd.PersistentFlags().StringVar(&kubeconfigPath, "kubeconfig", "", "Path to the kubeconfig file to use for CLI requests")
 	RootCmd.PersistentFlags().StringVar(&apiAddr, "api-addr", "", "Override kubeconfig and communicate directly with the control plane at host:port (mostly for testing)")
+	RootCmd.PersistentFlags().StringVar(&apiVia, "via", viaKubeAPI,
+		fmt.Sprintf("How to reach the control plane's public API; one of: %s (through the Kubernetes API server's service proxy), %s (through a port-forward to the controller pod)", viaKubeAPI, viaPortForward))
 	RootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Turn on debug logging")
 
+	RootCmd.AddCommand(newCmdAlpha())
 	RootCmd.AddCommand(newCmdCheck())
 	RootCmd.AddCommand(newCmdCompletion())
 	RootCmd.AddCommand(newCmdDashboard())
@@ -65,6 +93,7 @@ func init() {
 	RootCmd.AddCommand(newCmdInject())
 	RootCmd.AddCommand(newCmdInstall())
 	RootCmd.AddCommand(newCmdStat())
+	RootCmd.AddCommand(newCmdStatus())
 	RootCmd.AddCommand(newCmdTap())
 	RootCmd.AddCommand(newCmdVersion())
 }
@@ -135,9 +164,113 @@ func newPublicAPIClient() (pb.ApiClient, error) {
 		}
 	}
 
+	if apiVia == viaPortForward {
+		return newPortForwardPublicAPIClient()
+	}
+
 	return public.NewExternalClient(controlPlaneNamespace, kubeAPI)
 }
 
+// newPortForwardPublicAPIClient port-forwards a local port to the public API
+// on the controller pod, for clusters where the API server's service proxy is
+// blocked. The port-forward lasts for the lifetime of the CLI process.
+func newPortForwardPublicAPIClient() (pb.ApiClient, error) {
+	pf, err := k8s.NewPortForward(kubeconfigPath, controlPlaneNamespace, controllerDeployment, 0, publicAPIPort)
+	if err != nil {
+		return nil, err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- pf.Run()
+	}()
+
+	select {
+	case <-pf.Ready():
+	case err := <-errCh:
+		return nil, fmt.Errorf("error port-forwarding to the public API: %s", err)
+	case <-time.After(30 * time.Second):
+		pf.Stop()
+		return nil, fmt.Errorf("timed out port-forwarding to the public API")
+	}
+
+	return public.NewInternalClient(controlPlaneNamespace, pf.AddressAndPort())
+}
+
+// runInterruptible runs fn with a context that's canceled on Ctrl-C or
+// SIGTERM, so that streaming commands close their streams and run their
+// deferred cleanup, such as flushing output files, rather than being killed
+// mid-write. If it was interrupted, the CLI exits once fn returns, or after
+// interruptGracePeriod if fn is stuck in a call that ignores ctx.
+func runInterruptible(fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	interrupted := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			close(interrupted)
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+
+		select {
+		case <-signals:
+		case <-time.After(interruptGracePeriod):
+		}
+		os.Exit(interruptedExitCode)
+	}()
+
+	err := fn(ctx)
+
+	select {
+	case <-interrupted:
+		os.Exit(interruptedExitCode)
+	default:
+	}
+	return err
+}
+
+// validatedTapAPIClient returns a client for the tap APIService, which taps
+// with the RBAC permissions of the current Kubernetes user. The APIService is
+// only reachable through the Kubernetes API, so tap isn't available with
+// --api-addr or --via=port-forward, which would bypass that authorization.
+func validatedTapAPIClient() public.TapAPIClient {
+	if apiAddr != "" || apiVia == viaPortForward {
+		fmt.Fprintf(os.Stderr, "Tap is only available through the Kubernetes API; it can't be used with --api-addr or --via=%s\n", viaPortForward)
+		os.Exit(1)
+	}
+
+	client, err := newTapAPIClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot connect to Kubernetes: %s\n", err)
+		os.Exit(1)
+	}
+
+	return client
+}
+
+func newTapAPIClient() (public.TapAPIClient, error) {
+	kubeAPI, err := k8s.NewAPI(kubeconfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range kubeAPI.SelfCheck() {
+		if result.Status != healthcheckPb.CheckStatus_OK {
+			return nil, fmt.Errorf(result.FriendlyMessageToUser)
+		}
+	}
+
+	return public.NewExternalTapClient(controlPlaneNamespace, kubeAPI)
+}
+
 type proxyConfigOptions struct {
 	linkerdVersion        string
 	proxyImage            string
@@ -152,11 +285,15 @@ type proxyConfigOptions struct {
 	proxyMetricsPort      uint
 	proxyOutboundCapacity map[string]uint
 	tls                   string
+	clusterDomain         string
+	seccompProfile        string
+	appArmorProfile       string
 }
 
 const (
 	optionalTLS           = "optional"
 	defaultDockerRegistry = "gcr.io/linkerd-io"
+	defaultClusterDomain  = "cluster.local"
 )
 
 func newProxyConfigOptions() *proxyConfigOptions {
@@ -174,6 +311,9 @@ func newProxyConfigOptions() *proxyConfigOptions {
 		proxyMetricsPort:      4191,
 		proxyOutboundCapacity: map[string]uint{},
 		tls: "",
+		clusterDomain:         defaultClusterDomain,
+		seccompProfile:        "",
+		appArmorProfile:       "",
 	}
 }
 
@@ -190,12 +330,40 @@ func (options *proxyConfigOptions) validate() error {
 	if _, err := time.ParseDuration(options.proxyBindTimeout); err != nil {
 		return fmt.Errorf("Invalid duration '%s' for --proxy-bind-timeout flag", options.proxyBindTimeout)
 	}
+	if !alphaNumDashDot.MatchString(options.clusterDomain) || strings.HasPrefix(options.clusterDomain, ".") || strings.HasSuffix(options.clusterDomain, ".") {
+		return fmt.Errorf("%s is not a valid cluster domain", options.clusterDomain)
+	}
+	if !validSeccompProfile(options.seccompProfile) {
+		return fmt.Errorf("--seccomp-profile must be blank, \"runtime/default\", \"docker/default\", \"unconfined\" or \"localhost/<profile>\"")
+	}
+	if !validAppArmorProfile(options.appArmorProfile) {
+		return fmt.Errorf("--apparmor-profile must be blank, \"runtime/default\", \"unconfined\" or \"localhost/<profile>\"")
+	}
 	if options.tls != "" && options.tls != optionalTLS {
 		return fmt.Errorf("--tls must be blank or set to \"%s\"", optionalTLS)
 	}
 	return nil
 }
 
+// validSeccompProfile returns true if profile is blank or is in one of the
+// forms accepted by the seccomp container annotation.
+func validSeccompProfile(profile string) bool {
+	if profile == "docker/default" {
+		return true
+	}
+	return validAppArmorProfile(profile)
+}
+
+// validAppArmorProfile returns true if profile is blank or is in one of the
+// forms accepted by the AppArmor container annotation.
+func validAppArmorProfile(profile string) bool {
+	switch profile {
+	case "", "runtime/default", "unconfined":
+		return true
+	}
+	return strings.HasPrefix(profile, "localhost/") && len(profile) > len("localhost/")
+}
+
 func (options *proxyConfigOptions) enableTLS() bool {
 	return options.tls == optionalTLS
 }
@@ -223,4 +391,7 @@ func addProxyConfigFlags(cmd *cobra.Command, options *proxyConfigOptions) {
 	cmd.PersistentFlags().UintVar(&options.proxyControlPort, "control-port", options.proxyControlPort, "Proxy port to use for control")
 	cmd.PersistentFlags().UintVar(&options.proxyMetricsPort, "metrics-port", options.proxyMetricsPort, "Proxy port to serve metrics on")
 	cmd.PersistentFlags().StringVar(&options.tls, "tls", options.tls, "Enable TLS; valid settings: \"optional\"")
+	cmd.PersistentFlags().StringVar(&options.clusterDomain, "cluster-domain", options.clusterDomain, "Kubernetes DNS domain of the cluster")
+	cmd.PersistentFlags().StringVar(&options.seccompProfile, "seccomp-profile", options.seccompProfile, "Seccomp profile for the proxy and init containers (for example: \"runtime/default\")")
+	cmd.PersistentFlags().StringVar(&options.appArmorProfile, "apparmor-profile", options.appArmorProfile, "AppArmor profile for the proxy and init containers (for example: \"runtime/default\")")
 }
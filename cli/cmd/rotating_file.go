@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	megabyte = 1024 * 1024
+
+	// rotatedFileTimeFormat is the format of the timestamp that a rotated file
+	// is renamed with, e.g. "tap.log" becomes "tap.20181014T120000.000.log".
+	// Files rotated within the same millisecond get a sequence number, e.g.
+	// "tap.20181014T120000.000_1.log", which sorts after the first one.
+	rotatedFileTimeFormat = "20060102T150405.000"
+)
+
+// rotatingFile writes to the file at path until it reaches maxSize bytes or
+// was opened maxAge ago, and then renames it after the time it was rotated
+// and carries on in a new file at path. A limit of 0 disables it. Files are
+// only rotated at the start of a line, so each file holds whole lines.
+type rotatingFile struct {
+	path    string
+	maxSize int64
+	maxAge  time.Duration
+
+	file        *os.File
+	size        int64
+	opened      time.Time
+	atLineStart bool
+
+	now func() time.Time
+}
+
+func newRotatingFile(path string, maxSize int64, maxAge time.Duration) (*rotatingFile, error) {
+	f := &rotatingFile{
+		path:    path,
+		maxSize: maxSize,
+		maxAge:  maxAge,
+		now:     time.Now,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if f.atLineStart && f.due() {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	f.atLineStart = n > 0 && p[n-1] == '\n'
+	return n, err
+}
+
+func (f *rotatingFile) Close() error {
+	return f.file.Close()
+}
+
+// due returns true if the current file has reached one of its limits.
+func (f *rotatingFile) due() bool {
+	if f.maxSize > 0 && f.size >= f.maxSize {
+		return true
+	}
+	return f.maxAge > 0 && f.now().Sub(f.opened) >= f.maxAge
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+
+	ext := filepath.Ext(f.path)
+	base := fmt.Sprintf("%s.%s", strings.TrimSuffix(f.path, ext), f.now().UTC().Format(rotatedFileTimeFormat))
+	rotated := base + ext
+	for i := 1; fileExists(rotated); i++ {
+		rotated = fmt.Sprintf("%s_%d%s", base, i, ext)
+	}
+	if err := os.Rename(f.path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate %s: %s", f.path, err)
+	}
+	log.Debugf("Rotated %s to %s", f.path, rotated)
+
+	return f.open()
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.Create(f.path)
+	if err != nil {
+		return err
+	}
+
+	f.file = file
+	f.size = 0
+	f.opened = f.now()
+	f.atLineStart = true
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
@@ -3,20 +3,28 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
 	"sort"
 	"strings"
 	"text/tabwriter"
 	"time"
+	"unicode/utf8"
 
+	"github.com/ghodss/yaml"
 	"github.com/linkerd/linkerd2/controller/api/util"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/format"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+const yamlOutput = "yaml"
+
 type statOptions struct {
 	namespace     string
 	timeWindow    string
@@ -25,6 +33,12 @@ type statOptions struct {
 	fromNamespace string
 	fromResource  string
 	allNamespaces bool
+	groupByLabel  string
+	watch         bool
+	watchInterval time.Duration
+	tree          bool
+	columns       []string
+	output        string
 }
 
 func newStatOptions() *statOptions {
@@ -36,6 +50,12 @@ func newStatOptions() *statOptions {
 		fromNamespace: "",
 		fromResource:  "",
 		allNamespaces: false,
+		groupByLabel:  "",
+		watch:         false,
+		watchInterval: 2 * time.Second,
+		tree:          false,
+		columns:       nil,
+		output:        "",
 	}
 }
 
@@ -48,7 +68,7 @@ func newCmdStat() *cobra.Command {
 		Long: `Display traffic stats about one or many resources.
 
   The RESOURCE argument specifies the target resource(s) to aggregate stats over:
-  (TYPE [NAME] | TYPE/NAME)
+  (TYPE [NAME] | TYPE/NAME | a comma-separated list of TYPE and TYPE/NAME)
 
   Examples:
   * deploy
@@ -57,6 +77,9 @@ func newCmdStat() *cobra.Command {
   * ns/my-ns
   * authority
   * au/my-authority
+  * ing/my-ingress
+  * deploy/my-deploy,deploy/my-other-deploy
+  * deploy,rc
   * all
 
 Valid resource types include:
@@ -66,6 +89,7 @@ Valid resource types include:
   * pods
   * replicationcontrollers
   * authorities (not supported in --from)
+  * ingresses (a row for each host of each ingress, not supported in --from or --to)
   * services (only supported if a --from is also specified, or as a --to)
   * all (all resource types, not supported in --from or --to)
 
@@ -83,6 +107,12 @@ If no resource name is specified, displays stats about all resources of the spec
   # Get all inbound stats to the web deployment.
   linkerd stat deploy/web
 
+  # Compare the web and api deployments in a single table.
+  linkerd stat deploy/web,deploy/api
+
+  # Get all deployments and replication controllers in the test namespace.
+  linkerd stat deploy,rc -n test
+
   # Get all pods in all namespaces that call the hello1 deployment in the test namesapce.
   linkerd stat pods --to deploy/hello1 --to-namespace test --all-namespaces
 
@@ -97,6 +127,33 @@ If no resource name is specified, displays stats about all resources of the spec
 
   # Get all inbound stats to the test namespace.
   linkerd stat ns/test
+
+  # Get the stats of the requests for each host of each ingress in the test namespace.
+  linkerd stat ingresses -n test
+
+  # Get inbound stats for each team, across all deployments labeled with a team.
+  linkerd stat deploy --group-by-label team --all-namespaces
+
+  # Continuously refresh deployment stats, with sparklines of recent success rate and request rate.
+  linkerd stat deploy -n test --watch
+
+  # Print a line of JSON with the deployments whose stats changed, every 10 seconds.
+  linkerd stat deploy -n test --watch --watch-interval 10s -o json
+
+  # Get all namespaces, with the stats of each namespace's deployments nested beneath it.
+  linkerd stat namespaces --tree
+
+  # Get the success rate and P99 latency of all deployments in the test namespace.
+  linkerd stat deploy -n test --columns success,latency_p99
+
+  # Get the success rate of each deployment in the test namespace, for scripts.
+  linkerd stat deploy -n test -o jsonpath='{range .rows[*]}{.name} {.successRate}{"\n"}{end}'
+
+  # Get the stats of all deployments in the test namespace as YAML.
+  linkerd stat deploy -n test -o yaml
+
+  # Compare the stats of the last minute, 10 minutes and hour side by side.
+  linkerd stat deploy -n test --time-window 1m,10m,1h
   `,
 		Args:      cobra.RangeArgs(1, 2),
 		ValidArgs: util.ValidTargets,
@@ -106,7 +163,36 @@ If no resource name is specified, displays stats about all resources of the spec
 				return fmt.Errorf("error creating metrics request while making stats request: %v", err)
 			}
 
-			output, err := requestStatsFromAPI(validatedPublicAPIClient(), req, options)
+			if options.watch {
+				watch := watchStats
+				if options.output == jsonOutput {
+					watch = watchStatsJSON
+				}
+				return runInterruptible(func(ctx context.Context) error {
+					return watch(ctx, os.Stdout, validatedPublicAPIClient(), req, options)
+				})
+			}
+
+			if options.output != "" {
+				resp, err := requestStatSummary(validatedPublicAPIClient(), req)
+				if err != nil {
+					return err
+				}
+				return renderStatOutput(os.Stdout, resp, options)
+			}
+
+			if statResourceType(req) == k8s.All {
+				return runInterruptible(func(ctx context.Context) error {
+					return requestStatStreamFromAPI(ctx, os.Stdout, validatedPublicAPIClient(), req, options)
+				})
+			}
+
+			var output string
+			if options.tree {
+				output, err = requestStatTreeFromAPI(validatedPublicAPIClient(), req, options)
+			} else {
+				output, err = requestStatsFromAPI(validatedPublicAPIClient(), req, options)
+			}
 			if err != nil {
 				return err
 			}
@@ -118,26 +204,211 @@ If no resource name is specified, displays stats about all resources of the spec
 	}
 
 	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the specified resource")
-	cmd.PersistentFlags().StringVarP(&options.timeWindow, "time-window", "t", options.timeWindow, "Stat window (for example: \"10s\", \"1m\", \"10m\", \"1h\")")
+	cmd.PersistentFlags().StringVarP(&options.timeWindow, "time-window", "t", options.timeWindow, "Stat window (for example: \"10s\", \"1m\", \"7m\", \"1h\"), or a comma-separated list of windows to show side by side")
 	cmd.PersistentFlags().StringVar(&options.toResource, "to", options.toResource, "If present, restricts outbound stats to the specified resource name")
 	cmd.PersistentFlags().StringVar(&options.toNamespace, "to-namespace", options.toNamespace, "Sets the namespace used to lookup the \"--to\" resource; by default the current \"--namespace\" is used")
 	cmd.PersistentFlags().StringVar(&options.fromResource, "from", options.fromResource, "If present, restricts outbound stats from the specified resource name")
 	cmd.PersistentFlags().StringVar(&options.fromNamespace, "from-namespace", options.fromNamespace, "Sets the namespace used from lookup the \"--from\" resource; by default the current \"--namespace\" is used")
 	cmd.PersistentFlags().BoolVar(&options.allNamespaces, "all-namespaces", options.allNamespaces, "If present, returns stats across all namespaces, ignoring the \"--namespace\" flag")
+	cmd.PersistentFlags().StringVar(&options.groupByLabel, "group-by-label", options.groupByLabel, "If present, aggregates stats across all resources that share a value of this pod label, which must be added to metrics with \"linkerd install --pod-labels\"")
+	cmd.PersistentFlags().BoolVarP(&options.watch, "watch", "w", options.watch, "Continuously refresh stats, rendering sparklines of recent success rate and request rate; with \"-o json\", print the rows that changed instead")
+	cmd.PersistentFlags().DurationVar(&options.watchInterval, "watch-interval", options.watchInterval, "How often to refresh stats when \"--watch\" is set")
+	cmd.PersistentFlags().BoolVar(&options.tree, "tree", options.tree, "If present with namespaces, nests the stats of each namespace's deployments beneath the namespace's rollup row")
+	cmd.PersistentFlags().StringSliceVar(&options.columns, "columns", options.columns, fmt.Sprintf("Columns to show after the resource name, in order; any of: %s (all if empty)", strings.Join(defaultStatColumns, ", ")))
+	cmd.PersistentFlags().StringVarP(&options.output, "output", "o", options.output, fmt.Sprintf("Output format; one of: \"%s\", \"%s\" or \"%sTEMPLATE\"", jsonOutput, yamlOutput, jsonpathOutputPrefix))
 
 	return cmd
 }
 
 func requestStatsFromAPI(client pb.ApiClient, req *pb.StatSummaryRequest, options *statOptions) (string, error) {
+	resp, err := requestStatSummary(client, req)
+	if err != nil {
+		return "", err
+	}
+
+	return renderStats(resp, statResourceType(req), options), nil
+}
+
+// requestStatStreamFromAPI requests stats for every resource type, and writes
+// the table of each type as soon as it arrives, so that the tables of quick
+// resource types aren't held up by slower ones.
+func requestStatStreamFromAPI(ctx context.Context, w io.Writer, client pb.ApiClient, req *pb.StatSummaryRequest, options *statOptions) error {
+	stream, err := client.StatSummaryStream(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	printed := false
+	for {
+		rsp, err := stream.Recv()
+		if err == io.EOF || (err != nil && ctx.Err() != nil) {
+			// the tables that arrived before an interrupt are still shown
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if e := rsp.GetError(); e != nil {
+			return fmt.Errorf(e.Error)
+		}
+
+		for _, table := range rsp.GetOk().GetStatTables() {
+			if len(table.GetPodGroup().GetRows()) == 0 {
+				continue
+			}
+			if printed {
+				fmt.Fprint(w, "\n")
+			}
+			printed = true
+			fmt.Fprint(w, renderStats(statSummaryOk(table), k8s.All, options))
+		}
+	}
+
+	if !printed && ctx.Err() == nil {
+		fmt.Fprintln(os.Stderr, "No traffic found.")
+		os.Exit(0)
+	}
+	return nil
+}
+
+// statSummaryOk wraps tables in a successful response, for rendering.
+func statSummaryOk(tables ...*pb.StatTable) *pb.StatSummaryResponse {
+	return &pb.StatSummaryResponse{
+		Response: &pb.StatSummaryResponse_Ok_{
+			Ok: &pb.StatSummaryResponse_Ok{StatTables: tables},
+		},
+	}
+}
+
+// statJSON is the JSON output of stat. Rates are fractions between 0 and 1,
+// and stats are null for resources without traffic.
+type statJSON struct {
+	Rows []*statJSONRow `json:"rows"`
+}
+
+type statJSONRow struct {
+	Type            string   `json:"type"`
+	Namespace       string   `json:"namespace"`
+	Name            string   `json:"name"`
+	TimeWindow      string   `json:"timeWindow"`
+	MeshedPods      *uint64  `json:"meshedPods"`
+	RunningPods     *uint64  `json:"runningPods"`
+	FailedPods      *uint64  `json:"failedPods"`
+	SuccessRate     *float64 `json:"successRate"`
+	RequestRate     *float64 `json:"requestRate"`
+	TLSRate         *float64 `json:"tlsRate"`
+	SuccessCount    *uint64  `json:"successCount"`
+	FailureCount    *uint64  `json:"failureCount"`
+	TLSRequestCount *uint64  `json:"tlsRequestCount"`
+	LatencyMsP50    *uint64  `json:"latencyMsP50"`
+	LatencyMsP95    *uint64  `json:"latencyMsP95"`
+	LatencyMsP99    *uint64  `json:"latencyMsP99"`
+}
+
+// newStatJSON converts the rows of resp, sorted by namespace and name within
+// each resource type. Rows of the same resource over several time windows are
+// kept in the order of the windows.
+func newStatJSON(resp *pb.StatSummaryResponse, options *statOptions) *statJSON {
+	out := &statJSON{Rows: []*statJSONRow{}}
+	for _, statTable := range resp.GetOk().GetStatTables() {
+		rows := statTable.GetPodGroup().GetRows()
+		tableRows := make([]*statJSONRow, 0, len(rows))
+		for _, r := range rows {
+			jsonRow := &statJSONRow{
+				Type:       r.Resource.Type,
+				Namespace:  r.Resource.Namespace,
+				Name:       r.Resource.Name,
+				TimeWindow: r.TimeWindow,
+			}
+			if hasPodCounts(r.Resource.Type) && options.groupByLabel == "" {
+				meshed, running, failed := r.MeshedPodCount, r.RunningPodCount, r.FailedPodCount
+				jsonRow.MeshedPods, jsonRow.RunningPods, jsonRow.FailedPods = &meshed, &running, &failed
+			}
+			if r.Stats != nil {
+				successRate, requestRate, tlsRate := getSuccessRate(*r), getRequestRate(*r), getPercentTls(*r)
+				jsonRow.SuccessRate, jsonRow.RequestRate, jsonRow.TLSRate = &successRate, &requestRate, &tlsRate
+				jsonRow.SuccessCount = &r.Stats.SuccessCount
+				jsonRow.FailureCount = &r.Stats.FailureCount
+				jsonRow.TLSRequestCount = &r.Stats.TlsRequestCount
+				jsonRow.LatencyMsP50 = &r.Stats.LatencyMsP50
+				jsonRow.LatencyMsP95 = &r.Stats.LatencyMsP95
+				jsonRow.LatencyMsP99 = &r.Stats.LatencyMsP99
+			}
+			tableRows = append(tableRows, jsonRow)
+		}
+		sort.SliceStable(tableRows, func(i, j int) bool {
+			if tableRows[i].Namespace != tableRows[j].Namespace {
+				return tableRows[i].Namespace < tableRows[j].Namespace
+			}
+			return tableRows[i].Name < tableRows[j].Name
+		})
+		out.Rows = append(out.Rows, tableRows...)
+	}
+	return out
+}
+
+// renderStatOutput writes the rows of resp in the machine-readable format
+// selected by --output. YAML is converted from the JSON, so both formats have
+// the same field names.
+func renderStatOutput(w io.Writer, resp *pb.StatSummaryResponse, options *statOptions) error {
+	stats := newStatJSON(resp, options)
+	if options.output != yamlOutput {
+		return renderJSON(w, options.output, stats)
+	}
+
+	b, err := yaml.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// requestStatTreeFromAPI requests stats for the namespaces selected by req and
+// for the deployments in them, and renders each namespace's deployments
+// beneath the namespace.
+func requestStatTreeFromAPI(client pb.ApiClient, req *pb.StatSummaryRequest, options *statOptions) (string, error) {
+	nsResp, err := requestStatSummary(client, req)
+	if err != nil {
+		return "", err
+	}
+
+	namespace := req.Selector.Resource.Name
+	deployReq, err := util.BuildStatSummaryRequest(util.StatSummaryRequestParams{
+		TimeWindow:    options.timeWindow,
+		ResourceType:  k8s.Deployment,
+		Namespace:     namespace,
+		AllNamespaces: namespace == "",
+	})
+	if err != nil {
+		return "", err
+	}
+	deployResp, err := requestStatSummary(client, deployReq)
+	if err != nil {
+		return "", err
+	}
+
+	var buffer bytes.Buffer
+	w := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', tabwriter.AlignRight)
+	writeStatTreeToBuffer(nsResp, deployResp, w, options)
+	w.Flush()
+
+	// strip left padding on the first column
+	out := string(buffer.Bytes()[padding:])
+	out = strings.Replace(out, "\n"+strings.Repeat(" ", padding), "\n", -1)
+
+	return out, nil
+}
+
+func requestStatSummary(client pb.ApiClient, req *pb.StatSummaryRequest) (*pb.StatSummaryResponse, error) {
 	resp, err := client.StatSummary(context.Background(), req)
 	if err != nil {
-		return "", fmt.Errorf("StatSummary API error: %v", err)
+		return nil, fmt.Errorf("StatSummary API error: %v", err)
 	}
 	if e := resp.GetError(); e != nil {
-		return "", fmt.Errorf("StatSummary API response error: %v", e.Error)
+		return nil, fmt.Errorf("StatSummary API response error: %v", e.Error)
 	}
-
-	return renderStats(resp, req.Selector.Resource.Type, options), nil
+	return resp, nil
 }
 
 func renderStats(resp *pb.StatSummaryResponse, resourceType string, options *statOptions) string {
@@ -167,6 +438,10 @@ type rowStats struct {
 type row struct {
 	meshed string
 	*rowStats
+
+	// windowStats holds the stats of each time window, keyed by the window,
+	// when several windows are shown side by side.
+	windowStats map[string]*rowStats
 }
 
 var (
@@ -178,6 +453,7 @@ func writeStatsToBuffer(resp *pb.StatSummaryResponse, reqResourceType string, w
 	maxNameLength := len(nameHeader)
 	maxNamespaceLength := len(namespaceHeader)
 	statTables := make(map[string]map[string]*row)
+	var resourceTypes []string // in the order of the response
 
 	for _, statTable := range resp.GetOk().StatTables {
 		table := statTable.GetPodGroup()
@@ -195,6 +471,7 @@ func writeStatsToBuffer(resp *pb.StatSummaryResponse, reqResourceType string, w
 
 			if _, ok := statTables[resourceKey]; !ok {
 				statTables[resourceKey] = make(map[string]*row)
+				resourceTypes = append(resourceTypes, resourceKey)
 			}
 
 			if len(nameWithPrefix) > maxNameLength {
@@ -205,24 +482,13 @@ func writeStatsToBuffer(resp *pb.StatSummaryResponse, reqResourceType string, w
 				maxNamespaceLength = len(namespace)
 			}
 
-			meshedCount := fmt.Sprintf("%d/%d", r.MeshedPodCount, r.RunningPodCount)
-			if resourceKey == k8s.Authority {
-				meshedCount = "-"
-			}
-			statTables[resourceKey][key] = &row{
-				meshed: meshedCount,
-			}
-
-			if r.Stats != nil {
-				statTables[resourceKey][key].rowStats = &rowStats{
-					requestRate: getRequestRate(*r),
-					successRate: getSuccessRate(*r),
-					tlsPercent:  getPercentTls(*r),
-					latencyP50:  r.Stats.LatencyMsP50,
-					latencyP95:  r.Stats.LatencyMsP95,
-					latencyP99:  r.Stats.LatencyMsP99,
-				}
+			statRow := newRow(r, options.groupByLabel != "")
+			if existing, ok := statTables[resourceKey][key]; ok {
+				existing.windowStats[r.TimeWindow] = statRow.rowStats
+				continue
 			}
+			statRow.windowStats = map[string]*rowStats{r.TimeWindow: statRow.rowStats}
+			statTables[resourceKey][key] = statRow
 		}
 	}
 
@@ -234,14 +500,12 @@ func writeStatsToBuffer(resp *pb.StatSummaryResponse, reqResourceType string, w
 	switch reqResourceType {
 	case k8s.All:
 		firstDisplayedStat := true // don't print a newline before the first stat
-		for _, resourceType := range k8s.StatAllResourceTypes {
-			if stats, ok := statTables[resourceType]; ok {
-				if !firstDisplayedStat {
-					fmt.Fprint(w, "\n")
-				}
-				firstDisplayedStat = false
-				printStatTable(stats, resourceType, w, maxNameLength, maxNamespaceLength, options)
+		for _, resourceType := range resourceTypes {
+			if !firstDisplayedStat {
+				fmt.Fprint(w, "\n")
 			}
+			firstDisplayedStat = false
+			printStatTable(statTables[resourceType], resourceType, w, maxNameLength, maxNamespaceLength, options)
 		}
 	default:
 		if stats, ok := statTables[reqResourceType]; ok {
@@ -250,24 +514,45 @@ func writeStatsToBuffer(resp *pb.StatSummaryResponse, reqResourceType string, w
 	}
 }
 
+// newRow converts a stat row from the API. Authorities, ingresses, and rows
+// grouped by label, have no meshed pod count.
+func newRow(r *pb.StatTable_PodGroup_Row, groupedByLabel bool) *row {
+	meshedCount := fmt.Sprintf("%d/%d", r.MeshedPodCount, r.RunningPodCount)
+	if !hasPodCounts(r.Resource.Type) || groupedByLabel {
+		meshedCount = "-"
+	}
+	statRow := &row{
+		meshed: meshedCount,
+	}
+
+	if r.Stats != nil {
+		statRow.rowStats = &rowStats{
+			requestRate: getRequestRate(*r),
+			successRate: getSuccessRate(*r),
+			tlsPercent:  getPercentTls(*r),
+			latencyP50:  r.Stats.LatencyMsP50,
+			latencyP95:  r.Stats.LatencyMsP95,
+			latencyP99:  r.Stats.LatencyMsP99,
+		}
+	}
+	return statRow
+}
+
+// hasPodCounts returns true if rows of resourceType have meshed and running
+// pod counts. Authorities and ingresses aren't backed by pods of their own.
+func hasPodCounts(resourceType string) bool {
+	return resourceType != k8s.Authority && resourceType != k8s.Ingress
+}
+
 func printStatTable(stats map[string]*row, resourceType string, w *tabwriter.Writer, maxNameLength int, maxNamespaceLength int, options *statOptions) {
 	headers := make([]string, 0)
-	if options.allNamespaces {
+	if options.showNamespace() {
 		headers = append(headers,
 			namespaceHeader+strings.Repeat(" ", maxNamespaceLength-len(namespaceHeader)))
 	}
-	headers = append(headers, []string{
-		nameHeader + strings.Repeat(" ", maxNameLength-len(nameHeader)),
-		"MESHED",
-		"SUCCESS",
-		"RPS",
-		"LATENCY_P50",
-		"LATENCY_P95",
-		"LATENCY_P99",
-		"TLS\t", // trailing \t is required to format last column
-	}...)
-
-	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	headers = append(headers, nameHeader+strings.Repeat(" ", maxNameLength-len(nameHeader)))
+	columns := options.statColumns()
+	printStatHeaders(w, headers, columns)
 
 	namePrefix := getNamePrefix(resourceType)
 
@@ -276,38 +561,137 @@ func printStatTable(stats map[string]*row, resourceType string, w *tabwriter.Wri
 		parts := strings.Split(key, "/")
 		namespace := parts[0]
 		name := namePrefix + parts[1]
-		values := make([]interface{}, 0)
-		templateString := "%s\t%s\t%.2f%%\t%.1frps\t%dms\t%dms\t%dms\t%.f%%\t\n"
-		templateStringEmpty := "%s\t%s\t-\t-\t-\t-\t-\t-\t\n"
+		values := make([]string, 0)
 
-		if options.allNamespaces {
+		if options.showNamespace() {
 			values = append(values,
 				namespace+strings.Repeat(" ", maxNamespaceLength-len(namespace)))
-			templateString = "%s\t" + templateString
-			templateStringEmpty = "%s\t" + templateStringEmpty
-		}
-		values = append(values, []interface{}{
-			name + strings.Repeat(" ", maxNameLength-len(name)),
-			stats[key].meshed,
-		}...)
-
-		if stats[key].rowStats != nil {
-			values = append(values, []interface{}{
-				stats[key].successRate * 100,
-				stats[key].requestRate,
-				stats[key].latencyP50,
-				stats[key].latencyP95,
-				stats[key].latencyP99,
-				stats[key].tlsPercent * 100,
-			}...)
-
-			fmt.Fprintf(w, templateString, values...)
-		} else {
-			fmt.Fprintf(w, templateStringEmpty, values...)
 		}
+		values = append(values, name+strings.Repeat(" ", maxNameLength-len(name)))
+
+		printStatRow(w, values, stats[key], columns)
+	}
+}
+
+// defaultStatColumns are the columns that --columns selects from, in the
+// order they're shown by default.
+var defaultStatColumns = []string{"meshed", "success", "rps", "latency_p50", "latency_p95", "latency_p99", "tls"}
+
+// printStatHeaders prints the leading headers, already padded, followed by the
+// headers of columns. A column of a single time window is headed with the
+// window, e.g. "SUCCESS(10m)".
+func printStatHeaders(w *tabwriter.Writer, headers []string, columns []string) {
+	for _, column := range columns {
+		header := strings.ToUpper(column)
+		if i := strings.Index(column, "@"); i >= 0 {
+			header = fmt.Sprintf("%s(%s)", strings.ToUpper(column[:i]), column[i+1:])
+		}
+		headers = append(headers, header)
 	}
+	// trailing \t is required to format last column
+	fmt.Fprintf(w, "%s\t\n", strings.Join(headers, "\t"))
 }
 
+// printStatRow prints the leading values, already padded, followed by the
+// columns of r.
+func printStatRow(w *tabwriter.Writer, values []string, r *row, columns []string) {
+	for _, column := range columns {
+		values = append(values, statColumnValue(column, r))
+	}
+	fmt.Fprintf(w, "%s\t\n", strings.Join(values, "\t"))
+}
+
+// statColumnValue returns the value of column for r. Columns of the form
+// "COLUMN@WINDOW" are the value of COLUMN over a single time window.
+func statColumnValue(column string, r *row) string {
+	if column == "meshed" {
+		return r.meshed
+	}
+	stats := r.rowStats
+	if i := strings.Index(column, "@"); i >= 0 {
+		column, stats = column[:i], r.windowStats[column[i+1:]]
+	}
+	if stats == nil {
+		return "-"
+	}
+
+	switch column {
+	case "success":
+		return fmt.Sprintf("%.2f%%", stats.successRate*100)
+	case "rps":
+		return fmt.Sprintf("%.1frps", stats.requestRate)
+	case "latency_p50":
+		return format.Millis(time.Duration(stats.latencyP50) * time.Millisecond)
+	case "latency_p95":
+		return format.Millis(time.Duration(stats.latencyP95) * time.Millisecond)
+	case "latency_p99":
+		return format.Millis(time.Duration(stats.latencyP99) * time.Millisecond)
+	case "tls":
+		return fmt.Sprintf("%.f%%", stats.tlsPercent*100)
+	default:
+		return "-"
+	}
+}
+
+// writeStatTreeToBuffer writes a row for every namespace in nsResp, followed
+// by a row for each of its deployments in deployResp.
+func writeStatTreeToBuffer(nsResp, deployResp *pb.StatSummaryResponse, w *tabwriter.Writer, options *statOptions) {
+	maxNameLength := len(nameHeader)
+	namespaces := make(map[string]*row)
+	deployments := make(map[string]map[string]*row)
+
+	for _, statTable := range nsResp.GetOk().GetStatTables() {
+		for _, r := range statTable.GetPodGroup().GetRows() {
+			namespaces[r.Resource.Name] = newRow(r, false)
+			if len(r.Resource.Name) > maxNameLength {
+				maxNameLength = len(r.Resource.Name)
+			}
+		}
+	}
+	for _, statTable := range deployResp.GetOk().GetStatTables() {
+		for _, r := range statTable.GetPodGroup().GetRows() {
+			// only nest deployments beneath the namespaces that were requested
+			if _, ok := namespaces[r.Resource.Namespace]; !ok {
+				continue
+			}
+			if _, ok := deployments[r.Resource.Namespace]; !ok {
+				deployments[r.Resource.Namespace] = make(map[string]*row)
+			}
+			deployments[r.Resource.Namespace][r.Resource.Name] = newRow(r, false)
+			if length := utf8.RuneCountInString(treeBranch) + len(r.Resource.Name); length > maxNameLength {
+				maxNameLength = length
+			}
+		}
+	}
+
+	if len(namespaces) == 0 {
+		fmt.Fprintln(os.Stderr, "No traffic found.")
+		os.Exit(0)
+	}
+
+	columns := options.selectedColumns()
+	printStatHeaders(w, []string{nameHeader + strings.Repeat(" ", maxNameLength-len(nameHeader))}, columns)
+
+	for _, namespace := range sortStatsKeys(namespaces) {
+		printStatRow(w, []string{namespace + strings.Repeat(" ", maxNameLength-len(namespace))}, namespaces[namespace], columns)
+
+		names := sortStatsKeys(deployments[namespace])
+		for i, name := range names {
+			branch := treeBranch
+			if i == len(names)-1 {
+				branch = treeLastBranch
+			}
+			label := branch + name
+			printStatRow(w, []string{label + strings.Repeat(" ", maxNameLength-utf8.RuneCountInString(label))}, deployments[namespace][name], columns)
+		}
+	}
+}
+
+const (
+	treeBranch     = "├─ "
+	treeLastBranch = "└─ "
+)
+
 func getNamePrefix(resourceType string) string {
 	if resourceType == "" {
 		return ""
@@ -318,14 +702,34 @@ func getNamePrefix(resourceType string) string {
 }
 
 func buildStatSummaryRequest(resource []string, options *statOptions) (*pb.StatSummaryRequest, error) {
-	target, err := util.BuildResource(options.namespace, resource...)
+	targets, err := util.BuildResources(options.namespace, resource...)
 	if err != nil {
 		return nil, err
 	}
 
-	err = options.validate(target.Type)
-	if err != nil {
-		return nil, err
+	for _, target := range targets {
+		err = options.validate(target.Type)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(targets) > 1 {
+		if options.groupByLabel != "" {
+			return nil, fmt.Errorf("--group-by-label is not supported with multiple resources")
+		}
+		if options.tree {
+			return nil, fmt.Errorf("--tree is not supported with multiple resources")
+		}
+	}
+	target := targets[0]
+
+	var additionalResources []string
+	for _, additional := range targets[1:] {
+		resource := k8s.ShortNameFromCanonicalResourceName(additional.Type)
+		if additional.Name != "" {
+			resource += "/" + additional.Name
+		}
+		additionalResources = append(additionalResources, resource)
 	}
 
 	var toRes, fromRes pb.Resource
@@ -342,8 +746,10 @@ func buildStatSummaryRequest(resource []string, options *statOptions) (*pb.StatS
 		}
 	}
 
+	windows := options.timeWindows()
+
 	requestParams := util.StatSummaryRequestParams{
-		TimeWindow:    options.timeWindow,
+		TimeWindow:    windows[0],
 		ResourceName:  target.Name,
 		ResourceType:  target.Type,
 		Namespace:     options.namespace,
@@ -354,11 +760,27 @@ func buildStatSummaryRequest(resource []string, options *statOptions) (*pb.StatS
 		FromType:      fromRes.Type,
 		FromNamespace: options.fromNamespace,
 		AllNamespaces: options.allNamespaces,
+		GroupByLabel:  options.groupByLabel,
+
+		AdditionalResources:   additionalResources,
+		AdditionalTimeWindows: windows[1:],
 	}
 
 	return util.BuildStatSummaryRequest(requestParams)
 }
 
+// statResourceType returns the resource type to render the stats of req as,
+// which is "all" if it selects resources of more than one type.
+func statResourceType(req *pb.StatSummaryRequest) string {
+	resourceType := req.Selector.Resource.Type
+	for _, resource := range req.AdditionalResources {
+		if resource.Type != resourceType {
+			return k8s.All
+		}
+	}
+	return resourceType
+}
+
 func getRequestRate(r pb.StatTable_PodGroup_Row) float64 {
 	success := r.Stats.SuccessCount
 	failure := r.Stats.FailureCount
@@ -397,6 +819,297 @@ func sortStatsKeys(stats map[string]*row) []string {
 	return sortedKeys
 }
 
+// sparklineWidth is the number of samples kept for each resource in watch
+// mode.
+const sparklineWidth = 30
+
+var sparklineTicks = []rune("▁▂▃▄▅▆▇█")
+
+// statHistory holds the most recent samples for a single resource. Success
+// rate samples are negative when the resource received no requests.
+type statHistory struct {
+	requestRate []float64
+	successRate []float64
+}
+
+func (h *statHistory) add(requestRate, successRate float64) {
+	h.requestRate = append(h.requestRate, requestRate)
+	h.successRate = append(h.successRate, successRate)
+	if len(h.requestRate) > sparklineWidth {
+		h.requestRate = h.requestRate[1:]
+		h.successRate = h.successRate[1:]
+	}
+}
+
+// statWatcher accumulates successive stat summaries so that they can be
+// rendered as sparklines.
+type statWatcher struct {
+	resourceType string
+	options      *statOptions
+	history      map[string]*statHistory // keyed by type/namespace/name
+}
+
+func newStatWatcher(resourceType string, options *statOptions) *statWatcher {
+	return &statWatcher{
+		resourceType: resourceType,
+		options:      options,
+		history:      make(map[string]*statHistory),
+	}
+}
+
+// watchStats redraws the stats of req every --watch-interval until ctx is
+// canceled. The last complete frame is left on the screen.
+func watchStats(ctx context.Context, w io.Writer, client pb.ApiClient, req *pb.StatSummaryRequest, options *statOptions) error {
+	watcher := newStatWatcher(statResourceType(req), options)
+	ticker := time.NewTicker(options.watchInterval)
+	defer ticker.Stop()
+
+	for {
+		var buffer bytes.Buffer
+		resp, err := client.StatSummary(ctx, req)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == nil {
+			if e := resp.GetError(); e != nil {
+				err = fmt.Errorf("StatSummary API response error: %v", e.Error)
+			} else {
+				watcher.update(resp)
+			}
+		} else {
+			err = fmt.Errorf("StatSummary API error: %v", err)
+		}
+
+		watcher.render(&buffer)
+		if err != nil {
+			fmt.Fprintf(&buffer, "\n%s\n", err)
+		}
+
+		// move the cursor home and clear the screen before redrawing
+		if _, err := fmt.Fprintf(w, "\033[H\033[2J%s", buffer.String()); err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// statJSONKey identifies a row of JSON output.
+type statJSONKey struct {
+	Type      string `json:"type"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// statJSONDelta is a line of "--watch -o json" output. Rows holds the rows
+// that were added or changed since the previous line, and Removed the rows
+// that are no longer reported.
+type statJSONDelta struct {
+	Time    time.Time      `json:"time"`
+	Rows    []*statJSONRow `json:"rows"`
+	Removed []statJSONKey  `json:"removed,omitempty"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// statJSONWatcher holds the rows of the last stat summary, to compute deltas
+// against.
+type statJSONWatcher struct {
+	rows map[statJSONKey]*statJSONRow
+}
+
+// watchStatsJSON writes a line of JSON for the stats of req every
+// --watch-interval until ctx is canceled. The first line has every row; after
+// that, lines only have the rows that changed, and are skipped if none did.
+func watchStatsJSON(ctx context.Context, w io.Writer, client pb.ApiClient, req *pb.StatSummaryRequest, options *statOptions) error {
+	watcher := &statJSONWatcher{}
+	ticker := time.NewTicker(options.watchInterval)
+	defer ticker.Stop()
+
+	for first := true; ; first = false {
+		delta := &statJSONDelta{Time: time.Now().UTC(), Rows: []*statJSONRow{}}
+		resp, err := client.StatSummary(ctx, req)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == nil {
+			if e := resp.GetError(); e != nil {
+				delta.Error = fmt.Sprintf("StatSummary API response error: %v", e.Error)
+			} else {
+				delta.Rows, delta.Removed = watcher.update(newStatJSON(resp, options))
+			}
+		} else {
+			delta.Error = fmt.Sprintf("StatSummary API error: %v", err)
+		}
+
+		if first || len(delta.Rows) > 0 || len(delta.Removed) > 0 || delta.Error != "" {
+			b, err := json.Marshal(delta)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "%s\n", b); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// update records the rows of stats, and returns the rows that were added or
+// changed and the keys of the rows that were removed since the last update.
+func (s *statJSONWatcher) update(stats *statJSON) ([]*statJSONRow, []statJSONKey) {
+	rows := make(map[statJSONKey]*statJSONRow, len(stats.Rows))
+	changed := []*statJSONRow{}
+	for _, r := range stats.Rows {
+		key := statJSONKey{Type: r.Type, Namespace: r.Namespace, Name: r.Name}
+		rows[key] = r
+		if previous, ok := s.rows[key]; !ok || !reflect.DeepEqual(previous, r) {
+			changed = append(changed, r)
+		}
+	}
+
+	var removed []statJSONKey
+	for key := range s.rows {
+		if _, ok := rows[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		if removed[i].Type != removed[j].Type {
+			return removed[i].Type < removed[j].Type
+		}
+		if removed[i].Namespace != removed[j].Namespace {
+			return removed[i].Namespace < removed[j].Namespace
+		}
+		return removed[i].Name < removed[j].Name
+	})
+
+	s.rows = rows
+	return changed, removed
+}
+
+// update records a sample for every resource in resp. Resources that are no
+// longer present are forgotten.
+func (s *statWatcher) update(resp *pb.StatSummaryResponse) {
+	seen := make(map[string]bool)
+	for _, statTable := range resp.GetOk().GetStatTables() {
+		for _, r := range statTable.GetPodGroup().GetRows() {
+			key := fmt.Sprintf("%s/%s/%s", r.Resource.Type, r.Resource.Namespace, r.Resource.Name)
+			seen[key] = true
+
+			h, ok := s.history[key]
+			if !ok {
+				h = &statHistory{}
+				s.history[key] = h
+			}
+
+			requestRate, successRate := 0.0, -1.0
+			if r.Stats != nil {
+				requestRate = getRequestRate(*r)
+				if r.Stats.SuccessCount+r.Stats.FailureCount > 0 {
+					successRate = getSuccessRate(*r)
+				}
+			}
+			h.add(requestRate, successRate)
+		}
+	}
+
+	for key := range s.history {
+		if !seen[key] {
+			delete(s.history, key)
+		}
+	}
+}
+
+func (s *statWatcher) render(w io.Writer) {
+	if len(s.history) == 0 {
+		fmt.Fprintln(w, "No traffic found.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', 0)
+
+	headers := make([]string, 0)
+	if s.options.showNamespace() {
+		headers = append(headers, namespaceHeader)
+	}
+	headers = append(headers, nameHeader, "SUCCESS", "SUCCESS_TREND", "RPS", "RPS_TREND")
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	keys := make([]string, 0, len(s.history))
+	for key := range s.history {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		parts := strings.SplitN(key, "/", 3)
+		resourceType, namespace, name := parts[0], parts[1], parts[2]
+		if s.resourceType == k8s.All {
+			name = getNamePrefix(resourceType) + name
+		}
+
+		h := s.history[key]
+		requestRate := h.requestRate[len(h.requestRate)-1]
+		successRate := "-"
+		if sr := h.successRate[len(h.successRate)-1]; sr >= 0 {
+			successRate = fmt.Sprintf("%.2f%%", sr*100)
+		}
+
+		maxRequestRate := 0.0
+		for _, rps := range h.requestRate {
+			if rps > maxRequestRate {
+				maxRequestRate = rps
+			}
+		}
+
+		columns := make([]string, 0)
+		if s.options.showNamespace() {
+			columns = append(columns, namespace)
+		}
+		columns = append(columns,
+			name,
+			successRate,
+			sparkline(h.successRate, 1),
+			fmt.Sprintf("%.1frps", requestRate),
+			sparkline(h.requestRate, maxRequestRate),
+		)
+		fmt.Fprintln(tw, strings.Join(columns, "\t"))
+	}
+
+	tw.Flush()
+}
+
+// sparkline renders values scaled against max as a string of block
+// characters. Negative values are rendered as blanks.
+func sparkline(values []float64, max float64) string {
+	var buffer bytes.Buffer
+	for _, v := range values {
+		if v < 0 {
+			buffer.WriteRune(' ')
+			continue
+		}
+
+		i := 0
+		if max > 0 {
+			i = int(v/max*float64(len(sparklineTicks)-1) + 0.5)
+		}
+		if i >= len(sparklineTicks) {
+			i = len(sparklineTicks) - 1
+		}
+		buffer.WriteRune(sparklineTicks[i])
+	}
+	return buffer.String()
+}
+
 // validate performs all validation on the command-line options.
 // It returns the first error encountered, or `nil` if the options are valid.
 func (o *statOptions) validate(resourceType string) error {
@@ -405,6 +1118,84 @@ func (o *statOptions) validate(resourceType string) error {
 		return err
 	}
 
+	if o.groupByLabel != "" {
+		if resourceType == k8s.All {
+			return fmt.Errorf("--group-by-label is not supported with resource type \"all\"")
+		}
+		if o.fromResource != "" {
+			return fmt.Errorf("--group-by-label is not supported with --from")
+		}
+	}
+
+	if o.tree {
+		if resourceType != k8s.Namespace {
+			return fmt.Errorf("--tree is only supported with resource type \"namespaces\"")
+		}
+		if o.toResource != "" || o.fromResource != "" {
+			return fmt.Errorf("--tree is not supported with --to or --from")
+		}
+		if o.watch {
+			return fmt.Errorf("--tree is not supported with --watch")
+		}
+		if o.groupByLabel != "" {
+			return fmt.Errorf("--tree is not supported with --group-by-label")
+		}
+	}
+
+	for _, column := range o.columns {
+		known := false
+		for _, c := range defaultStatColumns {
+			known = known || column == c
+		}
+		if !known {
+			return fmt.Errorf("--columns must be one or more of: %s", strings.Join(defaultStatColumns, ", "))
+		}
+	}
+	if len(o.columns) > 0 && o.watch {
+		return fmt.Errorf("--columns is not supported with --watch")
+	}
+
+	if o.output != "" {
+		if !isJSONOutput(o.output) && o.output != yamlOutput {
+			return fmt.Errorf("--output must be one of: %s, %s, %sTEMPLATE", jsonOutput, yamlOutput, jsonpathOutputPrefix)
+		}
+		if strings.HasPrefix(o.output, jsonpathOutputPrefix) {
+			if _, err := parseJSONPath(o.output); err != nil {
+				return err
+			}
+		}
+		if o.watch && o.output != jsonOutput {
+			return fmt.Errorf("--watch only supports --output %s", jsonOutput)
+		}
+		if o.tree {
+			return fmt.Errorf("--output is not supported with --tree")
+		}
+		if len(o.columns) > 0 {
+			return fmt.Errorf("--output is not supported with --columns")
+		}
+	}
+
+	if o.watch && o.watchInterval <= 0 {
+		return fmt.Errorf("--watch-interval must be greater than 0")
+	}
+
+	for _, window := range o.timeWindows() {
+		if window == "" {
+			return fmt.Errorf("--time-window must be a window or a comma-separated list of windows")
+		}
+	}
+	if len(o.timeWindows()) > 1 {
+		if o.watch {
+			return fmt.Errorf("multiple time windows are not supported with --watch")
+		}
+		if o.tree {
+			return fmt.Errorf("multiple time windows are not supported with --tree")
+		}
+		if o.groupByLabel != "" {
+			return fmt.Errorf("multiple time windows are not supported with --group-by-label")
+		}
+	}
+
 	if resourceType == k8s.Namespace {
 		err := o.validateNamespaceFlags()
 		if err != nil {
@@ -415,6 +1206,56 @@ func (o *statOptions) validate(resourceType string) error {
 	return nil
 }
 
+// selectedColumns returns the columns to show after the resource name.
+func (o *statOptions) selectedColumns() []string {
+	if len(o.columns) == 0 {
+		return defaultStatColumns
+	}
+	return o.columns
+}
+
+// statColumns returns the columns to show after the resource name in a table.
+// When several time windows are compared, every column other than "meshed" is
+// shown once per window, as "COLUMN@WINDOW".
+func (o *statOptions) statColumns() []string {
+	windows := o.timeWindows()
+	if len(windows) < 2 {
+		return o.selectedColumns()
+	}
+
+	columns := make([]string, 0)
+	for _, column := range o.selectedColumns() {
+		if column == "meshed" {
+			columns = append(columns, column)
+			continue
+		}
+		for _, window := range windows {
+			// rows are keyed by the window the API reports them over, which
+			// is normalized; the windows have been validated by the request
+			if normalized, err := util.NormalizeTimeWindow(window); err == nil {
+				window = normalized
+			}
+			columns = append(columns, column+"@"+window)
+		}
+	}
+	return columns
+}
+
+// timeWindows returns the windows of --time-window, in order.
+func (o *statOptions) timeWindows() []string {
+	windows := strings.Split(o.timeWindow, ",")
+	for i := range windows {
+		windows[i] = strings.TrimSpace(windows[i])
+	}
+	return windows
+}
+
+// showNamespace returns true if stats are shown with a namespace column. Rows
+// grouped by label aggregate resources across namespaces, so they have none.
+func (o *statOptions) showNamespace() bool {
+	return o.allNamespaces && o.groupByLabel == ""
+}
+
 // validateConflictingFlags validates that the options do not contain mutually
 // exclusive flags.
 func (o *statOptions) validateConflictingFlags() error {
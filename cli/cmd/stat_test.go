@@ -1,9 +1,20 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/linkerd/linkerd2/controller/api/public"
+	"github.com/linkerd/linkerd2/controller/api/public/publictest"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 )
 
@@ -42,6 +53,116 @@ emoji      1/2   100.00%   2.0rps         123ms         123ms         123ms   10
 		}
 	})
 
+	t.Run("Returns ingress stats without pod counts", func(t *testing.T) {
+		mockClient := &public.MockApiClient{}
+
+		response := public.GenStatSummaryResponse("web/web.example.com", k8s.Ingress, "emojivoto", nil)
+		mockClient.StatSummaryResponseToReturn = &response
+
+		expectedOutput := `NAME                  MESHED   SUCCESS      RPS   LATENCY_P50   LATENCY_P95   LATENCY_P99    TLS
+web/web.example.com        -   100.00%   2.0rps         123ms         123ms         123ms   100%
+`
+
+		options := newStatOptions()
+		req, err := buildStatSummaryRequest([]string{"ing"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		output, err := requestStatsFromAPI(mockClient, req, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if output != expectedOutput {
+			t.Fatalf("Wrong output:\n expected: \n%s\n, got: \n%s", expectedOutput, output)
+		}
+	})
+
+	t.Run("Streams a table per resource type for all", func(t *testing.T) {
+		mockClient := &public.MockApiClient{}
+
+		deployments := public.GenStatSummaryResponse("emoji", k8s.Deployment, "emojivoto", nil)
+		pods := public.GenStatSummaryResponse("emoji-1234", k8s.Pod, "emojivoto", nil)
+		mockClient.StatSummaryResponseToReturn = statSummaryOk(
+			deployments.GetOk().StatTables[0],
+			&pb.StatTable{Table: &pb.StatTable_PodGroup_{PodGroup: &pb.StatTable_PodGroup{}}},
+			pods.GetOk().StatTables[0],
+		)
+
+		options := newStatOptions()
+		req, err := buildStatSummaryRequest([]string{"all"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		if err := requestStatStreamFromAPI(context.Background(), &buf, mockClient, req, options); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedOutput := `NAME           MESHED   SUCCESS      RPS   LATENCY_P50   LATENCY_P95   LATENCY_P99    TLS
+deploy/emoji      0/0   100.00%   2.0rps         123ms         123ms         123ms   100%
+
+NAME            MESHED   SUCCESS      RPS   LATENCY_P50   LATENCY_P95   LATENCY_P99    TLS
+po/emoji-1234      0/0   100.00%   2.0rps         123ms         123ms         123ms   100%
+`
+		if buf.String() != expectedOutput {
+			t.Fatalf("Wrong output:\n expected: \n%s\n, got: \n%s", expectedOutput, buf.String())
+		}
+	})
+
+	t.Run("Returns a single table for multiple resources of the same type", func(t *testing.T) {
+		mockClient := &public.MockApiClient{}
+
+		web := public.GenStatSummaryResponse("web", k8s.Deployment, "emojivoto", nil)
+		api := public.GenStatSummaryResponse("api", k8s.Deployment, "emojivoto", nil)
+		table := web.GetOk().StatTables[0]
+		table.GetPodGroup().Rows = append(table.GetPodGroup().Rows, api.GetOk().StatTables[0].GetPodGroup().Rows...)
+		mockClient.StatSummaryResponseToReturn = statSummaryOk(table)
+
+		options := newStatOptions()
+		options.namespace = "emojivoto"
+		req, err := buildStatSummaryRequest([]string{"deploy/web,deploy/api"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(req.AdditionalResources) != 1 || req.AdditionalResources[0].Name != "api" {
+			t.Fatalf("Expected deploy/api to be an additional resource, got %+v", req.AdditionalResources)
+		}
+
+		output, err := requestStatsFromAPI(mockClient, req, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedOutput := `NAME   MESHED   SUCCESS      RPS   LATENCY_P50   LATENCY_P95   LATENCY_P99    TLS
+api       0/0   100.00%   2.0rps         123ms         123ms         123ms   100%
+web       0/0   100.00%   2.0rps         123ms         123ms         123ms   100%
+`
+		if output != expectedOutput {
+			t.Fatalf("Wrong output:\n expected: \n%s\n, got: \n%s", expectedOutput, output)
+		}
+	})
+
+	t.Run("Renders resources of several types like all", func(t *testing.T) {
+		options := newStatOptions()
+		req, err := buildStatSummaryRequest([]string{"deploy,rc"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resourceType := statResourceType(req); resourceType != k8s.All {
+			t.Fatalf("Expected resource type [%s], got [%s]", k8s.All, resourceType)
+		}
+
+		options.groupByLabel = "team"
+		expectedError := "--group-by-label is not supported with multiple resources"
+		_, err = buildStatSummaryRequest([]string{"deploy,rc"}, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
 	t.Run("Returns an error for named resource queries with the --all-namespaces flag", func(t *testing.T) {
 		options := newStatOptions()
 		options.allNamespaces = true
@@ -103,4 +224,501 @@ emoji      1/2   100.00%   2.0rps         123ms         123ms         123ms   10
 			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
 		}
 	})
+
+	t.Run("Returns stats grouped by label across all namespaces", func(t *testing.T) {
+		mockClient := &public.MockApiClient{}
+
+		response := public.GenStatSummaryResponse("payments", k8s.Deployment, "", nil)
+
+		mockClient.StatSummaryResponseToReturn = &response
+
+		expectedOutput := `NAME       MESHED   SUCCESS      RPS   LATENCY_P50   LATENCY_P95   LATENCY_P99    TLS
+payments        -   100.00%   2.0rps         123ms         123ms         123ms   100%
+`
+
+		options := newStatOptions()
+		options.allNamespaces = true
+		options.groupByLabel = "team"
+		args := []string{"deploy"}
+		req, err := buildStatSummaryRequest(args, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if req.GroupByLabel != "team" {
+			t.Fatalf("Expected the request to group by [team], got [%s]", req.GroupByLabel)
+		}
+
+		output, err := requestStatsFromAPI(mockClient, req, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if output != expectedOutput {
+			t.Fatalf("Wrong output:\n expected: \n%s\n, got: \n%s", expectedOutput, output)
+		}
+	})
+
+	t.Run("Rejects --group-by-label with --from", func(t *testing.T) {
+		options := newStatOptions()
+		options.groupByLabel = "team"
+		options.fromResource = "deploy/web"
+		args := []string{"deploy"}
+		expectedError := "--group-by-label is not supported with --from"
+
+		_, err := buildStatSummaryRequest(args, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Returns namespaces with their deployments nested beneath", func(t *testing.T) {
+		client := publictest.NewMockApiClient()
+
+		nsResponse := public.GenStatSummaryResponse("emojivoto", k8s.Namespace, "", &public.PodCounts{MeshedPods: 2, RunningPods: 2})
+		deployResponse := public.GenStatSummaryResponse("web", k8s.Deployment, "emojivoto", &public.PodCounts{MeshedPods: 1, RunningPods: 1})
+		emoji := public.GenStatSummaryResponse("emoji", k8s.Deployment, "emojivoto", &public.PodCounts{MeshedPods: 1, RunningPods: 1})
+		other := public.GenStatSummaryResponse("other", k8s.Deployment, "kube-system", nil)
+		table := deployResponse.GetOk().StatTables[0].GetPodGroup()
+		table.Rows = append(table.Rows, emoji.GetOk().StatTables[0].GetPodGroup().Rows[0], other.GetOk().StatTables[0].GetPodGroup().Rows[0])
+		client.SetStatSummaryResponses(&nsResponse, &deployResponse)
+
+		expectedOutput := `NAME        MESHED   SUCCESS      RPS   LATENCY_P50   LATENCY_P95   LATENCY_P99    TLS
+emojivoto      2/2   100.00%   2.0rps         123ms         123ms         123ms   100%
+├─ emoji       1/1   100.00%   2.0rps         123ms         123ms         123ms   100%
+└─ web         1/1   100.00%   2.0rps         123ms         123ms         123ms   100%
+`
+
+		options := newStatOptions()
+		options.tree = true
+		req, err := buildStatSummaryRequest([]string{"ns"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		output, err := requestStatTreeFromAPI(client, req, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if output != expectedOutput {
+			t.Fatalf("Wrong output:\n expected: \n%s\n, got: \n%s", expectedOutput, output)
+		}
+
+		requests := client.Requests()
+		if len(requests) != 2 {
+			t.Fatalf("Expected 2 requests, got %d", len(requests))
+		}
+		deployReq := requests[1].(*pb.StatSummaryRequest)
+		if deployReq.Selector.Resource.Type != k8s.Deployment || deployReq.Selector.Resource.Namespace != "" {
+			t.Fatalf("Expected a request for deployments in all namespaces, got %+v", deployReq.Selector.Resource)
+		}
+	})
+
+	t.Run("Returns only the selected columns, in order", func(t *testing.T) {
+		mockClient := &public.MockApiClient{}
+
+		response := public.GenStatSummaryResponse("web", k8s.Deployment, "emojivoto", nil)
+		response.GetOk().StatTables[0].GetPodGroup().Rows[0].Stats.LatencyMsP99 = 456
+
+		mockClient.StatSummaryResponseToReturn = &response
+
+		expectedOutput := `NAME   LATENCY_P99   SUCCESS
+web          456ms   100.00%
+`
+
+		options := newStatOptions()
+		options.columns = []string{"latency_p99", "success"}
+		req, err := buildStatSummaryRequest([]string{"deploy"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		output, err := requestStatsFromAPI(mockClient, req, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if output != expectedOutput {
+			t.Fatalf("Wrong output:\n expected: \n%s\n, got: \n%s", expectedOutput, output)
+		}
+	})
+
+	t.Run("Returns the stats of each time window side by side", func(t *testing.T) {
+		mockClient := &public.MockApiClient{}
+
+		response := public.GenStatSummaryResponse("web", k8s.Deployment, "emojivoto", &public.PodCounts{MeshedPods: 1, RunningPods: 1})
+		table := response.GetOk().StatTables[0].GetPodGroup()
+		row := proto.Clone(table.Rows[0]).(*pb.StatTable_PodGroup_Row)
+		row.TimeWindow = "90s"
+		row.Stats.FailureCount = 123
+		table.Rows = append(table.Rows, row)
+		mockClient.StatSummaryResponseToReturn = &response
+
+		options := newStatOptions()
+		options.timeWindow = "1m,1m30s"
+		options.columns = []string{"meshed", "success", "rps"}
+		req, err := buildStatSummaryRequest([]string{"deploy"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if req.TimeWindow != "1m" || !reflect.DeepEqual(req.AdditionalTimeWindows, []string{"90s"}) {
+			t.Fatalf("Expected time windows [1m] and [90s], got [%s] and %v", req.TimeWindow, req.AdditionalTimeWindows)
+		}
+
+		output, err := requestStatsFromAPI(mockClient, req, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedOutput := `NAME   MESHED   SUCCESS(1m)   SUCCESS(90s)   RPS(1m)   RPS(90s)
+web       1/1       100.00%         50.00%    2.0rps     2.7rps
+`
+		if output != expectedOutput {
+			t.Fatalf("Wrong output:\n expected: \n%s\n, got: \n%s", expectedOutput, output)
+		}
+	})
+
+	t.Run("Rejects multiple time windows with --watch", func(t *testing.T) {
+		options := newStatOptions()
+		options.timeWindow = "1m,10m"
+		options.watch = true
+		args := []string{"deploy"}
+		expectedError := "multiple time windows are not supported with --watch"
+
+		_, err := buildStatSummaryRequest(args, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Rejects unknown columns", func(t *testing.T) {
+		options := newStatOptions()
+		options.columns = []string{"success", "p99"}
+		args := []string{"deploy"}
+		expectedError := "--columns must be one or more of: meshed, success, rps, latency_p50, latency_p95, latency_p99, tls"
+
+		_, err := buildStatSummaryRequest(args, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Rejects --tree for resource types other than namespaces", func(t *testing.T) {
+		options := newStatOptions()
+		options.tree = true
+		args := []string{"deploy"}
+		expectedError := "--tree is only supported with resource type \"namespaces\""
+
+		_, err := buildStatSummaryRequest(args, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Rejects a non-positive --watch-interval", func(t *testing.T) {
+		options := newStatOptions()
+		options.watch = true
+		options.watchInterval = 0
+		args := []string{"deploy"}
+		expectedError := "--watch-interval must be greater than 0"
+
+		_, err := buildStatSummaryRequest(args, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+}
+
+func TestStatJSON(t *testing.T) {
+	t.Run("Renders rows as JSON", func(t *testing.T) {
+		response := public.GenStatSummaryResponse("emoji", k8s.Namespace, "", &public.PodCounts{MeshedPods: 1, RunningPods: 2})
+		expectedOutput := `{
+  "rows": [
+    {
+      "type": "namespace",
+      "namespace": "",
+      "name": "emoji",
+      "timeWindow": "1m",
+      "meshedPods": 1,
+      "runningPods": 2,
+      "failedPods": 0,
+      "successRate": 1,
+      "requestRate": 2.05,
+      "tlsRate": 1,
+      "successCount": 123,
+      "failureCount": 0,
+      "tlsRequestCount": 123,
+      "latencyMsP50": 123,
+      "latencyMsP95": 123,
+      "latencyMsP99": 123
+    }
+  ]
+}
+`
+
+		var buf bytes.Buffer
+		if err := renderJSON(&buf, jsonOutput, newStatJSON(&response, newStatOptions())); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if buf.String() != expectedOutput {
+			t.Fatalf("Wrong output:\n expected: \n%s\n, got: \n%s", expectedOutput, buf.String())
+		}
+	})
+
+	t.Run("Renders rows as YAML", func(t *testing.T) {
+		response := public.GenStatSummaryResponse("web", k8s.Deployment, "emojivoto", &public.PodCounts{MeshedPods: 1, RunningPods: 2, FailedPods: 1})
+		options := newStatOptions()
+		options.output = yamlOutput
+		expectedOutput := `rows:
+- failedPods: 1
+  failureCount: 0
+  latencyMsP50: 123
+  latencyMsP95: 123
+  latencyMsP99: 123
+  meshedPods: 1
+  name: web
+  namespace: emojivoto
+  requestRate: 2.05
+  runningPods: 2
+  successCount: 123
+  successRate: 1
+  timeWindow: 1m
+  tlsRate: 1
+  tlsRequestCount: 123
+  type: deployment
+`
+
+		var buf bytes.Buffer
+		if err := renderStatOutput(&buf, &response, options); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if buf.String() != expectedOutput {
+			t.Fatalf("Wrong output:\n expected: \n%s\n, got: \n%s", expectedOutput, buf.String())
+		}
+	})
+
+	t.Run("Extracts values with a jsonpath template", func(t *testing.T) {
+		response := public.GenStatSummaryResponse("web", k8s.Deployment, "emojivoto", nil)
+		other := public.GenStatSummaryResponse("voting", k8s.Deployment, "emojivoto", nil)
+		otherRow := other.GetOk().StatTables[0].GetPodGroup().Rows[0]
+		otherRow.Stats = nil
+		table := response.GetOk().StatTables[0].GetPodGroup()
+		table.Rows = append(table.Rows, otherRow)
+
+		var buf bytes.Buffer
+		output := jsonpathOutputPrefix + `{range .rows[*]}{.name}={.successRate} {end}`
+		if err := renderJSON(&buf, output, newStatJSON(&response, newStatOptions())); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expectedOutput := "voting=<nil> web=1 "
+		if buf.String() != expectedOutput {
+			t.Fatalf("Expected [%q], got [%q]", expectedOutput, buf.String())
+		}
+	})
+
+	t.Run("Rejects invalid jsonpath templates", func(t *testing.T) {
+		options := newStatOptions()
+		options.output = jsonpathOutputPrefix + "{.rows[*"
+
+		if _, err := buildStatSummaryRequest([]string{"deploy"}, options); err == nil {
+			t.Fatalf("Expected an error for an invalid jsonpath template")
+		}
+	})
+
+	t.Run("Rejects unknown output formats", func(t *testing.T) {
+		options := newStatOptions()
+		options.output = "wide"
+		expectedError := "--output must be one of: json, yaml, jsonpath=TEMPLATE"
+
+		_, err := buildStatSummaryRequest([]string{"deploy"}, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Rejects output formats other than JSON with --watch", func(t *testing.T) {
+		options := newStatOptions()
+		options.output = yamlOutput
+		options.watch = true
+		expectedError := "--watch only supports --output json"
+
+		_, err := buildStatSummaryRequest([]string{"deploy"}, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+}
+
+func TestStatWatch(t *testing.T) {
+	t.Run("Renders sparklines from successive samples", func(t *testing.T) {
+		options := newStatOptions()
+		watcher := newStatWatcher(k8s.Deployment, options)
+
+		for _, failures := range []uint64{0, 123, 0} {
+			response := public.GenStatSummaryResponse("web", k8s.Deployment, "emojivoto", nil)
+			response.GetOk().StatTables[0].GetPodGroup().Rows[0].Stats.FailureCount = failures
+			watcher.update(&response)
+		}
+
+		expectedOutput := `NAME   SUCCESS   SUCCESS_TREND   RPS      RPS_TREND
+web    100.00%   █▅█             2.0rps   ▅█▅
+`
+
+		var buf bytes.Buffer
+		watcher.render(&buf)
+		if buf.String() != expectedOutput {
+			t.Fatalf("Wrong output:\n expected: \n%s\n, got: \n%s", expectedOutput, buf.String())
+		}
+	})
+
+	t.Run("Forgets resources that are no longer reported", func(t *testing.T) {
+		options := newStatOptions()
+		watcher := newStatWatcher(k8s.Deployment, options)
+
+		response := public.GenStatSummaryResponse("web", k8s.Deployment, "emojivoto", nil)
+		watcher.update(&response)
+		watcher.update(&pb.StatSummaryResponse{})
+
+		var buf bytes.Buffer
+		watcher.render(&buf)
+		if buf.String() != "No traffic found.\n" {
+			t.Fatalf("Expected no traffic, got: \n%s", buf.String())
+		}
+	})
+
+	t.Run("Keeps rendering when the API returns an error", func(t *testing.T) {
+		client := publictest.NewMockApiClient()
+		client.SetError(errors.New("connection refused"))
+
+		options := newStatOptions()
+		req, err := buildStatSummaryRequest([]string{"deploy"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// stop watching after the first frame
+		var buf bytes.Buffer
+		ctx, cancel := context.WithCancel(context.Background())
+		if err := watchStats(ctx, &cancelingWriter{Writer: &buf, cancel: cancel}, client, req, options); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedError := "StatSummary API error: connection refused"
+		if !strings.Contains(buf.String(), expectedError) {
+			t.Fatalf("Expected output to contain [%s], got: \n%s", expectedError, buf.String())
+		}
+	})
+}
+
+func TestWatchStatsJSON(t *testing.T) {
+	t.Run("Writes the rows that changed", func(t *testing.T) {
+		web := public.GenStatSummaryResponse("web", k8s.Deployment, "emojivoto", nil)
+		voting := public.GenStatSummaryResponse("voting", k8s.Deployment, "emojivoto", nil)
+		both := public.GenStatSummaryResponse("web", k8s.Deployment, "emojivoto", nil)
+		table := both.GetOk().StatTables[0].GetPodGroup()
+		table.Rows = append(table.Rows, voting.GetOk().StatTables[0].GetPodGroup().Rows[0])
+		failing := public.GenStatSummaryResponse("web", k8s.Deployment, "emojivoto", nil)
+		failing.GetOk().StatTables[0].GetPodGroup().Rows[0].Stats.FailureCount = 123
+
+		client := publictest.NewMockApiClient()
+		client.SetStatSummaryResponses(&both, &both, &failing, &web)
+
+		options := newStatOptions()
+		options.output = jsonOutput
+		options.watchInterval = time.Millisecond
+		req, err := buildStatSummaryRequest([]string{"deploy"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// the second response is unchanged, so stop after the third line
+		var buf bytes.Buffer
+		ctx, cancel := context.WithCancel(context.Background())
+		w := &cancelingWriter{Writer: &buf, cancel: cancel, after: 3}
+		if err := watchStatsJSON(ctx, w, client, req, options); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var deltas []statJSONDelta
+		decoder := json.NewDecoder(&buf)
+		for decoder.More() {
+			var delta statJSONDelta
+			if err := decoder.Decode(&delta); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			deltas = append(deltas, delta)
+		}
+
+		names := func(delta statJSONDelta) []string {
+			names := []string{}
+			for _, r := range delta.Rows {
+				names = append(names, r.Name)
+			}
+			for _, key := range delta.Removed {
+				names = append(names, "-"+key.Name)
+			}
+			return names
+		}
+		expected := [][]string{{"voting", "web"}, {"web"}, {"web", "-voting"}}
+		if len(deltas) != len(expected) {
+			t.Fatalf("Expected %d lines, got %d: \n%s", len(expected), len(deltas), buf.String())
+		}
+		for i, delta := range deltas {
+			if got := names(delta); !reflect.DeepEqual(got, expected[i]) {
+				t.Errorf("Expected line %d to have rows %v, got %v", i, expected[i], got)
+			}
+		}
+		if *deltas[1].Rows[0].SuccessRate != 0.5 {
+			t.Errorf("Expected the changed success rate 0.5, got %v", *deltas[1].Rows[0].SuccessRate)
+		}
+	})
+
+	t.Run("Writes API errors", func(t *testing.T) {
+		client := publictest.NewMockApiClient()
+		client.SetError(errors.New("connection refused"))
+
+		options := newStatOptions()
+		options.output = jsonOutput
+		req, err := buildStatSummaryRequest([]string{"deploy"}, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var buf bytes.Buffer
+		ctx, cancel := context.WithCancel(context.Background())
+		if err := watchStatsJSON(ctx, &cancelingWriter{Writer: &buf, cancel: cancel}, client, req, options); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var delta statJSONDelta
+		if err := json.Unmarshal(buf.Bytes(), &delta); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expectedError := "StatSummary API error: connection refused"
+		if delta.Error != expectedError {
+			t.Fatalf("Expected error [%s], got [%s]", expectedError, delta.Error)
+		}
+	})
+}
+
+// cancelingWriter cancels a context once it's been written to after times, or
+// on the first write if after isn't set.
+type cancelingWriter struct {
+	io.Writer
+	cancel context.CancelFunc
+	after  int
+}
+
+func (w *cancelingWriter) Write(p []byte) (int, error) {
+	w.after--
+	if w.after <= 0 {
+		defer w.cancel()
+	}
+	return w.Writer.Write(p)
+}
+
+func TestSparkline(t *testing.T) {
+	expected := "▁▅█ █"
+	if got := sparkline([]float64{0, 0.5, 1, -1, 2}, 1); got != expected {
+		t.Fatalf("Expected [%s], got [%s]", expected, got)
+	}
 }
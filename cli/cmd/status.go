@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/util"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+const (
+	statusOkGlyph   = "✔"
+	statusWarnGlyph = "⚠"
+	statusFailGlyph = "✖"
+
+	// trustAnchorWarnWithin is how long before the trust anchor expires that
+	// status starts warning about it.
+	trustAnchorWarnWithin = 30 * 24 * time.Hour
+)
+
+type statusOptions struct {
+	timeWindow string
+}
+
+func newStatusOptions() *statusOptions {
+	return &statusOptions{
+		timeWindow: "1m",
+	}
+}
+
+// statusLine is a single line of the status summary.
+type statusLine struct {
+	subject string
+	glyph   string
+	message string
+}
+
+func newCmdStatus() *cobra.Command {
+	options := newStatusOptions()
+
+	cmd := &cobra.Command{
+		Use:   "status [flags]",
+		Short: "Print a one-screen summary of the health of Linkerd and its traffic",
+		Long: `Print a one-screen summary of the health of Linkerd and its traffic.
+
+The summary covers the control plane's checks and pods, the number and versions
+of proxies reporting metrics, the expiry of the TLS trust anchor, and the success
+rate and request rate across all namespaces. The process exits with a non-zero
+status if anything failed; "linkerd check" shows the details of each check.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := time.ParseDuration(options.timeWindow); err != nil {
+				return fmt.Errorf("--time-window must be a valid duration: %s", err)
+			}
+
+			var lines []statusLine
+			client, err := newPublicAPIClient()
+			if err != nil {
+				lines = append(lines, statusLine{"Control plane", statusFailGlyph, fmt.Sprintf("cannot connect to Linkerd: %s", err)})
+			} else {
+				lines = append(lines, getAPIStatus(client, options)...)
+			}
+
+			kubeAPI, err := k8s.NewAPI(kubeconfigPath)
+			if err != nil {
+				lines = append(lines, statusLine{"Trust anchor", statusFailGlyph, err.Error()})
+			} else {
+				lines = append(lines, trustAnchorStatus(kubeAPI, time.Now()))
+			}
+
+			renderStatus(os.Stdout, lines)
+			for _, line := range lines {
+				if line.glyph == statusFailGlyph {
+					os.Exit(2)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&options.timeWindow, "time-window", "t", options.timeWindow, "Window to compute the success rate and request rate over (for example: \"10s\", \"1m\", \"10m\", \"1h\")")
+
+	return cmd
+}
+
+// getAPIStatus returns the parts of the summary that come from the public
+// API: the control plane, the data plane and traffic.
+func getAPIStatus(client pb.ApiClient, options *statusOptions) []statusLine {
+	checks, err := client.SelfCheck(context.Background(), &healthcheckPb.SelfCheckRequest{})
+	if err != nil {
+		return []statusLine{{"Control plane", statusFailGlyph, fmt.Sprintf("cannot connect to Linkerd: %s", err)}}
+	}
+
+	pods, err := client.ListPods(context.Background(), &pb.ListPodsRequest{})
+	if err != nil {
+		return []statusLine{{"Control plane", statusFailGlyph, fmt.Sprintf("cannot list pods: %s", err)}}
+	}
+
+	return []statusLine{
+		controlPlaneStatus(checks, pods),
+		dataPlaneStatus(pods),
+		trafficStatus(client, options),
+	}
+}
+
+func controlPlaneStatus(checks *healthcheckPb.SelfCheckResponse, pods *pb.ListPodsResponse) statusLine {
+	passed := 0
+	for _, result := range checks.GetResults() {
+		if result.Status == healthcheckPb.CheckStatus_OK {
+			passed++
+		}
+	}
+
+	running, total := 0, 0
+	for _, pod := range pods.GetPods() {
+		if !pod.ControlPlane {
+			continue
+		}
+		total++
+		if pod.Status == string(coreV1.PodRunning) {
+			running++
+		}
+	}
+
+	glyph := statusOkGlyph
+	if passed < len(checks.GetResults()) || running < total || total == 0 {
+		glyph = statusFailGlyph
+	}
+	return statusLine{"Control plane", glyph, fmt.Sprintf("%d/%d pods running, %d/%d checks passed", running, total, passed, len(checks.GetResults()))}
+}
+
+func dataPlaneStatus(pods *pb.ListPodsResponse) statusLine {
+	versions := make(map[string]int)
+	proxies := 0
+	for _, pod := range pods.GetPods() {
+		if !pod.Added {
+			continue
+		}
+		proxies++
+		version := pod.ProxyVersion
+		if version == "" {
+			version = "unknown"
+		}
+		versions[version]++
+	}
+
+	if proxies == 0 {
+		return statusLine{"Data plane", statusWarnGlyph, "no proxies are reporting metrics"}
+	}
+
+	proxyCount := fmt.Sprintf("%d proxies", proxies)
+	if proxies == 1 {
+		proxyCount = "1 proxy"
+	}
+
+	if len(versions) == 1 {
+		for version := range versions {
+			return statusLine{"Data plane", statusOkGlyph, fmt.Sprintf("%s, version %s", proxyCount, version)}
+		}
+	}
+
+	// list the most common versions first
+	var spread []string
+	for version := range versions {
+		spread = append(spread, version)
+	}
+	sort.Slice(spread, func(i, j int) bool {
+		if versions[spread[i]] != versions[spread[j]] {
+			return versions[spread[i]] > versions[spread[j]]
+		}
+		return spread[i] < spread[j]
+	})
+	for i, version := range spread {
+		spread[i] = fmt.Sprintf("%s (%d)", version, versions[version])
+	}
+	return statusLine{"Data plane", statusWarnGlyph, fmt.Sprintf("%s, versions %s", proxyCount, strings.Join(spread, ", "))}
+}
+
+func trafficStatus(client pb.ApiClient, options *statusOptions) statusLine {
+	req, err := util.BuildStatSummaryRequest(util.StatSummaryRequestParams{
+		TimeWindow:   options.timeWindow,
+		ResourceType: k8s.Namespace,
+	})
+	if err != nil {
+		return statusLine{"Traffic", statusFailGlyph, err.Error()}
+	}
+	resp, err := requestStatSummary(client, req)
+	if err != nil {
+		return statusLine{"Traffic", statusFailGlyph, err.Error()}
+	}
+
+	var success, failure, tls uint64
+	lowestNamespace := ""
+	lowestSuccessRate := 0.0
+	for _, statTable := range resp.GetOk().GetStatTables() {
+		for _, r := range statTable.GetPodGroup().GetRows() {
+			if r.Stats == nil || r.Stats.SuccessCount+r.Stats.FailureCount == 0 {
+				continue
+			}
+			success += r.Stats.SuccessCount
+			failure += r.Stats.FailureCount
+			tls += r.Stats.TlsRequestCount
+
+			if successRate := getSuccessRate(*r); lowestNamespace == "" || successRate < lowestSuccessRate {
+				lowestNamespace, lowestSuccessRate = r.Resource.Name, successRate
+			}
+		}
+	}
+
+	if success+failure == 0 {
+		return statusLine{"Traffic", statusWarnGlyph, fmt.Sprintf("no traffic in the last %s", options.timeWindow)}
+	}
+
+	window, _ := time.ParseDuration(options.timeWindow)
+	total := float64(success + failure)
+	message := fmt.Sprintf("%.2f%% success, %.1frps, %.f%% TLS over %s; lowest: %s %.2f%%",
+		float64(success)/total*100,
+		total/window.Seconds(),
+		float64(tls)/total*100,
+		options.timeWindow,
+		lowestNamespace,
+		lowestSuccessRate*100,
+	)
+	return statusLine{"Traffic", statusOkGlyph, message}
+}
+
+func trustAnchorStatus(kubeAPI k8s.KubernetesApi, now time.Time) statusLine {
+	anchors, err := getTrustAnchors(kubeAPI)
+	if err != nil {
+		return statusLine{"Trust anchor", statusFailGlyph, err.Error()}
+	}
+	if anchors == nil {
+		return statusLine{"Trust anchor", statusOkGlyph, "TLS is not enabled"}
+	}
+
+	expiry, err := trustAnchorExpiry(anchors)
+	if err != nil {
+		return statusLine{"Trust anchor", statusFailGlyph, err.Error()}
+	}
+
+	remaining := expiry.Sub(now)
+	date := expiry.UTC().Format("2006-01-02")
+	switch {
+	case remaining <= 0:
+		return statusLine{"Trust anchor", statusFailGlyph, fmt.Sprintf("expired on %s", date)}
+	case remaining < trustAnchorWarnWithin:
+		return statusLine{"Trust anchor", statusWarnGlyph, fmt.Sprintf("expires in %d days (%s)", int(remaining.Hours()/24), date)}
+	default:
+		return statusLine{"Trust anchor", statusOkGlyph, fmt.Sprintf("expires in %d days (%s)", int(remaining.Hours()/24), date)}
+	}
+}
+
+// getTrustAnchors returns the PEM-encoded trust anchors that the CA publishes
+// in the control plane namespace, or nil if TLS is not enabled.
+func getTrustAnchors(kubeAPI k8s.KubernetesApi) ([]byte, error) {
+	client, err := kubeAPI.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("Error connecting to the API. Error message is [%s]", err)
+	}
+
+	endpoint, err := kubeAPI.UrlFor(controlPlaneNamespace, "/configmaps/"+k8s.TLSTrustAnchorConfigMapName)
+	if err != nil {
+		return nil, fmt.Errorf("Error generating URL for the trust anchors: %s", err)
+	}
+
+	req, _ := http.NewRequest("GET", endpoint.String(), nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("Error calling the Kubernetes API: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected Kubernetes API response for ConfigMap [%s]: %s", k8s.TLSTrustAnchorConfigMapName, resp.Status)
+	}
+
+	var configMap coreV1.ConfigMap
+	if err := json.NewDecoder(resp.Body).Decode(&configMap); err != nil {
+		return nil, fmt.Errorf("Error decoding ConfigMap [%s]: %s", k8s.TLSTrustAnchorConfigMapName, err)
+	}
+	return []byte(configMap.Data[k8s.TLSTrustAnchorFileName]), nil
+}
+
+// trustAnchorExpiry returns the earliest expiry of the PEM-encoded
+// certificates in anchors.
+func trustAnchorExpiry(anchors []byte) (time.Time, error) {
+	var expiry time.Time
+	for {
+		var block *pem.Block
+		block, anchors = pem.Decode(anchors)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("Error parsing trust anchor: %s", err)
+		}
+		if expiry.IsZero() || cert.NotAfter.Before(expiry) {
+			expiry = cert.NotAfter
+		}
+	}
+
+	if expiry.IsZero() {
+		return time.Time{}, fmt.Errorf("ConfigMap [%s] has no certificates", k8s.TLSTrustAnchorConfigMapName)
+	}
+	return expiry, nil
+}
+
+func renderStatus(w io.Writer, lines []statusLine) {
+	tw := tabwriter.NewWriter(w, 0, 0, padding, ' ', 0)
+	for _, line := range lines {
+		fmt.Fprintf(tw, "%s\t%s %s\n", line.subject, line.glyph, line.message)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	"github.com/linkerd/linkerd2/controller/api/public/publictest"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+func TestControlPlaneStatus(t *testing.T) {
+	pods := &pb.ListPodsResponse{Pods: []*pb.Pod{
+		{Name: "linkerd/controller", ControlPlane: true, Status: "Running"},
+		{Name: "linkerd/web", ControlPlane: true, Status: "Pending"},
+		{Name: "emojivoto/web", Status: "Pending"},
+	}}
+	checks := &healthcheckPb.SelfCheckResponse{Results: []*healthcheckPb.CheckResult{
+		{Status: healthcheckPb.CheckStatus_OK},
+		{Status: healthcheckPb.CheckStatus_OK},
+	}}
+
+	line := controlPlaneStatus(checks, pods)
+	expected := statusLine{"Control plane", statusFailGlyph, "1/2 pods running, 2/2 checks passed"}
+	if line != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, line)
+	}
+}
+
+func TestDataPlaneStatus(t *testing.T) {
+	testCases := []struct {
+		pods     []*pb.Pod
+		expected statusLine
+	}{
+		{
+			[]*pb.Pod{{Added: true, ProxyVersion: "v18.8.1"}, {Added: true, ProxyVersion: "v18.8.1"}, {}},
+			statusLine{"Data plane", statusOkGlyph, "2 proxies, version v18.8.1"},
+		},
+		{
+			[]*pb.Pod{{Added: true, ProxyVersion: "v18.7.3"}, {Added: true, ProxyVersion: "v18.8.1"}, {Added: true, ProxyVersion: "v18.8.1"}, {Added: true}},
+			statusLine{"Data plane", statusWarnGlyph, "4 proxies, versions v18.8.1 (2), unknown (1), v18.7.3 (1)"},
+		},
+		{
+			[]*pb.Pod{{ProxyVersion: "v18.8.1"}},
+			statusLine{"Data plane", statusWarnGlyph, "no proxies are reporting metrics"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected.message, func(t *testing.T) {
+			line := dataPlaneStatus(&pb.ListPodsResponse{Pods: tc.pods})
+			if line != tc.expected {
+				t.Fatalf("Expected %+v, got %+v", tc.expected, line)
+			}
+		})
+	}
+}
+
+func TestTrafficStatus(t *testing.T) {
+	t.Run("Sums traffic across namespaces", func(t *testing.T) {
+		client := publictest.NewMockApiClient()
+		response := public.GenStatSummaryResponse("emojivoto", k8s.Namespace, "", nil)
+		other := public.GenStatSummaryResponse("linkerd", k8s.Namespace, "", nil)
+		otherRow := other.GetOk().StatTables[0].GetPodGroup().Rows[0]
+		otherRow.Stats.FailureCount = 123
+		otherRow.Stats.TlsRequestCount = 0
+		table := response.GetOk().StatTables[0].GetPodGroup()
+		table.Rows = append(table.Rows, otherRow)
+		client.SetStatSummaryResponses(&response)
+
+		line := trafficStatus(client, newStatusOptions())
+		expected := statusLine{"Traffic", statusOkGlyph, "66.67% success, 6.2rps, 33% TLS over 1m; lowest: linkerd 50.00%"}
+		if line != expected {
+			t.Fatalf("Expected %+v, got %+v", expected, line)
+		}
+	})
+
+	t.Run("Warns when there's no traffic", func(t *testing.T) {
+		client := publictest.NewMockApiClient()
+
+		line := trafficStatus(client, newStatusOptions())
+		expected := statusLine{"Traffic", statusWarnGlyph, "no traffic in the last 1m"}
+		if line != expected {
+			t.Fatalf("Expected %+v, got %+v", expected, line)
+		}
+	})
+}
+
+func TestTrustAnchorStatus(t *testing.T) {
+	notAfter := time.Date(2027, 10, 14, 0, 0, 0, 0, time.UTC)
+	anchors := genTrustAnchor(t, notAfter)
+
+	newKubeAPI := func(t *testing.T, status int, configMap *coreV1.ConfigMap) (*k8s.MockKubeApi, func()) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(status)
+			if configMap != nil {
+				json.NewEncoder(w).Encode(configMap)
+			}
+		}))
+		u, err := url.Parse(server.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return &k8s.MockKubeApi{
+			UrlForUrlToReturn:       u,
+			NewClientClientToReturn: server.Client(),
+		}, server.Close
+	}
+
+	testCases := []struct {
+		desc      string
+		status    int
+		configMap *coreV1.ConfigMap
+		now       time.Time
+		expected  statusLine
+	}{
+		{
+			"Reports the expiry of the trust anchor",
+			http.StatusOK,
+			&coreV1.ConfigMap{Data: map[string]string{k8s.TLSTrustAnchorFileName: string(anchors)}},
+			notAfter.Add(-100 * 24 * time.Hour),
+			statusLine{"Trust anchor", statusOkGlyph, "expires in 100 days (2027-10-14)"},
+		},
+		{
+			"Warns when the trust anchor expires soon",
+			http.StatusOK,
+			&coreV1.ConfigMap{Data: map[string]string{k8s.TLSTrustAnchorFileName: string(anchors)}},
+			notAfter.Add(-7 * 24 * time.Hour),
+			statusLine{"Trust anchor", statusWarnGlyph, "expires in 7 days (2027-10-14)"},
+		},
+		{
+			"Fails when the trust anchor has expired",
+			http.StatusOK,
+			&coreV1.ConfigMap{Data: map[string]string{k8s.TLSTrustAnchorFileName: string(anchors)}},
+			notAfter.Add(time.Hour),
+			statusLine{"Trust anchor", statusFailGlyph, "expired on 2027-10-14"},
+		},
+		{
+			"Reports TLS as disabled without trust anchors",
+			http.StatusNotFound,
+			nil,
+			notAfter,
+			statusLine{"Trust anchor", statusOkGlyph, "TLS is not enabled"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			kubeAPI, stop := newKubeAPI(t, tc.status, tc.configMap)
+			defer stop()
+
+			line := trustAnchorStatus(kubeAPI, tc.now)
+			if line != tc.expected {
+				t.Fatalf("Expected %+v, got %+v", tc.expected, line)
+			}
+			if kubeAPI.UrlExtraPathStartingWithSlashReceived != "/configmaps/"+k8s.TLSTrustAnchorConfigMapName {
+				t.Fatalf("Expected a request for the trust anchor ConfigMap, got [%s]", kubeAPI.UrlExtraPathStartingWithSlashReceived)
+			}
+		})
+	}
+}
+
+func TestRenderStatus(t *testing.T) {
+	lines := []statusLine{
+		{"Control plane", statusOkGlyph, "5/5 pods running, 2/2 checks passed"},
+		{"Trust anchor", statusWarnGlyph, "expires in 7 days (2027-10-14)"},
+	}
+	expectedOutput := `Control plane   ✔ 5/5 pods running, 2/2 checks passed
+Trust anchor    ⚠ expires in 7 days (2027-10-14)
+`
+
+	var buf bytes.Buffer
+	renderStatus(&buf, lines)
+	if buf.String() != expectedOutput {
+		t.Fatalf("Wrong output:\n expected: \n%s\n, got: \n%s", expectedOutput, buf.String())
+	}
+}
+
+func genTrustAnchor(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Cluster-local Managed Pod CA"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
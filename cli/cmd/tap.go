@@ -1,40 +1,104 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/linkerd/linkerd2/controller/api/public"
 	"github.com/linkerd/linkerd2/controller/api/util"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/format"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+const (
+	wideOutput = "wide"
+	jsonOutput = "json"
+
+	// tapReconnectMaxBackoff caps the time between attempts to re-establish
+	// a failed tap stream.
+	tapReconnectMaxBackoff = 30 * time.Second
+)
+
+// tapReconnectBackoff is the time before the first attempt to re-establish
+// a failed tap stream. It doubles with each consecutive failure.
+var tapReconnectBackoff = time.Second
+
 type tapOptions struct {
-	namespace   string
-	toResource  string
-	toNamespace string
-	maxRps      float32
-	scheme      string
-	method      string
-	authority   string
-	path        string
+	namespace     string
+	toResource    string
+	toNamespace   string
+	fromResource  string
+	fromNamespace string
+	maxRps        float32
+	scheme        string
+	method        string
+	authority     string
+	path          string
+	notTo         string
+	notMethod     string
+	notPath       string
+	minLatency    time.Duration
+	status        string
+	grpcStatuses  []string
+	timeFormat    string
+	output        string
+	record        string
+	replay        string
+	duration      time.Duration
+	maxEvents     uint
+	maxReconnects uint
+
+	sampleRate      float32
+	pathSampleRates []string
+
+	outputFile        string
+	outputFileMaxSize int64
+	outputFileMaxAge  time.Duration
 }
 
 func newTapOptions() *tapOptions {
 	return &tapOptions{
-		namespace:   "default",
-		toResource:  "",
-		toNamespace: "",
-		maxRps:      1.0,
-		scheme:      "",
-		method:      "",
-		authority:   "",
-		path:        "",
+		namespace:     "default",
+		toResource:    "",
+		toNamespace:   "",
+		fromResource:  "",
+		fromNamespace: "",
+		maxRps:        1.0,
+		scheme:        "",
+		method:        "",
+		authority:     "",
+		path:          "",
+		notTo:         "",
+		notMethod:     "",
+		notPath:       "",
+		minLatency:    0,
+		status:        "",
+		grpcStatuses:  []string{},
+		timeFormat:    "",
+		output:        "",
+		record:        "",
+		replay:        "",
+		duration:      0,
+		maxEvents:     0,
+		maxReconnects: 10,
+
+		sampleRate:      0,
+		pathSampleRates: []string{},
+
+		outputFile:        "",
+		outputFileMaxSize: 0,
+		outputFileMaxAge:  0,
 	}
 }
 
@@ -61,7 +125,12 @@ func newCmdTap() *cobra.Command {
   * namespaces
   * pods
   * replicationcontrollers
-  * services (only supported as a "--to" resource)`,
+  * services (only supported as a "--to" or "--from" resource)
+
+  Tapping requires the "watch" verb on the tap subresource of the target, in
+  the <namespace>.tap.linkerd.io API group of the control plane. The
+  linkerd-<namespace>-tap ClusterRole that install creates grants it for every
+  resource type.`,
 		Example: `  # tap the web deployment in the default namespace
   linkerd tap deploy/web
 
@@ -69,28 +138,57 @@ func newCmdTap() *cobra.Command {
   linkerd tap pod/web-dlbvj
 
   # tap the test namespace, filter by request to prod namespace
-  linkerd tap ns/test --to ns/prod`,
-		Args:      cobra.RangeArgs(1, 2),
+  linkerd tap ns/test --to ns/prod
+
+  # tap the web deployment, only showing requests sent from the backends of the gateway service
+  linkerd tap deploy/web --from svc/gateway
+
+  # tap the web deployment, including each destination's workload
+  linkerd tap deploy/web -o wide
+
+  # tap the web deployment, excluding health checks and metrics scrapes
+  linkerd tap deploy/web --not-path "/(healthz|metrics)$"
+
+  # tap the web deployment, only showing requests that took at least 250ms
+  linkerd tap deploy/web --min-latency 250ms
+
+  # tap the web deployment, only showing requests that failed with a 5xx status
+  linkerd tap deploy/web --status 5xx
+
+  # tap the voting deployment, only showing gRPC requests that failed with NOT_FOUND or UNAVAILABLE
+  linkerd tap deploy/voting --grpc-status NOT_FOUND,UNAVAILABLE
+
+  # tap the web deployment, showing 1% of its requests and none of its health checks
+  linkerd tap deploy/web --sample-rate 0.01 --path-sample-rate /healthz=0
+
+  # tap the web deployment, recording the events to render them again later
+  linkerd tap deploy/web --record web.pb
+  linkerd tap --replay web.pb -o wide
+
+  # tap the web deployment for five minutes, or until 1000 events were received
+  linkerd tap deploy/web --duration 5m --max-events 1000
+
+  # tap the web deployment to JSON files, starting a new file every hour
+  linkerd tap deploy/web -o json --output-file web.json --output-file-max-age 1h`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if options.replay != "" {
+				if len(args) != 0 {
+					return fmt.Errorf("a resource cannot be tapped with --replay")
+				}
+				return nil
+			}
+			return cobra.RangeArgs(1, 2)(cmd, args)
+		},
 		ValidArgs: util.ValidTargets,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			requestParams := util.TapRequestParams{
-				Resource:    strings.Join(args, "/"),
-				Namespace:   options.namespace,
-				ToResource:  options.toResource,
-				ToNamespace: options.toNamespace,
-				MaxRps:      options.maxRps,
-				Scheme:      options.scheme,
-				Method:      options.method,
-				Authority:   options.authority,
-				Path:        options.path,
-			}
-
-			req, err := util.BuildTapByResourceRequest(requestParams)
+			err := options.validate()
 			if err != nil {
 				return err
 			}
 
-			return requestTapByResourceFromAPI(os.Stdout, validatedPublicAPIClient(), req)
+			return runInterruptible(func(ctx context.Context) error {
+				return runTap(ctx, args, options)
+			})
 		},
 	}
 
@@ -100,31 +198,350 @@ func newCmdTap() *cobra.Command {
 		"Display requests to this resource")
 	cmd.PersistentFlags().StringVar(&options.toNamespace, "to-namespace", options.toNamespace,
 		"Sets the namespace used to lookup the \"--to\" resource; by default the current \"--namespace\" is used")
+	cmd.PersistentFlags().StringVar(&options.fromResource, "from", options.fromResource,
+		"Display requests from the pods of this resource, which are looked up when tap starts")
+	cmd.PersistentFlags().StringVar(&options.fromNamespace, "from-namespace", options.fromNamespace,
+		"Sets the namespace used to lookup the \"--from\" resource; by default the current \"--namespace\" is used")
 	cmd.PersistentFlags().Float32Var(&options.maxRps, "max-rps", options.maxRps,
 		"Maximum requests per second to tap.")
+	cmd.PersistentFlags().Float32Var(&options.sampleRate, "sample-rate", options.sampleRate,
+		"Display each request with this probability, e.g. \"0.01\"; proxies report up to 10 times \"--max-rps\" to sample from, and at most 1000 requests per second per pod; 0 to display every request")
+	cmd.PersistentFlags().StringArrayVar(&options.pathSampleRates, "path-sample-rate", options.pathSampleRates,
+		"Override \"--sample-rate\" for requests with paths that start with a match for a regular expression, e.g. \"/healthz=0\"; can be repeated, and the first match applies")
 	cmd.PersistentFlags().StringVar(&options.scheme, "scheme", options.scheme,
 		"Display requests with this scheme")
 	cmd.PersistentFlags().StringVar(&options.method, "method", options.method,
 		"Display requests with this HTTP method")
 	cmd.PersistentFlags().StringVar(&options.authority, "authority", options.authority,
-		"Display requests with this :authority")
+		"Display requests with an :authority that matches this regular expression, e.g. \"web-svc\\.emojivoto.*\"; requests that only match its literal prefix still count toward \"--max-rps\"")
 	cmd.PersistentFlags().StringVar(&options.path, "path", options.path,
-		"Display requests with paths that start with this prefix")
+		"Display requests with paths that start with a match for this regular expression, e.g. \"/books/[0-9]+\"; requests that only match its literal prefix still count toward \"--max-rps\"")
+	cmd.PersistentFlags().StringVar(&options.notTo, "not-to", options.notTo,
+		"Exclude requests to this resource, which is looked up in the \"--to-namespace\"")
+	cmd.PersistentFlags().StringVar(&options.notMethod, "not-method", options.notMethod,
+		"Exclude requests with this HTTP method")
+	cmd.PersistentFlags().StringVar(&options.notPath, "not-path", options.notPath,
+		"Exclude requests with paths that start with a match for this regular expression")
+	cmd.PersistentFlags().DurationVar(&options.minLatency, "min-latency", options.minLatency,
+		"Only display requests whose response took at least this long to start, or that have waited this long without one, e.g. \"250ms\"")
+	cmd.PersistentFlags().StringVar(&options.status, "status", options.status,
+		"Only display requests whose response has this HTTP status, e.g. \"503\", or a status in this class, e.g. \"5xx\"")
+	cmd.PersistentFlags().StringSliceVar(&options.grpcStatuses, "grpc-status", options.grpcStatuses,
+		"Only display gRPC requests whose response ends with one of these statuses, e.g. \"NOT_FOUND,UNAVAILABLE\"")
+	cmd.PersistentFlags().StringVar(&options.timeFormat, "time-format", options.timeFormat,
+		"Prefix each event with the time it was received; one of: relative, rfc3339, unix-millis")
+	cmd.PersistentFlags().StringVarP(&options.output, "output", "o", options.output,
+		fmt.Sprintf("Output format; one of: \"%s\" or \"%s\"", wideOutput, jsonOutput))
+	cmd.PersistentFlags().StringVar(&options.record, "record", options.record,
+		"Also write the tap events to this file, to be rendered again with \"--replay\"")
+	cmd.PersistentFlags().StringVar(&options.replay, "replay", options.replay,
+		"Render the tap events recorded to this file with \"--record\", instead of tapping a resource")
+	cmd.PersistentFlags().DurationVar(&options.duration, "duration", options.duration,
+		"Stop tapping after this long, e.g. \"5m\"; 0 to tap until interrupted")
+	cmd.PersistentFlags().UintVar(&options.maxEvents, "max-events", options.maxEvents,
+		"Stop tapping after this many events; 0 for no limit")
+	cmd.PersistentFlags().UintVar(&options.maxReconnects, "max-reconnects", options.maxReconnects,
+		"Give up after failing to re-establish a dropped tap stream this many times in a row; 0 to exit when the stream drops")
+	cmd.PersistentFlags().StringVar(&options.outputFile, "output-file", options.outputFile,
+		"Write the tap events to this file instead of stdout")
+	cmd.PersistentFlags().Int64Var(&options.outputFileMaxSize, "output-file-max-size", options.outputFileMaxSize,
+		"Rotate the \"--output-file\" once it reaches this many megabytes; 0 for no limit")
+	cmd.PersistentFlags().DurationVar(&options.outputFileMaxAge, "output-file-max-age", options.outputFileMaxAge,
+		"Rotate the \"--output-file\" once it has been written to for this long, e.g. \"1h\"; 0 for no limit")
 
 	return cmd
 }
 
-func requestTapByResourceFromAPI(w io.Writer, client pb.ApiClient, req *pb.TapByResourceRequest) error {
-	rsp, err := client.TapByResource(context.Background(), req)
+// validate performs all validation on the command-line options.
+// It returns the first error encountered, or `nil` if the options are valid.
+func (o *tapOptions) validate() error {
+	if o.minLatency < 0 {
+		return fmt.Errorf("--min-latency must not be negative")
+	}
+
+	if o.timeFormat != "" {
+		if _, err := format.ParseTimeFormat(o.timeFormat); err != nil {
+			return err
+		}
+	}
+
+	switch o.output {
+	case "", wideOutput, jsonOutput:
+	default:
+		return fmt.Errorf("output format must be one of: %s, %s", wideOutput, jsonOutput)
+	}
+	if o.output == jsonOutput && o.timeFormat != "" {
+		return fmt.Errorf("--time-format is not supported with %s output", jsonOutput)
+	}
+	if o.duration < 0 {
+		return fmt.Errorf("--duration must not be negative")
+	}
+	if o.outputFile == "" && (o.outputFileMaxSize != 0 || o.outputFileMaxAge != 0) {
+		return fmt.Errorf("--output-file-max-size and --output-file-max-age require --output-file")
+	}
+	if o.outputFileMaxSize < 0 || o.outputFileMaxAge < 0 {
+		return fmt.Errorf("--output-file-max-size and --output-file-max-age must not be negative")
+	}
+	if o.replay != "" {
+		if o.record != "" {
+			return fmt.Errorf("--record and --replay flags are mutually exclusive")
+		}
+		// recordings don't hold the time each event was received
+		if o.timeFormat != "" {
+			return fmt.Errorf("--time-format is not supported with --replay")
+		}
+		if o.duration != 0 {
+			return fmt.Errorf("--duration is not supported with --replay")
+		}
+	}
+
+	return nil
+}
+
+// runTap taps the resource named by args, or replays a recording, until the
+// stream ends or ctx is canceled.
+func runTap(ctx context.Context, args []string, options *tapOptions) error {
+	var w io.Writer = os.Stdout
+	if options.outputFile != "" {
+		file, err := newRotatingFile(options.outputFile, options.outputFileMaxSize*megabyte, options.outputFileMaxAge)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if options.replay != "" {
+		return replayTap(w, options)
+	}
+
+	requestParams := util.TapRequestParams{
+		Resource:    strings.Join(args, "/"),
+		Namespace:   options.namespace,
+		ToResource:  options.toResource,
+		ToNamespace: options.toNamespace,
+		MaxRps:      options.maxRps,
+		Scheme:      options.scheme,
+		Method:      options.method,
+		Authority:   options.authority,
+		Path:        options.path,
+
+		FromResource:  options.fromResource,
+		FromNamespace: options.fromNamespace,
+
+		NotToResource: options.notTo,
+		NotMethod:     options.notMethod,
+		NotPath:       options.notPath,
+
+		MinLatency:     options.minLatency,
+		ResponseStatus: options.status,
+		GrpcStatuses:   options.grpcStatuses,
+
+		SampleRate:      options.sampleRate,
+		PathSampleRates: options.pathSampleRates,
+	}
+
+	req, err := util.BuildTapByResourceRequest(requestParams)
 	if err != nil {
 		return err
 	}
-	return renderTap(w, rsp)
+
+	return requestTapByResourceFromAPI(ctx, w, validatedTapAPIClient(), req, options)
 }
 
-func renderTap(w io.Writer, tapClient pb.Api_TapByResourceClient) error {
+func requestTapByResourceFromAPI(ctx context.Context, w io.Writer, client public.TapAPIClient, req *pb.TapByResourceRequest, options *tapOptions) error {
+	var recording io.WriteCloser
+	if options.record != "" {
+		var err error
+		recording, err = os.Create(options.record)
+		if err != nil {
+			return err
+		}
+		defer recording.Close()
+	}
+
+	var cancel context.CancelFunc
+	if options.duration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, options.duration)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	// closes the stream once --max-events were received
+	defer cancel()
+
+	connect := func() (tapEventSource, error) {
+		return client.TapByResource(ctx, req)
+	}
+	// the first connection isn't retried, so that errors in the request, or
+	// a missing control plane, are reported right away
+	rsp, err := connect()
+	if err != nil {
+		return err
+	}
+
+	reconnector := newTapReconnector(ctx, rsp, connect, options.maxReconnects, os.Stderr)
+	var events tapEventSource = &tapDeadline{tapEventSource: reconnector, ctx: ctx}
+	if recording != nil {
+		events = &tapRecorder{tapEventSource: events, w: recording}
+	}
+	return renderTap(w, events, options)
+}
+
+func replayTap(w io.Writer, options *tapOptions) error {
+	recording, err := os.Open(options.replay)
+	if err != nil {
+		return err
+	}
+	defer recording.Close()
+
+	return renderTap(w, &tapReplay{r: bufio.NewReader(recording)}, options)
+}
+
+// tapEventSource is a stream of tap events, from the public API or from a
+// recording.
+type tapEventSource interface {
+	Recv() (*pb.TapEvent, error)
+}
+
+// tapDeadline ends the stream cleanly once the --duration has passed, or tap
+// is interrupted, rather than with the error that the canceled stream
+// returns.
+type tapDeadline struct {
+	tapEventSource
+	ctx context.Context
+}
+
+func (d *tapDeadline) Recv() (*pb.TapEvent, error) {
+	event, err := d.tapEventSource.Recv()
+	if err != nil && d.ctx.Err() != nil {
+		return nil, io.EOF
+	}
+	return event, err
+}
+
+// tapReconnector re-establishes the tap stream, with exponential backoff,
+// when it fails before tap is done, and prints a notice to notices. Proxies
+// only report new requests to a new stream, so the events of requests that
+// were in flight when the stream failed are lost, rather than repeated.
+type tapReconnector struct {
+	ctx           context.Context
+	stream        tapEventSource
+	connect       func() (tapEventSource, error)
+	maxReconnects uint
+	notices       io.Writer
+}
+
+func newTapReconnector(ctx context.Context, stream tapEventSource, connect func() (tapEventSource, error), maxReconnects uint, notices io.Writer) *tapReconnector {
+	return &tapReconnector{
+		ctx:           ctx,
+		stream:        stream,
+		connect:       connect,
+		maxReconnects: maxReconnects,
+		notices:       notices,
+	}
+}
+
+func (r *tapReconnector) Recv() (*pb.TapEvent, error) {
+	failures := uint(0)
+	backoff := tapReconnectBackoff
+
+	for {
+		event, err := r.stream.Recv()
+		if err == nil {
+			return event, nil
+		}
+		// io.EOF is a stream that ended cleanly, e.g. a replay
+		if err == io.EOF || r.ctx.Err() != nil {
+			return nil, err
+		}
+
+		for {
+			if failures == r.maxReconnects {
+				return nil, err
+			}
+			failures++
+
+			fmt.Fprintf(r.notices, "Tap stream failed: %s; reconnecting in %s\n", err, backoff)
+			select {
+			case <-time.After(backoff):
+			case <-r.ctx.Done():
+				return nil, r.ctx.Err()
+			}
+			backoff *= 2
+			if backoff > tapReconnectMaxBackoff {
+				backoff = tapReconnectMaxBackoff
+			}
+
+			stream, connectErr := r.connect()
+			if connectErr == nil {
+				fmt.Fprintln(r.notices, "Tap stream reconnected")
+				r.stream = stream
+				break
+			}
+			err = connectErr
+		}
+	}
+}
+
+// tapRecorder writes each event it receives to w, as its length in bytes
+// (a varint) followed by the protobuf-encoded event.
+type tapRecorder struct {
+	tapEventSource
+	w io.Writer
+}
+
+func (r *tapRecorder) Recv() (*pb.TapEvent, error) {
+	event, err := r.tapEventSource.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := proto.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	size := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(size, uint64(len(buf)))
+	if _, err := r.w.Write(append(size[:n], buf...)); err != nil {
+		return nil, fmt.Errorf("failed to record tap event: %s", err)
+	}
+	return event, nil
+}
+
+// maxRecordedTapEventSize bounds the size of an event read from a tap
+// recording, so that a corrupt size isn't allocated.
+const maxRecordedTapEventSize = 4 * megabyte
+
+// tapReplay reads back the events written by tapRecorder.
+type tapReplay struct {
+	r *bufio.Reader
+}
+
+func (r *tapReplay) Recv() (*pb.TapEvent, error) {
+	size, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		// io.EOF is only returned if the recording ends between events
+		return nil, err
+	}
+
+	if size > maxRecordedTapEventSize {
+		return nil, fmt.Errorf("invalid tap recording: event of %d bytes exceeds the maximum of %d", size, maxRecordedTapEventSize)
+	}
+
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r.r, buf); err != nil {
+		return nil, fmt.Errorf("truncated tap recording: %s", err)
+	}
+	var event pb.TapEvent
+	if err := proto.Unmarshal(buf, &event); err != nil {
+		return nil, fmt.Errorf("invalid tap recording: %s", err)
+	}
+	return &event, nil
+}
+
+func renderTap(w io.Writer, tapClient tapEventSource, options *tapOptions) error {
 	tableWriter := tabwriter.NewWriter(w, 0, 0, 0, ' ', tabwriter.AlignRight)
-	err := writeTapEventsToBuffer(tapClient, tableWriter)
+	err := writeTapEventsToBuffer(tapClient, tableWriter, options)
 	if err != nil {
 		return err
 	}
@@ -133,8 +550,12 @@ func renderTap(w io.Writer, tapClient pb.Api_TapByResourceClient) error {
 	return nil
 }
 
-func writeTapEventsToBuffer(tapClient pb.Api_TapByResourceClient, w *tabwriter.Writer) error {
-	for {
+func writeTapEventsToBuffer(tapClient tapEventSource, w *tabwriter.Writer, options *tapOptions) error {
+	// options are validated before the stream is opened
+	timeFormat, _ := format.ParseTimeFormat(options.timeFormat)
+	start := time.Now()
+
+	for received := uint(0); options.maxEvents == 0 || received < options.maxEvents; received++ {
 		log.Debug("Waiting for data...")
 		event, err := tapClient.Recv()
 		if err == io.EOF {
@@ -144,7 +565,14 @@ func writeTapEventsToBuffer(tapClient pb.Api_TapByResourceClient, w *tabwriter.W
 			fmt.Fprintln(os.Stderr, err)
 			break
 		}
-		_, err = fmt.Fprintln(w, util.RenderTapEvent(event))
+		output, err := renderTapEvent(event, options.output)
+		if err != nil {
+			return err
+		}
+		if options.timeFormat != "" {
+			output = timeFormat.Time(time.Now(), start) + " " + output
+		}
+		_, err = fmt.Fprintln(w, output)
 		if err != nil {
 			return err
 		}
@@ -152,3 +580,18 @@ func writeTapEventsToBuffer(tapClient pb.Api_TapByResourceClient, w *tabwriter.W
 
 	return nil
 }
+
+func renderTapEvent(event *pb.TapEvent, output string) (string, error) {
+	switch output {
+	case wideOutput:
+		return util.RenderTapEventWide(event), nil
+	case jsonOutput:
+		e, err := json.Marshal(util.NewTapEventJSON(event))
+		if err != nil {
+			return "", err
+		}
+		return string(e), nil
+	default:
+		return util.RenderTapEvent(event), nil
+	}
+}
@@ -1,14 +1,27 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/linkerd/linkerd2/controller/api/public"
+	"github.com/linkerd/linkerd2/controller/api/public/publictest"
 	"github.com/linkerd/linkerd2/controller/api/util"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/addr"
@@ -78,7 +91,7 @@ func TestRequestTapByResourceFromAPI(t *testing.T) {
 		}
 
 		writer := bytes.NewBufferString("")
-		err = requestTapByResourceFromAPI(writer, mockApiClient, req)
+		err = requestTapByResourceFromAPI(context.Background(), writer, mockApiClient, req, newTapOptions())
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -116,7 +129,7 @@ func TestRequestTapByResourceFromAPI(t *testing.T) {
 		}
 
 		writer := bytes.NewBufferString("")
-		err = requestTapByResourceFromAPI(writer, mockApiClient, req)
+		err = requestTapByResourceFromAPI(context.Background(), writer, mockApiClient, req, newTapOptions())
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -132,6 +145,218 @@ func TestRequestTapByResourceFromAPI(t *testing.T) {
 		}
 	})
 
+	t.Run("Should render events received before the stream failed", func(t *testing.T) {
+		req, err := util.BuildTapByResourceRequest(util.TapRequestParams{Resource: "pod/pod-666"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		event := createEvent(
+			&pb.TapEvent_Http{
+				Event: &pb.TapEvent_Http_RequestInit_{
+					RequestInit: &pb.TapEvent_Http_RequestInit{
+						Id:   &pb.TapEvent_Http_StreamId{Base: 1},
+						Path: "/some/path",
+					},
+				},
+			},
+			map[string]string{},
+		)
+		mockApiClient := publictest.NewMockApiClient()
+		mockApiClient.SetTapScript(
+			publictest.Event(&event),
+			publictest.Sleep(10*time.Millisecond),
+			publictest.Fail(errors.New("connection reset")),
+		)
+
+		options := newTapOptions()
+		options.maxReconnects = 0
+		writer := bytes.NewBufferString("")
+		err = requestTapByResourceFromAPI(context.Background(), writer, mockApiClient, req, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedOutput := "req id=1:0 proxy=out src=0.0.0.1:0 dst=0.0.0.9:0 tls= :method=GET :authority= :path=/some/path\n"
+		if writer.String() != expectedOutput {
+			t.Fatalf("Expected function to render:\n%s\bbut got:\n%s", expectedOutput, writer.String())
+		}
+	})
+
+	t.Run("Should reconnect when the stream fails", func(t *testing.T) {
+		defer func(backoff time.Duration) { tapReconnectBackoff = backoff }(tapReconnectBackoff)
+		tapReconnectBackoff = time.Millisecond
+
+		req, err := util.BuildTapByResourceRequest(util.TapRequestParams{Resource: "pod/pod-666"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var events []pb.TapEvent
+		for _, path := range []string{"/first", "/second"} {
+			events = append(events, createEvent(
+				&pb.TapEvent_Http{
+					Event: &pb.TapEvent_Http_RequestInit_{
+						RequestInit: &pb.TapEvent_Http_RequestInit{
+							Id:   &pb.TapEvent_Http_StreamId{Base: 1},
+							Path: path,
+						},
+					},
+				},
+				map[string]string{},
+			))
+		}
+		mockApiClient := publictest.NewMockApiClient()
+		mockApiClient.SetTapScript(
+			publictest.Event(&events[0]),
+			publictest.Fail(errors.New("connection reset")),
+		)
+		connect := func() (tapEventSource, error) {
+			// proxies only report new requests to a new stream
+			mockApiClient.SetTapScript(
+				publictest.Event(&events[1]),
+				publictest.Fail(errors.New("connection reset")),
+			)
+			return mockApiClient.TapByResource(context.Background(), req)
+		}
+
+		stream, err := mockApiClient.TapByResource(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		notices := bytes.NewBufferString("")
+		reconnector := newTapReconnector(context.Background(), stream, connect, 1, notices)
+
+		for _, expected := range events {
+			event, err := reconnector.Recv()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !proto.Equal(event, &expected) {
+				t.Fatalf("Expected event %v, got %v", expected, event)
+			}
+		}
+		if _, err := reconnector.Recv(); err == nil || err.Error() != "connection reset" {
+			t.Fatalf("Expected the stream error after --max-reconnects, got %v", err)
+		}
+
+		expectedNotices := "Tap stream failed: connection reset; reconnecting in 1ms\n" +
+			"Tap stream reconnected\n" +
+			"Tap stream failed: connection reset; reconnecting in 1ms\n" +
+			"Tap stream reconnected\n"
+		if notices.String() != expectedNotices {
+			t.Fatalf("Expected notices:\n%s\nbut got:\n%s", expectedNotices, notices.String())
+		}
+	})
+
+	t.Run("Should replay recorded events", func(t *testing.T) {
+		req, err := util.BuildTapByResourceRequest(util.TapRequestParams{Resource: "pod/pod-666"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		dir, err := ioutil.TempDir("", "linkerd-tap")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer os.RemoveAll(dir)
+
+		var events []publictest.TapStep
+		for _, path := range []string{"/first", "/second"} {
+			event := createEvent(
+				&pb.TapEvent_Http{
+					Event: &pb.TapEvent_Http_RequestInit_{
+						RequestInit: &pb.TapEvent_Http_RequestInit{
+							Id:   &pb.TapEvent_Http_StreamId{Base: 1},
+							Path: path,
+						},
+					},
+				},
+				map[string]string{},
+			)
+			events = append(events, publictest.Event(&event))
+		}
+		mockApiClient := publictest.NewMockApiClient()
+		mockApiClient.SetTapScript(events...)
+
+		options := newTapOptions()
+		options.record = filepath.Join(dir, "tap.pb")
+		recorded := bytes.NewBufferString("")
+		if err := requestTapByResourceFromAPI(context.Background(), recorded, mockApiClient, req, options); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		options = newTapOptions()
+		options.replay = filepath.Join(dir, "tap.pb")
+		replayed := bytes.NewBufferString("")
+		if err := replayTap(replayed, options); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if replayed.String() != recorded.String() {
+			t.Fatalf("Expected the replay to render:\n%s\nbut got:\n%s", recorded.String(), replayed.String())
+		}
+		if strings.Count(replayed.String(), "\n") != 2 {
+			t.Fatalf("Expected 2 replayed events, got:\n%s", replayed.String())
+		}
+	})
+
+	t.Run("Should stop after --max-events or --duration", func(t *testing.T) {
+		req, err := util.BuildTapByResourceRequest(util.TapRequestParams{Resource: "pod/pod-666"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var events []publictest.TapStep
+		for _, path := range []string{"/first", "/second"} {
+			event := createEvent(
+				&pb.TapEvent_Http{
+					Event: &pb.TapEvent_Http_RequestInit_{
+						RequestInit: &pb.TapEvent_Http_RequestInit{
+							Id:   &pb.TapEvent_Http_StreamId{Base: 1},
+							Path: path,
+						},
+					},
+				},
+				map[string]string{},
+			)
+			events = append(events, publictest.Event(&event))
+		}
+		mockApiClient := publictest.NewMockApiClient()
+		mockApiClient.SetTapScript(append(events, publictest.Block())...)
+
+		options := newTapOptions()
+		options.maxEvents = 1
+		writer := bytes.NewBufferString("")
+		if err := requestTapByResourceFromAPI(context.Background(), writer, mockApiClient, req, options); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Count(writer.String(), "\n") != 1 || !strings.Contains(writer.String(), ":path=/first") {
+			t.Fatalf("Expected only the first event, got:\n%s", writer.String())
+		}
+
+		options = newTapOptions()
+		options.duration = 10 * time.Millisecond
+		writer = bytes.NewBufferString("")
+		if err := requestTapByResourceFromAPI(context.Background(), writer, mockApiClient, req, options); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Count(writer.String(), "\n") != 2 {
+			t.Fatalf("Expected both events before the stream blocked, got:\n%s", writer.String())
+		}
+
+		// Ctrl-C cancels the context, which ends the stream the same way
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		writer = bytes.NewBufferString("")
+		if err := requestTapByResourceFromAPI(ctx, writer, mockApiClient, req, newTapOptions()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Count(writer.String(), "\n") != 2 {
+			t.Fatalf("Expected both events before tap was interrupted, got:\n%s", writer.String())
+		}
+	})
+
 	t.Run("Should return error if stream returned error", func(t *testing.T) {
 		t.SkipNow()
 		resourceType := k8s.Pod
@@ -155,13 +380,222 @@ func TestRequestTapByResourceFromAPI(t *testing.T) {
 		}
 
 		writer := bytes.NewBufferString("")
-		err = requestTapByResourceFromAPI(writer, mockApiClient, req)
+		err = requestTapByResourceFromAPI(context.Background(), writer, mockApiClient, req, newTapOptions())
 		if err == nil {
 			t.Fatalf("Expecting error, got nothing but output [%s]", writer.String())
 		}
 	})
 }
 
+func TestTapOptionsValidate(t *testing.T) {
+	t.Run("Accepts supported time formats", func(t *testing.T) {
+		options := newTapOptions()
+		options.timeFormat = "rfc3339"
+
+		if err := options.validate(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Rejects unsupported time formats", func(t *testing.T) {
+		options := newTapOptions()
+		options.timeFormat = "iso8601"
+		expectedError := "unsupported time format [iso8601], must be one of: relative, rfc3339, unix-millis"
+
+		err := options.validate()
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Rejects unsupported output formats", func(t *testing.T) {
+		options := newTapOptions()
+		options.output = "yaml"
+		expectedError := "output format must be one of: wide, json"
+
+		err := options.validate()
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Rejects time formats with --replay", func(t *testing.T) {
+		options := newTapOptions()
+		options.replay = "tap.pb"
+		options.timeFormat = "relative"
+		expectedError := "--time-format is not supported with --replay"
+
+		err := options.validate()
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Rejects time formats with JSON output", func(t *testing.T) {
+		options := newTapOptions()
+		options.output = jsonOutput
+		options.timeFormat = "rfc3339"
+		expectedError := "--time-format is not supported with json output"
+
+		err := options.validate()
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Rejects a negative minimum latency", func(t *testing.T) {
+		options := newTapOptions()
+		options.minLatency = -time.Second
+		expectedError := "--min-latency must not be negative"
+
+		err := options.validate()
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Rejects --duration with --replay", func(t *testing.T) {
+		options := newTapOptions()
+		options.replay = "tap.pb"
+		options.duration = time.Minute
+		expectedError := "--duration is not supported with --replay"
+
+		err := options.validate()
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Rejects output file limits without --output-file", func(t *testing.T) {
+		options := newTapOptions()
+		options.outputFileMaxAge = time.Hour
+		expectedError := "--output-file-max-size and --output-file-max-age require --output-file"
+
+		err := options.validate()
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+}
+
+func TestTapOutputFile(t *testing.T) {
+	newOutputFile := func(t *testing.T, maxSize int64, maxAge time.Duration) (*rotatingFile, string, func()) {
+		dir, err := ioutil.TempDir("", "linkerd-tap")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		file, err := newRotatingFile(filepath.Join(dir, "tap.log"), maxSize, maxAge)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return file, dir, func() { os.RemoveAll(dir) }
+	}
+
+	readFiles := func(t *testing.T, dir string) []string {
+		names, err := filepath.Glob(filepath.Join(dir, "tap*.log"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		// rotated files sort by their timestamp, before the current file
+		sort.Strings(names)
+		var contents []string
+		for _, name := range names {
+			b, err := ioutil.ReadFile(name)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			contents = append(contents, string(b))
+		}
+		return contents
+	}
+
+	t.Run("Rotates the file once it reaches its maximum size, between lines", func(t *testing.T) {
+		file, dir, cleanup := newOutputFile(t, 10, 0)
+		defer cleanup()
+		start := time.Date(2018, 10, 14, 12, 0, 0, 0, time.UTC)
+		file.now = func() time.Time { start = start.Add(time.Second); return start }
+
+		for _, s := range []string{"req ", "id=0:1\n", "req id=0:2\n", "req id=0:3\n"} {
+			if _, err := file.Write([]byte(s)); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		file.Close()
+
+		expected := []string{"req id=0:1\n", "req id=0:2\n", "req id=0:3\n"}
+		if contents := readFiles(t, dir); !reflect.DeepEqual(contents, expected) {
+			t.Fatalf("Expected files %q, got %q", expected, contents)
+		}
+	})
+
+	t.Run("Rotates the file once it reaches its maximum age", func(t *testing.T) {
+		file, dir, cleanup := newOutputFile(t, 0, time.Minute)
+		defer cleanup()
+		now := time.Now()
+		file.now = func() time.Time { return now }
+		file.opened = now
+
+		file.Write([]byte("req id=0:1\n"))
+		now = now.Add(30 * time.Second)
+		file.Write([]byte("req id=0:2\n"))
+		now = now.Add(30 * time.Second)
+		file.Write([]byte("req id=0:3\n"))
+		file.Close()
+
+		expected := []string{"req id=0:1\nreq id=0:2\n", "req id=0:3\n"}
+		if contents := readFiles(t, dir); !reflect.DeepEqual(contents, expected) {
+			t.Fatalf("Expected files %q, got %q", expected, contents)
+		}
+	})
+
+	t.Run("Keeps every file rotated within the same millisecond", func(t *testing.T) {
+		file, dir, cleanup := newOutputFile(t, 1, 0)
+		defer cleanup()
+		now := time.Date(2018, 10, 14, 12, 0, 0, 0, time.UTC)
+		file.now = func() time.Time { return now }
+
+		for _, s := range []string{"req id=0:1\n", "req id=0:2\n", "req id=0:3\n"} {
+			if _, err := file.Write([]byte(s)); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		file.Close()
+
+		expected := []string{"req id=0:1\n", "req id=0:2\n", "req id=0:3\n"}
+		if contents := readFiles(t, dir); !reflect.DeepEqual(contents, expected) {
+			t.Fatalf("Expected files %q, got %q", expected, contents)
+		}
+	})
+}
+
+func TestTapReplay(t *testing.T) {
+	t.Run("Rejects truncated recordings", func(t *testing.T) {
+		event := createEvent(&pb.TapEvent_Http{}, map[string]string{})
+		var recording bytes.Buffer
+		recorder := &tapRecorder{tapEventSource: &public.MockApi_TapByResourceClient{TapEventsToReturn: []pb.TapEvent{event}}, w: &recording}
+		if _, err := recorder.Recv(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		replay := &tapReplay{r: bufio.NewReader(bytes.NewReader(recording.Bytes()[:recording.Len()-1]))}
+		if _, err := replay.Recv(); err == nil || err == io.EOF {
+			t.Fatalf("Expected an error for a truncated recording, got [%v]", err)
+		}
+	})
+
+	t.Run("Rejects oversized events", func(t *testing.T) {
+		size := make([]byte, binary.MaxVarintLen64)
+		n := binary.PutUvarint(size, 1<<40)
+
+		replay := &tapReplay{r: bufio.NewReader(bytes.NewReader(size[:n]))}
+		_, err := replay.Recv()
+		expectedError := "invalid tap recording: event of 1099511627776 bytes exceeds the maximum of 4194304"
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%v]", expectedError, err)
+		}
+	})
+}
+
 func TestEventToString(t *testing.T) {
 	toTapEvent := func(httpEvent *pb.TapEvent_Http) *pb.TapEvent {
 		streamId := &pb.TapEvent_Http_StreamId{
@@ -315,6 +749,111 @@ func TestEventToString(t *testing.T) {
 		}
 	})
 
+	t.Run("Renders destinations resolved to a service by name", func(t *testing.T) {
+		event := toTapEvent(&pb.TapEvent_Http{})
+		event.DestinationMeta = &pb.TapEvent_EndpointMeta{
+			Labels: map[string]string{"service": "web", "service_port": "http"},
+		}
+
+		expectedOutput := "unknown proxy=out src=1.2.3.4:5555 dst=svc/web:http tls="
+		output := util.RenderTapEvent(event)
+		if output != expectedOutput {
+			t.Fatalf("Expecting command output to be [%s], got [%s]", expectedOutput, output)
+		}
+
+		delete(event.DestinationMeta.Labels, "service_port")
+		expectedOutput = "unknown proxy=out src=1.2.3.4:5555 dst=svc/web:6666 tls="
+		output = util.RenderTapEvent(event)
+		if output != expectedOutput {
+			t.Fatalf("Expecting command output to be [%s], got [%s]", expectedOutput, output)
+		}
+	})
+
+	t.Run("Renders destination workload metadata in wide and JSON output", func(t *testing.T) {
+		event := toTapEvent(&pb.TapEvent_Http{
+			Event: &pb.TapEvent_Http_ResponseInit_{
+				ResponseInit: &pb.TapEvent_Http_ResponseInit{
+					SinceRequestInit: &duration.Duration{Nanos: 999000},
+					HttpStatus:       http.StatusOK,
+				},
+			},
+		})
+		event.DestinationMeta = &pb.TapEvent_EndpointMeta{
+			Labels: map[string]string{
+				"deployment":     "voting",
+				"namespace":      "emojivoto",
+				"pod":            "voting-6b8b9c6f5d-x7k2p",
+				"serviceaccount": "voting",
+			},
+		}
+
+		expectedOutput := "rsp id=7:8 proxy=out src=1.2.3.4:5555 dst=voting-6b8b9c6f5d-x7k2p:6666 tls= :status=200 latency=999µs dst_deployment=voting dst_namespace=emojivoto dst_serviceaccount=voting peer_identity="
+		output, err := renderTapEvent(event, wideOutput)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if output != expectedOutput {
+			t.Fatalf("Expecting command output to be [%s], got [%s]", expectedOutput, output)
+		}
+
+		output, err = renderTapEvent(event, jsonOutput)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var decoded util.TapEventJSON
+		if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+			t.Fatalf("Unexpected error decoding [%s]: %v", output, err)
+		}
+		expectedWorkload := util.TapWorkload{Deployment: "voting", Namespace: "emojivoto", ServiceAccount: "voting"}
+		if decoded.DestinationWorkload != expectedWorkload {
+			t.Fatalf("Expected destination workload %+v, got %+v", expectedWorkload, decoded.DestinationWorkload)
+		}
+		if decoded.Type != "rsp" || decoded.ID != "7:8" || decoded.HTTPStatus != http.StatusOK {
+			t.Fatalf("Unexpected JSON output: %s", output)
+		}
+	})
+
+	t.Run("Renders the TLS identity of the peer in wide and JSON output", func(t *testing.T) {
+		event := toTapEvent(&pb.TapEvent_Http{})
+		event.DestinationMeta = &pb.TapEvent_EndpointMeta{
+			Labels: map[string]string{
+				"control_plane_ns": "linkerd",
+				"deployment":       "voting",
+				"namespace":        "emojivoto",
+				"pod":              "voting-6b8b9c6f5d-x7k2p",
+				"tls":              "true",
+			},
+		}
+
+		expectedIdentity := "voting.deployment.emojivoto.linkerd-managed.linkerd.svc.cluster.local"
+		expectedOutput := "unknown proxy=out src=1.2.3.4:5555 dst=voting-6b8b9c6f5d-x7k2p:6666 tls=true dst_deployment=voting dst_namespace=emojivoto dst_serviceaccount= peer_identity=" + expectedIdentity
+		output, err := renderTapEvent(event, wideOutput)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if output != expectedOutput {
+			t.Fatalf("Expecting command output to be [%s], got [%s]", expectedOutput, output)
+		}
+
+		output, err = renderTapEvent(event, jsonOutput)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var decoded util.TapEventJSON
+		if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+			t.Fatalf("Unexpected error decoding [%s]: %v", output, err)
+		}
+		if decoded.PeerIdentity != expectedIdentity {
+			t.Fatalf("Expected peer identity [%s], got [%s]", expectedIdentity, decoded.PeerIdentity)
+		}
+
+		// the identity isn't known without TLS
+		event.DestinationMeta.Labels["tls"] = "no_identity"
+		if identity := util.GetTapPeerIdentity(event); identity != "" {
+			t.Fatalf("Expected no peer identity without TLS, got [%s]", identity)
+		}
+	})
+
 	t.Run("Handles unknown event types", func(t *testing.T) {
 		event := toTapEvent(&pb.TapEvent_Http{})
 
@@ -6,6 +6,8 @@ kind: Namespace
 apiVersion: v1
 metadata:
   name: {{.Namespace}}
+  labels:
+    {{.ControllerNamespaceLabel}}: {{.Namespace}}
 
 ### Service Account Controller ###
 ---
@@ -21,19 +23,27 @@ kind: ClusterRole
 apiVersion: rbac.authorization.k8s.io/v1beta1
 metadata:
   name: linkerd-{{.Namespace}}-controller
+  labels:
+    {{.ControllerNamespaceLabel}}: {{.Namespace}}
 rules:
 - apiGroups: ["extensions", "apps"]
-  resources: ["deployments", "replicasets"]
+  resources: ["deployments", "replicasets", "ingresses"]
   verbs: ["list", "get", "watch"]
 - apiGroups: [""]
   resources: ["pods", "endpoints", "services", "namespaces", "replicationcontrollers"]
   verbs: ["list", "get", "watch"]
+- apiGroups: ["apiregistration.k8s.io"]
+  resources: ["apiservices"]
+  resourceNames: ["v1alpha1.{{.Namespace}}.tap.linkerd.io"]
+  verbs: ["get", "patch"]
 
 ---
 kind: ClusterRoleBinding
 apiVersion: rbac.authorization.k8s.io/v1beta1
 metadata:
   name: linkerd-{{.Namespace}}-controller
+  labels:
+    {{.ControllerNamespaceLabel}}: {{.Namespace}}
 roleRef:
   apiGroup: rbac.authorization.k8s.io
   kind: ClusterRole
@@ -43,6 +53,86 @@ subjects:
   name: linkerd-controller
   namespace: {{.Namespace}}
 
+### Tap APIService RBAC ###
+# The tap APIService authorizes taps with SubjectAccessReviews, reads how the
+# Kubernetes API authenticates the requests that it forwards, and keeps its
+# serving certificate in a secret.
+---
+kind: ClusterRoleBinding
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: linkerd-{{.Namespace}}-tap-auth-delegator
+  labels:
+    {{.ControllerNamespaceLabel}}: {{.Namespace}}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: system:auth-delegator
+subjects:
+- kind: ServiceAccount
+  name: linkerd-controller
+  namespace: {{.Namespace}}
+
+---
+kind: RoleBinding
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: linkerd-{{.Namespace}}-tap-auth-reader
+  namespace: kube-system
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: extension-apiserver-authentication-reader
+subjects:
+- kind: ServiceAccount
+  name: linkerd-controller
+  namespace: {{.Namespace}}
+
+---
+kind: Role
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: linkerd-{{.Namespace}}-tap-apiserver
+  namespace: {{.Namespace}}
+rules:
+- apiGroups: [""]
+  resources: ["secrets"]
+  verbs: ["create"]
+- apiGroups: [""]
+  resources: ["secrets"]
+  resourceNames: ["tap-apiserver-tls"]
+  verbs: ["get", "update"]
+
+---
+kind: RoleBinding
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: linkerd-{{.Namespace}}-tap-apiserver
+  namespace: {{.Namespace}}
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: linkerd-{{.Namespace}}-tap-apiserver
+subjects:
+- kind: ServiceAccount
+  name: linkerd-controller
+  namespace: {{.Namespace}}
+
+### Tap RBAC ###
+# Bind this role to users, or to groups, to allow them to tap any resource.
+# A RoleBinding to it allows tapping the resources in a single namespace.
+---
+kind: ClusterRole
+apiVersion: rbac.authorization.k8s.io/v1beta1
+metadata:
+  name: linkerd-{{.Namespace}}-tap
+  labels:
+    {{.ControllerNamespaceLabel}}: {{.Namespace}}
+rules:
+- apiGroups: ["{{.Namespace}}.tap.linkerd.io"]
+  resources: ["*"]
+  verbs: ["watch"]
+
 ### Service Account Prometheus ###
 ---
 kind: ServiceAccount
@@ -57,6 +147,8 @@ kind: ClusterRole
 apiVersion: rbac.authorization.k8s.io/v1beta1
 metadata:
   name: linkerd-{{.Namespace}}-prometheus
+  labels:
+    {{.ControllerNamespaceLabel}}: {{.Namespace}}
 rules:
 - apiGroups: [""]
   resources: ["pods"]
@@ -67,6 +159,8 @@ kind: ClusterRoleBinding
 apiVersion: rbac.authorization.k8s.io/v1beta1
 metadata:
   name: linkerd-{{.Namespace}}-prometheus
+  labels:
+    {{.ControllerNamespaceLabel}}: {{.Namespace}}
 roleRef:
   apiGroup: rbac.authorization.k8s.io
   kind: ClusterRole
@@ -115,6 +209,44 @@ spec:
     port: {{.ProxyAPIPort}}
     targetPort: {{.ProxyAPIPort}}
 
+---
+kind: Service
+apiVersion: v1
+metadata:
+  name: tap
+  namespace: {{.Namespace}}
+  labels:
+    {{.ControllerComponentLabel}}: controller
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  type: ClusterIP
+  selector:
+    {{.ControllerComponentLabel}}: controller
+  ports:
+  - name: https
+    port: 443
+    targetPort: 8443
+
+---
+kind: APIService
+apiVersion: apiregistration.k8s.io/v1beta1
+metadata:
+  name: v1alpha1.{{.Namespace}}.tap.linkerd.io
+  labels:
+    {{.ControllerComponentLabel}}: controller
+    {{.ControllerNamespaceLabel}}: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  group: {{.Namespace}}.tap.linkerd.io
+  version: v1alpha1
+  groupPriorityMinimum: 1000
+  versionPriority: 100
+  service:
+    name: tap
+    namespace: {{.Namespace}}
+
 ---
 kind: Deployment
 apiVersion: extensions/v1beta1
@@ -146,12 +278,24 @@ spec:
         imagePullPolicy: {{.ImagePullPolicy}}
         args:
         - "public-api"
-        - "-prometheus-url=http://prometheus.{{.Namespace}}.svc.cluster.local:9090"
+        {{- if .MetricsURL}}
+        - "-prometheus-url={{.MetricsURL}}"
+        {{- else}}
+        - "-prometheus-url=http://prometheus.{{.Namespace}}.svc.{{.ClusterDomain}}:9090"
+        {{- end}}
         - "-controller-namespace={{.Namespace}}"
         - "-log-level={{.ControllerLogLevel}}"
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop:
+            - ALL
+          readOnlyRootFilesystem: true
+          runAsNonRoot: true
+          runAsUser: 2103
         livenessProbe:
           httpGet:
-            path: /ping
+            path: /live
             port: 9995
           initialDelaySeconds: 10
         readinessProbe:
@@ -170,10 +314,19 @@ spec:
         args:
         - "destination"
         - "-enable-tls={{.EnableTLS}}"
+        - "-kubernetes-dns-zone={{.ClusterDomain}}"
         - "-log-level={{.ControllerLogLevel}}"
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop:
+            - ALL
+          readOnlyRootFilesystem: true
+          runAsNonRoot: true
+          runAsUser: 2103
         livenessProbe:
           httpGet:
-            path: /ping
+            path: /live
             port: 9999
           initialDelaySeconds: 10
         readinessProbe:
@@ -193,9 +346,17 @@ spec:
         - "proxy-api"
         - "-addr=:{{.ProxyAPIPort}}"
         - "-log-level={{.ControllerLogLevel}}"
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop:
+            - ALL
+          readOnlyRootFilesystem: true
+          runAsNonRoot: true
+          runAsUser: 2103
         livenessProbe:
           httpGet:
-            path: /ping
+            path: /live
             port: 9996
           initialDelaySeconds: 10
         readinessProbe:
@@ -207,16 +368,30 @@ spec:
         ports:
         - name: grpc
           containerPort: 8088
+        - name: apiserver
+          containerPort: 8443
         - name: admin-http
           containerPort: 9998
         image: {{.ControllerImage}}
         imagePullPolicy: {{.ImagePullPolicy}}
         args:
         - "tap"
+        - "-controller-namespace={{.Namespace}}"
         - "-log-level={{.ControllerLogLevel}}"
+        {{- if .PodLabels}}
+        - "-pod-labels={{.PodLabels}}"
+        {{- end}}
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop:
+            - ALL
+          readOnlyRootFilesystem: true
+          runAsNonRoot: true
+          runAsUser: 2103
         livenessProbe:
           httpGet:
-            path: /ping
+            path: /live
             port: 9998
           initialDelaySeconds: 10
         readinessProbe:
@@ -277,15 +452,50 @@ spec:
         image: {{.WebImage}}
         imagePullPolicy: {{.ImagePullPolicy}}
         args:
-        - "-api-addr=api.{{.Namespace}}.svc.cluster.local:8085"
+        - "-api-addr=api.{{.Namespace}}.svc.{{.ClusterDomain}}:8085"
         - "-static-dir=/dist"
         - "-template-dir=/templates"
         - "-uuid={{.UUID}}"
         - "-controller-namespace={{.Namespace}}"
         - "-log-level={{.ControllerLogLevel}}"
+        {{- if .WebNotifyMinSuccessRate}}
+        - "-notify-min-success-rate={{.WebNotifyMinSuccessRate}}"
+        {{- end}}
+        {{- if .WebNotifyMaxLatencyP99}}
+        - "-notify-max-latency-p99={{.WebNotifyMaxLatencyP99}}"
+        {{- end}}
+        {{- if .WebWebhookSecretName}}
+        - "-notify-webhook-url-file=/var/run/linkerd/webhooks/notify-webhook-url"
+        - "-audit-webhook-url-file=/var/run/linkerd/webhooks/audit-webhook-url"
+        {{- end}}
+        {{- if .WebOIDCIssuerURL}}
+        - "-oidc-issuer-url={{.WebOIDCIssuerURL}}"
+        - "-oidc-client-id={{.WebOIDCClientID}}"
+        - "-oidc-redirect-url={{.WebOIDCRedirectURL}}"
+        {{- if .WebOIDCSecretName}}
+        - "-oidc-client-secret-file=/var/run/linkerd/oidc/client-secret"
+        {{- end}}
+        {{- if .WebOIDCReadOnlyGroups}}
+        - "-oidc-read-only-groups={{.WebOIDCReadOnlyGroups}}"
+        {{- end}}
+        {{- if .WebOIDCTapGroups}}
+        - "-oidc-tap-groups={{.WebOIDCTapGroups}}"
+        {{- end}}
+        {{- if .WebOIDCAdminGroups}}
+        - "-oidc-admin-groups={{.WebOIDCAdminGroups}}"
+        {{- end}}
+        {{- end}}
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop:
+            - ALL
+          readOnlyRootFilesystem: true
+          runAsNonRoot: true
+          runAsUser: 2103
         livenessProbe:
           httpGet:
-            path: /ping
+            path: /live
             port: 9994
           initialDelaySeconds: 10
         readinessProbe:
@@ -293,6 +503,33 @@ spec:
             path: /ready
             port: 9994
           failureThreshold: 7
+        {{- if or (and .WebOIDCIssuerURL .WebOIDCSecretName) .WebWebhookSecretName}}
+        volumeMounts:
+        {{- if and .WebOIDCIssuerURL .WebOIDCSecretName}}
+        - name: oidc-client-secret
+          mountPath: /var/run/linkerd/oidc
+          readOnly: true
+        {{- end}}
+        {{- if .WebWebhookSecretName}}
+        - name: webhooks
+          mountPath: /var/run/linkerd/webhooks
+          readOnly: true
+        {{- end}}
+      volumes:
+      {{- if and .WebOIDCIssuerURL .WebOIDCSecretName}}
+      - name: oidc-client-secret
+        secret:
+          secretName: {{.WebOIDCSecretName}}
+          items:
+          - key: client-secret
+            path: client-secret
+      {{- end}}
+      {{- if .WebWebhookSecretName}}
+      - name: webhooks
+        secret:
+          secretName: {{.WebWebhookSecretName}}
+      {{- end}}
+        {{- end}}
 
 ### Prometheus ###
 ---
@@ -338,6 +575,8 @@ spec:
       - name: prometheus-config
         configMap:
           name: prometheus-config
+      - name: data
+        emptyDir: {}
       containers:
       - name: prometheus
         ports:
@@ -347,11 +586,22 @@ spec:
         - name: prometheus-config
           mountPath: /etc/prometheus
           readOnly: true
+        - name: data
+          mountPath: /data
         image: {{.PrometheusImage}}
         imagePullPolicy: {{.ImagePullPolicy}}
         args:
+        - "--storage.tsdb.path=/data"
         - "--storage.tsdb.retention=6h"
         - "--config.file=/etc/prometheus/prometheus.yml"
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop:
+            - ALL
+          readOnlyRootFilesystem: true
+          runAsNonRoot: true
+          runAsUser: 65534
         readinessProbe:
           httpGet:
             path: /-/ready
@@ -447,6 +697,11 @@ data:
       # foo=bar
       - action: labelmap
         regex: __meta_kubernetes_pod_label_linkerd_io_(.+)
+      {{- range .PodMetricLabels}}
+      - source_labels: [{{.MetaLabel}}]
+        action: replace
+        target_label: {{.Name}}
+      {{- end}}
 
 ### Grafana ###
 ---
@@ -498,6 +753,8 @@ spec:
             path: provisioning/datasources/datasources.yaml
           - key: dashboards.yaml
             path: provisioning/dashboards/dashboards.yaml
+      - name: data
+        emptyDir: {}
       containers:
       - name: grafana
         ports:
@@ -507,8 +764,18 @@ spec:
         - name: grafana-config
           mountPath: /etc/grafana
           readOnly: true
+        - name: data
+          mountPath: /data
         image: {{.GrafanaImage}}
         imagePullPolicy: {{.ImagePullPolicy}}
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop:
+            - ALL
+          readOnlyRootFilesystem: true
+          runAsNonRoot: true
+          runAsUser: 472
         livenessProbe:
           httpGet:
             path: /api/health
@@ -552,6 +819,12 @@ data:
     [analytics]
     check_for_updates = false
 
+    [paths]
+    data = /data
+
+    [log]
+    mode = console
+
   datasources.yaml: |-
     apiVersion: 1
     datasources:
@@ -559,7 +832,7 @@ data:
       type: prometheus
       access: proxy
       orgId: 1
-      url: http://prometheus.{{.Namespace}}.svc.cluster.local:9090
+      url: http://prometheus.{{.Namespace}}.svc.{{.ClusterDomain}}:9090
       isDefault: true
       jsonData:
         timeInterval: "5s"
@@ -595,6 +868,8 @@ kind: ClusterRole
 apiVersion: rbac.authorization.k8s.io/v1beta1
 metadata:
   name: linkerd-{{.Namespace}}-ca
+  labels:
+    {{.ControllerNamespaceLabel}}: {{.Namespace}}
 rules:
 - apiGroups: [""]
   resources: ["configmaps"]
@@ -612,12 +887,17 @@ rules:
 - apiGroups: [""]
   resources: ["secrets"]
   verbs: ["create", "update"]
+- apiGroups: [""]
+  resources: ["events"]
+  verbs: ["create", "patch"]
 
 ---
 kind: ClusterRoleBinding
 apiVersion: rbac.authorization.k8s.io/v1beta1
 metadata:
   name: linkerd-{{.Namespace}}-ca
+  labels:
+    {{.ControllerNamespaceLabel}}: {{.Namespace}}
 roleRef:
   apiGroup: rbac.authorization.k8s.io
   kind: ClusterRole
@@ -659,9 +939,17 @@ spec:
         - "ca"
         - "-controller-namespace={{.Namespace}}"
         - "-log-level={{.ControllerLogLevel}}"
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop:
+            - ALL
+          readOnlyRootFilesystem: true
+          runAsNonRoot: true
+          runAsUser: 2103
         livenessProbe:
           httpGet:
-            path: /ping
+            path: /live
             port: 9997
           initialDelaySeconds: 10
         readinessProbe:
@@ -670,3 +958,140 @@ spec:
             port: 9997
           failureThreshold: 7
 `
+
+const NetworkPolicyTemplate = `
+### Network Policies ###
+# Only ingress is restricted: the Kubernetes API server, which every
+# component talks to, can't be selected portably by an egress rule.
+---
+kind: NetworkPolicy
+apiVersion: networking.k8s.io/v1
+metadata:
+  name: linkerd-default
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  podSelector: {}
+  policyTypes:
+  - Ingress
+  ingress:
+  # Prometheus scrapes the admin and proxy metrics ports of every component.
+  - from:
+    - podSelector:
+        matchLabels:
+          {{.ControllerComponentLabel}}: prometheus
+  # The public API reports the readiness of every component from its admin
+  # server, and tap taps the proxy of every component.
+  - from:
+    - podSelector:
+        matchLabels:
+          {{.ControllerComponentLabel}}: controller
+    ports:
+    - protocol: TCP
+      port: 9994
+    - protocol: TCP
+      port: 9995
+    - protocol: TCP
+      port: 9996
+    - protocol: TCP
+      port: 9997
+    - protocol: TCP
+      port: 9998
+    - protocol: TCP
+      port: 9999
+    - protocol: TCP
+      port: {{.ProxyControlPort}}
+
+---
+kind: NetworkPolicy
+apiVersion: networking.k8s.io/v1
+metadata:
+  name: linkerd-controller
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  podSelector:
+    matchLabels:
+      {{.ControllerComponentLabel}}: controller
+  policyTypes:
+  - Ingress
+  ingress:
+  # The public API is reached by the web component and by the CLI through the
+  # Kubernetes API server proxy; the proxy API is reached by proxies in every
+  # namespace; the tap APIService is reached by the Kubernetes API server.
+  - ports:
+    - protocol: TCP
+      port: 8085
+    - protocol: TCP
+      port: {{.ProxyAPIPort}}
+    - protocol: TCP
+      port: 8443
+
+---
+kind: NetworkPolicy
+apiVersion: networking.k8s.io/v1
+metadata:
+  name: linkerd-web
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  podSelector:
+    matchLabels:
+      {{.ControllerComponentLabel}}: web
+  policyTypes:
+  - Ingress
+  ingress:
+  - ports:
+    - protocol: TCP
+      port: 8084
+
+---
+kind: NetworkPolicy
+apiVersion: networking.k8s.io/v1
+metadata:
+  name: linkerd-prometheus
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  podSelector:
+    matchLabels:
+      {{.ControllerComponentLabel}}: prometheus
+  policyTypes:
+  - Ingress
+  ingress:
+  - from:
+    - podSelector:
+        matchLabels:
+          {{.ControllerComponentLabel}}: controller
+    - podSelector:
+        matchLabels:
+          {{.ControllerComponentLabel}}: grafana
+    ports:
+    - protocol: TCP
+      port: 9090
+
+---
+kind: NetworkPolicy
+apiVersion: networking.k8s.io/v1
+metadata:
+  name: linkerd-grafana
+  namespace: {{.Namespace}}
+  annotations:
+    {{.CreatedByAnnotation}}: {{.CliVersion}}
+spec:
+  podSelector:
+    matchLabels:
+      {{.ControllerComponentLabel}}: grafana
+  policyTypes:
+  - Ingress
+  ingress:
+  # Grafana is embedded in the dashboard through the Kubernetes API server
+  # proxy.
+  - ports:
+    - protocol: TCP
+      port: 3000
+`
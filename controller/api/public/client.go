@@ -5,10 +5,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/linkerd/linkerd2/controller/api/util"
 	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/k8s"
@@ -38,6 +40,14 @@ func (c *grpcOverHttpClient) StatSummary(ctx context.Context, req *pb.StatSummar
 	return &msg, err
 }
 
+func (c *grpcOverHttpClient) StatSummaryStream(ctx context.Context, req *pb.StatSummaryRequest, _ ...grpc.CallOption) (pb.Api_StatSummaryStreamClient, error) {
+	reader, err := c.openStream(ctx, "StatSummaryStream", req)
+	if err != nil {
+		return nil, err
+	}
+	return &statSummaryStreamClient{ctx: ctx, reader: reader}, nil
+}
+
 func (c *grpcOverHttpClient) Version(ctx context.Context, req *pb.Empty, _ ...grpc.CallOption) (*pb.VersionInfo, error) {
 	var msg pb.VersionInfo
 	err := c.apiRequest(ctx, "Version", req, &msg)
@@ -75,7 +85,20 @@ func (c *grpcOverHttpClient) Tap(ctx context.Context, req *pb.TapRequest, _ ...g
 }
 
 func (c *grpcOverHttpClient) TapByResource(ctx context.Context, req *pb.TapByResourceRequest, _ ...grpc.CallOption) (pb.Api_TapByResourceClient, error) {
-	url := c.endpointNameToPublicApiUrl("TapByResource")
+	reader, err := c.openStream(ctx, "TapByResource", req)
+	if err != nil {
+		return nil, err
+	}
+	return &tapClient{ctx: ctx, reader: reader}, nil
+}
+
+// openStream calls a streaming endpoint and returns a reader for the stream,
+// whose response body is closed once ctx is done.
+func (c *grpcOverHttpClient) openStream(ctx context.Context, endpoint string, req proto.Message) (*bufio.Reader, error) {
+	return c.openStreamAt(ctx, c.endpointNameToPublicApiUrl(endpoint), req)
+}
+
+func (c *grpcOverHttpClient) openStreamAt(ctx context.Context, url *url.URL, req proto.Message) (*bufio.Reader, error) {
 	httpRsp, err := c.post(ctx, url, req)
 	if err != nil {
 		return nil, err
@@ -92,7 +115,7 @@ func (c *grpcOverHttpClient) TapByResource(ctx context.Context, req *pb.TapByRes
 		httpRsp.Body.Close()
 	}()
 
-	return &tapClient{ctx: ctx, reader: bufio.NewReader(httpRsp.Body)}, nil
+	return bufio.NewReader(httpRsp.Body), nil
 }
 
 func (c *grpcOverHttpClient) apiRequest(ctx context.Context, endpoint string, req proto.Message, protoResponse proto.Message) error {
@@ -176,6 +199,27 @@ func (c *grpcOverHttpClient) checkIfNamespaceExists() *healthcheckPb.CheckResult
 	return checkResult
 }
 
+// TapAPIClient is the part of pb.ApiClient that the tap APIService serves.
+type TapAPIClient interface {
+	TapByResource(ctx context.Context, req *pb.TapByResourceRequest, opts ...grpc.CallOption) (pb.Api_TapByResourceClient, error)
+}
+
+// tapAPIClient calls the tap APIService through the Kubernetes API, with the
+// credentials of the Kubernetes API client.
+type tapAPIClient struct {
+	client                *grpcOverHttpClient
+	controlPlaneNamespace string
+}
+
+func (c *tapAPIClient) TapByResource(ctx context.Context, req *pb.TapByResourceRequest, _ ...grpc.CallOption) (pb.Api_TapByResourceClient, error) {
+	url := c.client.serverURL.ResolveReference(&url.URL{Path: util.TapAPIPath(c.controlPlaneNamespace, req.GetTarget().GetResource())})
+	reader, err := c.client.openStreamAt(ctx, url, req)
+	if err != nil {
+		return nil, err
+	}
+	return &tapClient{ctx: ctx, reader: reader}, nil
+}
+
 type tapClient struct {
 	ctx    context.Context
 	reader *bufio.Reader
@@ -183,8 +227,24 @@ type tapClient struct {
 
 func (c tapClient) Recv() (*pb.TapEvent, error) {
 	var msg pb.TapEvent
-	err := fromByteStreamToProtocolBuffers(c.reader, &msg)
-	return &msg, err
+	for {
+		messageAsBytes, err := deserializePayloadFromReader(c.reader)
+		if err != nil {
+			return &msg, fmt.Errorf("error reading byte stream header: %v", err)
+		}
+
+		// The server sends empty messages as heartbeats while no events are
+		// flowing.
+		if len(messageAsBytes) == 0 {
+			continue
+		}
+
+		err = proto.Unmarshal(messageAsBytes, &msg)
+		if err != nil {
+			return &msg, fmt.Errorf("error unmarshalling array of [%d] bytes error: %v", len(messageAsBytes), err)
+		}
+		return &msg, nil
+	}
 }
 
 // satisfy the pb.Api_TapClient interface
@@ -195,6 +255,31 @@ func (c tapClient) Context() context.Context     { return c.ctx }
 func (c tapClient) SendMsg(interface{}) error    { return nil }
 func (c tapClient) RecvMsg(interface{}) error    { return nil }
 
+type statSummaryStreamClient struct {
+	ctx    context.Context
+	reader *bufio.Reader
+}
+
+// Recv returns the next response in the stream, or io.EOF once the server has
+// sent every table.
+func (c statSummaryStreamClient) Recv() (*pb.StatSummaryResponse, error) {
+	if _, err := c.reader.Peek(1); err == io.EOF {
+		return nil, io.EOF
+	}
+
+	var msg pb.StatSummaryResponse
+	err := fromByteStreamToProtocolBuffers(c.reader, &msg)
+	return &msg, err
+}
+
+// satisfy the pb.Api_StatSummaryStreamClient interface
+func (c statSummaryStreamClient) Header() (metadata.MD, error) { return nil, nil }
+func (c statSummaryStreamClient) Trailer() metadata.MD         { return nil }
+func (c statSummaryStreamClient) CloseSend() error             { return nil }
+func (c statSummaryStreamClient) Context() context.Context     { return c.ctx }
+func (c statSummaryStreamClient) SendMsg(interface{}) error    { return nil }
+func (c statSummaryStreamClient) RecvMsg(interface{}) error    { return nil }
+
 func fromByteStreamToProtocolBuffers(byteStreamContainingMessage *bufio.Reader, out proto.Message) error {
 	messageAsBytes, err := deserializePayloadFromReader(byteStreamContainingMessage)
 	if err != nil {
@@ -247,3 +332,27 @@ func NewExternalClient(controlPlaneNamespace string, kubeApi k8s.KubernetesApi)
 
 	return newClient(apiURL, httpClientToUse, controlPlaneNamespace)
 }
+
+// NewExternalTapClient returns a client for the tap APIService of the control
+// plane in controlPlaneNamespace. Taps are authorized with the RBAC
+// permissions of the user that kubeApi is configured with.
+func NewExternalTapClient(controlPlaneNamespace string, kubeApi k8s.KubernetesApi) (TapAPIClient, error) {
+	// any Kubernetes API URL has the scheme and host that's needed
+	apiURL, err := kubeApi.UrlFor("default", "/")
+	if err != nil {
+		return nil, err
+	}
+
+	httpClientToUse, err := kubeApi.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &tapAPIClient{
+		client: &grpcOverHttpClient{
+			serverURL:  apiURL,
+			httpClient: httpClientToUse,
+		},
+		controlPlaneNamespace: controlPlaneNamespace,
+	}, nil
+}
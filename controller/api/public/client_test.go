@@ -13,6 +13,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
 )
 
 type mockTransport struct {
@@ -202,6 +203,71 @@ func TestSelfCheck(t *testing.T) {
 	})
 }
 
+func TestNewExternalTapClient(t *testing.T) {
+	t.Run("Taps through the tap APIService", func(t *testing.T) {
+		mockTransport := &mockTransport{}
+		mockTransport.responseToReturn = &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bufferedReader(t, &pb.TapEvent{})),
+		}
+		kubeApi := &k8s.MockKubeApi{
+			UrlForUrlToReturn:       &url.URL{Scheme: "https", Host: "some-hostname", Path: "/api/v1/namespaces/default/"},
+			NewClientClientToReturn: &http.Client{Transport: mockTransport},
+		}
+
+		client, err := NewExternalTapClient("linkerd", kubeApi)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		req := &pb.TapByResourceRequest{
+			Target: &pb.ResourceSelection{
+				Resource: &pb.Resource{Namespace: "emojivoto", Type: "deployment", Name: "web"},
+			},
+		}
+		_, err = client.TapByResource(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedUrlRequested := "https://some-hostname/apis/linkerd.tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/deployments/web/tap"
+		actualUrlRequested := mockTransport.requestSent.URL.String()
+		if actualUrlRequested != expectedUrlRequested {
+			t.Fatalf("Expected request to URL [%v], but got [%v]", expectedUrlRequested, actualUrlRequested)
+		}
+	})
+}
+
+func TestTapClientRecv(t *testing.T) {
+	t.Run("Skips heartbeats between tap events", func(t *testing.T) {
+		expectedTapEvents := []*pb.TapEvent{
+			{Source: &pb.TcpAddress{Port: 6666}},
+			{Source: &pb.TcpAddress{Port: 1983}},
+		}
+
+		stubWriter := newStubResponseWriter()
+		for _, event := range expectedTapEvents {
+			if err := writeHeartbeatToHttpResponse(stubWriter); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if err := writeProtoToHttpResponse(stubWriter, event); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		client := tapClient{ctx: context.TODO(), reader: bufio.NewReader(stubWriter.body)}
+		for _, expectedTapEvent := range expectedTapEvents {
+			actualTapEvent, err := client.Recv()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !proto.Equal(actualTapEvent, expectedTapEvent) {
+				t.Fatalf("Expecting tap event to be [%v], but was [%v]", expectedTapEvent, actualTapEvent)
+			}
+		}
+	})
+}
+
 func bufferedReader(t *testing.T, msg proto.Message) *bufio.Reader {
 	msgBytes, err := proto.Marshal(msg)
 	if err != nil {
@@ -3,7 +3,12 @@ package public
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"runtime"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/golang/protobuf/ptypes/duration"
@@ -13,7 +18,6 @@ import (
 	"github.com/linkerd/linkerd2/controller/k8s"
 	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/pkg/version"
-	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -23,11 +27,16 @@ import (
 
 type (
 	grpcServer struct {
-		prometheusAPI       promv1.API
+		metrics             MetricsProvider
 		tapClient           tapPb.TapClient
 		k8sAPI              *k8s.API
 		controllerNamespace string
 		ignoredNamespaces   []string
+
+		// promQuerySlots bounds the number of Prometheus queries in flight, so
+		// that a StatSummary request for all resource types, or many dashboard
+		// clients polling at once, can't saturate Prometheus.
+		promQuerySlots chan struct{}
 	}
 )
 
@@ -45,18 +54,19 @@ const (
 )
 
 func newGrpcServer(
-	promAPI promv1.API,
+	metrics MetricsProvider,
 	tapClient tapPb.TapClient,
 	k8sAPI *k8s.API,
 	controllerNamespace string,
 	ignoredNamespaces []string,
 ) *grpcServer {
 	return &grpcServer{
-		prometheusAPI:       promAPI,
+		metrics:             metrics,
 		tapClient:           tapClient,
 		k8sAPI:              k8sAPI,
 		controllerNamespace: controllerNamespace,
 		ignoredNamespaces:   ignoredNamespaces,
+		promQuerySlots:      make(chan struct{}, maxConcurrentPromQueries),
 	}
 }
 
@@ -127,6 +137,7 @@ func (s *grpcServer) ListPods(ctx context.Context, req *pb.ListPodsRequest) (*pb
 			Added:               added,
 			ControllerNamespace: controllerNS,
 			ControlPlane:        controllerComponent != "",
+			ProxyVersion:        pod.Annotations[pkgK8s.ProxyVersionAnnotation],
 		}
 
 		ownerKind, ownerName := s.k8sAPI.GetOwnerKindAndName(pod)
@@ -199,16 +210,51 @@ func (s *grpcServer) SelfCheck(ctx context.Context, in *healthcheckPb.SelfCheckR
 			promClientCheck,
 		},
 	}
+	response.Results = append(response.Results, s.readinessChecks(ctx)...)
 	return response, nil
 }
 
+// readinessChecks reports the readiness of each control plane container that
+// has one, fetched from its admin server's /ready endpoint by pod IP.
+func (s *grpcServer) readinessChecks(ctx context.Context) []*healthcheckPb.CheckResult {
+	selector, err := labels.Parse(pkgK8s.ControllerComponentLabel)
+	if err != nil {
+		return nil
+	}
+	pods, err := s.k8sAPI.Pod().Lister().Pods(s.controllerNamespace).List(selector)
+	if err != nil {
+		// already reported by the Kubernetes check
+		return nil
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	results := make([]*healthcheckPb.CheckResult, 0)
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			port, ok := pkgK8s.ReadinessPort(container)
+			if !ok {
+				continue
+			}
+
+			endpoint := &url.URL{
+				Scheme: "http",
+				Host:   net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port))),
+				Path:   pkgK8s.ReadinessPath,
+			}
+			readiness, err := pkgK8s.GetReadiness(ctx, http.DefaultClient, endpoint)
+			results = append(results, pkgK8s.ReadinessCheckResult(pod.Name, container.Name, readiness, err))
+		}
+	}
+	return results
+}
+
 func (s *grpcServer) Tap(req *pb.TapRequest, stream pb.Api_TapServer) error {
 	return status.Error(codes.Unimplemented, "Tap is deprecated, use TapByResource")
 }
 
 // Pass through to tap service
 func (s *grpcServer) TapByResource(req *pb.TapByResourceRequest, stream pb.Api_TapByResourceServer) error {
-	tapStream := stream.(tapServer)
+	tapStream := stream.(*tapServer)
 	tapClient, err := s.tapClient.TapByResource(tapStream.Context(), req)
 	if err != nil {
 		log.Errorf("Unexpected error tapping [%v]: %v", req, err)
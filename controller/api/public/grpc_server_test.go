@@ -2,10 +2,16 @@ package public
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"sort"
 	"testing"
 
 	"github.com/golang/protobuf/ptypes/duration"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	tap "github.com/linkerd/linkerd2/controller/gen/controller/tap"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/controller/k8s"
@@ -42,6 +48,7 @@ func listPodResponsesEqual(a pb.ListPodsResponse, b pb.ListPodsResponse) bool {
 			(aPod.Added != bPod.Added) ||
 			(aPod.Status != bPod.Status) ||
 			(aPod.PodIP != bPod.PodIP) ||
+			(aPod.ProxyVersion != bPod.ProxyVersion) ||
 			(aPod.GetDeployment() != bPod.GetDeployment()) {
 			return false
 		}
@@ -74,6 +81,8 @@ metadata:
   namespace: emojivoto
   labels:
     pod-template-hash: hash-meshed
+  annotations:
+    linkerd.io/proxy-version: testinjectversion
   ownerReferences:
   - apiVersion: extensions/v1beta1
     kind: ReplicaSet
@@ -135,6 +144,7 @@ spec:
 							Status:          "Running",
 							PodIP:           "1.2.3.4",
 							Owner:           &pb.Pod_Deployment{Deployment: "emojivoto/meshed-deployment"},
+							ProxyVersion:    "testinjectversion",
 						},
 						&pb.Pod{
 							Name:   "emojivoto/emojivoto-not-meshed",
@@ -154,7 +164,7 @@ spec:
 			}
 
 			fakeGrpcServer := newGrpcServer(
-				&MockProm{Res: exp.promRes},
+				NewPrometheusProvider(&MockProm{Res: exp.promRes}),
 				tap.NewTapClient(nil),
 				k8sAPI,
 				"linkerd",
@@ -174,3 +184,70 @@ spec:
 		}
 	})
 }
+
+func TestSelfCheck(t *testing.T) {
+	t.Run("Reports the readiness of each control plane container", func(t *testing.T) {
+		adminServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"ready": false, "dependencies": [{"name": "kubernetes caches synced", "ready": false, "error": "pending"}]}`)
+		}))
+		defer adminServer.Close()
+		_, port, _ := net.SplitHostPort(adminServer.Listener.Addr().String())
+
+		k8sAPI, err := k8s.NewFakeAPI(fmt.Sprintf(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: controller-1234
+  namespace: linkerd
+  labels:
+    linkerd.io/control-plane-component: controller
+spec:
+  containers:
+  - name: destination
+    readinessProbe:
+      httpGet:
+        path: /ready
+        port: %s
+  - name: linkerd-proxy
+    readinessProbe:
+      httpGet:
+        path: /metrics
+        port: 4191
+status:
+  phase: Running
+  podIP: 127.0.0.1
+`, port))
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+
+		fakeGrpcServer := newGrpcServer(
+			NewPrometheusProvider(&MockProm{Res: model.Vector{}}),
+			tap.NewTapClient(nil),
+			k8sAPI,
+			"linkerd",
+			[]string{},
+		)
+
+		k8sAPI.Sync(nil)
+
+		rsp, err := fakeGrpcServer.SelfCheck(context.TODO(), &healthcheckPb.SelfCheckRequest{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expected := []string{
+			"control plane can talk to Kubernetes: OK",
+			"control plane can talk to Prometheus: OK",
+			"destination is ready: FAIL",
+		}
+		var got []string
+		for _, result := range rsp.Results {
+			got = append(got, fmt.Sprintf("%s: %s", result.CheckDescription, result.Status))
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("Expected results %v, got %v", expected, got)
+		}
+	})
+}
@@ -4,24 +4,31 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	tapPb "github.com/linkerd/linkerd2/controller/gen/controller/tap"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/pkg/prometheus"
-	promApi "github.com/prometheus/client_golang/api"
-	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
+// tapHeartbeatInterval is how often an empty message is written to tap
+// streams, so that the response headers reach the client promptly and idle
+// streams aren't closed by proxies in between.
+const tapHeartbeatInterval = time.Second
+
 var (
-	statSummaryPath   = fullUrlPathFor("StatSummary")
-	versionPath       = fullUrlPathFor("Version")
-	listPodsPath      = fullUrlPathFor("ListPods")
-	tapByResourcePath = fullUrlPathFor("TapByResource")
-	selfCheckPath     = fullUrlPathFor("SelfCheck")
+	statSummaryPath       = fullUrlPathFor("StatSummary")
+	statSummaryStreamPath = fullUrlPathFor("StatSummaryStream")
+	versionPath           = fullUrlPathFor("Version")
+	listPodsPath          = fullUrlPathFor("ListPods")
+	tapByResourcePath     = fullUrlPathFor("TapByResource")
+	selfCheckPath         = fullUrlPathFor("SelfCheck")
 )
 
 type handler struct {
@@ -38,10 +45,20 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	// Compress unary responses, which can be large on big clusters. Streams
+	// are flushed message by message, so they're left as they are.
+	if req.URL.Path != tapByResourcePath && req.URL.Path != statSummaryStreamPath && acceptsGzip(req) {
+		gz := newGzipResponseWriter(w)
+		defer gz.Close()
+		w = gz
+	}
+
 	// Serve request
 	switch req.URL.Path {
 	case statSummaryPath:
 		h.handleStatSummary(w, req)
+	case statSummaryStreamPath:
+		h.handleStatSummaryStream(w, req)
 	case versionPath:
 		h.handleVersion(w, req)
 	case listPodsPath:
@@ -77,6 +94,28 @@ func (h *handler) handleStatSummary(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+func (h *handler) handleStatSummaryStream(w http.ResponseWriter, req *http.Request) {
+	flushableWriter, err := newStreamingWriter(w)
+	if err != nil {
+		writeErrorToHttpResponse(w, err)
+		return
+	}
+
+	var protoRequest pb.StatSummaryRequest
+	err = httpRequestToProto(req, &protoRequest)
+	if err != nil {
+		writeErrorToHttpResponse(w, err)
+		return
+	}
+
+	server := &statSummaryStreamServer{w: flushableWriter, req: req}
+	err = h.grpcServer.StatSummaryStream(&protoRequest, server)
+	if err != nil {
+		server.writeError(err, &protoRequest)
+		return
+	}
+}
+
 func (h *handler) handleVersion(w http.ResponseWriter, req *http.Request) {
 	var protoRequest pb.Empty
 	err := httpRequestToProto(req, &protoRequest)
@@ -141,6 +180,13 @@ func (h *handler) handleListPods(w http.ResponseWriter, req *http.Request) {
 }
 
 func (h *handler) handleTapByResource(w http.ResponseWriter, req *http.Request) {
+	ServeTapByResource(w, req, h.grpcServer.TapByResource)
+}
+
+// ServeTapByResource serves a TapByResource request made over HTTP, streaming
+// the events that tap sends, with heartbeats while none are flowing. The tap
+// APIService serves tap with it too, authorizing each request before tapping.
+func ServeTapByResource(w http.ResponseWriter, req *http.Request, tap func(*pb.TapByResourceRequest, pb.Api_TapByResourceServer) error) {
 	flushableWriter, err := newStreamingWriter(w)
 	if err != nil {
 		writeErrorToHttpResponse(w, err)
@@ -154,10 +200,21 @@ func (h *handler) handleTapByResource(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	server := tapServer{w: flushableWriter, req: req}
-	err = h.grpcServer.TapByResource(&protoRequest, server)
+	server := &tapServer{w: flushableWriter, req: req}
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		server.heartbeat(tapHeartbeatInterval, stop)
+	}()
+
+	err = tap(&protoRequest, server)
+	// w must not be written to once the handler returns, so wait for the
+	// heartbeat goroutine to exit
+	close(stop)
+	<-stopped
 	if err != nil {
-		writeErrorToHttpResponse(w, err)
+		server.writeError(err)
 		return
 	}
 }
@@ -165,9 +222,15 @@ func (h *handler) handleTapByResource(w http.ResponseWriter, req *http.Request)
 type tapServer struct {
 	w   flushableResponseWriter
 	req *http.Request
+
+	// mu serializes writes from Send and the heartbeat goroutine.
+	mu sync.Mutex
 }
 
-func (s tapServer) Send(msg *pb.TapEvent) error {
+func (s *tapServer) Send(msg *pb.TapEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	err := writeProtoToHttpResponse(s.w, msg)
 	if err != nil {
 		writeErrorToHttpResponse(s.w, err)
@@ -178,13 +241,84 @@ func (s tapServer) Send(msg *pb.TapEvent) error {
 	return nil
 }
 
+func (s *tapServer) writeError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeErrorToHttpResponse(s.w, err)
+}
+
+func (s *tapServer) heartbeat(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			err := writeHeartbeatToHttpResponse(s.w)
+			s.mu.Unlock()
+			if err != nil {
+				log.Debugf("Error writing tap heartbeat: %v", err)
+				return
+			}
+		case <-stop:
+			return
+		case <-s.req.Context().Done():
+			return
+		}
+	}
+}
+
 // satisfy the pb.Api_TapServer interface
-func (s tapServer) SetHeader(metadata.MD) error  { return nil }
-func (s tapServer) SendHeader(metadata.MD) error { return nil }
-func (s tapServer) SetTrailer(metadata.MD)       {}
-func (s tapServer) Context() context.Context     { return s.req.Context() }
-func (s tapServer) SendMsg(interface{}) error    { return nil }
-func (s tapServer) RecvMsg(interface{}) error    { return nil }
+func (s *tapServer) SetHeader(metadata.MD) error  { return nil }
+func (s *tapServer) SendHeader(metadata.MD) error { return nil }
+func (s *tapServer) SetTrailer(metadata.MD)       {}
+func (s *tapServer) Context() context.Context     { return s.req.Context() }
+func (s *tapServer) SendMsg(interface{}) error    { return nil }
+func (s *tapServer) RecvMsg(interface{}) error    { return nil }
+
+type statSummaryStreamServer struct {
+	w    flushableResponseWriter
+	req  *http.Request
+	sent bool
+}
+
+func (s *statSummaryStreamServer) Send(msg *pb.StatSummaryResponse) error {
+	err := writeProtoToHttpResponse(s.w, msg)
+	if err != nil {
+		return err
+	}
+
+	s.sent = true
+	s.w.Flush()
+	return nil
+}
+
+// writeError reports err in the linkerd-error header if no tables have been
+// sent yet. Once the stream has started the header can't be set, so err is
+// sent as an error response instead.
+func (s *statSummaryStreamServer) writeError(err error, req *pb.StatSummaryRequest) {
+	if !s.sent {
+		writeErrorToHttpResponse(s.w, err)
+		return
+	}
+
+	message := err.Error()
+	if grpcError, ok := status.FromError(err); ok {
+		message = grpcError.Message()
+	}
+	if err := s.Send(statSummaryError(req, message)); err != nil {
+		log.Debugf("Error writing stat summary stream error: %v", err)
+	}
+}
+
+// satisfy the pb.Api_StatSummaryStreamServer interface
+func (s *statSummaryStreamServer) SetHeader(metadata.MD) error  { return nil }
+func (s *statSummaryStreamServer) SendHeader(metadata.MD) error { return nil }
+func (s *statSummaryStreamServer) SetTrailer(metadata.MD)       {}
+func (s *statSummaryStreamServer) Context() context.Context     { return s.req.Context() }
+func (s *statSummaryStreamServer) SendMsg(interface{}) error    { return nil }
+func (s *statSummaryStreamServer) RecvMsg(interface{}) error    { return nil }
 
 func fullUrlPathFor(method string) string {
 	return apiRoot + apiPrefix + method
@@ -192,7 +326,7 @@ func fullUrlPathFor(method string) string {
 
 func NewServer(
 	addr string,
-	prometheusClient promApi.Client,
+	metrics MetricsProvider,
 	tapClient tapPb.TapClient,
 	k8sAPI *k8s.API,
 	controllerNamespace string,
@@ -200,7 +334,7 @@ func NewServer(
 ) *http.Server {
 	baseHandler := &handler{
 		grpcServer: newGrpcServer(
-			promv1.NewAPI(prometheusClient),
+			metrics,
 			tapClient,
 			k8sAPI,
 			controllerNamespace,
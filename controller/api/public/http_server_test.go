@@ -1,10 +1,15 @@
 package public
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
+	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
@@ -24,6 +29,17 @@ func (m *mockGrpcServer) StatSummary(ctx context.Context, req *pb.StatSummaryReq
 	return m.ResponseToReturn.(*pb.StatSummaryResponse), m.ErrorToReturn
 }
 
+func (m *mockGrpcServer) StatSummaryStream(req *pb.StatSummaryRequest, stream pb.Api_StatSummaryStreamServer) error {
+	m.LastRequestReceived = req
+	if m.ErrorToReturn == nil {
+		for _, rsp := range SplitStatTables(m.ResponseToReturn.(*pb.StatSummaryResponse)) {
+			stream.Send(rsp)
+		}
+	}
+
+	return m.ErrorToReturn
+}
+
 func (m *mockGrpcServer) Version(ctx context.Context, req *pb.Empty) (*pb.VersionInfo, error) {
 	m.LastRequestReceived = req
 	return m.ResponseToReturn.(*pb.VersionInfo), m.ErrorToReturn
@@ -183,6 +199,55 @@ func TestServer(t *testing.T) {
 		}
 	})
 
+	t.Run("Delegates streamed stat tables to the underlying grpc server", func(t *testing.T) {
+		mockGrpcServer := &mockGrpcServer{}
+
+		listener, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatalf("Could not start listener: %v", err)
+		}
+
+		go func() {
+			handler := &handler{
+				grpcServer: mockGrpcServer,
+			}
+			err := http.Serve(listener, handler)
+			if err != nil {
+				t.Fatalf("Could not start server: %v", err)
+			}
+		}()
+
+		client, err := NewInternalClient("linkerd", listener.Addr().String())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		deployments := GenStatSummaryResponse("emoji", "deployment", "emojivoto", nil)
+		pods := GenStatSummaryResponse("emoji-1234", "pod", "emojivoto", nil)
+		response := statSummaryOk(deployments.GetOk().StatTables[0], pods.GetOk().StatTables[0])
+		mockGrpcServer.ResponseToReturn = response
+		mockGrpcServer.ErrorToReturn = nil
+
+		stream, err := client.StatSummaryStream(context.TODO(), &pb.StatSummaryRequest{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for _, expected := range SplitStatTables(response) {
+			actual, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !proto.Equal(actual, expected) {
+				t.Fatalf("Expecting stat table to be [%v], but was [%v]", expected, actual)
+			}
+		}
+
+		if _, err := stream.Recv(); err != io.EOF {
+			t.Fatalf("Expecting io.EOF after the last table, got [%v]", err)
+		}
+	})
+
 	t.Run("Handles errors before opening keep-alive response", func(t *testing.T) {
 		mockGrpcServer := &mockGrpcServer{}
 
@@ -215,6 +280,50 @@ func TestServer(t *testing.T) {
 	})
 }
 
+func TestServerCompression(t *testing.T) {
+	mockGrpcServer := &mockGrpcServer{ResponseToReturn: &pb.VersionInfo{BuildDate: "02/21/1983"}}
+	handler := &handler{grpcServer: mockGrpcServer}
+
+	newRequest := func(acceptEncoding string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, versionPath, bytes.NewReader([]byte{}))
+		if acceptEncoding != "" {
+			req.Header.Set(acceptEncodingHeader, acceptEncoding)
+		}
+		return req
+	}
+
+	t.Run("Compresses responses for clients that accept gzip", func(t *testing.T) {
+		rsp := httptest.NewRecorder()
+		handler.ServeHTTP(rsp, newRequest("gzip, deflate"))
+
+		if encoding := rsp.Header().Get(contentEncodingHeader); encoding != gzipEncoding {
+			t.Fatalf("Expected a gzip-encoded response, got [%s]", encoding)
+		}
+		body, err := gzip.NewReader(rsp.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var version pb.VersionInfo
+		if err := fromByteStreamToProtocolBuffers(bufio.NewReader(body), &version); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !proto.Equal(&version, mockGrpcServer.ResponseToReturn) {
+			t.Fatalf("Expected response [%v], got [%v]", mockGrpcServer.ResponseToReturn, &version)
+		}
+	})
+
+	t.Run("Doesn't compress responses for other clients", func(t *testing.T) {
+		for _, acceptEncoding := range []string{"", "deflate", "gzip;q=0"} {
+			rsp := httptest.NewRecorder()
+			handler.ServeHTTP(rsp, newRequest(acceptEncoding))
+
+			if encoding := rsp.Header().Get(contentEncodingHeader); encoding != "" {
+				t.Fatalf("Expected no encoding for Accept-Encoding [%s], got [%s]", acceptEncoding, encoding)
+			}
+		}
+	})
+}
+
 func assertCallWasForwarded(t *testing.T, mockGrpcServer *mockGrpcServer, expectedRequest proto.Message, expectedResponse proto.Message, functionCall func() (proto.Message, error)) {
 	mockGrpcServer.ErrorToReturn = nil
 	mockGrpcServer.ResponseToReturn = expectedResponse
@@ -0,0 +1,48 @@
+package public
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// MetricsProvider evaluates the PromQL queries behind StatSummary, ListPods
+// and SelfCheck. The queries themselves are built by the public API, so a
+// store only has to answer instant queries to back it.
+type MetricsProvider interface {
+	// QueryVector evaluates query at the current time.
+	QueryVector(ctx context.Context, query string) (model.Vector, error)
+}
+
+type prometheusProvider struct {
+	api promv1.API
+}
+
+// NewPrometheusProvider returns a MetricsProvider backed by the Prometheus
+// HTTP API. Stores that serve the same API, such as Thanos Query or
+// VictoriaMetrics, can be used through it too.
+func NewPrometheusProvider(api promv1.API) MetricsProvider {
+	return &prometheusProvider{api: api}
+}
+
+func (p *prometheusProvider) QueryVector(ctx context.Context, query string) (model.Vector, error) {
+	// single data point (aka summary) query
+	res, err := p.api.Query(ctx, query, time.Time{})
+	if err != nil {
+		log.Errorf("Query(%+v) failed with: %+v", query, err)
+		return nil, err
+	}
+	log.Debugf("Query response:\n\t%+v", res)
+
+	if res.Type() != model.ValVector {
+		err = fmt.Errorf("Unexpected query result type (expected Vector): %s", res.Type())
+		log.Error(err)
+		return nil, err
+	}
+
+	return res.(model.Vector), nil
+}
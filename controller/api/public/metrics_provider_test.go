@@ -0,0 +1,41 @@
+package public
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/common/model"
+)
+
+func TestPrometheusProvider(t *testing.T) {
+	t.Run("Returns the vector result of an instant query", func(t *testing.T) {
+		vec := model.Vector{
+			&model.Sample{
+				Metric: model.Metric{"pod": "emojivoto-1"},
+				Value:  123,
+			},
+		}
+		mockProm := &MockProm{Res: vec}
+		provider := NewPrometheusProvider(mockProm)
+
+		res, err := provider.QueryVector(context.TODO(), "up")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(res, vec) {
+			t.Fatalf("Expected %v, got %v", vec, res)
+		}
+		if !reflect.DeepEqual(mockProm.QueriesExecuted, []string{"up"}) {
+			t.Fatalf("Expected the query to be executed once, got %v", mockProm.QueriesExecuted)
+		}
+	})
+
+	t.Run("Rejects results that aren't vectors", func(t *testing.T) {
+		provider := NewPrometheusProvider(&MockProm{Res: &model.Scalar{Value: 1}})
+
+		if _, err := provider.QueryVector(context.TODO(), "scalar(up)"); err == nil {
+			t.Fatalf("Expected an error for a scalar result, got nil")
+		}
+	})
+}
@@ -2,12 +2,15 @@ package public
 
 import (
 	"bufio"
+	"compress/gzip"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
@@ -19,6 +22,9 @@ const (
 	errorHeader                = "linkerd-error"
 	defaultHttpErrorStatusCode = http.StatusInternalServerError
 	contentTypeHeader          = "Content-Type"
+	contentEncodingHeader      = "Content-Encoding"
+	acceptEncodingHeader       = "Accept-Encoding"
+	gzipEncoding               = "gzip"
 	protobufContentType        = "application/octet-stream"
 	numBytesForMessageLength   = 4
 )
@@ -97,6 +103,42 @@ func writeProtoToHttpResponse(w http.ResponseWriter, msg proto.Message) error {
 	return err
 }
 
+// gzipResponseWriter compresses everything written to the response. Close
+// must be called once the response is complete.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz *gzip.Writer
+}
+
+func newGzipResponseWriter(w http.ResponseWriter) *gzipResponseWriter {
+	w.Header().Set(contentEncodingHeader, gzipEncoding)
+	w.Header().Add("Vary", acceptEncodingHeader)
+	return &gzipResponseWriter{ResponseWriter: w, gz: gzip.NewWriter(w)}
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	return w.gz.Write(p)
+}
+
+func (w *gzipResponseWriter) Close() error {
+	return w.gz.Close()
+}
+
+// acceptsGzip returns true if the client accepts gzip-encoded responses.
+// Go's HTTP client asks for them, and decompresses them, unless its transport
+// disables compression.
+func acceptsGzip(req *http.Request) bool {
+	for _, encoding := range strings.Split(req.Header.Get(acceptEncodingHeader), ",") {
+		parts := strings.Split(encoding, ";")
+		if strings.TrimSpace(parts[0]) != gzipEncoding {
+			continue
+		}
+		// "gzip;q=0" means gzip is not acceptable
+		return len(parts) < 2 || strings.Replace(parts[1], " ", "", -1) != "q=0"
+	}
+	return false
+}
+
 func newStreamingWriter(w http.ResponseWriter) (flushableResponseWriter, error) {
 	flushableWriter, ok := w.(flushableResponseWriter)
 	if !ok {
@@ -105,9 +147,28 @@ func newStreamingWriter(w http.ResponseWriter) (flushableResponseWriter, error)
 
 	flushableWriter.Header().Set("Connection", "keep-alive")
 	flushableWriter.Header().Set("Transfer-Encoding", "chunked")
+	flushableWriter.Header().Set("Cache-Control", "no-cache")
+	// Ask buffering reverse proxies, such as nginx, to pass each chunk
+	// through as soon as it's written.
+	flushableWriter.Header().Set("X-Accel-Buffering", "no")
 	return flushableWriter, nil
 }
 
+// writeHeartbeatToHttpResponse writes an empty message to a streaming
+// response and flushes it. Clients skip empty messages; they keep a quiet
+// stream moving through proxies that buffer or time out idle connections.
+func writeHeartbeatToHttpResponse(w flushableResponseWriter) error {
+	heartbeat, err := serializeAsPayload([]byte{})
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(heartbeat); err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
+
 func serializeAsPayload(messageContentsInBytes []byte) ([]byte, error) {
 	lengthOfThePayload := uint32(len(messageContentsInBytes))
 
@@ -150,6 +211,14 @@ func checkIfResponseHasError(rsp *http.Response) error {
 	}
 
 	if rsp.StatusCode != http.StatusOK {
+		// Errors from the Kubernetes API itself, e.g. when the user isn't
+		// authorized to call an endpoint, have a Status object as their body.
+		var kubeStatus struct {
+			Message string `json:"message"`
+		}
+		if rsp.Body != nil && json.NewDecoder(rsp.Body).Decode(&kubeStatus) == nil && kubeStatus.Message != "" {
+			return fmt.Errorf("Unexpected API response: %s: %s", rsp.Status, kubeStatus.Message)
+		}
 		return fmt.Errorf("Unexpected API response: %s", rsp.Status)
 	}
 
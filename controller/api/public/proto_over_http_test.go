@@ -425,6 +425,13 @@ func TestNewStreamingWriter(t *testing.T) {
 		if actualValue != expectedValue {
 			t.Fatalf("Expected header [%s] to be set to [%s], but was [%s]", header, expectedValue, actualValue)
 		}
+
+		header = "X-Accel-Buffering"
+		expectedValue = "no"
+		actualValue = rawWriter.Header().Get(header)
+		if actualValue != expectedValue {
+			t.Fatalf("Expected header [%s] to be set to [%s], but was [%s]", header, expectedValue, actualValue)
+		}
 	})
 
 	t.Run("Returns an error if writer doesnt support streaming", func(t *testing.T) {
@@ -518,6 +525,25 @@ func TestCheckIfResponseHasError(t *testing.T) {
 			t.Fatalf("Expected error message to be [%s], but it was [%s]", expectedErrorMessage, actualErrorMessage)
 		}
 	})
+
+	t.Run("returns the message of a Kubernetes API error", func(t *testing.T) {
+		response := &http.Response{
+			StatusCode: http.StatusForbidden,
+			Status:     "403 Forbidden",
+			Body:       ioutil.NopCloser(strings.NewReader(`{"kind":"Status","message":"user \"alice\" cannot watch deployments/tap"}`)),
+		}
+
+		err := checkIfResponseHasError(response)
+		if err == nil {
+			t.Fatalf("Expecting error, got nothing")
+		}
+
+		expectedErrorMessage := `Unexpected API response: 403 Forbidden: user "alice" cannot watch deployments/tap`
+		actualErrorMessage := err.Error()
+		if actualErrorMessage != expectedErrorMessage {
+			t.Fatalf("Expected error message to be [%s], but it was [%s]", expectedErrorMessage, actualErrorMessage)
+		}
+	})
 }
 
 func assertResponseHasProtobufContentType(t *testing.T, responseWriter *stubResponseWriter) {
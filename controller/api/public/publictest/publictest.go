@@ -0,0 +1,342 @@
+/*
+Package publictest provides a scriptable, concurrency-safe implementation of
+the public API client, for use in tests that need to simulate API behavior
+without a running control plane.
+
+Unary calls return whatever responses have been configured on the client,
+and every request received is recorded so that tests can assert on it.
+StatSummaryStream streams the next StatSummary response one table at a time. Tap
+calls replay a script of steps, which makes it possible to simulate slow
+streams, mid-stream errors and early EOFs:
+
+	client := publictest.NewMockApiClient()
+	client.SetTapScript(
+		publictest.Event(event1),
+		publictest.Sleep(100*time.Millisecond),
+		publictest.Event(event2),
+		publictest.Fail(errors.New("connection reset")),
+	)
+*/
+package publictest
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TapStep is a single step in a scripted tap stream.
+type TapStep struct {
+	event *pb.TapEvent
+	delay time.Duration
+	err   error
+	block bool
+}
+
+// Event returns a step that delivers a tap event to the caller of Recv.
+func Event(event *pb.TapEvent) TapStep {
+	return TapStep{event: event}
+}
+
+// Sleep returns a step that delays the stream for the given duration before
+// moving on to the next step. The delay is cut short if the stream's context
+// is cancelled.
+func Sleep(d time.Duration) TapStep {
+	return TapStep{delay: d}
+}
+
+// Fail returns a step that terminates the stream with the given error.
+func Fail(err error) TapStep {
+	return TapStep{err: err}
+}
+
+// Block returns a step that holds the stream open until its context is
+// cancelled, simulating a tap with no matching traffic.
+func Block() TapStep {
+	return TapStep{block: true}
+}
+
+// MockApiClient implements pb.ApiClient. All methods are safe for concurrent
+// use. Streams that reach the end of their script return io.EOF.
+type MockApiClient struct {
+	mu sync.Mutex
+
+	err         error
+	statSummary []*pb.StatSummaryResponse
+	versionInfo *pb.VersionInfo
+	listPods    *pb.ListPodsResponse
+	selfCheck   *healthcheckPb.SelfCheckResponse
+	tapScript   []TapStep
+
+	requests []proto.Message
+}
+
+// NewMockApiClient returns a client that answers every unary call with an
+// empty response and every tap with an immediate EOF.
+func NewMockApiClient() *MockApiClient {
+	return &MockApiClient{}
+}
+
+// SetError configures an error to be returned by every subsequent call.
+// Passing nil clears it.
+func (c *MockApiClient) SetError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+// SetStatSummaryResponses configures the responses for StatSummary. Calls
+// consume the responses in order; once a single response remains it is
+// returned to all further calls.
+func (c *MockApiClient) SetStatSummaryResponses(responses ...*pb.StatSummaryResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.statSummary = responses
+}
+
+// SetVersionInfo configures the response for Version.
+func (c *MockApiClient) SetVersionInfo(rsp *pb.VersionInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.versionInfo = rsp
+}
+
+// SetListPodsResponse configures the response for ListPods.
+func (c *MockApiClient) SetListPodsResponse(rsp *pb.ListPodsResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listPods = rsp
+}
+
+// SetSelfCheckResponse configures the response for SelfCheck.
+func (c *MockApiClient) SetSelfCheckResponse(rsp *healthcheckPb.SelfCheckResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.selfCheck = rsp
+}
+
+// SetTapScript configures the steps replayed by each stream returned from
+// TapByResource.
+func (c *MockApiClient) SetTapScript(steps ...TapStep) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tapScript = steps
+}
+
+// Requests returns a copy of all requests received so far, in order.
+func (c *MockApiClient) Requests() []proto.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	requests := make([]proto.Message, len(c.requests))
+	copy(requests, c.requests)
+	return requests
+}
+
+func (c *MockApiClient) record(req proto.Message) error {
+	c.requests = append(c.requests, req)
+	return c.err
+}
+
+func (c *MockApiClient) StatSummary(ctx context.Context, req *pb.StatSummaryRequest, _ ...grpc.CallOption) (*pb.StatSummaryResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.record(req); err != nil {
+		return nil, err
+	}
+
+	return c.nextStatSummary(), nil
+}
+
+// StatSummaryStream consumes the next StatSummary response, like StatSummary,
+// and streams it one table at a time.
+func (c *MockApiClient) StatSummaryStream(ctx context.Context, req *pb.StatSummaryRequest, _ ...grpc.CallOption) (pb.Api_StatSummaryStreamClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.record(req); err != nil {
+		return nil, err
+	}
+
+	rsp := c.nextStatSummary()
+	stream := &StatSummaryStream{ctx: ctx}
+	if rsp.GetOk() == nil {
+		stream.responses = []*pb.StatSummaryResponse{rsp}
+	}
+	for _, table := range rsp.GetOk().GetStatTables() {
+		stream.responses = append(stream.responses, &pb.StatSummaryResponse{
+			Response: &pb.StatSummaryResponse_Ok_{
+				Ok: &pb.StatSummaryResponse_Ok{StatTables: []*pb.StatTable{table}},
+			},
+		})
+	}
+	return stream, nil
+}
+
+func (c *MockApiClient) nextStatSummary() *pb.StatSummaryResponse {
+	if len(c.statSummary) == 0 {
+		return &pb.StatSummaryResponse{}
+	}
+	rsp := c.statSummary[0]
+	if len(c.statSummary) > 1 {
+		c.statSummary = c.statSummary[1:]
+	}
+	return rsp
+}
+
+func (c *MockApiClient) Version(ctx context.Context, req *pb.Empty, _ ...grpc.CallOption) (*pb.VersionInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.record(req); err != nil {
+		return nil, err
+	}
+
+	if c.versionInfo == nil {
+		return &pb.VersionInfo{}, nil
+	}
+	return c.versionInfo, nil
+}
+
+func (c *MockApiClient) ListPods(ctx context.Context, req *pb.ListPodsRequest, _ ...grpc.CallOption) (*pb.ListPodsResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.record(req); err != nil {
+		return nil, err
+	}
+
+	if c.listPods == nil {
+		return &pb.ListPodsResponse{}, nil
+	}
+	return c.listPods, nil
+}
+
+func (c *MockApiClient) SelfCheck(ctx context.Context, req *healthcheckPb.SelfCheckRequest, _ ...grpc.CallOption) (*healthcheckPb.SelfCheckResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.record(req); err != nil {
+		return nil, err
+	}
+
+	if c.selfCheck == nil {
+		return &healthcheckPb.SelfCheckResponse{}, nil
+	}
+	return c.selfCheck, nil
+}
+
+func (c *MockApiClient) Tap(ctx context.Context, req *pb.TapRequest, _ ...grpc.CallOption) (pb.Api_TapClient, error) {
+	return nil, status.Error(codes.Unimplemented, "Tap is deprecated, use TapByResource")
+}
+
+func (c *MockApiClient) TapByResource(ctx context.Context, req *pb.TapByResourceRequest, _ ...grpc.CallOption) (pb.Api_TapByResourceClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.record(req); err != nil {
+		return nil, err
+	}
+
+	steps := make([]TapStep, len(c.tapScript))
+	copy(steps, c.tapScript)
+	return &TapStream{ctx: ctx, steps: steps}, nil
+}
+
+// StatSummaryStream returns one response per table. It implements
+// pb.Api_StatSummaryStreamClient.
+type StatSummaryStream struct {
+	ctx       context.Context
+	mu        sync.Mutex
+	responses []*pb.StatSummaryResponse
+}
+
+// Recv returns the next table's response, or io.EOF once every table has
+// been returned.
+func (s *StatSummaryStream) Recv() (*pb.StatSummaryResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ctx.Err(); err != nil {
+		return nil, contextError(err)
+	}
+	if len(s.responses) == 0 {
+		return nil, io.EOF
+	}
+	rsp := s.responses[0]
+	s.responses = s.responses[1:]
+	return rsp, nil
+}
+
+// satisfy the pb.Api_StatSummaryStreamClient interface
+func (s *StatSummaryStream) Header() (metadata.MD, error) { return nil, nil }
+func (s *StatSummaryStream) Trailer() metadata.MD         { return nil }
+func (s *StatSummaryStream) CloseSend() error             { return nil }
+func (s *StatSummaryStream) Context() context.Context     { return s.ctx }
+func (s *StatSummaryStream) SendMsg(interface{}) error    { return nil }
+func (s *StatSummaryStream) RecvMsg(interface{}) error    { return nil }
+
+// TapStream replays a script of tap steps. It implements
+// pb.Api_TapByResourceClient.
+type TapStream struct {
+	ctx   context.Context
+	mu    sync.Mutex
+	steps []TapStep
+}
+
+// Recv advances the script until it produces an event or an error.
+func (s *TapStream) Recv() (*pb.TapEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		if err := s.ctx.Err(); err != nil {
+			return nil, contextError(err)
+		}
+		if len(s.steps) == 0 {
+			return nil, io.EOF
+		}
+
+		step := s.steps[0]
+		switch {
+		case step.block:
+			<-s.ctx.Done()
+			continue
+		case step.delay > 0:
+			select {
+			case <-time.After(step.delay):
+			case <-s.ctx.Done():
+				continue
+			}
+		}
+
+		s.steps = s.steps[1:]
+		switch {
+		case step.err != nil:
+			s.steps = nil
+			return nil, step.err
+		case step.event != nil:
+			return step.event, nil
+		}
+	}
+}
+
+// contextError maps a context error to the gRPC status a real stream would
+// report.
+func contextError(err error) error {
+	if err == context.DeadlineExceeded {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Canceled, err.Error())
+}
+
+// satisfy the pb.Api_TapByResourceClient interface
+func (s *TapStream) Header() (metadata.MD, error) { return nil, nil }
+func (s *TapStream) Trailer() metadata.MD         { return nil }
+func (s *TapStream) CloseSend() error             { return nil }
+func (s *TapStream) Context() context.Context     { return s.ctx }
+func (s *TapStream) SendMsg(interface{}) error    { return nil }
+func (s *TapStream) RecvMsg(interface{}) error    { return nil }
@@ -0,0 +1,115 @@
+package publictest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMockApiClient(t *testing.T) {
+	t.Run("Returns StatSummary responses in order, repeating the last one", func(t *testing.T) {
+		first := &pb.StatSummaryResponse{Response: &pb.StatSummaryResponse_Error{Error: &pb.ResourceError{Error: "first"}}}
+		second := &pb.StatSummaryResponse{Response: &pb.StatSummaryResponse_Error{Error: &pb.ResourceError{Error: "second"}}}
+
+		client := NewMockApiClient()
+		client.SetStatSummaryResponses(first, second)
+
+		for _, expected := range []string{"first", "second", "second"} {
+			rsp, err := client.StatSummary(context.Background(), &pb.StatSummaryRequest{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if rsp.GetError().GetError() != expected {
+				t.Fatalf("Expected response [%s], got [%s]", expected, rsp.GetError().GetError())
+			}
+		}
+	})
+
+	t.Run("Records requests from concurrent callers", func(t *testing.T) {
+		client := NewMockApiClient()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				client.Version(context.Background(), &pb.Empty{})
+				client.ListPods(context.Background(), &pb.ListPodsRequest{})
+			}()
+		}
+		wg.Wait()
+
+		if len(client.Requests()) != 20 {
+			t.Fatalf("Expected 20 recorded requests, got %d", len(client.Requests()))
+		}
+	})
+
+	t.Run("Returns the configured error", func(t *testing.T) {
+		client := NewMockApiClient()
+		client.SetError(errors.New("expected"))
+
+		_, err := client.TapByResource(context.Background(), &pb.TapByResourceRequest{})
+		if err == nil || err.Error() != "expected" {
+			t.Fatalf("Expected error [expected], got [%v]", err)
+		}
+	})
+}
+
+func TestTapStream(t *testing.T) {
+	event := &pb.TapEvent{ProxyDirection: pb.TapEvent_INBOUND}
+
+	t.Run("Replays events and ends with EOF", func(t *testing.T) {
+		client := NewMockApiClient()
+		client.SetTapScript(Event(event), Sleep(10*time.Millisecond), Event(event))
+
+		stream, err := client.TapByResource(context.Background(), &pb.TapByResourceRequest{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for i := 0; i < 2; i++ {
+			if _, err := stream.Recv(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		if _, err := stream.Recv(); err != io.EOF {
+			t.Fatalf("Expected EOF, got [%v]", err)
+		}
+	})
+
+	t.Run("Terminates the stream on a scripted failure", func(t *testing.T) {
+		client := NewMockApiClient()
+		client.SetTapScript(Event(event), Fail(errors.New("reset")), Event(event))
+
+		stream, _ := client.TapByResource(context.Background(), &pb.TapByResourceRequest{})
+		stream.Recv()
+
+		if _, err := stream.Recv(); err == nil || err.Error() != "reset" {
+			t.Fatalf("Expected error [reset], got [%v]", err)
+		}
+		if _, err := stream.Recv(); err != io.EOF {
+			t.Fatalf("Expected EOF after failure, got [%v]", err)
+		}
+	})
+
+	t.Run("Unblocks when the context is cancelled", func(t *testing.T) {
+		client := NewMockApiClient()
+		client.SetTapScript(Block())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		stream, _ := client.TapByResource(ctx, &pb.TapByResourceRequest{})
+
+		_, err := stream.Recv()
+		if status.Code(err) != codes.DeadlineExceeded {
+			t.Fatalf("Expected DeadlineExceeded, got [%v]", err)
+		}
+	})
+}
@@ -2,9 +2,11 @@ package public
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
-	"time"
+	"sort"
+	"strings"
 
 	proto "github.com/golang/protobuf/proto"
 	"github.com/linkerd/linkerd2/controller/api/util"
@@ -13,6 +15,7 @@ import (
 	"github.com/prometheus/common/model"
 	log "github.com/sirupsen/logrus"
 	apiv1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -52,6 +55,12 @@ const (
 
 	namespaceLabel    = model.LabelName("namespace")
 	dstNamespaceLabel = model.LabelName("dst_namespace")
+
+	// maxConcurrentPromQueries is the number of Prometheus queries the public
+	// API runs at once. Each query is already grouped by resource label, so a
+	// request issues a fixed number of queries per resource type regardless of
+	// how many resources there are.
+	maxConcurrentPromQueries = 10
 )
 
 var promTypes = []promType{promRequests, promLatencyP50, promLatencyP95, promLatencyP99}
@@ -64,11 +73,45 @@ type podStats struct {
 }
 
 func (s *grpcServer) StatSummary(ctx context.Context, req *pb.StatSummaryRequest) (*pb.StatSummaryResponse, error) {
+	statTables := make([]*pb.StatTable, 0)
+	errRsp, err := s.statTables(ctx, req, func(table *pb.StatTable) error {
+		statTables = append(statTables, table)
+		return nil
+	})
+	if errRsp != nil || err != nil {
+		return errRsp, err
+	}
+
+	return statSummaryOk(statTables...), nil
+}
+
+func (s *grpcServer) StatSummaryStream(req *pb.StatSummaryRequest, stream pb.Api_StatSummaryStreamServer) error {
+	errRsp, err := s.statTables(stream.Context(), req, func(table *pb.StatTable) error {
+		return stream.Send(statSummaryOk(table))
+	})
+	if err != nil {
+		return err
+	}
+	if errRsp != nil {
+		return stream.Send(errRsp)
+	}
+	return nil
+}
+
+// statTables computes the stat tables for req, one per resource type, and
+// passes each to send as soon as it and the tables before it are ready. It
+// returns an error response if req isn't valid.
+func (s *grpcServer) statTables(ctx context.Context, req *pb.StatSummaryRequest, send func(*pb.StatTable) error) (*pb.StatSummaryResponse, error) {
 
 	// check for well-formed request
 	if req.GetSelector().GetResource() == nil {
 		return statSummaryError(req, "StatSummary request missing Selector Resource"), nil
 	}
+	for _, resource := range req.AdditionalResources {
+		if resource == nil {
+			return statSummaryError(req, "StatSummary request has an empty additional resource"), nil
+		}
+	}
 
 	// special case to check for services as outbound only
 	if isInvalidServiceRequest(req) {
@@ -86,48 +129,169 @@ func (s *grpcServer) StatSummary(ctx context.Context, req *pb.StatSummaryRequest
 		}
 	}
 
-	statTables := make([]*pb.StatTable, 0)
+	// ingresses only have inbound stats, for the hosts they route
+	if req.GetToResource().GetType() == k8s.Ingress || req.GetFromResource().GetType() == k8s.Ingress {
+		return statSummaryError(req, "resource type 'ingress' is not supported as a filter"), nil
+	}
+	for _, target := range statTargets(req) {
+		if target.GetType() == k8s.Ingress && (req.GetToResource() != nil || req.GetFromResource() != nil) {
+			return statSummaryError(req, "resource type 'ingress' is not supported with 'to' or 'from' queries"), nil
+		}
+	}
 
-	var resourcesToQuery []string
-	if req.Selector.Resource.Type == k8s.All {
-		resourcesToQuery = k8s.StatAllResourceTypes
-	} else {
-		resourcesToQuery = []string{req.Selector.Resource.Type}
+	windows, err := statTimeWindows(req)
+	if err != nil {
+		return statSummaryError(req, err.Error()), nil
 	}
+	req = proto.Clone(req).(*pb.StatSummaryRequest)
+	req.TimeWindow = windows[0]
+
+	if req.GroupByLabel != "" {
+		if err := validateGroupByLabel(req); err != nil {
+			return statSummaryError(req, err.Error()), nil
+		}
+		result := s.labelGroupQuery(ctx, req)
+		if result.err != nil {
+			return nil, util.GRPCError(result.err)
+		}
+		return nil, send(result.res)
+	}
+
+	// request stats for each selected resource, expanding "all" to every
+	// resource type, in parallel; each result is buffered so that no query is
+	// left blocked if an earlier one fails
+	var resourceTypes []string
+	resultChans := make(map[string][]chan resourceResult)
+
+	for _, target := range statTargets(req) {
+		var resourcesToQuery []*pb.Resource
+		if target.Type == k8s.All {
+			for _, resourceType := range k8s.StatAllResourceTypes {
+				resourcesToQuery = append(resourcesToQuery, &pb.Resource{
+					Namespace: target.Namespace,
+					Type:      resourceType,
+					Name:      target.Name,
+				})
+			}
+		} else {
+			resourcesToQuery = []*pb.Resource{target}
+		}
 
-	// request stats for the resourcesToQuery, in parallel
-	resultChan := make(chan resourceResult)
+		for _, resource := range resourcesToQuery {
+			if _, ok := resultChans[resource.Type]; !ok {
+				resourceTypes = append(resourceTypes, resource.Type)
+			}
 
-	for _, resource := range resourcesToQuery {
-		statReq := proto.Clone(req).(*pb.StatSummaryRequest)
-		statReq.Selector.Resource.Type = resource
+			for _, window := range windows {
+				statReq := proto.Clone(req).(*pb.StatSummaryRequest)
+				statReq.Selector.Resource = resource
+				statReq.AdditionalResources = nil
+				statReq.TimeWindow = window
+				statReq.AdditionalTimeWindows = nil
+				resultChan := make(chan resourceResult, 1)
+				resultChans[resource.Type] = append(resultChans[resource.Type], resultChan)
+
+				go func() {
+					switch resourceType := statReq.GetSelector().GetResource().GetType(); {
+					case resourceType == k8s.Ingress:
+						resultChan <- s.ingressQuery(ctx, statReq)
+					case isNonK8sResourceQuery(resourceType):
+						resultChan <- s.nonK8sResourceQuery(ctx, statReq)
+					default:
+						resultChan <- s.k8sResourceQuery(ctx, statReq)
+					}
+				}()
+			}
+		}
+	}
 
-		go func() {
-			if isNonK8sResourceQuery(statReq.GetSelector().GetResource().GetType()) {
-				resultChan <- s.nonK8sResourceQuery(ctx, statReq)
-			} else {
-				resultChan <- s.k8sResourceQuery(ctx, statReq)
+	// send a table per resource type, with the rows of every resource of that
+	// type
+	for _, resourceType := range resourceTypes {
+		var tables []*pb.StatTable
+		for _, resultChan := range resultChans[resourceType] {
+			result := <-resultChan
+			if result.err != nil {
+				return nil, util.GRPCError(result.err)
 			}
-		}()
+			tables = append(tables, result.res)
+		}
+		if err := send(mergeStatTables(tables)); err != nil {
+			return nil, err
+		}
 	}
 
-	for i := 0; i < len(resourcesToQuery); i++ {
-		result := <-resultChan
-		if result.err != nil {
-			return nil, util.GRPCError(result.err)
+	return nil, nil
+}
+
+// statTargets returns the resources selected by req.
+func statTargets(req *pb.StatSummaryRequest) []*pb.Resource {
+	return append([]*pb.Resource{req.GetSelector().GetResource()}, req.GetAdditionalResources()...)
+}
+
+// statTimeWindows returns the windows to report the stats of req over, in the
+// form that Prometheus accepts.
+func statTimeWindows(req *pb.StatSummaryRequest) ([]string, error) {
+	var windows []string
+	for _, window := range append([]string{req.TimeWindow}, req.AdditionalTimeWindows...) {
+		normalized, err := util.NormalizeTimeWindow(window)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time window: %s", err)
+		}
+		windows = append(windows, normalized)
+	}
+	return windows, nil
+}
+
+// mergeStatTables combines the rows of tables of the same resource type into
+// one table. A row for a resource that was selected more than once, e.g. by
+// "deploy" and "deploy/web", is only kept the first time for each window.
+func mergeStatTables(tables []*pb.StatTable) *pb.StatTable {
+	if len(tables) == 1 {
+		return tables[0]
+	}
+
+	type windowKey struct {
+		rKey
+		window string
+	}
+	seen := make(map[windowKey]bool)
+	rows := make([]*pb.StatTable_PodGroup_Row, 0)
+	for _, table := range tables {
+		for _, row := range table.GetPodGroup().GetRows() {
+			key := windowKey{
+				rKey: rKey{
+					Namespace: row.GetResource().GetNamespace(),
+					Type:      row.GetResource().GetType(),
+					Name:      row.GetResource().GetName(),
+				},
+				window: row.GetTimeWindow(),
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			rows = append(rows, row)
 		}
-		statTables = append(statTables, result.res)
 	}
 
-	rsp := pb.StatSummaryResponse{
+	return &pb.StatTable{
+		Table: &pb.StatTable_PodGroup_{
+			PodGroup: &pb.StatTable_PodGroup{
+				Rows: rows,
+			},
+		},
+	}
+}
+
+func statSummaryOk(statTables ...*pb.StatTable) *pb.StatSummaryResponse {
+	return &pb.StatSummaryResponse{
 		Response: &pb.StatSummaryResponse_Ok_{ // https://github.com/golang/protobuf/issues/205
 			Ok: &pb.StatSummaryResponse_Ok{
 				StatTables: statTables,
 			},
 		},
 	}
-
-	return &rsp, nil
 }
 
 func statSummaryError(req *pb.StatSummaryRequest, message string) *pb.StatSummaryResponse {
@@ -257,6 +421,160 @@ func (s *grpcServer) nonK8sResourceQuery(ctx context.Context, req *pb.StatSummar
 	return resourceResult{res: &rsp, err: nil}
 }
 
+// ingressQuery returns a row for each host of each selected ingress, named
+// "INGRESS/HOST", with the stats of the inbound requests for that host in the
+// ingress's namespace, i.e. those that the ingress controller routed to the
+// ingress's backends. Rules without a host aren't reported, since their
+// requests can't be told apart from those sent from inside the cluster.
+func (s *grpcServer) ingressQuery(ctx context.Context, req *pb.StatSummaryRequest) resourceResult {
+	resource := req.GetSelector().GetResource()
+	objects, err := s.k8sAPI.GetObjects(resource.Namespace, k8s.Ingress, resource.Name)
+	if err != nil {
+		return resourceResult{res: nil, err: err}
+	}
+
+	authorityReq := proto.Clone(req).(*pb.StatSummaryRequest)
+	authorityReq.Selector.Resource = &pb.Resource{
+		Namespace: resource.Namespace,
+		Type:      k8s.Authority,
+	}
+	requestMetrics, err := s.getPrometheusMetrics(ctx, authorityReq, req.TimeWindow)
+	if err != nil {
+		return resourceResult{res: nil, err: err}
+	}
+
+	rows := make([]*pb.StatTable_PodGroup_Row, 0)
+	for _, object := range objects {
+		ing, ok := object.(*extensionsv1beta1.Ingress)
+		if !ok {
+			continue
+		}
+
+		for _, host := range ingressHosts(ing) {
+			row := pb.StatTable_PodGroup_Row{
+				Resource: &pb.Resource{
+					Type:      k8s.Ingress,
+					Namespace: ing.Namespace,
+					Name:      ing.Name + "/" + host,
+				},
+				TimeWindow: req.TimeWindow,
+				Stats:      hostStats(requestMetrics, ing.Namespace, host),
+			}
+			rows = append(rows, &row)
+		}
+	}
+
+	rsp := pb.StatTable{
+		Table: &pb.StatTable_PodGroup_{
+			PodGroup: &pb.StatTable_PodGroup{
+				Rows: rows,
+			},
+		},
+	}
+	return resourceResult{res: &rsp, err: nil}
+}
+
+// ingressHosts returns the distinct hosts of the rules of an ingress.
+func ingressHosts(ing *extensionsv1beta1.Ingress) []string {
+	hosts := []string{}
+	seen := make(map[string]bool)
+	for _, rule := range ing.Spec.Rules {
+		if rule.Host == "" || seen[rule.Host] {
+			continue
+		}
+		seen[rule.Host] = true
+		hosts = append(hosts, rule.Host)
+	}
+	return hosts
+}
+
+// hostStats combines the stats of the authorities in namespace that are host,
+// with or without a port. Latency percentiles can't be combined, so the
+// highest of each is reported. It returns nil if host received no requests.
+func hostStats(authorityStats map[rKey]*pb.BasicStats, namespace, host string) *pb.BasicStats {
+	var stats *pb.BasicStats
+	for key, authority := range authorityStats {
+		if key.Namespace != namespace || (key.Name != host && !strings.HasPrefix(key.Name, host+":")) {
+			continue
+		}
+		if stats == nil {
+			stats = &pb.BasicStats{}
+		}
+		stats.SuccessCount += authority.SuccessCount
+		stats.FailureCount += authority.FailureCount
+		stats.TlsRequestCount += authority.TlsRequestCount
+		if authority.LatencyMsP50 > stats.LatencyMsP50 {
+			stats.LatencyMsP50 = authority.LatencyMsP50
+		}
+		if authority.LatencyMsP95 > stats.LatencyMsP95 {
+			stats.LatencyMsP95 = authority.LatencyMsP95
+		}
+		if authority.LatencyMsP99 > stats.LatencyMsP99 {
+			stats.LatencyMsP99 = authority.LatencyMsP99
+		}
+	}
+	return stats
+}
+
+func validateGroupByLabel(req *pb.StatSummaryRequest) error {
+	if !model.LabelName(req.GroupByLabel).IsValid() {
+		return fmt.Errorf("invalid label to group by: %s", req.GroupByLabel)
+	}
+	if len(req.AdditionalResources) > 0 {
+		return errors.New("multiple resources are not supported when grouping by label")
+	}
+	if len(req.AdditionalTimeWindows) > 0 {
+		return errors.New("multiple time windows are not supported when grouping by label")
+	}
+	switch req.Selector.Resource.Type {
+	case k8s.All:
+		return errors.New("resource type 'all' is not supported when grouping by label")
+	case k8s.Deployment, k8s.Namespace, k8s.Pod, k8s.ReplicationController, k8s.Service, k8s.Authority:
+	default:
+		return fmt.Errorf("unimplemented resource type: %s", req.Selector.Resource.Type)
+	}
+	if req.GetFromResource() != nil {
+		return errors.New("'from' queries are not supported when grouping by label")
+	}
+	return nil
+}
+
+// labelGroupQuery returns a row per value of req.GroupByLabel, aggregating the
+// stats of every selected resource with that value. The pod counts of a row
+// aren't known, as its pods can belong to resources of any type.
+func (s *grpcServer) labelGroupQuery(ctx context.Context, req *pb.StatSummaryRequest) resourceResult {
+	requestMetrics, err := s.getPrometheusMetrics(ctx, req, req.TimeWindow)
+	if err != nil {
+		return resourceResult{res: nil, err: err}
+	}
+	rows := make([]*pb.StatTable_PodGroup_Row, 0)
+
+	for rkey, metrics := range requestMetrics {
+		if rkey.Name == "" {
+			continue
+		}
+		row := pb.StatTable_PodGroup_Row{
+			Resource: &pb.Resource{
+				Type:      req.GetSelector().GetResource().GetType(),
+				Namespace: req.GetSelector().GetResource().GetNamespace(),
+				Name:      rkey.Name,
+			},
+			TimeWindow: req.TimeWindow,
+			Stats:      metrics,
+		}
+		rows = append(rows, &row)
+	}
+
+	rsp := pb.StatTable{
+		Table: &pb.StatTable_PodGroup_{
+			PodGroup: &pb.StatTable_PodGroup{
+				Rows: rows,
+			},
+		},
+	}
+	return resourceResult{res: &rsp, err: nil}
+}
+
 func isNonK8sResourceQuery(resourceType string) bool {
 	return resourceType == k8s.Authority
 }
@@ -353,31 +671,63 @@ func promResourceType(resource *pb.Resource) model.LabelName {
 	return model.LabelName(resource.Type)
 }
 
-func buildRequestLabels(req *pb.StatSummaryRequest) (labels model.LabelSet, labelNames model.LabelNames) {
+// promSelector renders labels as a Prometheus selector, with a `!=""` matcher
+// for each of nonEmpty.
+func promSelector(labels model.LabelSet, nonEmpty model.LabelNames) string {
+	if len(nonEmpty) == 0 {
+		return labels.String()
+	}
+
+	matchers := make([]string, 0, len(labels)+len(nonEmpty))
+	for name, value := range labels {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", name, value))
+	}
+	sort.Strings(matchers)
+	for _, name := range nonEmpty {
+		matchers = append(matchers, fmt.Sprintf(`%s!=""`, name))
+	}
+	return "{" + strings.Join(matchers, ", ") + "}"
+}
+
+func buildRequestLabels(req *pb.StatSummaryRequest) (labels string, labelNames model.LabelNames) {
 	// labelNames: the group by in the prometheus query
-	// labels: the labels for the resource we want to query for
+	// labels: the selector for the resource we want to query for
+	var set model.LabelSet
+	var nonEmpty model.LabelNames
 
 	switch out := req.Outbound.(type) {
 	case *pb.StatSummaryRequest_ToResource:
 		labelNames = promGroupByLabelNames(req.Selector.Resource)
 
-		labels = labels.Merge(promDstQueryLabels(out.ToResource))
-		labels = labels.Merge(promQueryLabels(req.Selector.Resource))
-		labels = labels.Merge(promDirectionLabels("outbound"))
+		set = set.Merge(promDstQueryLabels(out.ToResource))
+		set = set.Merge(promQueryLabels(req.Selector.Resource))
+		set = set.Merge(promDirectionLabels("outbound"))
 
 	case *pb.StatSummaryRequest_FromResource:
 		labelNames = promDstGroupByLabelNames(req.Selector.Resource)
 
-		labels = labels.Merge(promQueryLabels(out.FromResource))
-		labels = labels.Merge(promDirectionLabels("outbound"))
+		set = set.Merge(promQueryLabels(out.FromResource))
+		set = set.Merge(promDirectionLabels("outbound"))
 
 	default:
 		labelNames = promGroupByLabelNames(req.Selector.Resource)
 
-		labels = labels.Merge(promQueryLabels(req.Selector.Resource))
-		labels = labels.Merge(promDirectionLabels("inbound"))
+		set = set.Merge(promQueryLabels(req.Selector.Resource))
+		set = set.Merge(promDirectionLabels("inbound"))
 	}
 
+	if req.GroupByLabel != "" {
+		labelNames = model.LabelNames{model.LabelName(req.GroupByLabel)}
+		// without a resource name, only match the requests of resources of the
+		// selected type, and leave out those without the label rather than
+		// grouping them into a row of their own
+		nonEmpty = model.LabelNames{model.LabelName(req.GroupByLabel)}
+		if resourceType := promResourceType(req.Selector.Resource); resourceType != nonEmpty[0] {
+			nonEmpty = append(nonEmpty, resourceType)
+		}
+	}
+
+	labels = promSelector(set, nonEmpty)
 	return
 }
 
@@ -563,26 +913,24 @@ func isInvalidServiceRequest(req *pb.StatSummaryRequest) bool {
 	if fromResource != nil {
 		return fromResource.Type == k8s.Service
 	} else {
-		return req.Selector.Resource.Type == k8s.Service
+		for _, target := range statTargets(req) {
+			if target.Type == k8s.Service {
+				return true
+			}
+		}
+		return false
 	}
 }
 
 func (s *grpcServer) queryProm(ctx context.Context, query string) (model.Vector, error) {
 	log.Debugf("Query request:\n\t%+v", query)
 
-	// single data point (aka summary) query
-	res, err := s.prometheusAPI.Query(ctx, query, time.Time{})
-	if err != nil {
-		log.Errorf("Query(%+v) failed with: %+v", query, err)
-		return nil, err
-	}
-	log.Debugf("Query response:\n\t%+v", res)
-
-	if res.Type() != model.ValVector {
-		err = fmt.Errorf("Unexpected query result type (expected Vector): %s", res.Type())
-		log.Error(err)
-		return nil, err
+	select {
+	case s.promQuerySlots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
+	defer func() { <-s.promQuerySlots }()
 
-	return res.(model.Vector), nil
+	return s.metrics.QueryVector(ctx, query)
 }
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"reflect"
 	"sort"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	tap "github.com/linkerd/linkerd2/controller/gen/controller/tap"
@@ -76,7 +78,7 @@ func testStatSummary(t *testing.T, expectations []statSumExpected) {
 
 		mockProm := &MockProm{Res: exp.mockPromResponse}
 		fakeGrpcServer := newGrpcServer(
-			mockProm,
+			NewPrometheusProvider(mockProm),
 			tap.NewTapClient(nil),
 			k8sAPI,
 			"linkerd",
@@ -272,6 +274,157 @@ status:
 		testStatSummary(t, expectations)
 	})
 
+	t.Run("Returns a single table for additional resources of the same type", func(t *testing.T) {
+		expectedResponse := GenStatSummaryResponse("emojivoto-1", pkgK8s.Pod, "emojivoto", &PodCounts{
+			MeshedPods:  1,
+			RunningPods: 1,
+			FailedPods:  0,
+		})
+		table := expectedResponse.GetOk().StatTables[0].GetPodGroup()
+		table.Rows = append(table.Rows, &pb.StatTable_PodGroup_Row{
+			Resource: &pb.Resource{
+				Namespace: "emojivoto",
+				Type:      pkgK8s.Pod,
+				Name:      "emojivoto-2",
+			},
+			TimeWindow:      "1m",
+			MeshedPodCount:  1,
+			RunningPodCount: 1,
+		})
+
+		expectations := []statSumExpected{
+			statSumExpected{
+				err: nil,
+				k8sConfigs: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-1
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+    linkerd.io/control-plane-ns: linkerd
+status:
+  phase: Running
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-2
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+    linkerd.io/control-plane-ns: linkerd
+status:
+  phase: Running
+`,
+				},
+				mockPromResponse: prometheusMetric("emojivoto-1", "pod", "emojivoto", "success", false),
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Name:      "emojivoto-1",
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Pod,
+						},
+					},
+					AdditionalResources: []*pb.Resource{
+						&pb.Resource{Name: "emojivoto-2", Namespace: "emojivoto", Type: pkgK8s.Pod},
+						// selected twice, but only reported once
+						&pb.Resource{Name: "emojivoto-1", Namespace: "emojivoto", Type: pkgK8s.Pod},
+					},
+					TimeWindow: "1m",
+				},
+				expectedResponse: expectedResponse,
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
+	t.Run("Returns a row per time window", func(t *testing.T) {
+		expectedResponse := GenStatSummaryResponse("emojivoto-1", pkgK8s.Pod, "emojivoto", &PodCounts{
+			MeshedPods:  1,
+			RunningPods: 1,
+			FailedPods:  0,
+		})
+		table := expectedResponse.GetOk().StatTables[0].GetPodGroup()
+		row := proto.Clone(table.Rows[0]).(*pb.StatTable_PodGroup_Row)
+		row.TimeWindow = "90s"
+		table.Rows = append(table.Rows, row)
+
+		expectations := []statSumExpected{
+			statSumExpected{
+				err: nil,
+				k8sConfigs: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-1
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+    linkerd.io/control-plane-ns: linkerd
+status:
+  phase: Running
+`,
+				},
+				mockPromResponse: prometheusMetric("emojivoto-1", "pod", "emojivoto", "success", false),
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Name:      "emojivoto-1",
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Pod,
+						},
+					},
+					TimeWindow:            "1m",
+					AdditionalTimeWindows: []string{"1m30s"},
+				},
+				expectedPrometheusQueries: []string{
+					`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+					`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+					`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (le, namespace, pod))`,
+					`sum(increase(response_total{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[1m])) by (namespace, pod, classification, tls)`,
+					`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[90s])) by (le, namespace, pod))`,
+					`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[90s])) by (le, namespace, pod))`,
+					`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[90s])) by (le, namespace, pod))`,
+					`sum(increase(response_total{direction="inbound", namespace="emojivoto", pod="emojivoto-1"}[90s])) by (namespace, pod, classification, tls)`,
+				},
+				expectedResponse: expectedResponse,
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
+	t.Run("Rejects invalid time windows", func(t *testing.T) {
+		k8sAPI, err := k8s.NewFakeAPI()
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+		fakeGrpcServer := newGrpcServer(
+			NewPrometheusProvider(&MockProm{Res: model.Vector{}}),
+			tap.NewTapClient(nil),
+			k8sAPI,
+			"linkerd",
+			[]string{},
+		)
+
+		rsp, err := fakeGrpcServer.StatSummary(context.TODO(), &pb.StatSummaryRequest{
+			Selector:              &pb.ResourceSelection{Resource: &pb.Resource{Type: pkgK8s.Pod}},
+			TimeWindow:            "1m",
+			AdditionalTimeWindows: []string{"0s"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expectedError := "invalid time window: time window must be greater than 0: 0s"
+		if rsp.GetError().GetError() != expectedError {
+			t.Fatalf("Expected error [%s], got %+v", expectedError, rsp)
+		}
+	})
+
 	t.Run("Queries prometheus for outbound metrics if from resource is specified, ignores resource name", func(t *testing.T) {
 		expectations := []statSumExpected{
 			statSumExpected{
@@ -727,6 +880,82 @@ status:
 		testStatSummary(t, expectations)
 	})
 
+	t.Run("Groups stats by a label if a label to group by is specified", func(t *testing.T) {
+		expectations := []statSumExpected{
+			statSumExpected{
+				err: nil,
+				mockPromResponse: model.Vector{
+					genPromSample("payments", "team", "emojivoto", "success", false),
+					genPromSample("", "team", "emojivoto", "success", false),
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Deployment,
+						},
+					},
+					TimeWindow:   "1m",
+					GroupByLabel: "team",
+				},
+				expectedPrometheusQueries: []string{
+					`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", team!="", deployment!=""}[1m])) by (le, team))`,
+					`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", team!="", deployment!=""}[1m])) by (le, team))`,
+					`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto", team!="", deployment!=""}[1m])) by (le, team))`,
+					`sum(increase(response_total{direction="inbound", namespace="emojivoto", team!="", deployment!=""}[1m])) by (team, classification, tls)`,
+				},
+				expectedResponse: GenStatSummaryResponse("payments", pkgK8s.Deployment, "emojivoto", nil),
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
+	t.Run("Rejects invalid requests to group by a label", func(t *testing.T) {
+		k8sAPI, err := k8s.NewFakeAPI()
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+		fakeGrpcServer := newGrpcServer(
+			NewPrometheusProvider(&MockProm{Res: model.Vector{}}),
+			tap.NewTapClient(nil),
+			k8sAPI,
+			"linkerd",
+			[]string{},
+		)
+
+		invalidRequests := []*pb.StatSummaryRequest{
+			&pb.StatSummaryRequest{
+				Selector:     &pb.ResourceSelection{Resource: &pb.Resource{Type: pkgK8s.Deployment}},
+				GroupByLabel: "cost-center",
+			},
+			&pb.StatSummaryRequest{
+				Selector:     &pb.ResourceSelection{Resource: &pb.Resource{Type: pkgK8s.All}},
+				GroupByLabel: "team",
+			},
+			&pb.StatSummaryRequest{
+				Selector:     &pb.ResourceSelection{Resource: &pb.Resource{Type: pkgK8s.Deployment}},
+				Outbound:     &pb.StatSummaryRequest_FromResource{FromResource: &pb.Resource{Type: pkgK8s.Deployment, Name: "web"}},
+				GroupByLabel: "team",
+			},
+			&pb.StatSummaryRequest{
+				Selector:            &pb.ResourceSelection{Resource: &pb.Resource{Type: pkgK8s.Deployment}},
+				AdditionalResources: []*pb.Resource{&pb.Resource{Type: pkgK8s.Pod}},
+				GroupByLabel:        "team",
+			},
+		}
+
+		for _, req := range invalidRequests {
+			rsp, err := fakeGrpcServer.StatSummary(context.TODO(), req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if rsp.GetError() == nil {
+				t.Fatalf("Expected an error response for %+v, got %+v", req, rsp)
+			}
+		}
+	})
+
 	t.Run("Given an invalid resource type, returns error", func(t *testing.T) {
 		k8sAPI, err := k8s.NewFakeAPI()
 		if err != nil {
@@ -768,7 +997,7 @@ status:
 
 		for _, exp := range expectations {
 			fakeGrpcServer := newGrpcServer(
-				&MockProm{Res: exp.mockPromResponse},
+				NewPrometheusProvider(&MockProm{Res: exp.mockPromResponse}),
 				tap.NewTapClient(nil),
 				k8sAPI,
 				"linkerd",
@@ -792,7 +1021,7 @@ status:
 			t.Fatalf("NewFakeAPI returned an error: %s", err)
 		}
 		fakeGrpcServer := newGrpcServer(
-			&MockProm{Res: model.Vector{}},
+			NewPrometheusProvider(&MockProm{Res: model.Vector{}}),
 			tap.NewTapClient(nil),
 			k8sAPI,
 			"linkerd",
@@ -1062,6 +1291,63 @@ status:
 		testStatSummary(t, expectations)
 	})
 
+	t.Run("Queries prometheus for the stats of each host of an ingress", func(t *testing.T) {
+		expectations := []statSumExpected{
+			statSumExpected{
+				err: nil,
+				k8sConfigs: []string{`
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: web
+  namespace: emojivoto
+spec:
+  backend:
+    serviceName: web-svc
+    servicePort: 80
+  rules:
+  - host: web.example.com
+    http:
+      paths:
+      - path: /
+        backend:
+          serviceName: web-svc
+          servicePort: 80
+  - host: web.example.com
+    http:
+      paths:
+      - path: /api
+        backend:
+          serviceName: api-svc
+          servicePort: 80
+`,
+				},
+				mockPromResponse: model.Vector{
+					genPromSample("web.example.com:80", "authority", "emojivoto", "success", false),
+					genPromSample("web.example.org", "authority", "emojivoto", "success", false),
+				},
+				req: pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Ingress,
+						},
+					},
+					TimeWindow: "1m",
+				},
+				expectedPrometheusQueries: []string{
+					`histogram_quantile(0.5, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto"}[1m])) by (le, namespace, authority))`,
+					`histogram_quantile(0.95, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto"}[1m])) by (le, namespace, authority))`,
+					`histogram_quantile(0.99, sum(irate(response_latency_ms_bucket{direction="inbound", namespace="emojivoto"}[1m])) by (le, namespace, authority))`,
+					`sum(increase(response_total{direction="inbound", namespace="emojivoto"}[1m])) by (namespace, authority, classification, tls)`,
+				},
+				expectedResponse: GenStatSummaryResponse("web/web.example.com", pkgK8s.Ingress, "emojivoto", nil),
+			},
+		}
+
+		testStatSummary(t, expectations)
+	})
+
 	t.Run("Queries prometheus for authority stats when --from authority is used", func(t *testing.T) {
 		expectations := []statSumExpected{
 			statSumExpected{
@@ -1153,4 +1439,58 @@ status:
 
 		testStatSummary(t, expectations)
 	})
+
+	t.Run("Limits the number of concurrent Prometheus queries", func(t *testing.T) {
+		k8sAPI, err := k8s.NewFakeAPI()
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+		k8sAPI.Sync(nil)
+
+		mockProm := &slowProm{MockProm: MockProm{Res: model.Vector{}}}
+		fakeGrpcServer := newGrpcServer(NewPrometheusProvider(mockProm), tap.NewTapClient(nil), k8sAPI, "linkerd", []string{})
+
+		_, err = fakeGrpcServer.StatSummary(context.TODO(), &pb.StatSummaryRequest{
+			Selector: &pb.ResourceSelection{
+				Resource: &pb.Resource{Type: pkgK8s.All},
+			},
+			TimeWindow: "1m",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expectedQueries := len(pkgK8s.StatAllResourceTypes) * len(promTypes)
+		if len(mockProm.QueriesExecuted) != expectedQueries {
+			t.Fatalf("Expected %d queries, got %d", expectedQueries, len(mockProm.QueriesExecuted))
+		}
+		if mockProm.maxInFlight > maxConcurrentPromQueries {
+			t.Fatalf("Expected at most %d concurrent queries, got %d", maxConcurrentPromQueries, mockProm.maxInFlight)
+		}
+	})
+}
+
+// slowProm is a MockProm whose queries take long enough to overlap, and that
+// records the highest number of queries it saw in flight at once.
+type slowProm struct {
+	MockProm
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (m *slowProm) Query(ctx context.Context, query string, ts time.Time) (model.Value, error) {
+	m.mu.Lock()
+	m.inFlight++
+	if m.inFlight > m.maxInFlight {
+		m.maxInFlight = m.inFlight
+	}
+	m.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	m.mu.Lock()
+	m.inFlight--
+	m.mu.Unlock()
+	return m.MockProm.Query(ctx, query, ts)
 }
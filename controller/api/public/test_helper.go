@@ -27,6 +27,14 @@ func (c *MockApiClient) StatSummary(ctx context.Context, in *pb.StatSummaryReque
 	return c.StatSummaryResponseToReturn, c.ErrorToReturn
 }
 
+// StatSummaryStream streams StatSummaryResponseToReturn one table at a time.
+func (c *MockApiClient) StatSummaryStream(ctx context.Context, in *pb.StatSummaryRequest, opts ...grpc.CallOption) (pb.Api_StatSummaryStreamClient, error) {
+	if c.ErrorToReturn != nil {
+		return nil, c.ErrorToReturn
+	}
+	return &MockApi_StatSummaryStreamClient{ResponsesToReturn: SplitStatTables(c.StatSummaryResponseToReturn)}, nil
+}
+
 func (c *MockApiClient) Version(ctx context.Context, in *pb.Empty, opts ...grpc.CallOption) (*pb.VersionInfo, error) {
 	return c.VersionInfoToReturn, c.ErrorToReturn
 }
@@ -91,6 +99,37 @@ func (a *MockApi_TapByResourceClient) Recv() (*pb.TapEvent, error) {
 	return &eventPopped, errorPopped
 }
 
+type MockApi_StatSummaryStreamClient struct {
+	ResponsesToReturn []*pb.StatSummaryResponse
+	grpc.ClientStream
+}
+
+func (a *MockApi_StatSummaryStreamClient) Recv() (*pb.StatSummaryResponse, error) {
+	if len(a.ResponsesToReturn) == 0 {
+		return nil, io.EOF
+	}
+	var rsp *pb.StatSummaryResponse
+	rsp, a.ResponsesToReturn = a.ResponsesToReturn[0], a.ResponsesToReturn[1:]
+	return rsp, nil
+}
+
+// SplitStatTables splits rsp into the responses that StatSummaryStream would
+// send for it, one per table.
+func SplitStatTables(rsp *pb.StatSummaryResponse) []*pb.StatSummaryResponse {
+	if rsp == nil {
+		return nil
+	}
+	if rsp.GetOk() == nil {
+		return []*pb.StatSummaryResponse{rsp}
+	}
+
+	responses := make([]*pb.StatSummaryResponse, 0)
+	for _, table := range rsp.GetOk().StatTables {
+		responses = append(responses, statSummaryOk(table))
+	}
+	return responses
+}
+
 //
 // Prometheus client
 //
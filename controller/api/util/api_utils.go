@@ -3,11 +3,17 @@ package util
 import (
 	"errors"
 	"fmt"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/duration"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/addr"
+	"github.com/linkerd/linkerd2/pkg/format"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -20,9 +26,23 @@ import (
   Shared utilities for interacting with the controller public api
 */
 
+const (
+	// TapAPIVersion is the version of the tap APIService, which serves tap
+	// through the Kubernetes API so that access to it is authorized with RBAC.
+	TapAPIVersion = "v1alpha1"
+
+	// TapAPISubresource is the subresource of a tap target that users need
+	// "watch" access to in order to tap it.
+	TapAPISubresource = "tap"
+)
+
 var (
 	defaultMetricTimeWindow = "1m"
 
+	// promDurationRegex matches durations with a single unit, which
+	// Prometheus accepts as they are.
+	promDurationRegex = regexp.MustCompile(`^[0-9]+(ms|s|m|h)$`)
+
 	// ValidTargets specifies resource types allowed as a target:
 	// target resource on an inbound query
 	// target resource on an outbound 'to' query
@@ -59,6 +79,15 @@ type StatSummaryRequestParams struct {
 	FromType      string
 	FromName      string
 	AllNamespaces bool
+	GroupByLabel  string
+
+	// AdditionalResources selects further resources alongside ResourceType
+	// and ResourceName, each as a "TYPE" or "TYPE/NAME" string.
+	AdditionalResources []string
+
+	// AdditionalTimeWindows are further windows to report stats over
+	// alongside TimeWindow.
+	AdditionalTimeWindows []string
 }
 
 type TapRequestParams struct {
@@ -71,6 +100,36 @@ type TapRequestParams struct {
 	Method      string
 	Authority   string
 	Path        string
+
+	// FromResource, if set, only reports requests sent from its pods, which
+	// are resolved when the tap starts. It can be a service. FromNamespace
+	// defaults to Namespace.
+	FromResource  string
+	FromNamespace string
+
+	// NotToResource, NotMethod and NotPath exclude requests that would
+	// otherwise match. NotToResource is looked up in ToNamespace.
+	NotToResource string
+	NotMethod     string
+	NotPath       string
+
+	// MinLatency, if set, only reports requests whose response took at least
+	// this long to start.
+	MinLatency time.Duration
+
+	// ResponseStatus, if set, only reports requests whose response has this
+	// HTTP status, e.g. "503", or a status in this class, e.g. "5xx".
+	ResponseStatus string
+
+	// GrpcStatuses, if set, only reports gRPC requests whose response ends
+	// with one of these statuses, each a name like "NOT_FOUND" or a code.
+	GrpcStatuses []string
+
+	// SampleRate, if set, reports each request with this probability, between
+	// 0 and 1. PathSampleRates override it for the paths that match a regex;
+	// each is a regex and a rate, e.g. "/healthz=0".
+	SampleRate      float32
+	PathSampleRates []string
 }
 
 // GRPCError generates a gRPC error code, as defined in
@@ -106,13 +165,17 @@ func GRPCError(err error) error {
 }
 
 func BuildStatSummaryRequest(p StatSummaryRequestParams) (*pb.StatSummaryRequest, error) {
-	window := defaultMetricTimeWindow
-	if p.TimeWindow != "" {
-		_, err := time.ParseDuration(p.TimeWindow)
+	window, err := NormalizeTimeWindow(p.TimeWindow)
+	if err != nil {
+		return nil, err
+	}
+	var additionalWindows []string
+	for _, additional := range p.AdditionalTimeWindows {
+		additionalWindow, err := NormalizeTimeWindow(additional)
 		if err != nil {
 			return nil, err
 		}
-		window = p.TimeWindow
+		additionalWindows = append(additionalWindows, additionalWindow)
 	}
 
 	if p.AllNamespaces && p.ResourceName != "" {
@@ -139,7 +202,23 @@ func BuildStatSummaryRequest(p StatSummaryRequestParams) (*pb.StatSummaryRequest
 				Type:      resourceType,
 			},
 		},
-		TimeWindow: window,
+		TimeWindow:            window,
+		AdditionalTimeWindows: additionalWindows,
+	}
+
+	for _, additional := range p.AdditionalResources {
+		resource, err := BuildResource(targetNamespace, additional)
+		if err != nil {
+			return nil, err
+		}
+		if p.AllNamespaces && resource.Name != "" {
+			return nil, errors.New("stats for a resource cannot be retrieved by name across all namespaces")
+		}
+		statRequest.AdditionalResources = append(statRequest.AdditionalResources, &resource)
+	}
+
+	if p.GroupByLabel != "" {
+		statRequest.GroupByLabel = k8s.MetricLabelName(p.GroupByLabel)
 	}
 
 	if p.ToName != "" || p.ToType != "" || p.ToNamespace != "" {
@@ -191,18 +270,58 @@ func BuildStatSummaryRequest(p StatSummaryRequestParams) (*pb.StatSummaryRequest
 	return statRequest, nil
 }
 
-// An authority can only receive traffic, not send it, so it can't be a --from
+// An authority or an ingress can only receive traffic, not send it, so it
+// can't be a --from
 func validateFromResourceType(resourceType string) (string, error) {
 	name, err := k8s.CanonicalResourceNameFromFriendlyName(resourceType)
 	if err != nil {
 		return "", err
 	}
-	if name == k8s.Authority {
+	switch name {
+	case k8s.Authority:
 		return "", errors.New("cannot query traffic --from an authority")
+	case k8s.Ingress:
+		return "", errors.New("cannot query traffic --from an ingress")
 	}
 	return name, nil
 }
 
+// NormalizeTimeWindow returns window, a duration such as "1m30s", in the
+// form that Prometheus accepts in range queries, which have a single unit,
+// such as "90s". Windows that already have a single unit are returned as they
+// are, and an empty window is the default window.
+func NormalizeTimeWindow(window string) (string, error) {
+	if window == "" {
+		return defaultMetricTimeWindow, nil
+	}
+
+	d, err := time.ParseDuration(window)
+	if err != nil {
+		return "", err
+	}
+	if d <= 0 {
+		return "", fmt.Errorf("time window must be greater than 0: %s", window)
+	}
+	if promDurationRegex.MatchString(window) {
+		return window, nil
+	}
+
+	for _, unit := range []struct {
+		duration time.Duration
+		suffix   string
+	}{
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+		{time.Millisecond, "ms"},
+	} {
+		if d%unit.duration == 0 {
+			return fmt.Sprintf("%d%s", d/unit.duration, unit.suffix), nil
+		}
+	}
+	return "", fmt.Errorf("time window must be a whole number of milliseconds: %s", window)
+}
+
 // BuildResource parses input strings, typically from CLI flags, to build a
 // Resource object for use in the protobuf API.
 func BuildResource(namespace string, args ...string) (pb.Resource, error) {
@@ -229,6 +348,35 @@ func BuildResource(namespace string, args ...string) (pb.Resource, error) {
 	}
 }
 
+// BuildResources is like BuildResource, but also accepts a comma-separated
+// list of resources as its only argument, e.g. "deploy/web,deploy/api" or
+// "deploy,rc".
+func BuildResources(namespace string, args ...string) ([]pb.Resource, error) {
+	if len(args) != 1 || !strings.Contains(args[0], ",") {
+		resource, err := BuildResource(namespace, args...)
+		if err != nil {
+			return nil, err
+		}
+		return []pb.Resource{resource}, nil
+	}
+
+	var resources []pb.Resource
+	for _, arg := range strings.Split(args[0], ",") {
+		if arg == "" {
+			return nil, errors.New("Invalid resource string: " + args[0])
+		}
+		resource, err := BuildResource(namespace, arg)
+		if err != nil {
+			return nil, err
+		}
+		if resource.Type == k8s.All {
+			return nil, errors.New("resource type 'all' cannot be combined with other resources")
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
 func buildResource(namespace string, resType string, name string) (pb.Resource, error) {
 	canonicalType, err := k8s.CanonicalResourceNameFromFriendlyName(resType)
 	if err != nil {
@@ -246,6 +394,70 @@ func buildResource(namespace string, resType string, name string) (pb.Resource,
 	}, nil
 }
 
+// CompileTapPathRegex compiles a tap path filter. The expression is anchored
+// at the start of the path, so a plain path still matches as a prefix.
+func CompileTapPathRegex(path string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + path + ")")
+}
+
+// CompileTapAuthorityRegex compiles a tap authority filter. The expression is
+// anchored at both ends, so a plain authority still matches exactly.
+func CompileTapAuthorityRegex(authority string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + authority + ")$")
+}
+
+// TapAPIGroup returns the API group of the tap APIService of the control
+// plane in controlPlaneNamespace. Each control plane has its own group, so
+// that several can be installed in the same cluster.
+func TapAPIGroup(controlPlaneNamespace string) string {
+	return controlPlaneNamespace + ".tap.linkerd.io"
+}
+
+func tapAPIPrefix(controlPlaneNamespace string) string {
+	return fmt.Sprintf("/apis/%s/%s/watch/", TapAPIGroup(controlPlaneNamespace), TapAPIVersion)
+}
+
+// TapAPIPath returns the path of the tap APIService endpoint that taps
+// target, e.g. /apis/linkerd.tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/deployments/web/tap.
+// The name is left out to tap all of the resources of a type in a namespace,
+// and namespaces are tapped at /apis/linkerd.tap.linkerd.io/v1alpha1/watch/namespaces/NAME/tap.
+func TapAPIPath(controlPlaneNamespace string, target *pb.Resource) string {
+	prefix := tapAPIPrefix(controlPlaneNamespace)
+	if target.Type == k8s.Namespace {
+		return prefix + path.Join("namespaces", target.Name, TapAPISubresource)
+	}
+	return prefix + path.Join("namespaces", target.Namespace, k8s.PluralResourceName(target.Type), target.Name, TapAPISubresource)
+}
+
+// ParseTapAPIPath returns the target of a tap APIService path built by
+// TapAPIPath.
+func ParseTapAPIPath(controlPlaneNamespace, apiPath string) (*pb.Resource, error) {
+	prefix := tapAPIPrefix(controlPlaneNamespace)
+	parts := strings.Split(strings.TrimPrefix(apiPath, prefix), "/")
+	if !strings.HasPrefix(apiPath, prefix) || parts[0] != "namespaces" || parts[len(parts)-1] != TapAPISubresource {
+		return nil, fmt.Errorf("invalid tap path: %s", apiPath)
+	}
+
+	switch len(parts) {
+	case 2:
+		return &pb.Resource{Type: k8s.Namespace}, nil
+	case 3:
+		return &pb.Resource{Type: k8s.Namespace, Name: parts[1]}, nil
+	case 4, 5:
+		resourceType, err := k8s.CanonicalResourceNameFromFriendlyName(parts[2])
+		if err != nil || k8s.PluralResourceName(resourceType) != parts[2] {
+			return nil, fmt.Errorf("invalid resource type in tap path: %s", apiPath)
+		}
+		target := &pb.Resource{Namespace: parts[1], Type: resourceType}
+		if len(parts) == 5 {
+			target.Name = parts[3]
+		}
+		return target, nil
+	default:
+		return nil, fmt.Errorf("invalid tap path: %s", apiPath)
+	}
+}
+
 func BuildTapByResourceRequest(params TapRequestParams) (*pb.TapByResourceRequest, error) {
 	target, err := BuildResource(params.Namespace, params.Resource)
 	if err != nil {
@@ -276,6 +488,29 @@ func BuildTapByResourceRequest(params TapRequestParams) (*pb.TapByResourceReques
 		matches = append(matches, &match)
 	}
 
+	if params.FromResource != "" {
+		fromNamespace := params.FromNamespace
+		if fromNamespace == "" {
+			fromNamespace = params.Namespace
+		}
+		source, err := BuildResource(fromNamespace, params.FromResource)
+		if err != nil {
+			return nil, fmt.Errorf("source resource invalid: %s", err)
+		}
+		if !contains(ValidDestinations, source.Type) {
+			return nil, fmt.Errorf("unsupported resource type [%s]", source.Type)
+		}
+
+		match := pb.TapByResourceRequest_Match{
+			Match: &pb.TapByResourceRequest_Match_Sources{
+				Sources: &pb.ResourceSelection{
+					Resource: &source,
+				},
+			},
+		}
+		matches = append(matches, &match)
+	}
+
 	if params.Scheme != "" {
 		match := buildMatchHTTP(&pb.TapByResourceRequest_Match_Http{
 			Match: &pb.TapByResourceRequest_Match_Http_Scheme{Scheme: params.Scheme},
@@ -289,19 +524,61 @@ func BuildTapByResourceRequest(params TapRequestParams) (*pb.TapByResourceReques
 		matches = append(matches, &match)
 	}
 	if params.Authority != "" {
+		if _, err := CompileTapAuthorityRegex(params.Authority); err != nil {
+			return nil, fmt.Errorf("authority is not a valid regular expression: %s", err)
+		}
 		match := buildMatchHTTP(&pb.TapByResourceRequest_Match_Http{
 			Match: &pb.TapByResourceRequest_Match_Http_Authority{Authority: params.Authority},
 		})
 		matches = append(matches, &match)
 	}
 	if params.Path != "" {
+		if _, err := CompileTapPathRegex(params.Path); err != nil {
+			return nil, fmt.Errorf("path is not a valid regular expression: %s", err)
+		}
 		match := buildMatchHTTP(&pb.TapByResourceRequest_Match_Http{
 			Match: &pb.TapByResourceRequest_Match_Http_Path{Path: params.Path},
 		})
 		matches = append(matches, &match)
 	}
 
-	return &pb.TapByResourceRequest{
+	if params.NotToResource != "" {
+		destination, err := BuildResource(params.ToNamespace, params.NotToResource)
+		if err != nil {
+			return nil, fmt.Errorf("excluded destination resource invalid: %s", err)
+		}
+		if !contains(ValidDestinations, destination.Type) {
+			return nil, fmt.Errorf("unsupported resource type [%s]", destination.Type)
+		}
+
+		match := buildMatchNot(&pb.TapByResourceRequest_Match{
+			Match: &pb.TapByResourceRequest_Match_Destinations{
+				Destinations: &pb.ResourceSelection{
+					Resource: &destination,
+				},
+			},
+		})
+		matches = append(matches, &match)
+	}
+	if params.NotMethod != "" {
+		httpMatch := buildMatchHTTP(&pb.TapByResourceRequest_Match_Http{
+			Match: &pb.TapByResourceRequest_Match_Http_Method{Method: params.NotMethod},
+		})
+		match := buildMatchNot(&httpMatch)
+		matches = append(matches, &match)
+	}
+	if params.NotPath != "" {
+		if _, err := CompileTapPathRegex(params.NotPath); err != nil {
+			return nil, fmt.Errorf("excluded path is not a valid regular expression: %s", err)
+		}
+		httpMatch := buildMatchHTTP(&pb.TapByResourceRequest_Match_Http{
+			Match: &pb.TapByResourceRequest_Match_Http_Path{Path: params.NotPath},
+		})
+		match := buildMatchNot(&httpMatch)
+		matches = append(matches, &match)
+	}
+
+	req := &pb.TapByResourceRequest{
 		Target: &pb.ResourceSelection{
 			Resource: &target,
 		},
@@ -313,7 +590,95 @@ func BuildTapByResourceRequest(params TapRequestParams) (*pb.TapByResourceReques
 				},
 			},
 		},
-	}, nil
+	}
+	if params.MinLatency > 0 {
+		req.MinLatency = ptypes.DurationProto(params.MinLatency)
+	}
+	if params.ResponseStatus != "" {
+		statusRange, err := parseStatusRange(params.ResponseStatus)
+		if err != nil {
+			return nil, err
+		}
+		req.ResponseStatus = statusRange
+	}
+	for _, s := range params.GrpcStatuses {
+		code, err := parseGrpcStatus(s)
+		if err != nil {
+			return nil, err
+		}
+		req.GrpcStatuses = append(req.GrpcStatuses, uint32(code))
+	}
+	if params.SampleRate < 0 || params.SampleRate > 1 {
+		return nil, fmt.Errorf("sample rate must be between 0 and 1, got [%v]", params.SampleRate)
+	}
+	req.SampleRate = params.SampleRate
+	for _, s := range params.PathSampleRates {
+		pathSampleRate, err := parsePathSampleRate(s)
+		if err != nil {
+			return nil, err
+		}
+		req.PathSampleRates = append(req.PathSampleRates, pathSampleRate)
+	}
+	return req, nil
+}
+
+// parsePathSampleRate parses a path regex and the rate at which to sample
+// the requests that match it, e.g. "/healthz=0". They're split at the last
+// "=", since the regex may contain one.
+func parsePathSampleRate(s string) (*pb.TapByResourceRequest_PathSampleRate, error) {
+	i := strings.LastIndex(s, "=")
+	if i < 0 {
+		return nil, fmt.Errorf("path sample rate must be a path regex and a rate like \"/healthz=0\", got [%s]", s)
+	}
+
+	path := s[:i]
+	rate, err := strconv.ParseFloat(s[i+1:], 32)
+	if err != nil || rate < 0 || rate > 1 {
+		return nil, fmt.Errorf("sample rate for path [%s] must be between 0 and 1, got [%s]", path, s[i+1:])
+	}
+	if _, err := CompileTapPathRegex(path); err != nil {
+		return nil, fmt.Errorf("sampled path is not a valid regular expression: %s", err)
+	}
+	return &pb.TapByResourceRequest_PathSampleRate{Path: path, Rate: float32(rate)}, nil
+}
+
+// parseStatusRange parses an HTTP status, e.g. "503", or a status class, e.g.
+// "5xx", into the range of statuses it matches.
+func parseStatusRange(s string) (*pb.TapByResourceRequest_StatusRange, error) {
+	invalid := fmt.Errorf("response status must be an HTTP status like \"503\" or a status class like \"5xx\", got [%s]", s)
+
+	if len(s) == 3 && strings.ToLower(s[1:]) == "xx" {
+		class, err := strconv.ParseUint(s[:1], 10, 32)
+		if err != nil || class < 1 || class > 5 {
+			return nil, invalid
+		}
+		return &pb.TapByResourceRequest_StatusRange{Min: uint32(class) * 100, Max: uint32(class)*100 + 99}, nil
+	}
+
+	code, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || code < 100 || code > 599 {
+		return nil, invalid
+	}
+	return &pb.TapByResourceRequest_StatusRange{Min: uint32(code), Max: uint32(code)}, nil
+}
+
+// parseGrpcStatus parses a gRPC status, either its name, e.g. "NOT_FOUND" or
+// "NotFound", or its code, e.g. "5".
+func parseGrpcStatus(s string) (codes.Code, error) {
+	if code, err := strconv.ParseUint(s, 10, 32); err == nil && code <= uint64(codes.Unauthenticated) {
+		return codes.Code(code), nil
+	}
+
+	name := strings.ToLower(strings.Replace(s, "_", "", -1))
+	if name == "cancelled" {
+		return codes.Canceled, nil
+	}
+	for code := codes.OK; code <= codes.Unauthenticated; code++ {
+		if name == strings.ToLower(code.String()) {
+			return code, nil
+		}
+	}
+	return 0, fmt.Errorf("gRPC status must be a status name like \"NOT_FOUND\" or a code like \"5\", got [%s]", s)
 }
 
 func buildMatchHTTP(match *pb.TapByResourceRequest_Match_Http) pb.TapByResourceRequest_Match {
@@ -324,6 +689,14 @@ func buildMatchHTTP(match *pb.TapByResourceRequest_Match_Http) pb.TapByResourceR
 	}
 }
 
+func buildMatchNot(match *pb.TapByResourceRequest_Match) pb.TapByResourceRequest_Match {
+	return pb.TapByResourceRequest_Match{
+		Match: &pb.TapByResourceRequest_Match_Not{
+			Not: match,
+		},
+	}
+}
+
 func contains(list []string, s string) bool {
 	for _, elem := range list {
 		if s == elem {
@@ -336,6 +709,8 @@ func contains(list []string, s string) bool {
 func formatPeer(peerAddr *pb.TcpAddress, labels map[string]string) string {
 	if pod := labels["pod"]; pod != "" {
 		return fmt.Sprintf("%s:%d", pod, peerAddr.GetPort())
+	} else if svc := labels["service"]; svc != "" {
+		return addr.PublicAddressToServiceString(peerAddr, svc, labels["service_port"])
 	} else {
 		return addr.PublicAddressToString(peerAddr)
 	}
@@ -380,42 +755,42 @@ func RenderTapEvent(event *pb.TapEvent) string {
 		)
 
 	case *pb.TapEvent_Http_ResponseInit_:
-		return fmt.Sprintf("rsp id=%d:%d %s :status=%d latency=%dµs",
+		return fmt.Sprintf("rsp id=%d:%d %s :status=%d latency=%s",
 			ev.ResponseInit.GetId().GetBase(),
 			ev.ResponseInit.GetId().GetStream(),
 			flow,
 			ev.ResponseInit.GetHttpStatus(),
-			ev.ResponseInit.GetSinceRequestInit().GetNanos()/1000,
+			format.Micros(toDuration(ev.ResponseInit.GetSinceRequestInit())),
 		)
 
 	case *pb.TapEvent_Http_ResponseEnd_:
 		switch eos := ev.ResponseEnd.GetEos().GetEnd().(type) {
 		case *pb.Eos_GrpcStatusCode:
-			return fmt.Sprintf("end id=%d:%d %s grpc-status=%s duration=%dµs response-length=%dB",
+			return fmt.Sprintf("end id=%d:%d %s grpc-status=%s duration=%s response-length=%dB",
 				ev.ResponseEnd.GetId().GetBase(),
 				ev.ResponseEnd.GetId().GetStream(),
 				flow,
 				codes.Code(eos.GrpcStatusCode),
-				ev.ResponseEnd.GetSinceResponseInit().GetNanos()/1000,
+				format.Micros(toDuration(ev.ResponseEnd.GetSinceResponseInit())),
 				ev.ResponseEnd.GetResponseBytes(),
 			)
 
 		case *pb.Eos_ResetErrorCode:
-			return fmt.Sprintf("end id=%d:%d %s reset-error=%+v duration=%dµs response-length=%dB",
+			return fmt.Sprintf("end id=%d:%d %s reset-error=%+v duration=%s response-length=%dB",
 				ev.ResponseEnd.GetId().GetBase(),
 				ev.ResponseEnd.GetId().GetStream(),
 				flow,
 				eos.ResetErrorCode,
-				ev.ResponseEnd.GetSinceResponseInit().GetNanos()/1000,
+				format.Micros(toDuration(ev.ResponseEnd.GetSinceResponseInit())),
 				ev.ResponseEnd.GetResponseBytes(),
 			)
 
 		default:
-			return fmt.Sprintf("end id=%d:%d %s duration=%dµs response-length=%dB",
+			return fmt.Sprintf("end id=%d:%d %s duration=%s response-length=%dB",
 				ev.ResponseEnd.GetId().GetBase(),
 				ev.ResponseEnd.GetId().GetStream(),
 				flow,
-				ev.ResponseEnd.GetSinceResponseInit().GetNanos()/1000,
+				format.Micros(toDuration(ev.ResponseEnd.GetSinceResponseInit())),
 				ev.ResponseEnd.GetResponseBytes(),
 			)
 		}
@@ -424,3 +799,178 @@ func RenderTapEvent(event *pb.TapEvent) string {
 		return fmt.Sprintf("unknown %s", flow)
 	}
 }
+
+// TapWorkload identifies the workload that a tap event's peer belongs to.
+type TapWorkload struct {
+	Deployment     string `json:"deployment,omitempty"`
+	Namespace      string `json:"namespace,omitempty"`
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+}
+
+// TapEventJSON is the JSON representation of a TapEvent, as rendered by
+// `linkerd tap -o json`.
+type TapEventJSON struct {
+	Type                string            `json:"type"`
+	ID                  string            `json:"id"`
+	ProxyDirection      string            `json:"proxyDirection"`
+	Source              string            `json:"source"`
+	SourceLabels        map[string]string `json:"sourceLabels,omitempty"`
+	Destination         string            `json:"destination"`
+	DestinationLabels   map[string]string `json:"destinationLabels,omitempty"`
+	DestinationWorkload TapWorkload       `json:"destinationWorkload"`
+	TLS                 string            `json:"tls,omitempty"`
+	PeerIdentity        string            `json:"peerIdentity,omitempty"`
+
+	Method    string `json:"method,omitempty"`
+	Authority string `json:"authority,omitempty"`
+	Path      string `json:"path,omitempty"`
+
+	HTTPStatus uint32 `json:"httpStatus,omitempty"`
+	Latency    string `json:"latency,omitempty"`
+
+	GRPCStatus     string `json:"grpcStatus,omitempty"`
+	ResetErrorCode uint32 `json:"resetErrorCode,omitempty"`
+	Duration       string `json:"duration,omitempty"`
+	ResponseBytes  uint64 `json:"responseBytes,omitempty"`
+}
+
+// GetTapWorkload returns the workload described by a tap event peer's labels.
+func GetTapWorkload(labels map[string]string) TapWorkload {
+	return TapWorkload{
+		Deployment:     labels["deployment"],
+		Namespace:      labels["namespace"],
+		ServiceAccount: labels["serviceaccount"],
+	}
+}
+
+// tapOwnerLabels are the labels that k8s.GetPodLabels names after a pod's
+// owner, along with the kind of owner in the pod's TLS identity.
+var tapOwnerLabels = []struct{ label, kind string }{
+	{"deployment", "deployment"},
+	{"replicationcontroller", "replicationcontroller"},
+	{"replicaset", "replicaset"},
+	{"daemonset", "daemonset"},
+	{"statefulset", "statefulset"},
+	{"k8s_job", "job"},
+}
+
+// GetTapPeerIdentity returns the TLS identity of the peer of the proxy that
+// reported a tap event: its source for inbound events, and its destination
+// for outbound events. It's empty unless the connection to the peer was
+// secured by TLS and the peer's owner is known from its labels.
+func GetTapPeerIdentity(event *pb.TapEvent) string {
+	var labels map[string]string
+	switch event.GetProxyDirection() {
+	case pb.TapEvent_INBOUND:
+		labels = event.GetSourceMeta().GetLabels()
+	case pb.TapEvent_OUTBOUND:
+		labels = event.GetDestinationMeta().GetLabels()
+	}
+	if labels["tls"] != "true" || labels["namespace"] == "" || labels["control_plane_ns"] == "" {
+		return ""
+	}
+
+	identity := k8s.TLSIdentity{
+		Namespace:           labels["namespace"],
+		ControllerNamespace: labels["control_plane_ns"],
+	}
+	for _, owner := range tapOwnerLabels {
+		if name := labels[owner.label]; name != "" {
+			identity.Name = name
+			identity.Kind = owner.kind
+			break
+		}
+	}
+	// pods without an owner are their own owner
+	if identity.Name == "" {
+		if identity.Name = labels["pod"]; identity.Name == "" {
+			return ""
+		}
+		identity.Kind = k8s.Pod
+	}
+	return identity.ToDNSName()
+}
+
+// RenderTapEventWide renders a tap event like RenderTapEvent, followed by the
+// destination's workload metadata and the peer's TLS identity.
+func RenderTapEventWide(event *pb.TapEvent) string {
+	workload := GetTapWorkload(event.GetDestinationMeta().GetLabels())
+	return fmt.Sprintf("%s dst_deployment=%s dst_namespace=%s dst_serviceaccount=%s peer_identity=%s",
+		RenderTapEvent(event),
+		workload.Deployment,
+		workload.Namespace,
+		workload.ServiceAccount,
+		GetTapPeerIdentity(event),
+	)
+}
+
+// NewTapEventJSON converts a tap event to its JSON representation.
+func NewTapEventJSON(event *pb.TapEvent) TapEventJSON {
+	srcLabels := event.GetSourceMeta().GetLabels()
+	dstLabels := event.GetDestinationMeta().GetLabels()
+
+	ev := TapEventJSON{
+		ProxyDirection:      strings.ToLower(event.GetProxyDirection().String()),
+		Source:              formatPeer(event.GetSource(), srcLabels),
+		SourceLabels:        srcLabels,
+		Destination:         formatPeer(event.GetDestination(), dstLabels),
+		DestinationLabels:   dstLabels,
+		DestinationWorkload: GetTapWorkload(dstLabels),
+		PeerIdentity:        GetTapPeerIdentity(event),
+	}
+	switch event.GetProxyDirection() {
+	case pb.TapEvent_INBOUND:
+		ev.TLS = srcLabels["tls"]
+	case pb.TapEvent_OUTBOUND:
+		ev.TLS = dstLabels["tls"]
+	}
+
+	formatID := func(id *pb.TapEvent_Http_StreamId) string {
+		return fmt.Sprintf("%d:%d", id.GetBase(), id.GetStream())
+	}
+
+	switch http := event.GetHttp().GetEvent().(type) {
+	case *pb.TapEvent_Http_RequestInit_:
+		ev.Type = "req"
+		ev.ID = formatID(http.RequestInit.GetId())
+		ev.Method = http.RequestInit.GetMethod().GetRegistered().String()
+		ev.Authority = http.RequestInit.GetAuthority()
+		ev.Path = http.RequestInit.GetPath()
+
+	case *pb.TapEvent_Http_ResponseInit_:
+		ev.Type = "rsp"
+		ev.ID = formatID(http.ResponseInit.GetId())
+		ev.HTTPStatus = http.ResponseInit.GetHttpStatus()
+		ev.Latency = format.Micros(toDuration(http.ResponseInit.GetSinceRequestInit()))
+
+	case *pb.TapEvent_Http_ResponseEnd_:
+		ev.Type = "end"
+		ev.ID = formatID(http.ResponseEnd.GetId())
+		ev.Duration = format.Micros(toDuration(http.ResponseEnd.GetSinceResponseInit()))
+		ev.ResponseBytes = http.ResponseEnd.GetResponseBytes()
+		switch eos := http.ResponseEnd.GetEos().GetEnd().(type) {
+		case *pb.Eos_GrpcStatusCode:
+			ev.GRPCStatus = codes.Code(eos.GrpcStatusCode).String()
+		case *pb.Eos_ResetErrorCode:
+			ev.ResetErrorCode = eos.ResetErrorCode
+		}
+
+	default:
+		ev.Type = "unknown"
+	}
+
+	return ev
+}
+
+// toDuration converts a protobuf duration to a time.Duration, treating missing
+// or invalid durations as zero.
+func toDuration(d *duration.Duration) time.Duration {
+	if d == nil {
+		return 0
+	}
+	converted, err := ptypes.Duration(d)
+	if err != nil {
+		return 0
+	}
+	return converted
+}
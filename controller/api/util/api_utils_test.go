@@ -4,6 +4,7 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/k8s"
@@ -63,6 +64,42 @@ func TestBuildStatSummaryRequest(t *testing.T) {
 		}
 	})
 
+	t.Run("Maps the label to group by to its Prometheus name", func(t *testing.T) {
+		statSummaryRequest, err := BuildStatSummaryRequest(
+			StatSummaryRequestParams{
+				ResourceType: "deploy",
+				GroupByLabel: "cost-center",
+			},
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error from BuildStatSummaryRequest: %s", err)
+		}
+		if statSummaryRequest.GroupByLabel != "cost_center" {
+			t.Fatalf("Expected to group by [cost_center], got [%s]", statSummaryRequest.GroupByLabel)
+		}
+	})
+
+	t.Run("Selects additional resources in the target namespace", func(t *testing.T) {
+		statSummaryRequest, err := BuildStatSummaryRequest(
+			StatSummaryRequestParams{
+				Namespace:           "emojivoto",
+				ResourceType:        "deploy",
+				ResourceName:        "web",
+				AdditionalResources: []string{"deploy/voting", "rc"},
+			},
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error from BuildStatSummaryRequest: %s", err)
+		}
+		expected := []*pb.Resource{
+			{Namespace: "emojivoto", Type: k8s.Deployment, Name: "voting"},
+			{Namespace: "emojivoto", Type: k8s.ReplicationController},
+		}
+		if !reflect.DeepEqual(statSummaryRequest.AdditionalResources, expected) {
+			t.Fatalf("Expected additional resources %+v, got %+v", expected, statSummaryRequest.AdditionalResources)
+		}
+	})
+
 	t.Run("Parses valid time windows", func(t *testing.T) {
 		expectations := []string{
 			"1m",
@@ -107,6 +144,46 @@ func TestBuildStatSummaryRequest(t *testing.T) {
 		}
 	})
 
+	t.Run("Normalizes time windows for Prometheus", func(t *testing.T) {
+		statSummaryRequest, err := BuildStatSummaryRequest(
+			StatSummaryRequestParams{
+				TimeWindow:            "1m30s",
+				AdditionalTimeWindows: []string{"10m", "1h0m0s", "1.5s"},
+				ResourceType:          k8s.Deployment,
+			},
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if statSummaryRequest.TimeWindow != "90s" {
+			t.Fatalf("Expected TimeWindow [90s], got [%s]", statSummaryRequest.TimeWindow)
+		}
+		expected := []string{"10m", "1h", "1500ms"}
+		if !reflect.DeepEqual(statSummaryRequest.AdditionalTimeWindows, expected) {
+			t.Fatalf("Expected additional time windows %v, got %v", expected, statSummaryRequest.AdditionalTimeWindows)
+		}
+	})
+
+	t.Run("Rejects invalid additional time windows", func(t *testing.T) {
+		expectations := map[string]string{
+			"0s":   "time window must be greater than 0: 0s",
+			"1us":  "time window must be a whole number of milliseconds: 1us",
+			"abcd": "time: invalid duration abcd",
+		}
+
+		for timeWindow, msg := range expectations {
+			_, err := BuildStatSummaryRequest(
+				StatSummaryRequestParams{
+					AdditionalTimeWindows: []string{timeWindow},
+					ResourceType:          k8s.Deployment,
+				},
+			)
+			if err == nil || err.Error() != msg {
+				t.Fatalf("BuildStatSummaryRequest(%s) should have returned: %s but got: %v", timeWindow, msg, err)
+			}
+		}
+	})
+
 	t.Run("Rejects invalid Kubernetes resource types", func(t *testing.T) {
 		expectations := map[string]string{
 			"foo": "cannot find Kubernetes canonical name from friendly name [foo]",
@@ -197,3 +274,259 @@ func TestBuildResource(t *testing.T) {
 		}
 	})
 }
+
+func TestTapAPIPath(t *testing.T) {
+	t.Run("Parses the paths that it builds", func(t *testing.T) {
+		expectations := map[string]pb.Resource{
+			"/apis/linkerd.tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/deployments/web/tap": {Namespace: "emojivoto", Type: k8s.Deployment, Name: "web"},
+			"/apis/linkerd.tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/authorities/tap":     {Namespace: "emojivoto", Type: k8s.Authority},
+			"/apis/linkerd.tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/tap":                 {Type: k8s.Namespace, Name: "emojivoto"},
+			"/apis/linkerd.tap.linkerd.io/v1alpha1/watch/namespaces/tap":                           {Type: k8s.Namespace},
+		}
+
+		for expectedPath, target := range expectations {
+			target := target
+			if path := TapAPIPath("linkerd", &target); path != expectedPath {
+				t.Fatalf("Expected path [%s] for %+v, got [%s]", expectedPath, target, path)
+			}
+
+			parsed, err := ParseTapAPIPath("linkerd", expectedPath)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(*parsed, target) {
+				t.Fatalf("Expected path [%s] to parse to %+v, got %+v", expectedPath, target, *parsed)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid paths", func(t *testing.T) {
+		invalid := []string{
+			"/apis/linkerd.tap.linkerd.io/v1alpha1/namespaces/emojivoto/deployments/web/tap",
+			"/apis/linkerd.tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/deployments/web",
+			"/apis/linkerd.tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/deploy/web/tap",
+			"/apis/linkerd.tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/deployments/web/extra/tap",
+			"/apis/other.tap.linkerd.io/v1alpha1/watch/namespaces/emojivoto/deployments/web/tap",
+		}
+
+		for _, path := range invalid {
+			if target, err := ParseTapAPIPath("linkerd", path); err == nil {
+				t.Fatalf("Expected an error for path [%s], got %+v", path, target)
+			}
+		}
+	})
+}
+
+func TestBuildTapByResourceRequest(t *testing.T) {
+	t.Run("Accepts regular expressions for path and authority", func(t *testing.T) {
+		_, err := BuildTapByResourceRequest(TapRequestParams{
+			Resource:  "deploy/web",
+			Namespace: "emojivoto",
+			Authority: "web-svc\\.emojivoto(:80)?",
+			Path:      "/books/[0-9]+",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Wraps exclusion filters in negative matches", func(t *testing.T) {
+		req, err := BuildTapByResourceRequest(TapRequestParams{
+			Resource:      "deploy/web",
+			Namespace:     "emojivoto",
+			ToNamespace:   "emojivoto",
+			NotToResource: "deploy/voting",
+			NotMethod:     "GET",
+			NotPath:       "/healthz",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		matches := req.GetMatch().GetAll().GetMatches()
+		if len(matches) != 3 {
+			t.Fatalf("Expected 3 matches, got %d: %+v", len(matches), matches)
+		}
+		if dst := matches[0].GetNot().GetDestinations().GetResource(); dst.GetName() != "voting" || dst.GetNamespace() != "emojivoto" {
+			t.Fatalf("Expected negated destination [emojivoto/voting], got %+v", matches[0])
+		}
+		if method := matches[1].GetNot().GetHttp().GetMethod(); method != "GET" {
+			t.Fatalf("Expected negated method [GET], got %+v", matches[1])
+		}
+		if path := matches[2].GetNot().GetHttp().GetPath(); path != "/healthz" {
+			t.Fatalf("Expected negated path [/healthz], got %+v", matches[2])
+		}
+	})
+
+	t.Run("Looks up the source in the target namespace by default", func(t *testing.T) {
+		req, err := BuildTapByResourceRequest(TapRequestParams{
+			Resource:     "deploy/web",
+			Namespace:    "emojivoto",
+			FromResource: "svc/gateway",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		matches := req.GetMatch().GetAll().GetMatches()
+		if len(matches) != 1 {
+			t.Fatalf("Expected 1 match, got %d: %+v", len(matches), matches)
+		}
+		if src := matches[0].GetSources().GetResource(); src.GetType() != k8s.Service || src.GetName() != "gateway" || src.GetNamespace() != "emojivoto" {
+			t.Fatalf("Expected source [emojivoto/service/gateway], got %+v", matches[0])
+		}
+	})
+
+	t.Run("Sets the minimum latency only when given", func(t *testing.T) {
+		req, err := BuildTapByResourceRequest(TapRequestParams{Resource: "deploy/web"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if req.MinLatency != nil {
+			t.Fatalf("Expected no minimum latency, got %+v", req.MinLatency)
+		}
+
+		req, err = BuildTapByResourceRequest(TapRequestParams{Resource: "deploy/web", MinLatency: 250 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if minLatency := toDuration(req.MinLatency); minLatency != 250*time.Millisecond {
+			t.Fatalf("Expected a minimum latency of 250ms, got %s", minLatency)
+		}
+	})
+
+	t.Run("Parses response statuses and status classes", func(t *testing.T) {
+		testCases := []struct {
+			status   string
+			min, max uint32
+		}{
+			{"503", 503, 503},
+			{"5xx", 500, 599},
+			{"2XX", 200, 299},
+		}
+
+		for _, tc := range testCases {
+			req, err := BuildTapByResourceRequest(TapRequestParams{Resource: "deploy/web", ResponseStatus: tc.status})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if statusRange := req.ResponseStatus; statusRange.Min != tc.min || statusRange.Max != tc.max {
+				t.Fatalf("Expected status [%s] to match %d-%d, got %+v", tc.status, tc.min, tc.max, statusRange)
+			}
+		}
+
+		for _, status := range []string{"600", "99", "6xx", "0xx", "5x", "abc"} {
+			if _, err := BuildTapByResourceRequest(TapRequestParams{Resource: "deploy/web", ResponseStatus: status}); err == nil {
+				t.Fatalf("Expected error for response status [%s], got nil", status)
+			}
+		}
+	})
+
+	t.Run("Parses gRPC statuses", func(t *testing.T) {
+		req, err := BuildTapByResourceRequest(TapRequestParams{
+			Resource:     "deploy/web",
+			GrpcStatuses: []string{"NOT_FOUND", "Unavailable", "cancelled", "16"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := []uint32{uint32(codes.NotFound), uint32(codes.Unavailable), uint32(codes.Canceled), uint32(codes.Unauthenticated)}
+		if !reflect.DeepEqual(req.GrpcStatuses, expected) {
+			t.Fatalf("Expected gRPC statuses %v, got %v", expected, req.GrpcStatuses)
+		}
+
+		for _, status := range []string{"17", "-1", "NOT_A_STATUS", ""} {
+			if _, err := BuildTapByResourceRequest(TapRequestParams{Resource: "deploy/web", GrpcStatuses: []string{status}}); err == nil {
+				t.Fatalf("Expected error for gRPC status [%s], got nil", status)
+			}
+		}
+	})
+
+	t.Run("Parses path sample rates", func(t *testing.T) {
+		req, err := BuildTapByResourceRequest(TapRequestParams{
+			Resource:        "deploy/web",
+			SampleRate:      0.01,
+			PathSampleRates: []string{"/healthz=0", "/search\\?q=.*=0.5"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := []*pb.TapByResourceRequest_PathSampleRate{
+			{Path: "/healthz", Rate: 0},
+			{Path: "/search\\?q=.*", Rate: 0.5},
+		}
+		if req.SampleRate != 0.01 || !reflect.DeepEqual(req.PathSampleRates, expected) {
+			t.Fatalf("Expected sample rate 0.01 and path sample rates %v, got %v and %v", expected, req.SampleRate, req.PathSampleRates)
+		}
+
+		invalid := []TapRequestParams{
+			{Resource: "deploy/web", SampleRate: 1.5},
+			{Resource: "deploy/web", PathSampleRates: []string{"/healthz"}},
+			{Resource: "deploy/web", PathSampleRates: []string{"/healthz=2"}},
+			{Resource: "deploy/web", PathSampleRates: []string{"/books/[0-9=0.5"}},
+		}
+		for _, params := range invalid {
+			if _, err := BuildTapByResourceRequest(params); err == nil {
+				t.Fatalf("Expected error for params %+v, got nil", params)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid regular expressions", func(t *testing.T) {
+		invalid := []TapRequestParams{
+			{Resource: "deploy/web", Authority: "web-svc(:80"},
+			{Resource: "deploy/web", Path: "/books/[0-9"},
+			{Resource: "deploy/web", NotPath: "/books/[0-9"},
+		}
+
+		for _, params := range invalid {
+			if _, err := BuildTapByResourceRequest(params); err == nil {
+				t.Fatalf("Expected error for params %+v, got nil", params)
+			}
+		}
+	})
+}
+
+func TestBuildResources(t *testing.T) {
+	t.Run("Parses comma-separated resources", func(t *testing.T) {
+		expectations := map[string][]pb.Resource{
+			"deploy/web,deploy/api": {
+				{Namespace: "test-ns", Type: k8s.Deployment, Name: "web"},
+				{Namespace: "test-ns", Type: k8s.Deployment, Name: "api"},
+			},
+			"deploy,rc": {
+				{Namespace: "test-ns", Type: k8s.Deployment},
+				{Namespace: "test-ns", Type: k8s.ReplicationController},
+			},
+			"deploy/web": {
+				{Namespace: "test-ns", Type: k8s.Deployment, Name: "web"},
+			},
+		}
+
+		for arg, expected := range expectations {
+			resources, err := BuildResources("test-ns", arg)
+			if err != nil {
+				t.Fatalf("Unexpected error from BuildResources(%s) => %s", arg, err)
+			}
+			if !reflect.DeepEqual(resources, expected) {
+				t.Fatalf("Expected BuildResources(%s) to be %+v but was %+v", arg, expected, resources)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid lists of resources", func(t *testing.T) {
+		expectations := map[string]string{
+			"deploy,":        "Invalid resource string: deploy,",
+			"deploy,all":     "resource type 'all' cannot be combined with other resources",
+			"deploy,foo/bar": "cannot find Kubernetes canonical name from friendly name [foo]",
+		}
+
+		for arg, msg := range expectations {
+			_, err := BuildResources("test-ns", arg)
+			if err == nil || err.Error() != msg {
+				t.Fatalf("Expected BuildResources(%s) to return [%s], got [%v]", arg, msg, err)
+			}
+		}
+	})
+}
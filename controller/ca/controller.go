@@ -3,6 +3,7 @@ package ca
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/linkerd/linkerd2/controller/k8s"
@@ -11,17 +12,38 @@ import (
 	"k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 )
 
+const (
+	// eventComponent is the source of the events that the controller records.
+	eventComponent = "linkerd-ca"
+
+	// IssueCertificateFailedReason is the reason for events recorded on a
+	// workload when its certificate can't be issued.
+	IssueCertificateFailedReason = "IssueCertificateFailed"
+)
+
 type CertificateController struct {
 	namespace   string
 	k8sAPI      *k8s.API
 	ca          *CA
 	syncHandler func(key string) error
+	recorder    record.EventRecorder
+
+	// owners maps secret sync keys to the workload that the secret is for, so
+	// that failures can be recorded as events on it. podKeys maps meshed pods
+	// to their key, so that a workload is forgotten with its last pod.
+	ownersMu sync.RWMutex
+	owners   map[string]*podOwner
+	podKeys  map[types.UID]string
 
 	// The queue is keyed on a string. If the string doesn't contain any dots
 	// then it is a namespace name and the task is to create the CA bundle
@@ -31,16 +53,31 @@ type CertificateController struct {
 	queue workqueue.RateLimitingInterface
 }
 
+// podOwner is the workload that a secret is for, and how many of its meshed
+// pods there are.
+type podOwner struct {
+	ref  *v1.ObjectReference
+	pods int
+}
+
 func NewCertificateController(controllerNamespace string, k8sAPI *k8s.API) (*CertificateController, error) {
 	ca, err := NewCA()
 	if err != nil {
 		return nil, err
 	}
 
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{
+		Interface: k8sAPI.Client.CoreV1().Events(""),
+	})
+
 	c := &CertificateController{
 		namespace: controllerNamespace,
 		k8sAPI:    k8sAPI,
 		ca:        ca,
+		recorder:  broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: eventComponent}),
+		owners:    make(map[string]*podOwner),
+		podKeys:   make(map[types.UID]string),
 		queue: workqueue.NewNamedRateLimitingQueue(
 			workqueue.DefaultControllerRateLimiter(), "certificates"),
 	}
@@ -49,6 +86,7 @@ func NewCertificateController(controllerNamespace string, k8sAPI *k8s.API) (*Cer
 		cache.ResourceEventHandlerFuncs{
 			AddFunc:    c.handlePodAdd,
 			UpdateFunc: c.handlePodUpdate,
+			DeleteFunc: c.handlePodDelete,
 		},
 	)
 
@@ -139,6 +177,7 @@ func (c *CertificateController) syncSecret(key string) error {
 	certAndPrivateKey, err := c.ca.IssueEndEntityCertificate(dnsName)
 	if err != nil {
 		log.Errorf("Failed to issue certificate for %s", dnsName)
+		c.recordFailure(key, "Failed to issue certificate for %s: %s", dnsName, err)
 		return err
 	}
 	secret := &v1.Secret{
@@ -152,23 +191,97 @@ func (c *CertificateController) syncSecret(key string) error {
 	if apierrors.IsAlreadyExists(err) {
 		_, err = c.k8sAPI.Client.CoreV1().Secrets(identity.Namespace).Update(secret)
 	}
+	if err != nil {
+		c.recordFailure(key, "Failed to write secret %s for certificate %s: %s", secretName, dnsName, err)
+	}
 
 	return err
 }
 
+// recordFailure records a warning event on the workload that the secret sync
+// key is for, so that it's shown by `kubectl describe`.
+func (c *CertificateController) recordFailure(key string, format string, args ...interface{}) {
+	c.ownersMu.RLock()
+	owner, ok := c.owners[key]
+	c.ownersMu.RUnlock()
+	if !ok {
+		return
+	}
+
+	c.recorder.Eventf(owner.ref, v1.EventTypeWarning, IssueCertificateFailedReason, format, args...)
+}
+
 func (c *CertificateController) handlePodAdd(obj interface{}) {
 	pod := obj.(*v1.Pod)
 	if pkgK8s.IsMeshed(pod, c.namespace) {
 		log.Debugf("enqueuing update of CA bundle configmap in %s", pod.Namespace)
 		c.queue.Add(pod.Namespace)
 
-		ownerKind, ownerName := c.k8sAPI.GetOwnerKindAndName(pod)
-		item := fmt.Sprintf("%s.%s.%s", ownerName, ownerKind, pod.Namespace)
+		owner := c.k8sAPI.GetOwnerReference(pod)
+		item := fmt.Sprintf("%s.%s.%s", owner.Name, strings.ToLower(owner.Kind), pod.Namespace)
+		c.trackOwner(pod, item, owner)
+
 		log.Debugf("enqueuing secret write for %s", item)
 		c.queue.Add(item)
+	} else {
+		c.forgetOwner(pod)
 	}
 }
 
 func (c *CertificateController) handlePodUpdate(oldObj, newObj interface{}) {
 	c.handlePodAdd(newObj)
 }
+
+func (c *CertificateController) handlePodDelete(obj interface{}) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		if pod, ok = tombstone.Obj.(*v1.Pod); !ok {
+			return
+		}
+	}
+	c.forgetOwner(pod)
+}
+
+// trackOwner records that pod's secret sync key is item, for the workload
+// owner.
+func (c *CertificateController) trackOwner(pod *v1.Pod, item string, owner *v1.ObjectReference) {
+	c.ownersMu.Lock()
+	defer c.ownersMu.Unlock()
+
+	if key, ok := c.podKeys[pod.UID]; ok {
+		if key == item {
+			c.owners[item].ref = owner
+			return
+		}
+		c.releaseOwnerLocked(key)
+	}
+	c.podKeys[pod.UID] = item
+	if _, ok := c.owners[item]; !ok {
+		c.owners[item] = &podOwner{}
+	}
+	c.owners[item].ref = owner
+	c.owners[item].pods++
+}
+
+// forgetOwner forgets the workload of pod once none of its pods are left.
+func (c *CertificateController) forgetOwner(pod *v1.Pod) {
+	c.ownersMu.Lock()
+	defer c.ownersMu.Unlock()
+
+	if key, ok := c.podKeys[pod.UID]; ok {
+		delete(c.podKeys, pod.UID)
+		c.releaseOwnerLocked(key)
+	}
+}
+
+func (c *CertificateController) releaseOwnerLocked(key string) {
+	if owner, ok := c.owners[key]; ok {
+		if owner.pods--; owner.pods <= 0 {
+			delete(c.owners, key)
+		}
+	}
+}
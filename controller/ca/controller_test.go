@@ -2,6 +2,7 @@ package ca
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -9,7 +10,12 @@ import (
 	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
 	"k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 )
 
 var (
@@ -61,6 +67,96 @@ func TestCertificateController(t *testing.T) {
 	})
 }
 
+func TestCertificateControllerEvents(t *testing.T) {
+	t.Run("records an event on the workload when the secret can't be written", func(t *testing.T) {
+		controller, _, stopCh, err := new(injectedNSConfig)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		defer close(stopCh)
+
+		recorder := record.NewFakeRecorder(10)
+		controller.recorder = recorder
+		controller.k8sAPI.Client.(*fake.Clientset).PrependReactor("create", "secrets",
+			func(action k8sTesting.Action) (bool, runtime.Object, error) {
+				return true, nil, fmt.Errorf("secrets are forbidden")
+			})
+
+		controller.handlePodAdd(&v1.Pod{
+			ObjectMeta: meta.ObjectMeta{
+				Name:      injectedPodName,
+				Namespace: injectedNS,
+				Labels: map[string]string{
+					pkgK8s.ControllerNSLabel: controllerNS,
+				},
+				OwnerReferences: []meta.OwnerReference{
+					{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "injected-ds", UID: "ds-uid"},
+				},
+			},
+		})
+
+		key := fmt.Sprintf("injected-ds.daemonset.%s", injectedNS)
+		if err := controller.syncSecret(key); err == nil {
+			t.Fatal("expected syncSecret to return an error")
+		}
+
+		select {
+		case event := <-recorder.Events:
+			if !strings.HasPrefix(event, "Warning "+IssueCertificateFailedReason+" ") {
+				t.Fatalf("expected a %s warning, got [%s]", IssueCertificateFailedReason, event)
+			}
+		default:
+			t.Fatal("expected an event to be recorded")
+		}
+
+		owner := controller.owners[key].ref
+		if owner.Kind != "DaemonSet" || owner.Name != "injected-ds" || owner.UID != "ds-uid" {
+			t.Fatalf("expected the event to be recorded on the daemonset, got %+v", owner)
+		}
+	})
+}
+
+func TestCertificateControllerOwners(t *testing.T) {
+	t.Run("forgets a workload once its last pod is deleted", func(t *testing.T) {
+		controller, _, stopCh, err := new(injectedNSConfig)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		defer close(stopCh)
+
+		pod := func(name string) *v1.Pod {
+			return &v1.Pod{
+				ObjectMeta: meta.ObjectMeta{
+					Name:      name,
+					Namespace: injectedNS,
+					UID:       types.UID(name + "-uid"),
+					Labels: map[string]string{
+						pkgK8s.ControllerNSLabel: controllerNS,
+					},
+					OwnerReferences: []meta.OwnerReference{
+						{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "injected-ds", UID: "ds-uid"},
+					},
+				},
+			}
+		}
+		key := fmt.Sprintf("injected-ds.daemonset.%s", injectedNS)
+
+		controller.handlePodAdd(pod("injected-pod-1"))
+		controller.handlePodAdd(pod("injected-pod-2"))
+		controller.handlePodUpdate(nil, pod("injected-pod-2"))
+
+		controller.handlePodDelete(pod("injected-pod-1"))
+		if _, ok := controller.owners[key]; !ok {
+			t.Fatal("expected the workload to be kept while it has pods")
+		}
+
+		controller.handlePodDelete(cache.DeletedFinalStateUnknown{Key: injectedNS + "/injected-pod-2", Obj: pod("injected-pod-2")})
+		if _, ok := controller.owners[key]; ok {
+			t.Fatal("expected the workload to be forgotten with its last pod")
+		}
+	})
+}
+
 func new(fixtures ...string) (*CertificateController, chan bool, chan struct{}, error) {
 	k8sAPI, err := k8s.NewFakeAPI(fixtures...)
 	if err != nil {
@@ -17,12 +17,14 @@ func main() {
 	metricsAddr := flag.String("metrics-addr", ":9997", "address to serve scrapable metrics on")
 	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
 	kubeConfigPath := flag.String("kubeconfig", "", "path to kube config")
+	kubeAPIQPS := flag.Float64("kube-api-qps", 5, "maximum queries per second to the Kubernetes API")
+	kubeAPIBurst := flag.Int("kube-api-burst", 10, "maximum burst of queries to the Kubernetes API")
 	flags.ConfigureAndParse()
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
-	k8sClient, err := k8s.NewClientSet(*kubeConfigPath)
+	k8sClient, err := k8s.NewClientSet(*kubeConfigPath, float32(*kubeAPIQPS), *kubeAPIBurst)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -47,7 +49,7 @@ func main() {
 		controller.Run(ready, stopCh)
 	}()
 
-	go admin.StartServer(*metricsAddr, ready)
+	go admin.StartServer(*metricsAddr, admin.WaitFor("kubernetes caches synced", ready))
 
 	<-stop
 
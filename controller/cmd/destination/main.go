@@ -17,6 +17,8 @@ func main() {
 	addr := flag.String("addr", "127.0.0.1:8089", "address to serve on")
 	metricsAddr := flag.String("metrics-addr", ":9999", "address to serve scrapable metrics on")
 	kubeConfigPath := flag.String("kubeconfig", "", "path to kube config")
+	kubeAPIQPS := flag.Float64("kube-api-qps", 5, "maximum queries per second to the Kubernetes API")
+	kubeAPIBurst := flag.Int("kube-api-burst", 10, "maximum burst of queries to the Kubernetes API")
 	k8sDNSZone := flag.String("kubernetes-dns-zone", "", "The DNS suffix for the local Kubernetes zone.")
 	enableTLS := flag.Bool("enable-tls", false, "Enable TLS connections among pods in the service mesh")
 	flags.ConfigureAndParse()
@@ -24,7 +26,7 @@ func main() {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
-	k8sClient, err := k8s.NewClientSet(*kubeConfigPath)
+	k8sClient, err := k8s.NewClientSet(*kubeConfigPath, float32(*kubeAPIQPS), *kubeAPIBurst)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -51,7 +53,7 @@ func main() {
 		server.Serve(lis)
 	}()
 
-	go admin.StartServer(*metricsAddr, ready)
+	go admin.StartServer(*metricsAddr, admin.WaitFor("kubernetes caches synced", ready))
 
 	<-stop
 
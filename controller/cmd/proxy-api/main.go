@@ -38,7 +38,7 @@ func main() {
 		server.Serve(lis)
 	}()
 
-	go admin.StartServer(*metricsAddr, nil)
+	go admin.StartServer(*metricsAddr)
 
 	<-stop
 
@@ -7,6 +7,7 @@ import (
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
 	"github.com/linkerd/linkerd2/controller/k8s"
@@ -14,13 +15,16 @@ import (
 	"github.com/linkerd/linkerd2/pkg/admin"
 	"github.com/linkerd/linkerd2/pkg/flags"
 	promApi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	log "github.com/sirupsen/logrus"
 )
 
 func main() {
 	addr := flag.String("addr", ":8085", "address to serve on")
 	kubeConfigPath := flag.String("kubeconfig", "", "path to kube config")
-	prometheusUrl := flag.String("prometheus-url", "http://127.0.0.1:9090", "prometheus url")
+	kubeAPIQPS := flag.Float64("kube-api-qps", 5, "maximum queries per second to the Kubernetes API")
+	kubeAPIBurst := flag.Int("kube-api-burst", 10, "maximum burst of queries to the Kubernetes API")
+	prometheusUrl := flag.String("prometheus-url", "http://127.0.0.1:9090", "URL of the Prometheus, or Prometheus-compatible, query API to read metrics from")
 	metricsAddr := flag.String("metrics-addr", ":9995", "address to serve scrapable metrics on")
 	tapAddr := flag.String("tap-addr", "127.0.0.1:8088", "address of tap service")
 	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
@@ -36,13 +40,14 @@ func main() {
 	}
 	defer tapConn.Close()
 
-	k8sClient, err := k8s.NewClientSet(*kubeConfigPath)
+	k8sClient, err := k8s.NewClientSet(*kubeConfigPath, float32(*kubeAPIQPS), *kubeAPIBurst)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 	k8sAPI := k8s.NewAPI(
 		k8sClient,
 		k8s.Deploy,
+		k8s.Ing,
 		k8s.NS,
 		k8s.Pod,
 		k8s.RC,
@@ -55,9 +60,11 @@ func main() {
 		log.Fatal(err.Error())
 	}
 
+	prometheusAPI := promv1.NewAPI(prometheusClient)
+
 	server := public.NewServer(
 		*addr,
-		prometheusClient,
+		public.NewPrometheusProvider(prometheusAPI),
 		tapClient,
 		k8sAPI,
 		*controllerNamespace,
@@ -73,7 +80,18 @@ func main() {
 		server.ListenAndServe()
 	}()
 
-	go admin.StartServer(*metricsAddr, ready)
+	go admin.StartServer(*metricsAddr,
+		admin.WaitFor("kubernetes caches synced", ready),
+		admin.Dependency{
+			Name: "prometheus reachable",
+			Check: func(ctx context.Context) error {
+				_, err := prometheusAPI.Query(ctx, "1", time.Now())
+				return err
+			},
+			// StatSummary needs Prometheus, but the rest of the API doesn't.
+			Optional: true,
+		},
+	)
 
 	<-stop
 
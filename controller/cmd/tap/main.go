@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/linkerd/linkerd2/controller/k8s"
@@ -15,15 +17,22 @@ import (
 
 func main() {
 	addr := flag.String("addr", "127.0.0.1:8088", "address to serve on")
+	apiServerAddr := flag.String("apiserver-addr", ":8443", "address to serve the tap APIService on")
 	metricsAddr := flag.String("metrics-addr", ":9998", "address to serve scrapable metrics on")
 	kubeConfigPath := flag.String("kubeconfig", "", "path to kube config")
+	kubeAPIQPS := flag.Float64("kube-api-qps", 5, "maximum queries per second to the Kubernetes API")
+	kubeAPIBurst := flag.Int("kube-api-burst", 10, "maximum burst of queries to the Kubernetes API")
 	tapPort := flag.Uint("tap-port", 4190, "proxy tap port to connect to")
+	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
+	maxTaps := flag.Uint("max-concurrent-taps", 100, "maximum number of concurrent tap streams (0 for no limit)")
+	maxTapsPerNamespace := flag.Uint("max-concurrent-taps-per-namespace", 20, "maximum number of concurrent tap streams targeting a single namespace (0 for no limit)")
+	podLabels := flag.String("pod-labels", "", "comma-separated pod label keys to add to tap event metadata")
 	flags.ConfigureAndParse()
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
-	clientSet, err := k8s.NewClientSet(*kubeConfigPath)
+	clientSet, err := k8s.NewClientSet(*kubeConfigPath, float32(*kubeAPIQPS), *kubeAPIBurst)
 	if err != nil {
 		log.Fatalf("failed to create Kubernetes client: %s", err)
 	}
@@ -37,7 +46,25 @@ func main() {
 		k8s.RS,
 	)
 
-	server, lis, err := tap.NewServer(*addr, *tapPort, k8sAPI)
+	var podLabelKeys []string
+	if *podLabels != "" {
+		podLabelKeys = strings.Split(*podLabels, ",")
+	}
+
+	server, lis, err := tap.NewServer(*addr, *tapPort, *controllerNamespace, *maxTaps, *maxTapsPerNamespace, podLabelKeys, k8sAPI)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	// The APIService taps through the gRPC server, so that taps are limited
+	// in one place.
+	tapClient, tapConn, err := tap.NewClient(*addr)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	defer tapConn.Close()
+
+	apiServer, apiLis, err := tap.NewAPIServer(*apiServerAddr, *controllerNamespace, tapClient, k8sAPI)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -51,10 +78,17 @@ func main() {
 		server.Serve(lis)
 	}()
 
-	go admin.StartServer(*metricsAddr, ready)
+	go func() {
+		log.Println("starting tap APIService on", *apiServerAddr)
+		apiServer.Serve(apiLis)
+	}()
+
+	go admin.StartServer(*metricsAddr, admin.WaitFor("kubernetes caches synced", ready))
 
 	<-stop
 
 	log.Println("shutting down gRPC server on", *addr)
 	server.GracefulStop()
+	log.Println("shutting down tap APIService on", *apiServerAddr)
+	apiServer.Shutdown(context.Background())
 }
@@ -2,12 +2,14 @@ package destination
 
 import (
 	"fmt"
+	"reflect"
 	"sync"
 
 	net "github.com/linkerd/linkerd2-proxy-api/go/net"
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/pkg/addr"
 	log "github.com/sirupsen/logrus"
+	appsv1beta2 "k8s.io/api/apps/v1beta2"
 	"k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -60,6 +62,14 @@ func newEndpointsWatcher(k8sAPI *k8s.API) *endpointsWatcher {
 		},
 	)
 
+	// The metadata of an address names its pod's owner, which for the pods of
+	// a ReplicaSet is the ReplicaSet's owner.
+	k8sAPI.RS().Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: watcher.updateReplicaSet,
+		},
+	)
+
 	return watcher
 }
 
@@ -238,6 +248,25 @@ func (e *endpointsWatcher) deleteEndpoints(obj interface{}) {
 	}
 }
 
+func (e *endpointsWatcher) updateReplicaSet(oldObj, newObj interface{}) {
+	oldRS := oldObj.(*appsv1beta2.ReplicaSet)
+	newRS := newObj.(*appsv1beta2.ReplicaSet)
+	if reflect.DeepEqual(oldRS.GetOwnerReferences(), newRS.GetOwnerReferences()) {
+		return
+	}
+
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	for id, service := range e.servicePorts {
+		if id.namespace != newRS.Namespace {
+			continue
+		}
+		for _, sp := range service {
+			sp.refreshReplicaSet(newRS.Name)
+		}
+	}
+}
+
 func (e *endpointsWatcher) updateEndpoints(oldObj, newObj interface{}) {
 	e.addEndpoints(newObj)
 }
@@ -340,7 +369,7 @@ func (sp *servicePort) updateService(newService *v1.Service) {
 }
 
 func (sp *servicePort) updateAddresses(endpoints *v1.Endpoints, port intstr.IntOrString) {
-	newAddresses := sp.endpointsToAddresses(endpoints, port)
+	newAddresses := reuseAddresses(sp.addresses, sp.endpointsToAddresses(endpoints, port))
 	log.Debugf("Updating %s:%d to %v", sp.service, sp.port, newAddresses)
 
 	if len(newAddresses) == 0 {
@@ -356,6 +385,36 @@ func (sp *servicePort) updateAddresses(endpoints *v1.Endpoints, port intstr.IntO
 	sp.addresses = newAddresses
 }
 
+// refreshReplicaSet replaces the addresses of the pods of the named
+// ReplicaSet, so that their metadata is computed again, and sends them to the
+// listeners again.
+func (sp *servicePort) refreshReplicaSet(name string) {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	addresses := make([]*updateAddress, len(sp.addresses))
+	refreshed := make([]*updateAddress, 0)
+	for i, a := range sp.addresses {
+		addresses[i] = a
+		for _, owner := range a.pod.GetOwnerReferences() {
+			if owner.Kind == "ReplicaSet" && owner.Name == name {
+				addresses[i] = &updateAddress{address: a.address, pod: a.pod}
+				refreshed = append(refreshed, addresses[i])
+				break
+			}
+		}
+	}
+	if len(refreshed) == 0 {
+		return
+	}
+
+	log.Debugf("Refreshing the pods of ReplicaSet %s in %s:%d", name, sp.service, sp.port)
+	for _, listener := range sp.listeners {
+		listener.Update(refreshed, nil)
+	}
+	sp.addresses = addresses
+}
+
 func (sp *servicePort) subscribe(exists bool, listener updateListener) {
 	log.Debugf("Subscribing %s:%d exists=%t", sp.service, sp.port, exists)
 
@@ -5,8 +5,11 @@ import (
 	"sort"
 	"testing"
 
+	net "github.com/linkerd/linkerd2-proxy-api/go/net"
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/pkg/addr"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestEndpointsWatcher(t *testing.T) {
@@ -299,3 +302,34 @@ spec:
 		})
 	}
 }
+
+func TestServicePortRefreshReplicaSet(t *testing.T) {
+	pod := func(name, rs string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "ns",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: rs}},
+		}}
+	}
+	owned := &updateAddress{address: &net.TcpAddress{Ip: &net.IPAddress{}, Port: 1}, pod: pod("name1-1", "name1-abc")}
+	other := &updateAddress{address: &net.TcpAddress{Ip: &net.IPAddress{}, Port: 2}, pod: pod("name2-1", "name2-abc")}
+
+	listener, cancelFn := newCollectUpdateListener()
+	defer cancelFn()
+	sp := &servicePort{
+		listeners: []updateListener{listener},
+		addresses: []*updateAddress{owned, other},
+	}
+
+	sp.refreshReplicaSet("name1-abc")
+
+	if sp.addresses[0] == owned || sp.addresses[0].pod != owned.pod {
+		t.Fatalf("Expected the address of the ReplicaSet's pod to be replaced, got %+v", sp.addresses[0])
+	}
+	if sp.addresses[1] != other {
+		t.Fatalf("Expected the address of another ReplicaSet's pod to be kept")
+	}
+	if len(listener.added) != 1 || listener.added[0] != sp.addresses[0] {
+		t.Fatalf("Expected the refreshed address to be sent, got %v", listener.added)
+	}
+}
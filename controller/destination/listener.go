@@ -1,6 +1,8 @@
 package destination
 
 import (
+	"sync"
+
 	pb "github.com/linkerd/linkerd2-proxy-api/go/destination"
 	net "github.com/linkerd/linkerd2-proxy-api/go/net"
 	"github.com/linkerd/linkerd2/pkg/addr"
@@ -24,6 +26,17 @@ type ownerKindAndNameFn func(*coreV1.Pod) (string, string)
 type updateAddress struct {
 	address *net.TcpAddress
 	pod     *coreV1.Pod
+
+	// metadata is computed by the first listener that sends this address,
+	// and shared with every other listener of the same service.
+	metadataOnce sync.Once
+	metadata     addrMetadata
+}
+
+type addrMetadata struct {
+	labels      map[string]string
+	hint        *pb.ProtocolHint
+	tlsIdentity *pb.TlsIdentity
 }
 
 func diffUpdateAddresses(oldAddrs, newAddrs []*updateAddress) ([]*updateAddress, []*updateAddress) {
@@ -59,6 +72,23 @@ func diffUpdateAddresses(oldAddrs, newAddrs []*updateAddress) ([]*updateAddress,
 	return add, remove
 }
 
+// reuseAddresses replaces each of newAddrs with the equivalent address from
+// oldAddrs, if there is one for the same pod object, so that its metadata
+// isn't computed again.
+func reuseAddresses(oldAddrs, newAddrs []*updateAddress) []*updateAddress {
+	oldSet := make(map[string]*updateAddress, len(oldAddrs))
+	for _, a := range oldAddrs {
+		oldSet[addr.ProxyAddressToString(a.address)] = a
+	}
+
+	for i, a := range newAddrs {
+		if old, ok := oldSet[addr.ProxyAddressToString(a.address)]; ok && old.pod == a.pod {
+			newAddrs[i] = old
+		}
+	}
+	return newAddrs
+}
+
 // implements the updateListener interface
 type endpointListener struct {
 	stream           pb.Destination_GetServer
@@ -152,15 +182,22 @@ func (l *endpointListener) toWeightedAddrSet(addresses []*updateAddress) *pb.Wei
 }
 
 func (l *endpointListener) toWeightedAddr(address *updateAddress) *pb.WeightedAddr {
-	labels, hint, tlsIdentity := l.getAddrMetadata(address.pod)
+	address.metadataOnce.Do(func() {
+		address.metadata = l.getAddrMetadata(address.pod)
+	})
 
-	return &pb.WeightedAddr{
+	// The metadata is shared, so neither it nor the returned message may be
+	// modified.
+	weightedAddr := &pb.WeightedAddr{
 		Addr:         address.address,
 		Weight:       1,
-		MetricLabels: labels,
-		TlsIdentity:  tlsIdentity,
-		ProtocolHint: hint,
+		MetricLabels: address.metadata.labels,
+		ProtocolHint: address.metadata.hint,
 	}
+	if l.enableTLS {
+		weightedAddr.TlsIdentity = address.metadata.tlsIdentity
+	}
+	return weightedAddr
 }
 
 func (l *endpointListener) toAddrSet(addresses []*updateAddress) *pb.AddrSet {
@@ -171,7 +208,10 @@ func (l *endpointListener) toAddrSet(addresses []*updateAddress) *pb.AddrSet {
 	return &pb.AddrSet{Addrs: addrs}
 }
 
-func (l *endpointListener) getAddrMetadata(pod *coreV1.Pod) (map[string]string, *pb.ProtocolHint, *pb.TlsIdentity) {
+// getAddrMetadata computes the metadata for pod. It doesn't depend on the
+// listener's settings, so that it can be shared between listeners; the TLS
+// identity is only sent by listeners with TLS enabled.
+func (l *endpointListener) getAddrMetadata(pod *coreV1.Pod) addrMetadata {
 	controllerNs := pod.Labels[pkgK8s.ControllerNSLabel]
 	ownerKind, ownerName := l.ownerKindAndName(pod)
 	labels := pkgK8s.GetPodLabels(ownerKind, ownerName, pod)
@@ -191,10 +231,6 @@ func (l *endpointListener) getAddrMetadata(pod *coreV1.Pod) (map[string]string,
 		}
 	}
 
-	if !l.enableTLS {
-		return labels, hint, nil
-	}
-
 	identity := pkgK8s.TLSIdentity{
 		Name:                ownerName,
 		Kind:                ownerKind,
@@ -202,11 +238,15 @@ func (l *endpointListener) getAddrMetadata(pod *coreV1.Pod) (map[string]string,
 		ControllerNamespace: controllerNs,
 	}
 
-	return labels, hint, &pb.TlsIdentity{
-		Strategy: &pb.TlsIdentity_K8SPodIdentity_{
-			K8SPodIdentity: &pb.TlsIdentity_K8SPodIdentity{
-				PodIdentity:  identity.ToDNSName(),
-				ControllerNs: controllerNs,
+	return addrMetadata{
+		labels: labels,
+		hint:   hint,
+		tlsIdentity: &pb.TlsIdentity{
+			Strategy: &pb.TlsIdentity_K8SPodIdentity_{
+				K8SPodIdentity: &pb.TlsIdentity_K8SPodIdentity{
+					PodIdentity:  identity.ToDNSName(),
+					ControllerNs: controllerNs,
+				},
 			},
 		},
 	}
@@ -2,6 +2,7 @@ package destination
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -295,3 +296,113 @@ func checkAddress(t *testing.T, addr *pb.WeightedAddr, expectedAddress *net.TcpA
 		t.Fatalf("Expected added address to be [%+v] and weight to be [%d], but it was [%+v] and [%d]", expectedAddress, expectedWeight, actualAddress, actualWeight)
 	}
 }
+
+func TestSharedAddrMetadata(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod1",
+			Namespace: "ns",
+			Labels: map[string]string{
+				pkgK8s.ControllerNSLabel: "linkerd",
+			},
+		},
+	}
+	ownerCalls := 0
+	ownerKindAndName := func(pod *v1.Pod) (string, string) {
+		ownerCalls++
+		return "deployment", "web"
+	}
+
+	t.Run("Computes metadata once for all listeners, honoring each listener's TLS setting", func(t *testing.T) {
+		add := []*updateAddress{{address: addedAddress1, pod: pod}}
+		tlsStream := &mockDestination_GetServer{}
+		plainStream := &mockDestination_GetServer{}
+		newEndpointListener(tlsStream, ownerKindAndName, true).Update(add, nil)
+		newEndpointListener(plainStream, ownerKindAndName, false).Update(add, nil)
+
+		if ownerCalls != 1 {
+			t.Fatalf("Expected metadata to be computed once, got %d times", ownerCalls)
+		}
+
+		tlsAddr := tlsStream.updatesReceived[0].GetAdd().GetAddrs()[0]
+		plainAddr := plainStream.updatesReceived[0].GetAdd().GetAddrs()[0]
+		if !reflect.DeepEqual(tlsAddr.GetMetricLabels(), plainAddr.GetMetricLabels()) {
+			t.Fatalf("Expected both listeners to send the same labels, got %v and %v", tlsAddr.GetMetricLabels(), plainAddr.GetMetricLabels())
+		}
+		if tlsAddr.GetTlsIdentity() == nil {
+			t.Fatalf("Expected the TLS listener to send a TlsIdentity")
+		}
+		if plainAddr.GetTlsIdentity() != nil {
+			t.Fatalf("Expected the non-TLS listener not to send a TlsIdentity, got %v", plainAddr.GetTlsIdentity())
+		}
+	})
+
+	t.Run("Reuses addresses for unchanged pods", func(t *testing.T) {
+		updatedPod := pod.DeepCopy()
+		oldAddrs := []*updateAddress{
+			{address: addedAddress1, pod: pod},
+			{address: addedAddress2, pod: pod},
+		}
+		newAddrs := reuseAddresses(oldAddrs, []*updateAddress{
+			{address: addedAddress1, pod: pod},
+			{address: addedAddress2, pod: updatedPod},
+			{address: removedAddress1, pod: pod},
+		})
+
+		if newAddrs[0] != oldAddrs[0] {
+			t.Fatalf("Expected the address of the unchanged pod to be reused")
+		}
+		if newAddrs[1] == oldAddrs[1] || newAddrs[1].pod != updatedPod {
+			t.Fatalf("Expected the address of the updated pod to be replaced")
+		}
+		if newAddrs[2].address != removedAddress1 {
+			t.Fatalf("Expected the new address to be kept, got %v", newAddrs[2].address)
+		}
+	})
+}
+
+// BenchmarkEndpointListenerUpdate sends a large address set to many listeners
+// watching the same service, as happens when every proxy in a big mesh
+// resolves a popular service.
+func BenchmarkEndpointListenerUpdate(b *testing.B) {
+	const numAddresses = 1000
+	const numListeners = 20
+
+	addresses := make([]*updateAddress, numAddresses)
+	for i := range addresses {
+		addresses[i] = &updateAddress{
+			address: &net.TcpAddress{
+				Ip:   &net.IPAddress{Ip: &net.IPAddress_Ipv4{Ipv4: uint32(i)}},
+				Port: 8080,
+			},
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("web-%d", i),
+					Namespace: "emojivoto",
+					Labels: map[string]string{
+						pkgK8s.ControllerNSLabel: "linkerd",
+					},
+				},
+			},
+		}
+	}
+
+	ownerKindAndName := func(pod *v1.Pod) (string, string) {
+		return "deployment", "web"
+	}
+	streams := make([]*mockDestination_GetServer, numListeners)
+	listeners := make([]*endpointListener, numListeners)
+	for i := range listeners {
+		streams[i] = &mockDestination_GetServer{}
+		listeners[i] = newEndpointListener(streams[i], ownerKindAndName, true)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i, listener := range listeners {
+			streams[i].updatesReceived = nil
+			listener.Update(addresses, nil)
+		}
+	}
+}
@@ -8,6 +8,7 @@ It is generated from these files:
 	public.proto
 
 It has these top-level messages:
+
 	Empty
 	VersionInfo
 	ListPodsRequest
@@ -232,6 +233,7 @@ type Pod struct {
 	ControllerNamespace string                    `protobuf:"bytes,7,opt,name=controllerNamespace" json:"controllerNamespace,omitempty"`
 	ControlPlane        bool                      `protobuf:"varint,8,opt,name=controlPlane" json:"controlPlane,omitempty"`
 	Uptime              *google_protobuf.Duration `protobuf:"bytes,9,opt,name=uptime" json:"uptime,omitempty"`
+	ProxyVersion        string                    `protobuf:"bytes,15,opt,name=proxyVersion" json:"proxyVersion,omitempty"`
 }
 
 func (m *Pod) Reset()                    { *m = Pod{} }
@@ -372,6 +374,13 @@ func (m *Pod) GetUptime() *google_protobuf.Duration {
 	return nil
 }
 
+func (m *Pod) GetProxyVersion() string {
+	if m != nil {
+		return m.ProxyVersion
+	}
+	return ""
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Pod) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Pod_OneofMarshaler, _Pod_OneofUnmarshaler, _Pod_OneofSizer, []interface{}{
@@ -690,6 +699,23 @@ type TapByResourceRequest struct {
 	Match *TapByResourceRequest_Match `protobuf:"bytes,2,opt,name=match" json:"match,omitempty"`
 	// Limits the number of events to be inspected.
 	MaxRps float32 `protobuf:"fixed32,3,opt,name=maxRps" json:"maxRps,omitempty"`
+	// If set, only requests whose response latency is at least this long are
+	// reported.
+	MinLatency *google_protobuf.Duration `protobuf:"bytes,4,opt,name=minLatency" json:"minLatency,omitempty"`
+	// If set, only requests whose response has an HTTP status in this range are
+	// reported.
+	ResponseStatus *TapByResourceRequest_StatusRange `protobuf:"bytes,5,opt,name=responseStatus" json:"responseStatus,omitempty"`
+	// If set, each request is reported with this probability, between 0 and 1.
+	// Proxies are then asked for up to 10 times more requests than maxRps, and
+	// at most 1000 per second per pod, so the sample is drawn from more of each
+	// 10s interval. Requests beyond that are still never reported.
+	SampleRate float32 `protobuf:"fixed32,6,opt,name=sampleRate" json:"sampleRate,omitempty"`
+	// Overrides sampleRate for requests whose path matches a regex. The first
+	// matching override applies; a rate of 0 reports none of its requests.
+	PathSampleRates []*TapByResourceRequest_PathSampleRate `protobuf:"bytes,7,rep,name=pathSampleRates" json:"pathSampleRates,omitempty"`
+	// If set, only requests whose gRPC response ends with one of these status
+	// codes are reported.
+	GrpcStatuses []uint32 `protobuf:"varint,8,rep,packed,name=grpcStatuses" json:"grpcStatuses,omitempty"`
 }
 
 func (m *TapByResourceRequest) Reset()                    { *m = TapByResourceRequest{} }
@@ -718,6 +744,67 @@ func (m *TapByResourceRequest) GetMaxRps() float32 {
 	return 0
 }
 
+func (m *TapByResourceRequest) GetMinLatency() *google_protobuf.Duration {
+	if m != nil {
+		return m.MinLatency
+	}
+	return nil
+}
+
+func (m *TapByResourceRequest) GetResponseStatus() *TapByResourceRequest_StatusRange {
+	if m != nil {
+		return m.ResponseStatus
+	}
+	return nil
+}
+
+func (m *TapByResourceRequest) GetSampleRate() float32 {
+	if m != nil {
+		return m.SampleRate
+	}
+	return 0
+}
+
+func (m *TapByResourceRequest) GetPathSampleRates() []*TapByResourceRequest_PathSampleRate {
+	if m != nil {
+		return m.PathSampleRates
+	}
+	return nil
+}
+
+func (m *TapByResourceRequest) GetGrpcStatuses() []uint32 {
+	if m != nil {
+		return m.GrpcStatuses
+	}
+	return nil
+}
+
+type TapByResourceRequest_StatusRange struct {
+	Min uint32 `protobuf:"varint,1,opt,name=min" json:"min,omitempty"`
+	Max uint32 `protobuf:"varint,2,opt,name=max" json:"max,omitempty"`
+}
+
+func (m *TapByResourceRequest_StatusRange) Reset()         { *m = TapByResourceRequest_StatusRange{} }
+func (m *TapByResourceRequest_StatusRange) String() string { return proto.CompactTextString(m) }
+func (*TapByResourceRequest_StatusRange) ProtoMessage()    {}
+func (*TapByResourceRequest_StatusRange) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{6, 0}
+}
+
+func (m *TapByResourceRequest_StatusRange) GetMin() uint32 {
+	if m != nil {
+		return m.Min
+	}
+	return 0
+}
+
+func (m *TapByResourceRequest_StatusRange) GetMax() uint32 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
 type TapByResourceRequest_Match struct {
 	// Types that are valid to be assigned to Match:
 	//	*TapByResourceRequest_Match_All
@@ -725,13 +812,14 @@ type TapByResourceRequest_Match struct {
 	//	*TapByResourceRequest_Match_Not
 	//	*TapByResourceRequest_Match_Destinations
 	//	*TapByResourceRequest_Match_Http_
+	//	*TapByResourceRequest_Match_Sources
 	Match isTapByResourceRequest_Match_Match `protobuf_oneof:"match"`
 }
 
 func (m *TapByResourceRequest_Match) Reset()                    { *m = TapByResourceRequest_Match{} }
 func (m *TapByResourceRequest_Match) String() string            { return proto.CompactTextString(m) }
 func (*TapByResourceRequest_Match) ProtoMessage()               {}
-func (*TapByResourceRequest_Match) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6, 0} }
+func (*TapByResourceRequest_Match) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6, 1} }
 
 type isTapByResourceRequest_Match_Match interface{ isTapByResourceRequest_Match_Match() }
 
@@ -750,12 +838,16 @@ type TapByResourceRequest_Match_Destinations struct {
 type TapByResourceRequest_Match_Http_ struct {
 	Http *TapByResourceRequest_Match_Http `protobuf:"bytes,5,opt,name=http,oneof"`
 }
+type TapByResourceRequest_Match_Sources struct {
+	Sources *ResourceSelection `protobuf:"bytes,6,opt,name=sources,oneof"`
+}
 
 func (*TapByResourceRequest_Match_All) isTapByResourceRequest_Match_Match()          {}
 func (*TapByResourceRequest_Match_Any) isTapByResourceRequest_Match_Match()          {}
 func (*TapByResourceRequest_Match_Not) isTapByResourceRequest_Match_Match()          {}
 func (*TapByResourceRequest_Match_Destinations) isTapByResourceRequest_Match_Match() {}
 func (*TapByResourceRequest_Match_Http_) isTapByResourceRequest_Match_Match()        {}
+func (*TapByResourceRequest_Match_Sources) isTapByResourceRequest_Match_Match()      {}
 
 func (m *TapByResourceRequest_Match) GetMatch() isTapByResourceRequest_Match_Match {
 	if m != nil {
@@ -799,6 +891,13 @@ func (m *TapByResourceRequest_Match) GetHttp() *TapByResourceRequest_Match_Http
 	return nil
 }
 
+func (m *TapByResourceRequest_Match) GetSources() *ResourceSelection {
+	if x, ok := m.GetMatch().(*TapByResourceRequest_Match_Sources); ok {
+		return x.Sources
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*TapByResourceRequest_Match) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _TapByResourceRequest_Match_OneofMarshaler, _TapByResourceRequest_Match_OneofUnmarshaler, _TapByResourceRequest_Match_OneofSizer, []interface{}{
@@ -807,6 +906,7 @@ func (*TapByResourceRequest_Match) XXX_OneofFuncs() (func(msg proto.Message, b *
 		(*TapByResourceRequest_Match_Not)(nil),
 		(*TapByResourceRequest_Match_Destinations)(nil),
 		(*TapByResourceRequest_Match_Http_)(nil),
+		(*TapByResourceRequest_Match_Sources)(nil),
 	}
 }
 
@@ -839,6 +939,11 @@ func _TapByResourceRequest_Match_OneofMarshaler(msg proto.Message, b *proto.Buff
 		if err := b.EncodeMessage(x.Http); err != nil {
 			return err
 		}
+	case *TapByResourceRequest_Match_Sources:
+		b.EncodeVarint(6<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Sources); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("TapByResourceRequest_Match.Match has unexpected type %T", x)
@@ -889,6 +994,14 @@ func _TapByResourceRequest_Match_OneofUnmarshaler(msg proto.Message, tag, wire i
 		err := b.DecodeMessage(msg)
 		m.Match = &TapByResourceRequest_Match_Http_{msg}
 		return true, err
+	case 6: // match.sources
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ResourceSelection)
+		err := b.DecodeMessage(msg)
+		m.Match = &TapByResourceRequest_Match_Sources{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -923,6 +1036,11 @@ func _TapByResourceRequest_Match_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(5<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *TapByResourceRequest_Match_Sources:
+		s := proto.Size(x.Sources)
+		n += proto.SizeVarint(6<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -938,7 +1056,7 @@ func (m *TapByResourceRequest_Match_Seq) Reset()         { *m = TapByResourceReq
 func (m *TapByResourceRequest_Match_Seq) String() string { return proto.CompactTextString(m) }
 func (*TapByResourceRequest_Match_Seq) ProtoMessage()    {}
 func (*TapByResourceRequest_Match_Seq) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{6, 0, 0}
+	return fileDescriptor0, []int{6, 1, 0}
 }
 
 func (m *TapByResourceRequest_Match_Seq) GetMatches() []*TapByResourceRequest_Match {
@@ -961,7 +1079,7 @@ func (m *TapByResourceRequest_Match_Http) Reset()         { *m = TapByResourceRe
 func (m *TapByResourceRequest_Match_Http) String() string { return proto.CompactTextString(m) }
 func (*TapByResourceRequest_Match_Http) ProtoMessage()    {}
 func (*TapByResourceRequest_Match_Http) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{6, 0, 1}
+	return fileDescriptor0, []int{6, 1, 1}
 }
 
 type isTapByResourceRequest_Match_Http_Match interface{ isTapByResourceRequest_Match_Http_Match() }
@@ -1115,6 +1233,32 @@ func _TapByResourceRequest_Match_Http_OneofSizer(msg proto.Message) (n int) {
 	return n
 }
 
+type TapByResourceRequest_PathSampleRate struct {
+	Path string  `protobuf:"bytes,1,opt,name=path" json:"path,omitempty"`
+	Rate float32 `protobuf:"fixed32,2,opt,name=rate" json:"rate,omitempty"`
+}
+
+func (m *TapByResourceRequest_PathSampleRate) Reset()         { *m = TapByResourceRequest_PathSampleRate{} }
+func (m *TapByResourceRequest_PathSampleRate) String() string { return proto.CompactTextString(m) }
+func (*TapByResourceRequest_PathSampleRate) ProtoMessage()    {}
+func (*TapByResourceRequest_PathSampleRate) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{6, 2}
+}
+
+func (m *TapByResourceRequest_PathSampleRate) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *TapByResourceRequest_PathSampleRate) GetRate() float32 {
+	if m != nil {
+		return m.Rate
+	}
+	return 0
+}
+
 type HttpMethod struct {
 	// Types that are valid to be assigned to Type:
 	//	*HttpMethod_Registered_
@@ -2327,6 +2471,17 @@ type StatSummaryRequest struct {
 	//	*StatSummaryRequest_ToResource
 	//	*StatSummaryRequest_FromResource
 	Outbound isStatSummaryRequest_Outbound `protobuf_oneof:"outbound"`
+	// If set, aggregates stats across all resources that share a value of this
+	// Prometheus label, such as a pod label added with `install --pod-labels`,
+	// with a row per value.
+	GroupByLabel string `protobuf:"bytes,6,opt,name=group_by_label,json=groupByLabel" json:"group_by_label,omitempty"`
+	// Further resources to report stats for alongside the selector's resource,
+	// e.g. to compare related workloads in a single request. Those of the same
+	// type as another selected resource share its table.
+	AdditionalResources []*Resource `protobuf:"bytes,7,rep,name=additional_resources,json=additionalResources" json:"additional_resources,omitempty"`
+	// Further time windows to report stats over alongside time_window. Each
+	// table has a row per resource and window, in the order of the windows.
+	AdditionalTimeWindows []string `protobuf:"bytes,8,rep,name=additional_time_windows,json=additionalTimeWindows" json:"additional_time_windows,omitempty"`
 }
 
 func (m *StatSummaryRequest) Reset()                    { *m = StatSummaryRequest{} }
@@ -2392,6 +2547,27 @@ func (m *StatSummaryRequest) GetFromResource() *Resource {
 	return nil
 }
 
+func (m *StatSummaryRequest) GetGroupByLabel() string {
+	if m != nil {
+		return m.GroupByLabel
+	}
+	return ""
+}
+
+func (m *StatSummaryRequest) GetAdditionalResources() []*Resource {
+	if m != nil {
+		return m.AdditionalResources
+	}
+	return nil
+}
+
+func (m *StatSummaryRequest) GetAdditionalTimeWindows() []string {
+	if m != nil {
+		return m.AdditionalTimeWindows
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*StatSummaryRequest) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _StatSummaryRequest_OneofMarshaler, _StatSummaryRequest_OneofUnmarshaler, _StatSummaryRequest_OneofSizer, []interface{}{
@@ -2856,9 +3032,11 @@ func init() {
 	proto.RegisterType((*Pod)(nil), "linkerd2.public.Pod")
 	proto.RegisterType((*TapRequest)(nil), "linkerd2.public.TapRequest")
 	proto.RegisterType((*TapByResourceRequest)(nil), "linkerd2.public.TapByResourceRequest")
+	proto.RegisterType((*TapByResourceRequest_StatusRange)(nil), "linkerd2.public.TapByResourceRequest.StatusRange")
 	proto.RegisterType((*TapByResourceRequest_Match)(nil), "linkerd2.public.TapByResourceRequest.Match")
 	proto.RegisterType((*TapByResourceRequest_Match_Seq)(nil), "linkerd2.public.TapByResourceRequest.Match.Seq")
 	proto.RegisterType((*TapByResourceRequest_Match_Http)(nil), "linkerd2.public.TapByResourceRequest.Match.Http")
+	proto.RegisterType((*TapByResourceRequest_PathSampleRate)(nil), "linkerd2.public.TapByResourceRequest.PathSampleRate")
 	proto.RegisterType((*HttpMethod)(nil), "linkerd2.public.HttpMethod")
 	proto.RegisterType((*Scheme)(nil), "linkerd2.public.Scheme")
 	proto.RegisterType((*IPAddress)(nil), "linkerd2.public.IPAddress")
@@ -2903,6 +3081,9 @@ const _ = grpc.SupportPackageIsVersion4
 
 type ApiClient interface {
 	StatSummary(ctx context.Context, in *StatSummaryRequest, opts ...grpc.CallOption) (*StatSummaryResponse, error)
+	// Like `StatSummary`, but sends the table of each resource type in a
+	// response of its own, as soon as it and the tables before it are ready.
+	StatSummaryStream(ctx context.Context, in *StatSummaryRequest, opts ...grpc.CallOption) (Api_StatSummaryStreamClient, error)
 	ListPods(ctx context.Context, in *ListPodsRequest, opts ...grpc.CallOption) (*ListPodsResponse, error)
 	// Superceded by `TapByResource`.
 	Tap(ctx context.Context, in *TapRequest, opts ...grpc.CallOption) (Api_TapClient, error)
@@ -2929,6 +3110,38 @@ func (c *apiClient) StatSummary(ctx context.Context, in *StatSummaryRequest, opt
 	return out, nil
 }
 
+func (c *apiClient) StatSummaryStream(ctx context.Context, in *StatSummaryRequest, opts ...grpc.CallOption) (Api_StatSummaryStreamClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Api_serviceDesc.Streams[0], c.cc, "/linkerd2.public.Api/StatSummaryStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &apiStatSummaryStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Api_StatSummaryStreamClient interface {
+	Recv() (*StatSummaryResponse, error)
+	grpc.ClientStream
+}
+
+type apiStatSummaryStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *apiStatSummaryStreamClient) Recv() (*StatSummaryResponse, error) {
+	m := new(StatSummaryResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *apiClient) ListPods(ctx context.Context, in *ListPodsRequest, opts ...grpc.CallOption) (*ListPodsResponse, error) {
 	out := new(ListPodsResponse)
 	err := grpc.Invoke(ctx, "/linkerd2.public.Api/ListPods", in, out, c.cc, opts...)
@@ -2939,7 +3152,7 @@ func (c *apiClient) ListPods(ctx context.Context, in *ListPodsRequest, opts ...g
 }
 
 func (c *apiClient) Tap(ctx context.Context, in *TapRequest, opts ...grpc.CallOption) (Api_TapClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Api_serviceDesc.Streams[0], c.cc, "/linkerd2.public.Api/Tap", opts...)
+	stream, err := grpc.NewClientStream(ctx, &_Api_serviceDesc.Streams[1], c.cc, "/linkerd2.public.Api/Tap", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -2971,7 +3184,7 @@ func (x *apiTapClient) Recv() (*TapEvent, error) {
 }
 
 func (c *apiClient) TapByResource(ctx context.Context, in *TapByResourceRequest, opts ...grpc.CallOption) (Api_TapByResourceClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Api_serviceDesc.Streams[1], c.cc, "/linkerd2.public.Api/TapByResource", opts...)
+	stream, err := grpc.NewClientStream(ctx, &_Api_serviceDesc.Streams[2], c.cc, "/linkerd2.public.Api/TapByResource", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -3024,6 +3237,9 @@ func (c *apiClient) SelfCheck(ctx context.Context, in *linkerd2_common_healthche
 
 type ApiServer interface {
 	StatSummary(context.Context, *StatSummaryRequest) (*StatSummaryResponse, error)
+	// Like `StatSummary`, but sends the table of each resource type in a
+	// response of its own, as soon as it and the tables before it are ready.
+	StatSummaryStream(*StatSummaryRequest, Api_StatSummaryStreamServer) error
 	ListPods(context.Context, *ListPodsRequest) (*ListPodsResponse, error)
 	// Superceded by `TapByResource`.
 	Tap(*TapRequest, Api_TapServer) error
@@ -3055,6 +3271,27 @@ func _Api_StatSummary_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Api_StatSummaryStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StatSummaryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ApiServer).StatSummaryStream(m, &apiStatSummaryStreamServer{stream})
+}
+
+type Api_StatSummaryStreamServer interface {
+	Send(*StatSummaryResponse) error
+	grpc.ServerStream
+}
+
+type apiStatSummaryStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *apiStatSummaryStreamServer) Send(m *StatSummaryResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Api_ListPods_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListPodsRequest)
 	if err := dec(in); err != nil {
@@ -3173,6 +3410,11 @@ var _Api_serviceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StatSummaryStream",
+			Handler:       _Api_StatSummaryStream_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "Tap",
 			Handler:       _Api_Tap_Handler,
@@ -3190,160 +3432,173 @@ var _Api_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("public.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 2477 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x39, 0x3b, 0x73, 0x1b, 0xc9,
-	0xd1, 0x78, 0x2c, 0x5e, 0x0d, 0x80, 0x84, 0x46, 0x3a, 0x7d, 0x10, 0xee, 0x4a, 0x47, 0xad, 0x74,
-	0x3a, 0x96, 0xee, 0x33, 0x48, 0x41, 0x27, 0x9d, 0x28, 0x9d, 0x1f, 0x04, 0x09, 0x0b, 0xb4, 0x29,
-	0x12, 0x1a, 0x40, 0xbe, 0x2a, 0xd5, 0x55, 0xa1, 0x16, 0xd8, 0x21, 0xb1, 0xe6, 0x62, 0x67, 0xb5,
-	0x3b, 0x10, 0x0f, 0xa9, 0x23, 0x07, 0x0e, 0xed, 0xd8, 0xb1, 0x9d, 0xb8, 0x9c, 0xf8, 0x47, 0xf8,
-	0x0f, 0x38, 0xb3, 0x33, 0xa7, 0x4e, 0x1c, 0xdb, 0xae, 0x79, 0x2d, 0x16, 0x04, 0xf8, 0x90, 0x9c,
-	0x38, 0xc2, 0x74, 0x4f, 0x77, 0x6f, 0x4f, 0xbf, 0x67, 0x00, 0x25, 0x7f, 0x32, 0x70, 0x9d, 0x61,
-	0xdd, 0x0f, 0x28, 0xa3, 0x68, 0xd5, 0x75, 0xbc, 0x13, 0x12, 0xd8, 0x8d, 0xba, 0x44, 0xd7, 0x6e,
-	0x1f, 0x53, 0x7a, 0xec, 0x92, 0x0d, 0xb1, 0x3d, 0x98, 0x1c, 0x6d, 0xd8, 0x93, 0xc0, 0x62, 0x0e,
-	0xf5, 0x24, 0x43, 0xad, 0x3a, 0xa4, 0xe3, 0x31, 0xf5, 0x36, 0x46, 0xc4, 0x72, 0xd9, 0x68, 0x38,
-	0x22, 0xc3, 0x13, 0xb9, 0x63, 0xe6, 0x20, 0xd3, 0x1a, 0xfb, 0x6c, 0x6a, 0xbe, 0x85, 0xe2, 0xcf,
-	0x48, 0x10, 0x3a, 0xd4, 0xdb, 0xf3, 0x8e, 0x28, 0xfa, 0x04, 0x0a, 0xc7, 0x54, 0x21, 0xaa, 0xc9,
-	0xb5, 0xe4, 0x7a, 0x01, 0xcf, 0x10, 0x7c, 0x77, 0x30, 0x71, 0x5c, 0x7b, 0xd7, 0x62, 0xa4, 0x9a,
-	0x92, 0xbb, 0x11, 0x02, 0xdd, 0x87, 0x95, 0x80, 0xb8, 0xc4, 0x0a, 0x89, 0x16, 0x90, 0x16, 0x24,
-	0x67, 0xb0, 0xe6, 0x06, 0xac, 0xee, 0x3b, 0x21, 0xeb, 0x50, 0x3b, 0xc4, 0xe4, 0xed, 0x84, 0x84,
-	0x8c, 0x0b, 0xf6, 0xac, 0x31, 0x09, 0x7d, 0x6b, 0x48, 0xf4, 0x67, 0x23, 0x84, 0xf9, 0x35, 0x54,
-	0x66, 0x0c, 0xa1, 0x4f, 0xbd, 0x90, 0xa0, 0x75, 0x30, 0x7c, 0x6a, 0x87, 0xd5, 0xe4, 0x5a, 0x7a,
-	0xbd, 0xd8, 0xb8, 0x51, 0x3f, 0x63, 0x9a, 0x7a, 0x87, 0xda, 0x58, 0x50, 0x98, 0xbf, 0x32, 0x20,
-	0xdd, 0xa1, 0x36, 0x42, 0x60, 0x70, 0x91, 0x4a, 0xbc, 0x58, 0xa3, 0x1b, 0x90, 0xf1, 0xa9, 0xbd,
-	0xd7, 0x51, 0x87, 0x91, 0x00, 0x5a, 0x03, 0xb0, 0x89, 0xef, 0xd2, 0xe9, 0x98, 0x78, 0x4c, 0x1e,
-	0xa2, 0x9d, 0xc0, 0x31, 0x1c, 0xba, 0x03, 0xc5, 0x80, 0xf8, 0xae, 0x33, 0xb4, 0xfa, 0x21, 0x61,
-	0x55, 0xd0, 0x24, 0x0a, 0xd9, 0x25, 0x0c, 0x7d, 0x05, 0x37, 0x15, 0xc4, 0x1d, 0xd2, 0x1f, 0x52,
-	0x8f, 0x05, 0xd4, 0x75, 0x49, 0x50, 0x2d, 0x2a, 0xea, 0x8f, 0x62, 0xfb, 0x3b, 0xd1, 0x36, 0xba,
-	0x0b, 0xa5, 0x90, 0x59, 0x8c, 0x1c, 0x4d, 0x5c, 0x21, 0xbc, 0xa4, 0xc8, 0x8b, 0x1a, 0xcb, 0xa5,
-	0x7f, 0x0a, 0x60, 0x5b, 0x64, 0x4c, 0x3d, 0x41, 0x52, 0x56, 0x24, 0x05, 0x89, 0xe3, 0x04, 0x08,
-	0xd2, 0x3f, 0xa7, 0x83, 0xea, 0x8a, 0xda, 0xe1, 0x00, 0xba, 0x09, 0x59, 0x2e, 0x63, 0x12, 0x56,
-	0x0d, 0x71, 0x5c, 0x05, 0x71, 0x2b, 0x58, 0xb6, 0x4d, 0xec, 0x6a, 0x66, 0x2d, 0xb9, 0x9e, 0xc7,
-	0x12, 0x40, 0x3b, 0xb0, 0x1a, 0x3a, 0xde, 0x90, 0xec, 0x5b, 0x21, 0xc3, 0xc4, 0xa7, 0x01, 0xab,
-	0x66, 0xd7, 0x92, 0xeb, 0xc5, 0xc6, 0xad, 0xba, 0x0c, 0xbb, 0xba, 0x0e, 0xbb, 0xfa, 0xae, 0x0a,
-	0x3b, 0x7c, 0x96, 0x03, 0x6d, 0xc2, 0xf5, 0xd9, 0xc9, 0x0f, 0x22, 0x17, 0xe7, 0xc4, 0xf7, 0x97,
-	0x6d, 0x21, 0x13, 0x4a, 0x0a, 0xdd, 0x71, 0x2d, 0x8f, 0x54, 0xf3, 0x42, 0xa7, 0x39, 0x1c, 0x7a,
-	0x08, 0xd9, 0x89, 0xcf, 0x9c, 0x31, 0xa9, 0x16, 0x2e, 0xd3, 0x48, 0x11, 0x36, 0x73, 0x90, 0xa1,
-	0xa7, 0x1e, 0x09, 0xcc, 0xdf, 0xa7, 0x00, 0x7a, 0x96, 0xaf, 0x23, 0x0f, 0x41, 0xda, 0xa7, 0xb6,
-	0x0c, 0x0a, 0x6e, 0x27, 0x9f, 0xda, 0x67, 0xfc, 0x9f, 0x5a, 0xe2, 0xff, 0x9b, 0x90, 0x1d, 0x5b,
-	0xdf, 0x61, 0x3f, 0x14, 0xd1, 0x91, 0xc2, 0x0a, 0xe2, 0x78, 0x46, 0x3b, 0xdc, 0x54, 0xdc, 0xc2,
-	0x65, 0xac, 0x20, 0x1e, 0x7b, 0x8c, 0xee, 0x75, 0x84, 0x81, 0x0b, 0x58, 0xac, 0x51, 0x0d, 0xf2,
-	0x47, 0x01, 0x1d, 0x77, 0xb4, 0x61, 0xcb, 0x38, 0x82, 0xb9, 0x1c, 0xbe, 0xde, 0xeb, 0x28, 0x4b,
-	0x29, 0x48, 0x78, 0x70, 0x38, 0x22, 0x63, 0x69, 0x16, 0xee, 0x41, 0x01, 0x09, 0x7d, 0x08, 0x1b,
-	0x51, 0x5b, 0x18, 0xa4, 0x80, 0x15, 0xc4, 0xf3, 0xca, 0x9a, 0xb0, 0x11, 0x0d, 0x1c, 0x36, 0x95,
-	0x51, 0x8a, 0x67, 0x08, 0xae, 0x95, 0x6f, 0xb1, 0x91, 0x0c, 0x48, 0x2c, 0xd6, 0xcf, 0x52, 0xd5,
-	0x64, 0x33, 0x0f, 0x59, 0x66, 0x05, 0xc7, 0x84, 0x99, 0x7f, 0xcf, 0xc0, 0x8d, 0x9e, 0xe5, 0x37,
-	0xa7, 0x98, 0x84, 0x74, 0x12, 0x0c, 0x89, 0x36, 0xdb, 0x33, 0x4d, 0x22, 0x2c, 0x57, 0x6c, 0x98,
-	0x0b, 0x09, 0xa8, 0x39, 0xba, 0xc4, 0x25, 0x43, 0xe9, 0x0a, 0xc9, 0x81, 0xb6, 0x21, 0x33, 0xb6,
-	0xd8, 0x70, 0x24, 0x2c, 0x5b, 0x6c, 0x7c, 0xb1, 0xc0, 0xba, 0xec, 0x8b, 0xf5, 0x97, 0x9c, 0x05,
-	0x4b, 0xce, 0xf3, 0xec, 0x5f, 0xfb, 0x93, 0x01, 0x19, 0x41, 0x88, 0x76, 0x20, 0x6d, 0xb9, 0xae,
-	0xd2, 0x6e, 0xe3, 0x3d, 0x3e, 0x51, 0xef, 0x92, 0xb7, 0x3c, 0x10, 0x2c, 0xd7, 0x15, 0x42, 0xbc,
-	0xa9, 0xd2, 0xf3, 0x83, 0x84, 0x78, 0x53, 0xf4, 0x43, 0x48, 0x7b, 0x54, 0x96, 0x91, 0xf7, 0x3b,
-	0x2c, 0x17, 0xe0, 0x51, 0x86, 0xda, 0x50, 0xb2, 0x49, 0xc8, 0x1c, 0x4f, 0x44, 0xb4, 0x4c, 0xde,
-	0x2b, 0x59, 0xbc, 0x9d, 0xc0, 0x73, 0x9c, 0xe8, 0xc7, 0x60, 0x8c, 0x18, 0xf3, 0x45, 0x18, 0x16,
-	0x1b, 0x9b, 0xef, 0x73, 0xa0, 0x36, 0x63, 0x7e, 0x3b, 0x81, 0x05, 0x7f, 0x6d, 0x1f, 0xd2, 0x5d,
-	0xf2, 0x16, 0xb5, 0x20, 0x27, 0xdc, 0x41, 0x74, 0x19, 0x7e, 0x2f, 0x57, 0x6a, 0xde, 0xda, 0x14,
-	0x0c, 0x2e, 0x1d, 0x55, 0xa3, 0xe0, 0xd6, 0xd9, 0xa8, 0xc3, 0xbb, 0x1a, 0x85, 0xb7, 0x4e, 0x46,
-	0x1d, 0xe0, 0xb7, 0xe3, 0x01, 0xae, 0x2b, 0x75, 0x2c, 0xc4, 0x6f, 0xa8, 0x10, 0x37, 0xd4, 0x96,
-	0x80, 0x78, 0x31, 0x10, 0x1f, 0x8f, 0x16, 0xe6, 0x3f, 0x93, 0x00, 0x5c, 0x89, 0x97, 0x52, 0x6c,
-	0x1b, 0x20, 0x20, 0xc7, 0x4e, 0xc8, 0x48, 0x40, 0x64, 0x71, 0x58, 0x69, 0xdc, 0x5f, 0x38, 0xdc,
-	0x8c, 0xa1, 0x8e, 0x23, 0x6a, 0xd9, 0x06, 0x34, 0x84, 0xee, 0x41, 0x69, 0xe2, 0xc5, 0x64, 0xe9,
-	0x03, 0xcc, 0x61, 0x4d, 0x0f, 0x60, 0x26, 0x01, 0xe5, 0x20, 0xfd, 0xa2, 0xd5, 0xab, 0x24, 0x50,
-	0x1e, 0x8c, 0xce, 0x61, 0xb7, 0x57, 0x49, 0x72, 0x54, 0xe7, 0x75, 0xaf, 0x92, 0x42, 0x00, 0xd9,
-	0xdd, 0xd6, 0x7e, 0xab, 0xd7, 0xaa, 0xa4, 0x51, 0x01, 0x32, 0x9d, 0xed, 0xde, 0x4e, 0xbb, 0x62,
-	0xa0, 0x22, 0xe4, 0x0e, 0x3b, 0xbd, 0xbd, 0xc3, 0x83, 0x6e, 0x25, 0xc3, 0x81, 0x9d, 0xc3, 0x83,
-	0x83, 0xd6, 0x4e, 0xaf, 0x92, 0xe5, 0x32, 0xda, 0xad, 0xed, 0xdd, 0x4a, 0x8e, 0x93, 0xf7, 0xf0,
-	0xf6, 0x4e, 0xab, 0x92, 0x6f, 0x66, 0xc1, 0x60, 0x53, 0x9f, 0x98, 0xbf, 0x4d, 0x42, 0xb6, 0x2b,
-	0x6d, 0xbc, 0xbb, 0xe4, 0xc8, 0x8b, 0x31, 0x26, 0x89, 0xff, 0xdb, 0xe3, 0xde, 0x99, 0x3b, 0x2e,
-	0xd7, 0xb0, 0xd7, 0xeb, 0x54, 0x12, 0x5c, 0x43, 0xbe, 0xea, 0x56, 0x92, 0x91, 0x86, 0x3d, 0x28,
-	0xec, 0x75, 0xb6, 0x6d, 0x3b, 0x20, 0x21, 0x6f, 0x54, 0x86, 0xe3, 0xbf, 0xfb, 0x52, 0x68, 0x97,
-	0xe3, 0xde, 0xe4, 0x10, 0xfa, 0x42, 0x60, 0x9f, 0xa8, 0x34, 0xfd, 0x68, 0x41, 0xe7, 0xbd, 0xce,
-	0xbb, 0x27, 0x8a, 0xf8, 0x49, 0xd3, 0x80, 0x94, 0xe3, 0x9b, 0x9b, 0x60, 0x70, 0x2c, 0xef, 0x7c,
-	0x47, 0x4e, 0x10, 0xca, 0x2a, 0x96, 0xc5, 0x12, 0xe0, 0x75, 0xd1, 0xb5, 0x42, 0x59, 0xf9, 0xb3,
-	0x58, 0xac, 0xcd, 0x7d, 0x80, 0xde, 0xd0, 0xd7, 0x8a, 0x3c, 0xe0, 0x52, 0x54, 0x71, 0xa9, 0x2d,
-	0xf9, 0xa0, 0xa2, 0xc3, 0x29, 0xc7, 0x17, 0x55, 0x96, 0xd7, 0xf8, 0x94, 0xa8, 0xf1, 0x62, 0x6d,
-	0xda, 0x90, 0x6e, 0x51, 0x2e, 0xa6, 0x72, 0x1c, 0xf8, 0xc3, 0xbe, 0xec, 0xc3, 0xfd, 0x21, 0xb5,
-	0x65, 0xec, 0x97, 0xdb, 0x09, 0xbc, 0xc2, 0x77, 0xba, 0x62, 0x63, 0x87, 0xda, 0x84, 0xd3, 0x06,
-	0x24, 0x24, 0xac, 0x4f, 0x82, 0x80, 0x06, 0x92, 0x36, 0xa5, 0x69, 0xc5, 0x4e, 0x8b, 0x6f, 0x70,
-	0xda, 0x66, 0x06, 0xd2, 0xc4, 0xb3, 0xcd, 0x7f, 0x97, 0x20, 0xdf, 0xb3, 0xfc, 0xd6, 0x3b, 0xde,
-	0xb2, 0x1e, 0x41, 0x56, 0x66, 0xa1, 0x52, 0xfb, 0xe3, 0xc5, 0x5c, 0x8d, 0xce, 0x87, 0x15, 0x29,
-	0x7a, 0x01, 0x45, 0xb9, 0xea, 0x8f, 0x09, 0xb3, 0x54, 0xdd, 0xb8, 0xbf, 0x2c, 0xcb, 0xc5, 0x47,
-	0xea, 0x2d, 0xcf, 0xf6, 0xa9, 0xe3, 0xb1, 0x97, 0x84, 0x59, 0x18, 0x24, 0x2b, 0x5f, 0xa3, 0xef,
-	0x43, 0x31, 0x56, 0x89, 0x94, 0xab, 0x2e, 0x54, 0x21, 0x4e, 0x8f, 0x5e, 0x41, 0x25, 0x06, 0x4a,
-	0x65, 0x8c, 0xf7, 0x52, 0x66, 0x35, 0xc6, 0x2f, 0x34, 0x7a, 0x05, 0xab, 0x7e, 0x40, 0xbf, 0x9b,
-	0xf6, 0x6d, 0x27, 0x90, 0xe5, 0x52, 0x74, 0xe1, 0x95, 0xc6, 0xfa, 0xf9, 0x12, 0x3b, 0x9c, 0x61,
-	0x57, 0xd3, 0xe3, 0x15, 0x7f, 0x0e, 0x46, 0x5f, 0xaa, 0xf2, 0x2a, 0x4b, 0xfd, 0xed, 0xf3, 0xe5,
-	0xcc, 0x15, 0xd3, 0xdf, 0x24, 0xa1, 0x14, 0x57, 0x15, 0xfd, 0x04, 0xb2, 0xae, 0x35, 0x20, 0xae,
-	0xae, 0xaa, 0x8d, 0xab, 0x1d, 0xb1, 0xbe, 0x2f, 0x98, 0x5a, 0x1e, 0x0b, 0xa6, 0x58, 0x49, 0xa8,
-	0x6d, 0x41, 0x31, 0x86, 0x46, 0x15, 0x48, 0x9f, 0x90, 0xa9, 0x1a, 0x81, 0xf9, 0x92, 0x67, 0xc0,
-	0x3b, 0xcb, 0x9d, 0xe8, 0x71, 0x5e, 0x02, 0xcf, 0x52, 0x4f, 0x93, 0xb5, 0x7f, 0xe5, 0x54, 0x5d,
-	0x3e, 0x84, 0x52, 0x20, 0x2b, 0x77, 0xdf, 0xf1, 0x1c, 0xdd, 0xf1, 0x1f, 0x5c, 0x7c, 0xbc, 0xba,
-	0x2a, 0xf6, 0x7b, 0x9e, 0xc3, 0xf8, 0xf0, 0x1a, 0xcc, 0x40, 0x84, 0xa1, 0x1c, 0xa8, 0x39, 0x5e,
-	0x4a, 0xbc, 0x60, 0x10, 0x98, 0x93, 0x28, 0x79, 0x94, 0xc8, 0x52, 0x10, 0x83, 0xa5, 0x92, 0x4a,
-	0x26, 0xf1, 0x6c, 0xe5, 0x83, 0x07, 0x57, 0x14, 0xd9, 0xf2, 0x6c, 0xa9, 0x64, 0x04, 0xd6, 0x9e,
-	0x40, 0xbe, 0xcb, 0x02, 0x62, 0x8d, 0xf7, 0xc4, 0xd5, 0x61, 0x60, 0x85, 0x2a, 0x37, 0xb1, 0x58,
-	0xcb, 0x61, 0x9a, 0xef, 0x0b, 0xed, 0x0d, 0xac, 0xa0, 0xda, 0x5f, 0x93, 0x50, 0x8c, 0x9d, 0x1d,
-	0x7d, 0x05, 0x29, 0xc7, 0x56, 0x36, 0xfb, 0xfc, 0x12, 0x75, 0xf4, 0x07, 0x71, 0xca, 0xb1, 0x79,
-	0xc2, 0xc6, 0x9a, 0xde, 0xb2, 0x6c, 0x99, 0xf5, 0x9f, 0xa8, 0x1f, 0x6e, 0x44, 0x3d, 0x54, 0x1a,
-	0xe0, 0xff, 0xce, 0xa9, 0xe0, 0x51, 0x6b, 0x9d, 0x9b, 0x10, 0x8d, 0xf3, 0x26, 0xc4, 0xcc, 0x6c,
-	0x42, 0xac, 0xfd, 0x31, 0x09, 0xa5, 0xb8, 0x2b, 0x3e, 0xfc, 0x84, 0x2f, 0x00, 0x89, 0xfb, 0x42,
-	0x7f, 0x2e, 0xbc, 0x52, 0x97, 0x8d, 0xf4, 0x15, 0xc1, 0x14, 0xb7, 0xf1, 0xa7, 0x50, 0xe4, 0xa9,
-	0xa4, 0xea, 0xa8, 0x38, 0x7a, 0x19, 0x03, 0x47, 0xc9, 0x02, 0x5a, 0xfb, 0x5d, 0x8a, 0x3b, 0x25,
-	0x72, 0xee, 0xff, 0x80, 0xca, 0x7b, 0x70, 0x5d, 0x0b, 0x8a, 0x67, 0x42, 0xfa, 0x32, 0x49, 0xd7,
-	0x94, 0xa4, 0x98, 0xfd, 0x3f, 0xe3, 0xf7, 0x6e, 0x25, 0x64, 0x30, 0x65, 0x44, 0x4e, 0x88, 0x06,
-	0x8e, 0x92, 0xac, 0xc9, 0x91, 0xe8, 0x3e, 0xa4, 0x09, 0x0d, 0x55, 0x0d, 0x5f, 0xbc, 0x30, 0xb7,
-	0x68, 0x88, 0x39, 0x01, 0x9f, 0x89, 0x08, 0x3f, 0xbd, 0xf9, 0x14, 0x56, 0xe6, 0x0b, 0x1e, 0x1f,
-	0x2c, 0x5e, 0x1f, 0xfc, 0xf4, 0xe0, 0xf0, 0x9b, 0x83, 0x4a, 0x82, 0x03, 0x7b, 0x07, 0xcd, 0xc3,
-	0xd7, 0x07, 0xbb, 0x95, 0x24, 0x2a, 0x41, 0xfe, 0xf0, 0x75, 0x4f, 0x42, 0xa9, 0x99, 0x88, 0x35,
-	0xc8, 0x6f, 0xfb, 0x8e, 0x68, 0x4c, 0xbc, 0xd2, 0x88, 0xd6, 0xa5, 0xaa, 0x8f, 0x04, 0xf8, 0x75,
-	0xac, 0xd0, 0xa1, 0xb6, 0x20, 0x09, 0xd1, 0x73, 0xc8, 0x0a, 0xb4, 0x2e, 0x7d, 0x77, 0x97, 0xdd,
-	0xeb, 0x25, 0x6d, 0xb4, 0xc2, 0x8a, 0xa5, 0xf6, 0xb7, 0x24, 0xe4, 0x35, 0x12, 0x61, 0x28, 0xf0,
-	0x2b, 0xa3, 0xe5, 0x78, 0x24, 0x50, 0x8e, 0x6e, 0x5c, 0x41, 0x58, 0x7d, 0x47, 0x33, 0x09, 0x90,
-	0x0f, 0x93, 0x91, 0x98, 0xda, 0x3b, 0x58, 0x99, 0xdf, 0x46, 0x55, 0xc8, 0x8d, 0x49, 0x18, 0x5a,
-	0xc7, 0xfa, 0x59, 0x41, 0x83, 0x3c, 0xaf, 0x66, 0xdf, 0x57, 0x4f, 0x25, 0x11, 0x82, 0xdb, 0xc2,
-	0x19, 0x73, 0x2e, 0xf9, 0x42, 0x22, 0x01, 0x5e, 0x52, 0x02, 0x62, 0x85, 0xd4, 0xd3, 0xf7, 0x73,
-	0x09, 0x09, 0x73, 0x0a, 0x63, 0x75, 0x20, 0xaf, 0x67, 0xe9, 0x8b, 0x9f, 0x4c, 0xc4, 0x85, 0x73,
-	0xea, 0xeb, 0xaa, 0x2e, 0xd6, 0xd1, 0x03, 0x48, 0x7a, 0xf6, 0x00, 0x62, 0xbe, 0x85, 0x6b, 0x0b,
-	0xd7, 0x06, 0xf4, 0x18, 0xf2, 0x01, 0x99, 0x1b, 0x16, 0x6e, 0x9d, 0x7b, 0xd9, 0xc0, 0x11, 0x29,
-	0x8f, 0x43, 0xd1, 0x75, 0xfa, 0xa1, 0x90, 0x44, 0xf5, 0xb9, 0xcb, 0x02, 0xdb, 0x55, 0x48, 0xf3,
-	0x5b, 0x28, 0x6b, 0x66, 0x69, 0xc4, 0x0f, 0xfc, 0x5c, 0x14, 0x4f, 0xa9, 0x78, 0x3c, 0xfd, 0x21,
-	0x05, 0x88, 0x27, 0x7d, 0x77, 0x32, 0x1e, 0x5b, 0xc1, 0x54, 0xdf, 0x57, 0x7f, 0x00, 0xf9, 0x48,
-	0xab, 0xab, 0xdf, 0x58, 0x23, 0x1e, 0x5e, 0x61, 0x98, 0x33, 0x26, 0xfd, 0x53, 0xc7, 0xb3, 0xe9,
-	0xa9, 0xfa, 0x24, 0x70, 0xd4, 0x37, 0x02, 0x83, 0xfe, 0x1f, 0x0c, 0x8f, 0x7a, 0xba, 0xec, 0xde,
-	0x5c, 0x4c, 0xaf, 0xb1, 0xcf, 0xa6, 0xbc, 0xe7, 0x73, 0x2a, 0xf4, 0x35, 0x14, 0x19, 0xed, 0x47,
-	0xa7, 0x36, 0x2e, 0x39, 0x35, 0x1f, 0xb2, 0x19, 0x8d, 0x5c, 0xff, 0x23, 0x28, 0x1f, 0x05, 0x74,
-	0x3c, 0xe3, 0xcf, 0x5c, 0xce, 0x5f, 0xe2, 0x1c, 0x1a, 0x6e, 0x02, 0xe4, 0xe9, 0x84, 0x0d, 0xe8,
-	0xc4, 0xb3, 0xcd, 0xbf, 0x24, 0xe1, 0xfa, 0x9c, 0xc5, 0xd4, 0x0b, 0xdb, 0x16, 0xa4, 0xe8, 0xc9,
-	0xb9, 0x35, 0x72, 0x09, 0x47, 0xfd, 0xf0, 0xa4, 0x9d, 0xc0, 0x29, 0x7a, 0x82, 0x9e, 0xc4, 0x5d,
-	0xb3, 0x6c, 0x12, 0x9a, 0x0b, 0x80, 0x76, 0x42, 0x39, 0xaf, 0xb6, 0x0d, 0xa9, 0xc3, 0x13, 0xf4,
-	0x1c, 0xc4, 0x53, 0x57, 0x9f, 0x59, 0x03, 0x37, 0xba, 0x5a, 0xd6, 0x96, 0x6a, 0xd0, 0xe3, 0x24,
-	0x18, 0x42, 0xbd, 0x0c, 0xf9, 0xc9, 0x74, 0xd9, 0x13, 0x97, 0xba, 0xa6, 0x15, 0x3a, 0x62, 0x8c,
-	0x0e, 0xd1, 0x5d, 0x28, 0x87, 0x93, 0xe1, 0x90, 0x84, 0x7c, 0xd2, 0x9e, 0x78, 0x72, 0x90, 0x31,
-	0x70, 0x49, 0x21, 0x77, 0x38, 0x8e, 0x13, 0x1d, 0x59, 0x8e, 0x3b, 0x09, 0x88, 0x22, 0x92, 0xdd,
-	0xbd, 0xa4, 0x90, 0x92, 0xe8, 0x1e, 0x8f, 0x74, 0x46, 0xbc, 0xe1, 0xb4, 0x3f, 0x0e, 0xfb, 0xfe,
-	0xe3, 0x4d, 0xe1, 0x76, 0x03, 0x97, 0x14, 0xf6, 0x65, 0xd8, 0x79, 0xbc, 0x79, 0x96, 0x6a, 0xeb,
-	0xb1, 0xaa, 0xcb, 0x31, 0xaa, 0xad, 0xc7, 0x0b, 0x54, 0x5b, 0xc2, 0x9b, 0xf3, 0x54, 0x5b, 0xe8,
-	0x01, 0x5c, 0x63, 0x6e, 0x18, 0x75, 0x1d, 0xa9, 0x5a, 0x56, 0x10, 0xae, 0x32, 0x57, 0xbf, 0xa3,
-	0x0a, 0xed, 0xcc, 0x7f, 0x18, 0x50, 0x88, 0x8c, 0x83, 0x9a, 0x50, 0xf0, 0xa9, 0xdd, 0x3f, 0x0e,
-	0xe8, 0x44, 0xdf, 0x58, 0xee, 0x9e, 0x6f, 0x4b, 0x5e, 0x08, 0x5f, 0x70, 0xd2, 0x76, 0x02, 0xe7,
-	0x7d, 0xb5, 0xae, 0xfd, 0xda, 0x10, 0x95, 0x55, 0x00, 0xe8, 0x39, 0x18, 0x01, 0x3d, 0xd5, 0x7e,
-	0xf9, 0xfc, 0x0a, 0xb2, 0xea, 0x98, 0x9e, 0x62, 0xc1, 0x54, 0xfb, 0x73, 0x1a, 0xd2, 0x98, 0x9e,
-	0x7e, 0x68, 0xce, 0x5f, 0x9a, 0x86, 0xeb, 0x50, 0x19, 0x93, 0x70, 0x44, 0xec, 0x3e, 0x3f, 0xb4,
-	0x34, 0x93, 0xf4, 0xcd, 0x8a, 0xc4, 0x77, 0xa8, 0x2d, 0x7d, 0xf8, 0x00, 0xae, 0x05, 0x13, 0xcf,
-	0x73, 0xbc, 0xe3, 0x18, 0xa9, 0x74, 0xd0, 0xaa, 0xda, 0x88, 0x68, 0xd7, 0xa1, 0xc2, 0xfd, 0x3f,
-	0x27, 0x55, 0x1a, 0x7f, 0x45, 0xe2, 0x23, 0xca, 0x87, 0x90, 0xe1, 0xc1, 0xa8, 0xdb, 0xec, 0xe2,
-	0xcc, 0x36, 0x8b, 0x47, 0x2c, 0x29, 0xd1, 0xb7, 0x50, 0x96, 0x0d, 0xac, 0x3f, 0x98, 0x72, 0xf9,
-	0xd5, 0x9c, 0x30, 0xec, 0xd3, 0x2b, 0x1a, 0xb6, 0x2e, 0x3b, 0x58, 0x73, 0xca, 0x5b, 0x98, 0x98,
-	0xfd, 0x8b, 0x64, 0x86, 0xa9, 0xbd, 0x81, 0xca, 0x59, 0x82, 0x25, 0xb7, 0x80, 0xcd, 0xf8, 0x2d,
-	0x60, 0x59, 0xb2, 0x45, 0x9d, 0x32, 0x76, 0x43, 0xe0, 0x7d, 0x49, 0xe4, 0x68, 0xe3, 0x17, 0x06,
-	0xa4, 0xb7, 0x7d, 0x07, 0xbd, 0x81, 0x62, 0xac, 0x2e, 0xa0, 0xbb, 0x17, 0x57, 0x0d, 0x11, 0xb2,
-	0xb5, 0x7b, 0x57, 0x29, 0x2d, 0x66, 0x02, 0xbd, 0x82, 0xbc, 0xfe, 0x13, 0x00, 0xad, 0x2d, 0xf0,
-	0x9c, 0xf9, 0x43, 0xa1, 0x76, 0xe7, 0x02, 0x8a, 0x48, 0xe4, 0x2e, 0xa4, 0x7b, 0x96, 0x8f, 0x3e,
-	0x5e, 0x36, 0x00, 0x6a, 0x41, 0xb7, 0xce, 0x9d, 0x0e, 0xcd, 0xf4, 0x2f, 0x53, 0xc9, 0xcd, 0x24,
-	0x7a, 0x0d, 0xe5, 0xb9, 0x57, 0x2e, 0xf4, 0xd9, 0x95, 0x5e, 0xc1, 0x2e, 0x92, 0x9c, 0xd8, 0x4c,
-	0xa2, 0x6d, 0xc8, 0xe9, 0xbf, 0x5d, 0xce, 0xe9, 0x26, 0xb5, 0x4f, 0x16, 0xf0, 0xb1, 0xbf, 0x72,
-	0xcc, 0x04, 0x72, 0xa1, 0xd0, 0x25, 0xee, 0xd1, 0xce, 0x88, 0x0c, 0x4f, 0xd0, 0xf7, 0x66, 0xc4,
-	0xf2, 0x5f, 0xa1, 0x7a, 0xfc, 0x5f, 0xa1, 0x88, 0x4e, 0x6b, 0x57, 0xbf, 0x2a, 0xb9, 0xb6, 0x66,
-	0xf3, 0xd1, 0x9b, 0x87, 0xc7, 0x0e, 0x1b, 0x4d, 0x06, 0x9c, 0x61, 0x43, 0x71, 0xeb, 0xdf, 0xc6,
-	0xc6, 0xec, 0xad, 0x7f, 0xe3, 0x98, 0x78, 0x1b, 0x52, 0xe1, 0x41, 0x56, 0x4c, 0xb8, 0x8f, 0xfe,
-	0x13, 0x00, 0x00, 0xff, 0xff, 0xff, 0x14, 0xb8, 0x67, 0xe9, 0x1a, 0x00, 0x00,
+	// 2686 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcd, 0x19, 0x4d, 0x73, 0x1b, 0x59,
+	0x71, 0xf5, 0x69, 0xa9, 0x25, 0xd9, 0xca, 0xcb, 0xc7, 0x2a, 0xb3, 0x5b, 0xd9, 0x44, 0xc9, 0x66,
+	0x53, 0x59, 0x90, 0x1d, 0xe7, 0x83, 0x38, 0x2c, 0x0b, 0x96, 0x2d, 0x62, 0x83, 0x63, 0x6b, 0x47,
+	0x0a, 0x5b, 0xa4, 0x28, 0x54, 0x23, 0xcd, 0xd8, 0x1e, 0x32, 0x9a, 0x99, 0xcc, 0x8c, 0x92, 0xe8,
+	0x1f, 0xf0, 0x03, 0xe0, 0xcc, 0x19, 0x4e, 0xf0, 0x37, 0xa8, 0xe2, 0xcc, 0x61, 0xab, 0xe0, 0xc6,
+	0x95, 0x0b, 0xc5, 0x11, 0xe8, 0x7e, 0x1f, 0xa3, 0x91, 0x25, 0xdb, 0x4a, 0xe0, 0xc0, 0x49, 0xaf,
+	0xfb, 0x75, 0xf7, 0xf4, 0xeb, 0xef, 0xf7, 0x04, 0x65, 0x7f, 0xd4, 0x77, 0xec, 0x41, 0xc3, 0x0f,
+	0xbc, 0xc8, 0x63, 0x2b, 0x8e, 0xed, 0xbe, 0xb4, 0x02, 0x73, 0xbd, 0x21, 0xd0, 0xda, 0xb5, 0x23,
+	0xcf, 0x3b, 0x72, 0xac, 0x55, 0xbe, 0xdd, 0x1f, 0x1d, 0xae, 0x9a, 0xa3, 0xc0, 0x88, 0x6c, 0xcf,
+	0x15, 0x0c, 0x5a, 0x6d, 0xe0, 0x0d, 0x87, 0x9e, 0xbb, 0x7a, 0x6c, 0x19, 0x4e, 0x74, 0x3c, 0x38,
+	0xb6, 0x06, 0x2f, 0xc5, 0x4e, 0x7d, 0x09, 0x72, 0xad, 0xa1, 0x1f, 0x8d, 0xeb, 0xaf, 0xa0, 0xf4,
+	0x13, 0x2b, 0x08, 0x91, 0x67, 0xd7, 0x3d, 0xf4, 0xd8, 0xc7, 0x50, 0x3c, 0xf2, 0x24, 0xa2, 0x96,
+	0xba, 0x9e, 0xba, 0x53, 0xd4, 0x27, 0x08, 0xda, 0xed, 0x8f, 0x6c, 0xc7, 0xdc, 0x36, 0x22, 0xab,
+	0x96, 0x16, 0xbb, 0x31, 0x82, 0xdd, 0x86, 0xe5, 0xc0, 0x72, 0x2c, 0x23, 0xb4, 0x94, 0x80, 0x0c,
+	0x27, 0x39, 0x81, 0xad, 0xaf, 0xc2, 0xca, 0x9e, 0x1d, 0x46, 0x6d, 0xcf, 0x0c, 0x75, 0xeb, 0xd5,
+	0xc8, 0x0a, 0x23, 0x12, 0xec, 0x1a, 0x43, 0x2b, 0xf4, 0x8d, 0x81, 0xa5, 0x3e, 0x1b, 0x23, 0xea,
+	0x5f, 0x40, 0x75, 0xc2, 0x10, 0xfa, 0x9e, 0x1b, 0x5a, 0xec, 0x0e, 0x64, 0x7d, 0x84, 0x91, 0x38,
+	0x73, 0xa7, 0xb4, 0x7e, 0xa9, 0x71, 0xc2, 0x34, 0x0d, 0x24, 0xd6, 0x39, 0x45, 0xfd, 0xf7, 0x59,
+	0xc8, 0x20, 0xc4, 0x18, 0x64, 0x49, 0xa4, 0x14, 0xcf, 0xd7, 0xec, 0x12, 0xe4, 0x90, 0x66, 0xb7,
+	0x2d, 0x0f, 0x23, 0x00, 0x76, 0x1d, 0xc0, 0xb4, 0x7c, 0xc7, 0x1b, 0x0f, 0x2d, 0x37, 0x12, 0x87,
+	0xd8, 0xf9, 0x40, 0x4f, 0xe0, 0xd8, 0x0d, 0x28, 0x05, 0x08, 0xd9, 0x03, 0xa3, 0x17, 0x5a, 0x51,
+	0x0d, 0x14, 0x89, 0x44, 0x76, 0xac, 0x88, 0x7d, 0x07, 0xae, 0x48, 0x88, 0x1c, 0xd2, 0x1b, 0x78,
+	0x6e, 0x14, 0x78, 0x8e, 0x63, 0x05, 0xb5, 0x92, 0xa4, 0xbe, 0x9c, 0xd8, 0xdf, 0x8a, 0xb7, 0xd9,
+	0x4d, 0x28, 0x87, 0x11, 0xda, 0xf3, 0x70, 0xe4, 0x70, 0xe1, 0x65, 0x49, 0x5e, 0x52, 0x58, 0x92,
+	0xfe, 0x09, 0xaa, 0x68, 0x58, 0xe8, 0x5b, 0x4e, 0x52, 0x91, 0x24, 0x45, 0x81, 0x23, 0x02, 0x06,
+	0x99, 0x5f, 0x78, 0xfd, 0xda, 0xb2, 0xdc, 0x21, 0x80, 0x5d, 0x81, 0x3c, 0xc9, 0x18, 0x85, 0xb5,
+	0x2c, 0x3f, 0xae, 0x84, 0xc8, 0x0a, 0x86, 0x69, 0x5a, 0x66, 0x2d, 0x87, 0xe8, 0x82, 0x2e, 0x00,
+	0xb6, 0x05, 0x2b, 0xa1, 0xed, 0x0e, 0xac, 0x3d, 0x23, 0x8c, 0x74, 0xcb, 0xf7, 0x82, 0xa8, 0x96,
+	0xc7, 0xfd, 0xd2, 0xfa, 0xd5, 0x86, 0x08, 0xbb, 0x86, 0x0a, 0xbb, 0xc6, 0xb6, 0x0c, 0x3b, 0xfd,
+	0x24, 0x07, 0x5b, 0x83, 0x8b, 0x93, 0x93, 0xef, 0xc7, 0x2e, 0x5e, 0xe2, 0xdf, 0x9f, 0xb7, 0xc5,
+	0xea, 0x50, 0x96, 0xe8, 0xb6, 0x63, 0xb8, 0x56, 0xad, 0xc0, 0x75, 0x9a, 0xc2, 0xb1, 0x7b, 0x90,
+	0x1f, 0xf9, 0x91, 0x8d, 0xce, 0x2c, 0x9e, 0xa7, 0x91, 0x24, 0x24, 0xb1, 0xb8, 0xf9, 0x76, 0xac,
+	0x42, 0x73, 0x85, 0x6b, 0x30, 0x85, 0x6b, 0x62, 0x52, 0x78, 0x6f, 0x5c, 0x2b, 0xa8, 0xff, 0x2e,
+	0x0d, 0xd0, 0x35, 0x7c, 0x15, 0x9d, 0x68, 0x4b, 0x0c, 0x0c, 0x11, 0x38, 0x64, 0x4b, 0x04, 0x4e,
+	0xc4, 0x48, 0x7a, 0x4e, 0x8c, 0xa0, 0xb5, 0x87, 0xc6, 0x5b, 0xdd, 0x0f, 0x79, 0x04, 0xa5, 0x75,
+	0x09, 0x11, 0x3e, 0xf2, 0xda, 0x64, 0x4e, 0xf2, 0x42, 0x45, 0x97, 0x10, 0xc5, 0x67, 0xe4, 0x61,
+	0x28, 0xe6, 0x44, 0x7c, 0xd2, 0x9a, 0x69, 0x50, 0x38, 0x0c, 0xbc, 0x61, 0x5b, 0x19, 0xbf, 0xa2,
+	0xc7, 0x30, 0xc9, 0xa1, 0x35, 0x72, 0x08, 0x6b, 0x4a, 0x88, 0x7b, 0x19, 0x53, 0x7d, 0x28, 0x4c,
+	0x47, 0x5e, 0xe6, 0x10, 0xd7, 0xc7, 0x8a, 0x8e, 0xf1, 0x20, 0x45, 0x81, 0x17, 0x10, 0xe5, 0x9e,
+	0x31, 0xc2, 0x55, 0x60, 0x47, 0x63, 0x11, 0xc9, 0xfa, 0x04, 0x41, 0x5a, 0xf9, 0x46, 0x74, 0x2c,
+	0x82, 0x56, 0xe7, 0xeb, 0x27, 0xe9, 0x5a, 0xaa, 0x59, 0xc0, 0x53, 0x18, 0xc1, 0x91, 0x15, 0xd5,
+	0xff, 0x54, 0x84, 0x4b, 0x68, 0xac, 0xe6, 0x18, 0x73, 0xd3, 0x1b, 0x05, 0x03, 0x4b, 0x99, 0xed,
+	0x89, 0x22, 0xe1, 0x96, 0x2b, 0xad, 0xd7, 0x67, 0x92, 0x54, 0x71, 0x74, 0xb0, 0x40, 0x0c, 0x84,
+	0xbb, 0x04, 0x07, 0xdb, 0x84, 0xdc, 0xd0, 0x88, 0x06, 0xc7, 0xdc, 0xb2, 0xa5, 0xf5, 0xcf, 0x67,
+	0x58, 0xe7, 0x7d, 0xb1, 0xf1, 0x8c, 0x58, 0x74, 0xc1, 0x79, 0xaa, 0xfd, 0x37, 0x00, 0x86, 0xb6,
+	0xbb, 0x87, 0xb9, 0xe4, 0x0e, 0xc6, 0xdc, 0x07, 0x67, 0x06, 0x50, 0x82, 0x98, 0xfd, 0x94, 0x2a,
+	0x9c, 0x28, 0x40, 0x1d, 0x91, 0x48, 0x39, 0xce, 0x7e, 0x6f, 0x31, 0xf5, 0x04, 0x8f, 0x6e, 0xb8,
+	0x47, 0x96, 0x7e, 0x42, 0x10, 0xbb, 0x06, 0x10, 0x1a, 0x43, 0xdf, 0xb1, 0x74, 0xaa, 0xad, 0x79,
+	0xae, 0x71, 0x02, 0xc3, 0x7e, 0x0e, 0x2b, 0x64, 0xfb, 0x4e, 0x8c, 0x09, 0xd1, 0xed, 0x54, 0xfa,
+	0x1e, 0x2c, 0xf6, 0xed, 0xf6, 0x14, 0xb3, 0x7e, 0x52, 0x18, 0xe5, 0xc7, 0x51, 0xe0, 0x0f, 0x84,
+	0x36, 0x28, 0xbc, 0x80, 0xc2, 0x2b, 0xfa, 0x14, 0x4e, 0xbb, 0x07, 0xa5, 0xc4, 0x11, 0x58, 0x15,
+	0x32, 0x68, 0x1b, 0xee, 0xdc, 0x8a, 0x4e, 0x4b, 0x8e, 0x31, 0xde, 0x72, 0x9f, 0x11, 0xc6, 0x78,
+	0xab, 0xfd, 0x33, 0x0b, 0x39, 0xee, 0x15, 0x2c, 0x27, 0x19, 0xc3, 0x71, 0x64, 0x28, 0xac, 0xbe,
+	0x83, 0x3f, 0x1b, 0x1d, 0xeb, 0x15, 0x65, 0x1d, 0x72, 0x73, 0x21, 0xee, 0x58, 0x06, 0xc5, 0x7b,
+	0x09, 0x71, 0xc7, 0xec, 0xfb, 0x90, 0x71, 0x3d, 0x51, 0xd7, 0xdf, 0x2d, 0xb2, 0x48, 0x00, 0x72,
+	0xb2, 0x1d, 0x28, 0x9b, 0x88, 0xb4, 0x5d, 0x1e, 0x21, 0xa1, 0x8c, 0xa1, 0x05, 0xc2, 0x1b, 0x05,
+	0x4c, 0x71, 0xb2, 0x1f, 0x42, 0xf6, 0x38, 0x8a, 0x7c, 0x19, 0x46, 0x6b, 0xef, 0x72, 0xa0, 0x1d,
+	0xe4, 0x43, 0x79, 0x9c, 0x9f, 0x7d, 0x09, 0x4b, 0x82, 0x26, 0x94, 0x35, 0x7a, 0x31, 0x65, 0x14,
+	0x93, 0xb6, 0x07, 0x19, 0x34, 0x10, 0x6b, 0xc1, 0x12, 0xcf, 0x1d, 0x4b, 0xf5, 0xd5, 0x77, 0xca,
+	0x3b, 0xc5, 0xab, 0x8d, 0x21, 0x4b, 0xda, 0xb1, 0x5a, 0x5c, 0x89, 0x54, 0xe9, 0x54, 0xb5, 0xa8,
+	0x16, 0xd7, 0x22, 0x55, 0x39, 0x55, 0x35, 0xba, 0x96, 0xac, 0x46, 0xaa, 0xf5, 0x26, 0xea, 0xd1,
+	0x25, 0x59, 0x8f, 0xb2, 0x72, 0x8b, 0x43, 0x54, 0xb9, 0xf9, 0xc7, 0xe3, 0x85, 0xf6, 0x18, 0x96,
+	0xa7, 0x43, 0x3e, 0xae, 0x64, 0xa9, 0x49, 0x25, 0x23, 0x5c, 0xa0, 0x66, 0x99, 0xb4, 0xce, 0xd7,
+	0xf5, 0x7f, 0xa4, 0x00, 0x48, 0xfd, 0x67, 0x42, 0xa1, 0x1d, 0xc0, 0xae, 0x7e, 0x84, 0xe3, 0x87,
+	0x15, 0x58, 0xa2, 0x07, 0x2c, 0xaf, 0xdf, 0x9e, 0x31, 0xcb, 0x84, 0x01, 0x0d, 0xad, 0xa8, 0xc5,
+	0x44, 0xa0, 0x20, 0x76, 0x0b, 0xca, 0x23, 0x37, 0x21, 0x4b, 0x1d, 0x7d, 0x0a, 0x5b, 0x77, 0x01,
+	0x26, 0x12, 0xd8, 0x12, 0x64, 0x9e, 0xb6, 0xba, 0xd5, 0x0f, 0x58, 0x01, 0xb2, 0xed, 0x83, 0x4e,
+	0xb7, 0x9a, 0x22, 0x54, 0xfb, 0x79, 0xb7, 0x9a, 0x66, 0x00, 0xf9, 0xed, 0xd6, 0x5e, 0xab, 0xdb,
+	0xaa, 0x66, 0x58, 0x11, 0x72, 0xed, 0xcd, 0xee, 0xd6, 0x4e, 0x35, 0xcb, 0x4a, 0xb0, 0x74, 0xd0,
+	0xee, 0xee, 0x1e, 0xec, 0x77, 0xaa, 0x39, 0x02, 0xb6, 0x0e, 0xf6, 0xf7, 0x5b, 0x5b, 0xdd, 0x6a,
+	0x9e, 0x64, 0xec, 0xb4, 0x36, 0xb7, 0xab, 0x4b, 0x44, 0xde, 0xd5, 0x37, 0xb7, 0x5a, 0xd5, 0x42,
+	0x33, 0x8f, 0x6d, 0x67, 0xec, 0x5b, 0xf5, 0xdf, 0xa4, 0x20, 0xdf, 0x11, 0xde, 0xd9, 0x9e, 0x73,
+	0xe4, 0xd9, 0x80, 0x12, 0xc4, 0xff, 0xed, 0x71, 0x6f, 0x4c, 0x1d, 0x97, 0x34, 0xec, 0x76, 0xdb,
+	0x78, 0x5e, 0xd4, 0x90, 0x56, 0x9d, 0x6a, 0x2a, 0xd6, 0xb0, 0x0b, 0xc5, 0xdd, 0xf6, 0xa6, 0x69,
+	0x62, 0xe5, 0xa4, 0x99, 0x25, 0x6b, 0xfb, 0xaf, 0x1f, 0x70, 0xed, 0x96, 0x28, 0x0e, 0x08, 0x62,
+	0x9f, 0x73, 0xec, 0x23, 0x59, 0x20, 0x2e, 0xcf, 0xe8, 0xbc, 0xdb, 0x7e, 0xfd, 0x48, 0x12, 0x3f,
+	0x6a, 0x66, 0x21, 0x6d, 0xfb, 0xf5, 0x35, 0xc8, 0x12, 0x96, 0x86, 0xa0, 0x43, 0x3b, 0x08, 0x45,
+	0xb3, 0xca, 0xeb, 0x02, 0xa0, 0x00, 0x71, 0x70, 0x9a, 0xe1, 0x02, 0xf3, 0x3a, 0x5f, 0xd7, 0xf7,
+	0x70, 0x38, 0x18, 0xf8, 0x4a, 0x91, 0xbb, 0x24, 0x45, 0x96, 0x35, 0x6d, 0xce, 0x07, 0x25, 0x9d,
+	0x8e, 0x54, 0x3c, 0x04, 0xa9, 0x95, 0x8b, 0x02, 0xc9, 0xd7, 0x75, 0x13, 0x32, 0x2d, 0x8f, 0xc4,
+	0x54, 0xa9, 0xd6, 0xf6, 0xc4, 0x48, 0x86, 0xe3, 0xa2, 0x29, 0xb2, 0xa6, 0x82, 0xea, 0x2e, 0x4f,
+	0xaa, 0xf0, 0x16, 0xe2, 0x89, 0x16, 0x45, 0x5a, 0x51, 0xcf, 0x0a, 0x02, 0x2f, 0x10, 0xb4, 0x69,
+	0x45, 0xcb, 0x77, 0x5a, 0xb4, 0x41, 0xb4, 0xcd, 0x1c, 0x64, 0x2c, 0xd7, 0xac, 0xff, 0xbb, 0x0c,
+	0x05, 0x4c, 0xdd, 0xd6, 0x6b, 0x9a, 0x4c, 0xee, 0x63, 0x5e, 0xf2, 0xfc, 0x95, 0x6a, 0x7f, 0x34,
+	0x9b, 0xe5, 0xf1, 0xf9, 0x74, 0x49, 0xca, 0x9e, 0x42, 0x49, 0xac, 0x7a, 0x98, 0xa9, 0x86, 0xac,
+	0x58, 0xb7, 0xe7, 0xd5, 0x07, 0xfe, 0x91, 0x46, 0xcb, 0x35, 0x7d, 0xcf, 0x76, 0x23, 0xcc, 0x0a,
+	0x03, 0x3b, 0x19, 0x67, 0xa5, 0x35, 0xfb, 0x1e, 0x94, 0x12, 0x35, 0x50, 0xba, 0xea, 0x4c, 0x15,
+	0x92, 0xf4, 0xec, 0x2b, 0xa8, 0x26, 0x40, 0xa1, 0x4c, 0xf6, 0x9d, 0x94, 0x59, 0x49, 0xf0, 0x73,
+	0x8d, 0xbe, 0xc2, 0xde, 0x4a, 0x73, 0x60, 0xcf, 0xb4, 0x03, 0x51, 0x1b, 0x79, 0x15, 0x5d, 0x5e,
+	0xbf, 0x73, 0xba, 0xc4, 0x36, 0x31, 0x6c, 0x2b, 0x7a, 0x7d, 0xd9, 0x9f, 0x82, 0xd9, 0x03, 0x59,
+	0xd8, 0x45, 0x93, 0xb9, 0x76, 0xba, 0x9c, 0x64, 0x19, 0xd7, 0x7e, 0x9d, 0x82, 0x72, 0x52, 0x55,
+	0xf6, 0x23, 0xc8, 0x3b, 0x46, 0xdf, 0x72, 0x54, 0x3d, 0x5e, 0x5f, 0xec, 0x88, 0x8d, 0x3d, 0xce,
+	0xd4, 0xc2, 0x91, 0x79, 0xac, 0x4b, 0x09, 0xda, 0x06, 0x94, 0x12, 0x68, 0xea, 0xd5, 0x2f, 0xad,
+	0xb1, 0xac, 0x86, 0xb4, 0xa4, 0x0c, 0x78, 0x6d, 0x38, 0x23, 0x75, 0xb3, 0x13, 0xc0, 0x93, 0xf4,
+	0xe3, 0x94, 0xf6, 0xaf, 0x25, 0x59, 0xd1, 0x0f, 0xa0, 0x1c, 0x88, 0x9a, 0xdf, 0xb3, 0x5d, 0x5b,
+	0x0d, 0x76, 0x77, 0xcf, 0x3e, 0x5e, 0x43, 0xb6, 0x89, 0x5d, 0xe4, 0xa0, 0x7b, 0x4c, 0x30, 0x01,
+	0x99, 0x0e, 0x15, 0x35, 0x08, 0x09, 0x89, 0x67, 0xcc, 0x7b, 0x53, 0x12, 0x05, 0x8f, 0x14, 0x59,
+	0x0e, 0x12, 0xb0, 0x50, 0x52, 0xca, 0xc4, 0xd8, 0x97, 0x3e, 0xb8, 0xbb, 0xa0, 0x48, 0xb4, 0xa3,
+	0x50, 0x32, 0x06, 0xb5, 0x47, 0x50, 0xe8, 0x44, 0x81, 0x65, 0x0c, 0x77, 0xf9, 0x2d, 0xb2, 0x8f,
+	0x77, 0x59, 0x39, 0xf5, 0xf0, 0xb5, 0xb8, 0x57, 0xd1, 0x3e, 0xd7, 0x3e, 0xab, 0x4b, 0x48, 0xfb,
+	0x4b, 0x0a, 0x4a, 0x89, 0xb3, 0xe3, 0x95, 0x30, 0x6d, 0x9b, 0xd2, 0x66, 0x9f, 0x9d, 0xa3, 0x8e,
+	0xfa, 0x20, 0xd6, 0x0d, 0x93, 0x12, 0x36, 0xd1, 0x2e, 0xe7, 0x65, 0xcb, 0xa4, 0xff, 0xc4, 0x9d,
+	0x74, 0x35, 0xee, 0xbe, 0xc2, 0x00, 0x1f, 0x9e, 0x52, 0xc1, 0xe3, 0xa6, 0x3c, 0x75, 0x11, 0xc8,
+	0x9e, 0x76, 0x11, 0xc8, 0x4d, 0xda, 0xa7, 0xf6, 0x07, 0x8c, 0xd7, 0xa4, 0x2b, 0xde, 0xff, 0x84,
+	0x4f, 0x81, 0xf1, 0xab, 0x63, 0x6f, 0x2a, 0xbc, 0xd2, 0xe7, 0x0d, 0xe7, 0x55, 0xce, 0x94, 0xb4,
+	0xf1, 0x27, 0x50, 0xa2, 0x54, 0x92, 0x75, 0x94, 0x1f, 0xbd, 0xa2, 0x03, 0xa1, 0x44, 0x01, 0xd5,
+	0x7e, 0x9b, 0x26, 0xa7, 0xc4, 0xce, 0xfd, 0x3f, 0x50, 0x79, 0x17, 0x2e, 0x2a, 0x41, 0xc9, 0x4c,
+	0xc8, 0x9c, 0x27, 0xe9, 0x82, 0x94, 0x94, 0xb0, 0xff, 0xa7, 0x93, 0x0b, 0x4a, 0xaf, 0x3f, 0xa6,
+	0x4b, 0x42, 0x96, 0x47, 0x64, 0x9c, 0x64, 0x4d, 0x42, 0xb2, 0xdb, 0xd8, 0x14, 0x3c, 0x75, 0x79,
+	0x99, 0x7d, 0x3b, 0xc1, 0x7e, 0xa4, 0x13, 0x01, 0x4d, 0x53, 0x16, 0x9d, 0xbe, 0x4e, 0xd3, 0xd4,
+	0x74, 0x81, 0xc3, 0xc1, 0xe2, 0xf9, 0xfe, 0x8f, 0xf7, 0x0f, 0xbe, 0xde, 0xc7, 0x66, 0x8d, 0xc0,
+	0xee, 0x7e, 0xf3, 0xe0, 0xf9, 0xfe, 0x36, 0xce, 0x27, 0xd8, 0x69, 0x0e, 0x9e, 0x77, 0x05, 0x94,
+	0x9e, 0x88, 0xb8, 0x0e, 0x85, 0x4d, 0xdf, 0xe6, 0x8d, 0x89, 0x2a, 0x0d, 0x6f, 0x5d, 0xb2, 0xfa,
+	0x08, 0x80, 0x6e, 0xdd, 0xc5, 0xb6, 0x67, 0x72, 0x92, 0x90, 0x7d, 0x17, 0xf2, 0x1c, 0xad, 0x4a,
+	0xdf, 0xcd, 0x79, 0x4f, 0x3c, 0x82, 0x36, 0x5e, 0xe9, 0x92, 0x45, 0xfb, 0x6b, 0x0a, 0x0a, 0x0a,
+	0x89, 0x35, 0xa6, 0x48, 0xaf, 0x07, 0x86, 0x8d, 0x57, 0x7b, 0xe9, 0xe8, 0xf5, 0x05, 0x84, 0x35,
+	0xb6, 0x14, 0x13, 0x07, 0x69, 0x0c, 0x8d, 0xc5, 0x68, 0xaf, 0x61, 0x79, 0x7a, 0x1b, 0x47, 0xda,
+	0xa5, 0x21, 0x36, 0x2b, 0xe3, 0x48, 0xbd, 0x30, 0x29, 0x90, 0xf2, 0x6a, 0xf2, 0x7d, 0xf9, 0x6a,
+	0x16, 0x23, 0xc8, 0x16, 0xf6, 0x90, 0xb8, 0xc4, 0x63, 0x99, 0x00, 0xa8, 0xa4, 0x60, 0xa8, 0x85,
+	0xd8, 0x89, 0xe4, 0x53, 0x8d, 0x80, 0xb8, 0x39, 0xb9, 0xb1, 0xda, 0x50, 0x50, 0x53, 0xf8, 0xd9,
+	0xaf, 0x67, 0xfc, 0x5d, 0x01, 0xc7, 0x27, 0xf9, 0x65, 0xbe, 0x8e, 0xdf, 0xc2, 0x32, 0x93, 0xb7,
+	0xb0, 0xfa, 0x2b, 0xb8, 0x30, 0x73, 0x47, 0x60, 0x0f, 0xa1, 0x10, 0x58, 0x53, 0xc3, 0xc2, 0xd5,
+	0x53, 0x6f, 0x16, 0x7a, 0x4c, 0x4a, 0x71, 0xc8, 0xbb, 0x4e, 0x2f, 0xe4, 0x92, 0x3c, 0x75, 0xee,
+	0x0a, 0xc7, 0x76, 0x24, 0xb2, 0xfe, 0x33, 0xa8, 0x28, 0x66, 0x61, 0xc4, 0xf7, 0xfc, 0x5c, 0x1c,
+	0x4f, 0xe9, 0x64, 0x3c, 0x7d, 0x93, 0x01, 0x46, 0x49, 0xdf, 0x19, 0x0d, 0x87, 0x06, 0x36, 0x42,
+	0xf9, 0x2c, 0xf1, 0x25, 0x14, 0x62, 0xad, 0x16, 0x7f, 0x98, 0x88, 0x79, 0xa8, 0xc2, 0xd0, 0x8b,
+	0x52, 0xef, 0x8d, 0xed, 0x9a, 0xde, 0x1b, 0xf9, 0x49, 0x20, 0xd4, 0xd7, 0x1c, 0xc3, 0xbe, 0x85,
+	0xc6, 0xf5, 0x5c, 0x55, 0x76, 0xaf, 0xcc, 0xa6, 0x17, 0x3d, 0xbc, 0x52, 0xcf, 0x27, 0x2a, 0xf6,
+	0x05, 0x8a, 0xf3, 0x7a, 0xf1, 0xa9, 0xb3, 0xe7, 0x9c, 0x9a, 0x86, 0xec, 0xc8, 0x8b, 0x5d, 0xff,
+	0x03, 0xa8, 0xd0, 0xb3, 0xcf, 0x84, 0x3f, 0x77, 0x3e, 0x7f, 0x99, 0x38, 0x62, 0x09, 0xb7, 0x00,
+	0xc7, 0x4b, 0x6f, 0xe4, 0x63, 0xbd, 0xe8, 0x71, 0xef, 0xf0, 0xd9, 0xa7, 0x48, 0x57, 0x7f, 0xc4,
+	0x36, 0xc7, 0x7c, 0x66, 0x60, 0x7b, 0x70, 0xc9, 0x30, 0x4d, 0x9b, 0x4c, 0x61, 0x38, 0xf1, 0xd7,
+	0xd4, 0x1b, 0xc4, 0x19, 0x4e, 0xba, 0x38, 0x61, 0x53, 0xb8, 0x90, 0x3d, 0x82, 0x0f, 0x13, 0xd2,
+	0x12, 0xd6, 0x14, 0xef, 0x0e, 0x45, 0xfd, 0xf2, 0x64, 0xbb, 0x1b, 0x1b, 0x36, 0x6c, 0x02, 0x14,
+	0xbc, 0x51, 0xd4, 0xf7, 0x46, 0x38, 0xd1, 0xfe, 0x39, 0x05, 0x17, 0xa7, 0xbc, 0x2b, 0x1f, 0x86,
+	0x37, 0x20, 0xed, 0xbd, 0x3c, 0xb5, 0x9e, 0xcf, 0xe1, 0x68, 0x1c, 0xbc, 0x44, 0xa3, 0x20, 0x13,
+	0xaa, 0x95, 0x08, 0xa3, 0x79, 0x53, 0xdb, 0x54, 0xb0, 0x22, 0x93, 0x20, 0xd7, 0x36, 0x21, 0x7d,
+	0xf0, 0x12, 0x0b, 0x16, 0x7f, 0xa1, 0xed, 0x45, 0x46, 0xdf, 0x89, 0x2f, 0xd0, 0xda, 0x5c, 0x0d,
+	0xba, 0x44, 0x82, 0x43, 0xb1, 0x5a, 0xf2, 0x93, 0xa9, 0x12, 0xcd, 0x2f, 0xa0, 0x4d, 0x23, 0xb4,
+	0xf9, 0xc8, 0x1f, 0xb2, 0x9b, 0x50, 0x09, 0x47, 0x03, 0x34, 0x1b, 0xdd, 0x0a, 0x46, 0xae, 0x18,
+	0xba, 0xb2, 0x7a, 0x59, 0x22, 0xb7, 0x08, 0x47, 0x44, 0x87, 0x86, 0xed, 0x8c, 0x02, 0x4b, 0x12,
+	0x89, 0x49, 0xa4, 0x2c, 0x91, 0x82, 0xe8, 0x16, 0x65, 0x25, 0x7f, 0xc9, 0xea, 0x0d, 0xc3, 0x9e,
+	0xff, 0x70, 0x8d, 0x87, 0x28, 0x52, 0x49, 0xec, 0xb3, 0xb0, 0xfd, 0x70, 0xed, 0x24, 0xd5, 0xc6,
+	0x43, 0xd9, 0x43, 0x12, 0x54, 0x1b, 0x0f, 0x67, 0xa8, 0x36, 0x78, 0xe4, 0x4d, 0x53, 0x6d, 0xe0,
+	0x4d, 0xe5, 0x42, 0xe4, 0x84, 0x71, 0x87, 0x14, 0xaa, 0xe5, 0x39, 0xe1, 0x0a, 0x6e, 0xc8, 0x94,
+	0xe4, 0xda, 0xd5, 0xff, 0x9e, 0x85, 0x62, 0x6c, 0x1c, 0xd6, 0x84, 0xa2, 0xef, 0x99, 0x3d, 0x1e,
+	0x84, 0xd2, 0x9b, 0x37, 0x4f, 0xb7, 0x25, 0x15, 0xed, 0xa7, 0x44, 0x8a, 0x4e, 0x29, 0xf8, 0x72,
+	0xad, 0xfd, 0x2a, 0xcb, 0xbb, 0x00, 0x07, 0xd0, 0x3d, 0xd9, 0x80, 0x02, 0x4c, 0xf8, 0xe5, 0xb3,
+	0x05, 0x64, 0x35, 0x74, 0xef, 0x8d, 0xce, 0x99, 0xb4, 0x3f, 0x66, 0x20, 0x83, 0xd0, 0xfb, 0xd6,
+	0xa7, 0x73, 0x4b, 0xc6, 0x1d, 0xa8, 0x62, 0xb9, 0x3e, 0xb6, 0xcc, 0x1e, 0x1d, 0x5a, 0x98, 0x49,
+	0xf8, 0x66, 0x59, 0xe0, 0x51, 0x27, 0xe1, 0x43, 0xb4, 0x68, 0x30, 0x72, 0x5d, 0xdb, 0x3d, 0x4a,
+	0x90, 0x0a, 0x07, 0xad, 0xc8, 0x8d, 0x98, 0x16, 0xa5, 0x92, 0xff, 0xa7, 0xa4, 0x0a, 0xe3, 0x2f,
+	0x0b, 0x7c, 0x4c, 0x79, 0x0f, 0x72, 0x14, 0x8c, 0x6a, 0x24, 0x98, 0x9d, 0x2f, 0x27, 0xf1, 0xa8,
+	0x0b, 0x4a, 0x86, 0xb5, 0x5b, 0x34, 0x5b, 0x2a, 0x1c, 0xf4, 0x3c, 0x2e, 0x4a, 0xc1, 0xe3, 0x05,
+	0x0d, 0xdb, 0x10, 0xdd, 0xb6, 0x39, 0xa6, 0x76, 0xcb, 0xef, 0x29, 0x25, 0x6b, 0x82, 0xd1, 0x5e,
+	0x40, 0xf5, 0x24, 0xc1, 0x9c, 0x1b, 0xcb, 0x5a, 0xf2, 0xc6, 0x32, 0x2f, 0xd9, 0xe2, 0xae, 0x9e,
+	0xb8, 0xcd, 0x50, 0x0f, 0xe5, 0x39, 0xba, 0xfe, 0xb7, 0x2c, 0x64, 0x70, 0x26, 0x61, 0x2f, 0xc4,
+	0xbb, 0xa6, 0xac, 0x0b, 0xec, 0xe6, 0xd9, 0x55, 0x83, 0x87, 0xac, 0x76, 0x6b, 0x91, 0xd2, 0x52,
+	0xff, 0x80, 0xf5, 0xe1, 0x42, 0x62, 0x43, 0x0c, 0x90, 0xff, 0xd3, 0x2f, 0xac, 0xa5, 0xf0, 0xfe,
+	0x5a, 0x50, 0xff, 0x8f, 0xb1, 0xeb, 0x33, 0x5c, 0x27, 0xfe, 0x6b, 0xd3, 0x6e, 0x9c, 0x41, 0x11,
+	0xab, 0xbd, 0x0d, 0x19, 0x1c, 0x7d, 0xd9, 0x47, 0xf3, 0x06, 0x62, 0x25, 0xe8, 0xea, 0xa9, 0xd3,
+	0x72, 0x3d, 0xf3, 0xcb, 0x74, 0x0a, 0x15, 0x7b, 0x0e, 0x95, 0xa9, 0xf7, 0x42, 0xf6, 0xe9, 0x42,
+	0xef, 0x89, 0x67, 0x49, 0xa6, 0xf3, 0x6e, 0xc2, 0x92, 0xfa, 0x47, 0xf2, 0x94, 0xee, 0xaa, 0x7d,
+	0x3c, 0x83, 0x4f, 0xfc, 0xcb, 0x89, 0xe7, 0x73, 0xb0, 0xd6, 0x58, 0xce, 0xe1, 0x16, 0xfd, 0x25,
+	0xca, 0xbe, 0x3d, 0x21, 0x16, 0x7f, 0x98, 0x36, 0x92, 0x7f, 0x98, 0xc6, 0x74, 0x4a, 0xbb, 0xc6,
+	0xa2, 0xe4, 0xca, 0x9a, 0xcd, 0xfb, 0x2f, 0xee, 0x1d, 0xd9, 0xd1, 0xf1, 0xa8, 0x4f, 0x0c, 0xab,
+	0x92, 0x5b, 0xfd, 0xae, 0xaf, 0x4e, 0xfe, 0x06, 0x5b, 0x3d, 0xb2, 0xdc, 0x55, 0xa1, 0x70, 0x3f,
+	0xcf, 0x27, 0xfe, 0xfb, 0xff, 0x01, 0x81, 0xb8, 0xa6, 0xb7, 0x04, 0x1e, 0x00, 0x00,
 }
@@ -12,11 +12,13 @@ import (
 	"google.golang.org/grpc/status"
 	appsv1beta2 "k8s.io/api/apps/v1beta2"
 	apiv1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/informers"
 	appinformers "k8s.io/client-go/informers/apps/v1beta2"
 	coreinformers "k8s.io/client-go/informers/core/v1"
+	extensionsinformers "k8s.io/client-go/informers/extensions/v1beta1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 )
@@ -27,6 +29,7 @@ const (
 	CM ApiResource = iota
 	Deploy
 	Endpoint
+	Ing
 	NS
 	Pod
 	RC
@@ -41,6 +44,7 @@ type API struct {
 	cm       coreinformers.ConfigMapInformer
 	deploy   appinformers.DeploymentInformer
 	endpoint coreinformers.EndpointsInformer
+	ing      extensionsinformers.IngressInformer
 	ns       coreinformers.NamespaceInformer
 	pod      coreinformers.PodInformer
 	rc       coreinformers.ReplicationControllerInformer
@@ -53,7 +57,7 @@ type API struct {
 
 // NewAPI takes a Kubernetes client and returns an initialized API
 func NewAPI(k8sClient kubernetes.Interface, resources ...ApiResource) *API {
-	sharedInformers := informers.NewSharedInformerFactory(k8sClient, 10*time.Minute)
+	sharedInformers := informers.NewSharedInformerFactory(k8sClient, jitteredResyncPeriod())
 
 	api := &API{
 		Client:          k8sClient,
@@ -62,6 +66,12 @@ func NewAPI(k8sClient kubernetes.Interface, resources ...ApiResource) *API {
 	}
 
 	for _, resource := range resources {
+		// Register our own informer for the resource before the typed
+		// informer below is created, so that the typed informer uses it.
+		if obj, newFunc := newInformerFunc(resource); obj != nil {
+			sharedInformers.InformerFor(obj, newFunc)
+		}
+
 		switch resource {
 		case CM:
 			api.cm = sharedInformers.Core().V1().ConfigMaps()
@@ -72,6 +82,9 @@ func NewAPI(k8sClient kubernetes.Interface, resources ...ApiResource) *API {
 		case Endpoint:
 			api.endpoint = sharedInformers.Core().V1().Endpoints()
 			api.syncChecks = append(api.syncChecks, api.endpoint.Informer().HasSynced)
+		case Ing:
+			api.ing = sharedInformers.Extensions().V1beta1().Ingresses()
+			api.syncChecks = append(api.syncChecks, api.ing.Informer().HasSynced)
 		case NS:
 			api.ns = sharedInformers.Core().V1().Namespaces()
 			api.syncChecks = append(api.syncChecks, api.ns.Informer().HasSynced)
@@ -162,6 +175,13 @@ func (api *API) Endpoint() coreinformers.EndpointsInformer {
 	return api.endpoint
 }
 
+func (api *API) Ing() extensionsinformers.IngressInformer {
+	if api.ing == nil {
+		panic("Ing informer not configured")
+	}
+	return api.ing
+}
+
 func (api *API) CM() coreinformers.ConfigMapInformer {
 	if api.cm == nil {
 		panic("CM informer not configured")
@@ -184,6 +204,8 @@ func (api *API) GetObjects(namespace, restype, name string) ([]runtime.Object, e
 		return api.getRCs(namespace, name)
 	case k8s.Service:
 		return api.getServices(namespace, name)
+	case k8s.Ingress:
+		return api.getIngresses(namespace, name)
 	default:
 		// TODO: ReplicaSet
 		return nil, status.Errorf(codes.Unimplemented, "unimplemented resource type: %s", restype)
@@ -194,21 +216,38 @@ func (api *API) GetObjects(namespace, restype, name string) ([]runtime.Object, e
 // references from the Kubernetes API. The kind is represented as the Kubernetes
 // singular resource type (e.g. deployment, daemonset, job, etc.)
 func (api *API) GetOwnerKindAndName(pod *apiv1.Pod) (string, string) {
+	owner := api.GetOwnerReference(pod)
+	return strings.ToLower(owner.Kind), owner.Name
+}
+
+// GetOwnerReference returns a reference to the object that GetOwnerKindAndName
+// identifies as the owner of pod, e.g. to record events on it.
+func (api *API) GetOwnerReference(pod *apiv1.Pod) *apiv1.ObjectReference {
 	if len(pod.GetOwnerReferences()) != 1 {
-		return "pod", pod.Name
+		return &apiv1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Namespace:  pod.Namespace,
+			Name:       pod.Name,
+			UID:        pod.UID,
+		}
 	}
 
 	parent := pod.GetOwnerReferences()[0]
 	if parent.Kind == "ReplicaSet" {
 		rs, err := api.RS().Lister().ReplicaSets(pod.Namespace).Get(parent.Name)
-		if err != nil || len(rs.GetOwnerReferences()) != 1 {
-			return strings.ToLower(parent.Kind), parent.Name
+		if err == nil && len(rs.GetOwnerReferences()) == 1 {
+			parent = rs.GetOwnerReferences()[0]
 		}
-		rsParent := rs.GetOwnerReferences()[0]
-		return strings.ToLower(rsParent.Kind), rsParent.Name
 	}
 
-	return strings.ToLower(parent.Kind), parent.Name
+	return &apiv1.ObjectReference{
+		APIVersion: parent.APIVersion,
+		Kind:       parent.Kind,
+		Namespace:  pod.Namespace,
+		Name:       parent.Name,
+		UID:        parent.UID,
+	}
 }
 
 // GetPodsFor returns all running and pending Pods associated with a given
@@ -404,6 +443,32 @@ func (api *API) getServices(namespace, name string) ([]runtime.Object, error) {
 	return objects, nil
 }
 
+func (api *API) getIngresses(namespace, name string) ([]runtime.Object, error) {
+	var err error
+	var ingresses []*extensionsv1beta1.Ingress
+
+	if namespace == "" {
+		ingresses, err = api.Ing().Lister().List(labels.Everything())
+	} else if name == "" {
+		ingresses, err = api.Ing().Lister().Ingresses(namespace).List(labels.Everything())
+	} else {
+		var ing *extensionsv1beta1.Ingress
+		ing, err = api.Ing().Lister().Ingresses(namespace).Get(name)
+		ingresses = []*extensionsv1beta1.Ingress{ing}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []runtime.Object{}
+	for _, ing := range ingresses {
+		objects = append(objects, ing)
+	}
+
+	return objects, nil
+}
+
 func isPendingOrRunning(pod *apiv1.Pod) bool {
 	pending := pod.Status.Phase == apiv1.PodPending
 	running := pod.Status.Phase == apiv1.PodRunning
@@ -1,14 +1,20 @@
 package k8s
 
 import (
+	"net/http"
+
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/flowcontrol"
 	// Load all the auth plugins for the cloud providers.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 )
 
-func NewClientSet(kubeConfig string) (*kubernetes.Clientset, error) {
+// NewClientSet returns a Kubernetes client that sends at most qps requests
+// per second to the API, with bursts of up to burst requests. Requests
+// rejected with 429 Too Many Requests are retried with exponential backoff.
+func NewClientSet(kubeConfig string, qps float32, burst int) (*kubernetes.Clientset, error) {
 	var config *rest.Config
 	var err error
 
@@ -24,5 +30,26 @@ func NewClientSet(kubeConfig string) (*kubernetes.Clientset, error) {
 		return nil, err
 	}
 
+	configureThrottling(config, qps, burst)
 	return kubernetes.NewForConfig(config)
 }
+
+func configureThrottling(config *rest.Config, qps float32, burst int) {
+	if qps <= 0 {
+		qps = rest.DefaultQPS
+	}
+	if burst <= 0 {
+		burst = rest.DefaultBurst
+	}
+	config.QPS = qps
+	config.Burst = burst
+	config.RateLimiter = meteredRateLimiter{flowcontrol.NewTokenBucketRateLimiter(qps, burst)}
+
+	wrap := config.WrapTransport
+	config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		if wrap != nil {
+			rt = wrap(rt)
+		}
+		return newBackoffRoundTripper(rt)
+	}
+}
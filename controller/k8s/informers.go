@@ -0,0 +1,183 @@
+package k8s
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	appsv1beta2 "k8s.io/api/apps/v1beta2"
+	apiv1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/informers/internalinterfaces"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// resyncPeriod is jittered by up to resyncJitter, so that components
+	// started together don't resync together.
+	resyncPeriod = 10 * time.Minute
+	resyncJitter = 0.5
+)
+
+var (
+	// maxRelistDelay bounds the random delay before an informer re-lists.
+	// Informers re-list whenever their watch fails, e.g. when the API server
+	// restarts; the delay spreads out the re-lists from every informer in
+	// every component.
+	maxRelistDelay = 5 * time.Second
+
+	informerRelists = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubernetes_informer_relists_total",
+			Help: "A counter for full re-lists of Kubernetes resources by informers, after the initial list.",
+		},
+		[]string{"resource"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(informerRelists)
+}
+
+// jitteredResyncPeriod returns the resync period for this process' informers.
+func jitteredResyncPeriod() time.Duration {
+	return wait.Jitter(resyncPeriod, resyncJitter)
+}
+
+// relistListWatch delays and counts each List after the first.
+type relistListWatch struct {
+	cache.ListerWatcher
+	resource string
+
+	mu     sync.Mutex
+	listed bool
+}
+
+func (lw *relistListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	lw.mu.Lock()
+	relist := lw.listed
+	lw.listed = true
+	lw.mu.Unlock()
+
+	if relist {
+		informerRelists.WithLabelValues(lw.resource).Inc()
+		delay := time.Duration(rand.Int63n(int64(maxRelistDelay)))
+		log.Debugf("re-listing %s in %s", lw.resource, delay)
+		time.Sleep(delay)
+	}
+	return lw.ListerWatcher.List(options)
+}
+
+// newInformerFunc returns a function that builds an informer for resource,
+// for use with SharedInformerFactory.InformerFor. It's equivalent to the
+// factory's own informers, with re-lists delayed by relistListWatch.
+func newInformerFunc(resource ApiResource) (runtime.Object, internalinterfaces.NewInformerFunc) {
+	var obj runtime.Object
+	var name string
+	var list func(kubernetes.Interface, metav1.ListOptions) (runtime.Object, error)
+	var watchFn func(kubernetes.Interface, metav1.ListOptions) (watch.Interface, error)
+
+	switch resource {
+	case CM:
+		obj, name = &apiv1.ConfigMap{}, "configmaps"
+		list = func(c kubernetes.Interface, o metav1.ListOptions) (runtime.Object, error) {
+			return c.CoreV1().ConfigMaps(metav1.NamespaceAll).List(o)
+		}
+		watchFn = func(c kubernetes.Interface, o metav1.ListOptions) (watch.Interface, error) {
+			return c.CoreV1().ConfigMaps(metav1.NamespaceAll).Watch(o)
+		}
+	case Deploy:
+		obj, name = &appsv1beta2.Deployment{}, "deployments"
+		list = func(c kubernetes.Interface, o metav1.ListOptions) (runtime.Object, error) {
+			return c.AppsV1beta2().Deployments(metav1.NamespaceAll).List(o)
+		}
+		watchFn = func(c kubernetes.Interface, o metav1.ListOptions) (watch.Interface, error) {
+			return c.AppsV1beta2().Deployments(metav1.NamespaceAll).Watch(o)
+		}
+	case Endpoint:
+		obj, name = &apiv1.Endpoints{}, "endpoints"
+		list = func(c kubernetes.Interface, o metav1.ListOptions) (runtime.Object, error) {
+			return c.CoreV1().Endpoints(metav1.NamespaceAll).List(o)
+		}
+		watchFn = func(c kubernetes.Interface, o metav1.ListOptions) (watch.Interface, error) {
+			return c.CoreV1().Endpoints(metav1.NamespaceAll).Watch(o)
+		}
+	case Ing:
+		obj, name = &extensionsv1beta1.Ingress{}, "ingresses"
+		list = func(c kubernetes.Interface, o metav1.ListOptions) (runtime.Object, error) {
+			return c.ExtensionsV1beta1().Ingresses(metav1.NamespaceAll).List(o)
+		}
+		watchFn = func(c kubernetes.Interface, o metav1.ListOptions) (watch.Interface, error) {
+			return c.ExtensionsV1beta1().Ingresses(metav1.NamespaceAll).Watch(o)
+		}
+	case NS:
+		obj, name = &apiv1.Namespace{}, "namespaces"
+		list = func(c kubernetes.Interface, o metav1.ListOptions) (runtime.Object, error) {
+			return c.CoreV1().Namespaces().List(o)
+		}
+		watchFn = func(c kubernetes.Interface, o metav1.ListOptions) (watch.Interface, error) {
+			return c.CoreV1().Namespaces().Watch(o)
+		}
+	case Pod:
+		obj, name = &apiv1.Pod{}, "pods"
+		list = func(c kubernetes.Interface, o metav1.ListOptions) (runtime.Object, error) {
+			return c.CoreV1().Pods(metav1.NamespaceAll).List(o)
+		}
+		watchFn = func(c kubernetes.Interface, o metav1.ListOptions) (watch.Interface, error) {
+			return c.CoreV1().Pods(metav1.NamespaceAll).Watch(o)
+		}
+	case RC:
+		obj, name = &apiv1.ReplicationController{}, "replicationcontrollers"
+		list = func(c kubernetes.Interface, o metav1.ListOptions) (runtime.Object, error) {
+			return c.CoreV1().ReplicationControllers(metav1.NamespaceAll).List(o)
+		}
+		watchFn = func(c kubernetes.Interface, o metav1.ListOptions) (watch.Interface, error) {
+			return c.CoreV1().ReplicationControllers(metav1.NamespaceAll).Watch(o)
+		}
+	case RS:
+		obj, name = &appsv1beta2.ReplicaSet{}, "replicasets"
+		list = func(c kubernetes.Interface, o metav1.ListOptions) (runtime.Object, error) {
+			return c.AppsV1beta2().ReplicaSets(metav1.NamespaceAll).List(o)
+		}
+		watchFn = func(c kubernetes.Interface, o metav1.ListOptions) (watch.Interface, error) {
+			return c.AppsV1beta2().ReplicaSets(metav1.NamespaceAll).Watch(o)
+		}
+	case Svc:
+		obj, name = &apiv1.Service{}, "services"
+		list = func(c kubernetes.Interface, o metav1.ListOptions) (runtime.Object, error) {
+			return c.CoreV1().Services(metav1.NamespaceAll).List(o)
+		}
+		watchFn = func(c kubernetes.Interface, o metav1.ListOptions) (watch.Interface, error) {
+			return c.CoreV1().Services(metav1.NamespaceAll).Watch(o)
+		}
+	default:
+		return nil, nil
+	}
+
+	return obj, func(client kubernetes.Interface, resync time.Duration) cache.SharedIndexInformer {
+		lw := &relistListWatch{
+			ListerWatcher: &cache.ListWatch{
+				ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+					return list(client, options)
+				},
+				WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+					return watchFn(client, options)
+				},
+			},
+			resource: name,
+		}
+		return cache.NewSharedIndexInformer(
+			lw,
+			obj,
+			resync,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+		)
+	}
+}
@@ -0,0 +1,70 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+func relists(t *testing.T, resource string) float64 {
+	var m dto.Metric
+	if err := informerRelists.WithLabelValues(resource).Write(&m); err != nil {
+		t.Fatalf("Unexpected error reading metric: %v", err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestRelistListWatch(t *testing.T) {
+	defaultMaxRelistDelay := maxRelistDelay
+	maxRelistDelay = 10 * time.Millisecond
+	defer func() { maxRelistDelay = defaultMaxRelistDelay }()
+
+	lists := 0
+	lw := &relistListWatch{
+		ListerWatcher: &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				lists++
+				return &apiv1.PodList{}, nil
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return watch.NewFake(), nil
+			},
+		},
+		resource: "test-pods",
+	}
+
+	if _, err := lw.List(metav1.ListOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count := relists(t, "test-pods"); count != 0 {
+		t.Fatalf("Expected the initial list not to count as a re-list, got %v", count)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := lw.List(metav1.ListOptions{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if count := relists(t, "test-pods"); count != 2 {
+		t.Fatalf("Expected 2 re-lists, got %v", count)
+	}
+	if lists != 3 {
+		t.Fatalf("Expected 3 lists, got %d", lists)
+	}
+}
+
+func TestJitteredResyncPeriod(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		period := jitteredResyncPeriod()
+		if period < resyncPeriod || period > time.Duration(float64(resyncPeriod)*(1+resyncJitter)) {
+			t.Fatalf("Expected a resync period between %s and %s, got %s",
+				resyncPeriod, time.Duration(float64(resyncPeriod)*(1+resyncJitter)), period)
+		}
+	}
+}
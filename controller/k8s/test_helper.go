@@ -28,6 +28,7 @@ func NewFakeAPI(configs ...string) (*API, error) {
 		CM,
 		Deploy,
 		Endpoint,
+		Ing,
 		NS,
 		Pod,
 		RC,
@@ -0,0 +1,132 @@
+package k8s
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	tooManyRequestsBaseDelay  = 500 * time.Millisecond
+	tooManyRequestsMaxDelay   = 30 * time.Second
+	tooManyRequestsMaxRetries = 5
+)
+
+var (
+	clientThrottledRequests = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "kubernetes_api_client_throttled_requests_total",
+			Help: "A counter for Kubernetes API requests delayed by the client-side rate limiter.",
+		},
+	)
+
+	clientThrottleDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "kubernetes_api_client_throttle_duration_seconds",
+			Help:    "A histogram of how long Kubernetes API requests waited for the client-side rate limiter.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+		},
+	)
+
+	tooManyRequests = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "kubernetes_api_too_many_requests_total",
+			Help: "A counter for 429 Too Many Requests responses from the Kubernetes API.",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(clientThrottledRequests, clientThrottleDuration, tooManyRequests)
+}
+
+// meteredRateLimiter records when requests have to wait for the wrapped
+// RateLimiter.
+type meteredRateLimiter struct {
+	flowcontrol.RateLimiter
+}
+
+func (m meteredRateLimiter) Accept() {
+	if m.TryAccept() {
+		return
+	}
+
+	clientThrottledRequests.Inc()
+	start := time.Now()
+	m.RateLimiter.Accept()
+	clientThrottleDuration.Observe(time.Since(start).Seconds())
+}
+
+// backoffRoundTripper retries requests that the Kubernetes API rejects with
+// 429 Too Many Requests, backing off exponentially between attempts. A longer
+// Retry-After from the server is honored. Once its retries are exhausted, the
+// 429 response is returned without its Retry-After header, since client-go's
+// REST client would otherwise retry it again.
+type backoffRoundTripper struct {
+	rt         http.RoundTripper
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+	maxRetries int
+}
+
+func newBackoffRoundTripper(rt http.RoundTripper) http.RoundTripper {
+	return &backoffRoundTripper{
+		rt:         rt,
+		baseDelay:  tooManyRequestsBaseDelay,
+		maxDelay:   tooManyRequestsMaxDelay,
+		maxRetries: tooManyRequestsMaxRetries,
+	}
+}
+
+func (b *backoffRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	delay := b.baseDelay
+	for attempt := 0; ; attempt++ {
+		rsp, err := b.rt.RoundTrip(req)
+		if err != nil || rsp.StatusCode != http.StatusTooManyRequests {
+			return rsp, err
+		}
+		tooManyRequests.Inc()
+
+		// Requests whose body can't be replayed are returned to the caller.
+		if attempt >= b.maxRetries || (req.Body != nil && req.GetBody == nil) {
+			rsp.Header.Del("Retry-After")
+			return rsp, nil
+		}
+
+		wait := delay
+		if seconds, err := strconv.Atoi(rsp.Header.Get("Retry-After")); err == nil {
+			if retryAfter := time.Duration(seconds) * time.Second; retryAfter > wait {
+				wait = retryAfter
+			}
+		}
+		if wait > b.maxDelay {
+			wait = b.maxDelay
+		}
+		io.Copy(ioutil.Discard, rsp.Body)
+		rsp.Body.Close()
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			retry := *req
+			retry.Body = body
+			req = &retry
+		}
+
+		log.Debugf("Kubernetes API responded 429 to %s %s, retrying in %s", req.Method, req.URL.Path, wait)
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		delay *= 2
+	}
+}
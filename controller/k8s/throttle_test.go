@@ -0,0 +1,131 @@
+package k8s
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/client-go/rest"
+)
+
+func tooManyRequestsCount(t *testing.T) float64 {
+	var m dto.Metric
+	if err := tooManyRequests.Write(&m); err != nil {
+		t.Fatalf("Unexpected error reading metric: %v", err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestBackoffRoundTripper(t *testing.T) {
+	newServer := func(tooMany int, bodies *[]string) *httptest.Server {
+		attempts := 0
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			body, _ := ioutil.ReadAll(req.Body)
+			*bodies = append(*bodies, string(body))
+			attempts++
+			if attempts <= tooMany {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+	}
+	newClient := func(maxRetries int) *http.Client {
+		return &http.Client{Transport: &backoffRoundTripper{
+			rt:         http.DefaultTransport,
+			baseDelay:  time.Millisecond,
+			maxDelay:   10 * time.Millisecond,
+			maxRetries: maxRetries,
+		}}
+	}
+
+	t.Run("Retries 429 responses, replaying the request body", func(t *testing.T) {
+		var bodies []string
+		server := newServer(2, &bodies)
+		defer server.Close()
+
+		before := tooManyRequestsCount(t)
+		rsp, err := newClient(5).Post(server.URL, "application/json", strings.NewReader(`{"kind": "Event"}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		rsp.Body.Close()
+
+		if rsp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, rsp.StatusCode)
+		}
+		if len(bodies) != 3 {
+			t.Fatalf("Expected 3 attempts, got %d", len(bodies))
+		}
+		for _, body := range bodies {
+			if body != `{"kind": "Event"}` {
+				t.Fatalf("Expected every attempt to send the request body, got %v", bodies)
+			}
+		}
+		if counted := tooManyRequestsCount(t) - before; counted != 2 {
+			t.Fatalf("Expected 2 responses to be counted, got %v", counted)
+		}
+	})
+
+	t.Run("Returns the 429 response without Retry-After once retries are exhausted", func(t *testing.T) {
+		var bodies []string
+		server := newServer(10, &bodies)
+		defer server.Close()
+
+		rsp, err := newClient(2).Get(server.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		rsp.Body.Close()
+
+		if rsp.StatusCode != http.StatusTooManyRequests {
+			t.Fatalf("Expected status %d, got %d", http.StatusTooManyRequests, rsp.StatusCode)
+		}
+		if retryAfter := rsp.Header.Get("Retry-After"); retryAfter != "" {
+			t.Fatalf("Expected no Retry-After header, so client-go doesn't retry, got [%s]", retryAfter)
+		}
+		if len(bodies) != 3 {
+			t.Fatalf("Expected 3 attempts, got %d", len(bodies))
+		}
+	})
+}
+
+func TestConfigureThrottling(t *testing.T) {
+	t.Run("Uses the client defaults when no limits are given", func(t *testing.T) {
+		config := &rest.Config{}
+		configureThrottling(config, 0, 0)
+
+		if config.QPS != rest.DefaultQPS || config.Burst != rest.DefaultBurst {
+			t.Fatalf("Expected QPS %v and burst %d, got %v and %d", rest.DefaultQPS, rest.DefaultBurst, config.QPS, config.Burst)
+		}
+		if config.RateLimiter.QPS() != rest.DefaultQPS {
+			t.Fatalf("Expected rate limiter QPS %v, got %v", rest.DefaultQPS, config.RateLimiter.QPS())
+		}
+	})
+
+	t.Run("Keeps an existing transport wrapper", func(t *testing.T) {
+		wrapped := false
+		config := &rest.Config{
+			WrapTransport: func(rt http.RoundTripper) http.RoundTripper {
+				wrapped = true
+				return rt
+			},
+		}
+		configureThrottling(config, 50, 100)
+
+		if config.QPS != 50 || config.Burst != 100 {
+			t.Fatalf("Expected QPS 50 and burst 100, got %v and %d", config.QPS, config.Burst)
+		}
+		if _, ok := config.WrapTransport(http.DefaultTransport).(*backoffRoundTripper); !ok {
+			t.Fatalf("Expected the transport to retry 429 responses")
+		}
+		if !wrapped {
+			t.Fatalf("Expected the existing transport wrapper to be applied")
+		}
+	})
+}
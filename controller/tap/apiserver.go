@@ -0,0 +1,476 @@
+package tap
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	apiUtil "github.com/linkerd/linkerd2/controller/api/util"
+	"github.com/linkerd/linkerd2/controller/ca"
+	pb "github.com/linkerd/linkerd2/controller/gen/controller/tap"
+	publicPb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/prometheus"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	authV1 "k8s.io/api/authorization/v1"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// apiServerServiceName is the name of the service that the APIService
+	// forwards to.
+	apiServerServiceName = "tap"
+
+	// apiServerSecretName is the name of the secret, in the controller
+	// namespace, that holds the serving certificate of the tap APIService.
+	apiServerSecretName = "tap-apiserver-tls"
+	apiServerCAKey      = "ca.crt"
+
+	// certificateCheckInterval is how often the serving certificate is
+	// reloaded from its secret, and certificateRenewBefore is how long before
+	// it expires that it's replaced.
+	certificateCheckInterval = 10 * time.Minute
+	certificateRenewBefore   = 30 * 24 * time.Hour
+
+	// authConfigMapNamespace and authConfigMapName identify the config map in
+	// which the Kubernetes API publishes how extension API servers should
+	// authenticate the requests that it forwards to them.
+	authConfigMapNamespace = "kube-system"
+	authConfigMapName      = "extension-apiserver-authentication"
+)
+
+// apiServer serves tap through the Kubernetes API. The Kubernetes API
+// authenticates users and forwards their requests, with their identity in
+// request headers. apiServer authorizes each tap with a SubjectAccessReview,
+// and then taps through the tap gRPC server.
+type apiServer struct {
+	k8sAPI              *k8s.API
+	controllerNamespace string
+	tapClient           pb.TapClient
+	auth                *requestHeaderAuth
+
+	certLock sync.RWMutex
+	cert     tls.Certificate
+	caPEM    []byte
+}
+
+// requestHeaderAuth is how the requests forwarded by the Kubernetes API are
+// authenticated: they're made with a client certificate signed by clientCAs,
+// whose common name is one of allowedNames (any name if empty), and have the
+// user's identity in the given headers.
+type requestHeaderAuth struct {
+	clientCAs           *x509.CertPool
+	allowedNames        []string
+	usernameHeaders     []string
+	groupHeaders        []string
+	extraHeaderPrefixes []string
+}
+
+// requestUser is the identity of the user that a request is made for.
+type requestUser struct {
+	name   string
+	groups []string
+	extra  map[string]authV1.ExtraValue
+}
+
+// NewAPIServer returns a server for the tap APIService, along with a TLS
+// listener on addr. Its serving certificate is created in the controller
+// namespace by the first replica to start, and the APIService is updated to
+// trust it. The certificate is reloaded periodically, so that every replica
+// picks it up when it's renewed.
+func NewAPIServer(
+	addr string,
+	controllerNamespace string,
+	tapClient pb.TapClient,
+	k8sAPI *k8s.API,
+) (*http.Server, net.Listener, error) {
+	handler := &apiServer{
+		k8sAPI:              k8sAPI,
+		controllerNamespace: controllerNamespace,
+		tapClient:           tapClient,
+	}
+	if err := handler.loadCertificate(); err != nil {
+		return nil, nil, err
+	}
+	auth, err := newRequestHeaderAuth(k8sAPI.Client)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load the request header configuration: %s", err)
+	}
+	handler.auth = auth
+
+	lis, err := tls.Listen("tcp", addr, &tls.Config{
+		GetCertificate: handler.getCertificate,
+		ClientAuth:     tls.VerifyClientCertIfGiven,
+		ClientCAs:      auth.clientCAs,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go func() {
+		for range time.Tick(certificateCheckInterval) {
+			if err := handler.loadCertificate(); err != nil {
+				log.Errorf("Error reloading the tap APIService certificate: %s", err)
+			}
+		}
+	}()
+
+	return &http.Server{Handler: prometheus.WithTelemetry(handler)}, lis, nil
+}
+
+// loadCertificate loads the serving certificate from its secret, renewing it
+// if it's about to expire. When the CA changes, the APIService trusts both
+// the old and the new CA, so that replicas that haven't reloaded the
+// certificate yet keep serving.
+func (a *apiServer) loadCertificate() error {
+	cert, caPEM, err := apiServerCertificate(a.k8sAPI.Client, a.controllerNamespace)
+	if err != nil {
+		return fmt.Errorf("failed to load the tap APIService certificate: %s", err)
+	}
+
+	a.certLock.Lock()
+	defer a.certLock.Unlock()
+	if !bytes.Equal(caPEM, a.caPEM) {
+		caBundle := append(append([]byte{}, caPEM...), a.caPEM...)
+		if err := updateAPIServiceCABundle(a.k8sAPI.Client, a.controllerNamespace, caBundle); err != nil {
+			return fmt.Errorf("failed to update the %s APIService: %s", apiServiceName(a.controllerNamespace), err)
+		}
+	}
+	a.cert = cert
+	a.caPEM = caPEM
+	return nil
+}
+
+func (a *apiServer) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	a.certLock.RLock()
+	cert := a.cert
+	a.certLock.RUnlock()
+	return &cert, nil
+}
+
+func (a *apiServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	log.Debugf("Serving %s %s", req.Method, req.URL.Path)
+
+	if req.URL.Path == fmt.Sprintf("/apis/%s/%s", apiUtil.TapAPIGroup(a.controllerNamespace), apiUtil.TapAPIVersion) {
+		a.serveDiscovery(w)
+		return
+	}
+
+	target, err := apiUtil.ParseTapAPIPath(a.controllerNamespace, req.URL.Path)
+	if err != nil {
+		http.NotFound(w, req)
+		return
+	}
+	if req.Method != http.MethodPost {
+		http.Error(w, "POST required", http.StatusMethodNotAllowed)
+		return
+	}
+	user, err := a.auth.authenticate(req)
+	if err != nil {
+		log.Debugf("Rejecting unauthenticated tap request: %s", err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	public.ServeTapByResource(w, req, func(tapReq *publicPb.TapByResourceRequest, stream publicPb.Api_TapByResourceServer) error {
+		// The Kubernetes API authorized the path, so the request must be
+		// for the same target.
+		if resource := tapReq.GetTarget().GetResource(); resource.GetNamespace() != target.Namespace ||
+			resource.GetType() != target.Type || resource.GetName() != target.Name {
+			return status.Errorf(codes.InvalidArgument, "tap target %+v doesn't match the request path %s", resource, req.URL.Path)
+		}
+		if err := a.authorize(user, target); err != nil {
+			return err
+		}
+		return a.tap(tapReq, stream)
+	})
+}
+
+// authorize checks that user may watch the tap subresource of target.
+// Namespaces are authorized in the namespace itself, so that a RoleBinding
+// there is enough to tap it.
+func (a *apiServer) authorize(user *requestUser, target *publicPb.Resource) error {
+	namespace := target.Namespace
+	if target.Type == pkgK8s.Namespace {
+		namespace = target.Name
+	}
+
+	review, err := a.k8sAPI.Client.AuthorizationV1().SubjectAccessReviews().Create(&authV1.SubjectAccessReview{
+		Spec: authV1.SubjectAccessReviewSpec{
+			User:   user.name,
+			Groups: user.groups,
+			Extra:  user.extra,
+			ResourceAttributes: &authV1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        "watch",
+				Group:       apiUtil.TapAPIGroup(a.controllerNamespace),
+				Version:     apiUtil.TapAPIVersion,
+				Resource:    pkgK8s.PluralResourceName(target.Type),
+				Subresource: apiUtil.TapAPISubresource,
+				Name:        target.Name,
+			},
+		},
+	})
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to authorize tap: %s", err)
+	}
+	if !review.Status.Allowed {
+		message := fmt.Sprintf("user %q is not authorized to watch %s/%s", user.name, pkgK8s.PluralResourceName(target.Type), apiUtil.TapAPISubresource)
+		if namespace != "" {
+			message += fmt.Sprintf(" in the %q namespace", namespace)
+		}
+		if review.Status.Reason != "" {
+			message += ": " + review.Status.Reason
+		}
+		return status.Error(codes.PermissionDenied, message)
+	}
+	return nil
+}
+
+// tap streams the events of req from the tap gRPC server to stream.
+func (a *apiServer) tap(req *publicPb.TapByResourceRequest, stream publicPb.Api_TapByResourceServer) error {
+	tapClient, err := a.tapClient.TapByResource(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := tapClient.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(event); err != nil {
+			return err
+		}
+	}
+}
+
+// serveDiscovery lists the tap subresources of the tap targets, which is how
+// clients of the Kubernetes API discover what the APIService serves.
+func (a *apiServer) serveDiscovery(w http.ResponseWriter) {
+	resources := metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: apiUtil.TapAPIGroup(a.controllerNamespace) + "/" + apiUtil.TapAPIVersion,
+	}
+	for _, resourceType := range apiUtil.ValidTargets {
+		resources.APIResources = append(resources.APIResources, metav1.APIResource{
+			Name:       pkgK8s.PluralResourceName(resourceType) + "/" + apiUtil.TapAPISubresource,
+			Namespaced: resourceType != pkgK8s.Namespace,
+			Kind:       "Tap",
+			Verbs:      metav1.Verbs{"watch"},
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resources); err != nil {
+		log.Errorf("Error writing tap API discovery: %s", err)
+	}
+}
+
+func newRequestHeaderAuth(client kubernetes.Interface) (*requestHeaderAuth, error) {
+	cm, err := client.CoreV1().ConfigMaps(authConfigMapNamespace).Get(authConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM([]byte(cm.Data["requestheader-client-ca-file"])) {
+		return nil, fmt.Errorf("%s/%s has no requestheader-client-ca-file", authConfigMapNamespace, authConfigMapName)
+	}
+	auth := &requestHeaderAuth{clientCAs: clientCAs}
+
+	// the other settings are JSON lists
+	for key, value := range map[string]*[]string{
+		"requestheader-allowed-names":        &auth.allowedNames,
+		"requestheader-username-headers":     &auth.usernameHeaders,
+		"requestheader-group-headers":        &auth.groupHeaders,
+		"requestheader-extra-headers-prefix": &auth.extraHeaderPrefixes,
+	} {
+		if cm.Data[key] == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(cm.Data[key]), value); err != nil {
+			return nil, fmt.Errorf("invalid %s in %s/%s: %s", key, authConfigMapNamespace, authConfigMapName, err)
+		}
+	}
+	if len(auth.usernameHeaders) == 0 {
+		return nil, fmt.Errorf("%s/%s has no requestheader-username-headers", authConfigMapNamespace, authConfigMapName)
+	}
+	return auth, nil
+}
+
+// authenticate returns the user that req was forwarded for by the
+// Kubernetes API. Headers naming a user are only trusted on requests made
+// with a verified client certificate with an allowed name.
+func (a *requestHeaderAuth) authenticate(req *http.Request) (*requestUser, error) {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 {
+		return nil, fmt.Errorf("no verified client certificate")
+	}
+	if len(a.allowedNames) > 0 {
+		commonName := req.TLS.PeerCertificates[0].Subject.CommonName
+		if !contains(a.allowedNames, commonName) {
+			return nil, fmt.Errorf("client certificate name %q is not allowed", commonName)
+		}
+	}
+
+	user := &requestUser{extra: map[string]authV1.ExtraValue{}}
+	for _, header := range a.usernameHeaders {
+		if user.name = req.Header.Get(header); user.name != "" {
+			break
+		}
+	}
+	if user.name == "" {
+		return nil, fmt.Errorf("no user in the request headers")
+	}
+	for _, header := range a.groupHeaders {
+		user.groups = append(user.groups, req.Header[http.CanonicalHeaderKey(header)]...)
+	}
+	for _, prefix := range a.extraHeaderPrefixes {
+		prefix = http.CanonicalHeaderKey(prefix)
+		for header, values := range req.Header {
+			if !strings.HasPrefix(header, prefix) {
+				continue
+			}
+			// extra keys are lowercased and percent-encoded in header names
+			key, err := url.PathUnescape(strings.ToLower(strings.TrimPrefix(header, prefix)))
+			if err != nil {
+				continue
+			}
+			user.extra[key] = append(user.extra[key], values...)
+		}
+	}
+	return user, nil
+}
+
+// apiServerCertificate returns the serving certificate of the tap APIService
+// and the PEM-encoded certificate of the CA that issued it. They're kept in a
+// secret so that every replica serves a certificate that the APIService
+// trusts; the first replica to start, or to find the certificate about to
+// expire, creates a new one.
+func apiServerCertificate(client kubernetes.Interface, controllerNamespace string) (tls.Certificate, []byte, error) {
+	secrets := client.CoreV1().Secrets(controllerNamespace)
+
+	secret, err := secrets.Get(apiServerSecretName, metav1.GetOptions{})
+	switch {
+	case k8sErrors.IsNotFound(err):
+		secret, err = newAPIServerSecret(controllerNamespace)
+		if err != nil {
+			return tls.Certificate{}, nil, err
+		}
+		_, err = secrets.Create(secret)
+		if k8sErrors.IsAlreadyExists(err) {
+			// another replica created it first
+			secret, err = secrets.Get(apiServerSecretName, metav1.GetOptions{})
+		}
+	case err == nil && apiServerCertificateExpired(secret):
+		log.Infof("replacing the expiring tap APIService certificate")
+		var renewed *apiv1.Secret
+		renewed, err = newAPIServerSecret(controllerNamespace)
+		if err != nil {
+			return tls.Certificate{}, nil, err
+		}
+		renewed.ResourceVersion = secret.ResourceVersion
+		secret, err = secrets.Update(renewed)
+	}
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+
+	cert, err := tls.X509KeyPair(secret.Data[apiv1.TLSCertKey], secret.Data[apiv1.TLSPrivateKeyKey])
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	return cert, secret.Data[apiServerCAKey], nil
+}
+
+// newAPIServerSecret issues a serving certificate for the tap APIService's
+// service from a new CA, and returns a secret that holds both.
+func newAPIServerSecret(controllerNamespace string) (*apiv1.Secret, error) {
+	authority, err := ca.NewCA()
+	if err != nil {
+		return nil, err
+	}
+	dnsName := fmt.Sprintf("%s.%s.svc", apiServerServiceName, controllerNamespace)
+	cert, err := authority.IssueEndEntityCertificate(dnsName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      apiServerSecretName,
+			Namespace: controllerNamespace,
+			Labels:    map[string]string{pkgK8s.ControllerComponentLabel: "controller"},
+		},
+		Type: apiv1.SecretTypeTLS,
+		Data: map[string][]byte{
+			apiv1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate}),
+			apiv1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: cert.PrivateKey}),
+			apiServerCAKey:         []byte(authority.TrustAnchorPEM()),
+		},
+	}, nil
+}
+
+// apiServerCertificateExpired returns true if the certificate in secret
+// can't be parsed or expires within certificateRenewBefore.
+func apiServerCertificateExpired(secret *apiv1.Secret) bool {
+	block, _ := pem.Decode(secret.Data[apiv1.TLSCertKey])
+	if block == nil {
+		return true
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	return err != nil || time.Now().Add(certificateRenewBefore).After(cert.NotAfter)
+}
+
+// apiServiceName returns the name of the APIService through which the
+// Kubernetes API serves tap for the control plane in controllerNamespace.
+func apiServiceName(controllerNamespace string) string {
+	return apiUtil.TapAPIVersion + "." + apiUtil.TapAPIGroup(controllerNamespace)
+}
+
+// updateAPIServiceCABundle sets the CAs that the Kubernetes API trusts to
+// have issued the tap APIService's serving certificate.
+func updateAPIServiceCABundle(client kubernetes.Interface, controllerNamespace string, caPEM []byte) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"caBundle": caPEM},
+	})
+	if err != nil {
+		return err
+	}
+
+	return client.Discovery().RESTClient().
+		Patch(types.MergePatchType).
+		AbsPath("/apis/apiregistration.k8s.io/v1beta1/apiservices", apiServiceName(controllerNamespace)).
+		Body(patch).
+		Do().
+		Error()
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
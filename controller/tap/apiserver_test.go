@@ -0,0 +1,197 @@
+package tap
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	public "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/controller/k8s"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	authV1 "k8s.io/api/authorization/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+func TestAPIServerAuthorize(t *testing.T) {
+	user := &requestUser{name: "alice", groups: []string{"devs"}}
+
+	testCases := []struct {
+		target        *public.Resource
+		allowed       bool
+		expectedAttrs authV1.ResourceAttributes
+		expectedCode  codes.Code
+	}{
+		{
+			target:  &public.Resource{Namespace: "emojivoto", Type: pkgK8s.Deployment, Name: "web"},
+			allowed: true,
+			expectedAttrs: authV1.ResourceAttributes{
+				Namespace:   "emojivoto",
+				Verb:        "watch",
+				Group:       "linkerd.tap.linkerd.io",
+				Version:     "v1alpha1",
+				Resource:    "deployments",
+				Subresource: "tap",
+				Name:        "web",
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			target:  &public.Resource{Type: pkgK8s.Namespace, Name: "emojivoto"},
+			allowed: false,
+			expectedAttrs: authV1.ResourceAttributes{
+				Namespace:   "emojivoto",
+				Verb:        "watch",
+				Group:       "linkerd.tap.linkerd.io",
+				Version:     "v1alpha1",
+				Resource:    "namespaces",
+				Subresource: "tap",
+				Name:        "emojivoto",
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.target.Type, func(t *testing.T) {
+			var review *authV1.SubjectAccessReview
+			clientSet := fake.NewSimpleClientset()
+			clientSet.PrependReactor("create", "subjectaccessreviews", func(action k8sTesting.Action) (bool, runtime.Object, error) {
+				review = action.(k8sTesting.CreateAction).GetObject().(*authV1.SubjectAccessReview)
+				review.Status.Allowed = tc.allowed
+				return true, review, nil
+			})
+			server := &apiServer{k8sAPI: k8s.NewAPI(clientSet), controllerNamespace: "linkerd"}
+
+			err := server.authorize(user, tc.target)
+			if code := status.Code(err); code != tc.expectedCode {
+				t.Fatalf("Expected code %s, got %s (%v)", tc.expectedCode, code, err)
+			}
+			if review.Spec.User != user.name || !reflect.DeepEqual(review.Spec.Groups, user.groups) {
+				t.Errorf("Expected review for %+v, got %+v", user, review.Spec)
+			}
+			if !reflect.DeepEqual(*review.Spec.ResourceAttributes, tc.expectedAttrs) {
+				t.Errorf("Expected resource attributes %+v, got %+v", tc.expectedAttrs, *review.Spec.ResourceAttributes)
+			}
+		})
+	}
+}
+
+func TestRequestHeaderAuthenticate(t *testing.T) {
+	auth := &requestHeaderAuth{
+		allowedNames:        []string{"front-proxy-client"},
+		usernameHeaders:     []string{"X-Remote-User"},
+		groupHeaders:        []string{"X-Remote-Group"},
+		extraHeaderPrefixes: []string{"X-Remote-Extra-"},
+	}
+	verified := func(commonName string) *tls.ConnectionState {
+		cert := &x509.Certificate{}
+		cert.Subject.CommonName = commonName
+		return &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}
+	}
+
+	t.Run("Returns the user in the request headers", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/", nil)
+		req.TLS = verified("front-proxy-client")
+		req.Header.Set("X-Remote-User", "alice")
+		req.Header.Add("X-Remote-Group", "devs")
+		req.Header.Add("X-Remote-Group", "system:authenticated")
+		req.Header.Set("X-Remote-Extra-Scopes%2fTeam", "emoji")
+
+		user, err := auth.authenticate(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := &requestUser{
+			name:   "alice",
+			groups: []string{"devs", "system:authenticated"},
+			extra:  map[string]authV1.ExtraValue{"scopes/team": {"emoji"}},
+		}
+		if !reflect.DeepEqual(user, expected) {
+			t.Fatalf("Expected %+v, got %+v", expected, user)
+		}
+	})
+
+	t.Run("Rejects requests that aren't from the Kubernetes API", func(t *testing.T) {
+		for name, state := range map[string]*tls.ConnectionState{
+			"no certificate":  nil,
+			"unverified":      {PeerCertificates: verified("front-proxy-client").PeerCertificates},
+			"disallowed name": verified("someone-else"),
+		} {
+			req, _ := http.NewRequest(http.MethodPost, "/", nil)
+			req.TLS = state
+			req.Header.Set("X-Remote-User", "alice")
+			if _, err := auth.authenticate(req); err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+	})
+}
+
+func TestAPIServerCertificate(t *testing.T) {
+	clientSet := fake.NewSimpleClientset()
+
+	_, caPEM, err := apiServerCertificate(clientSet, "linkerd")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	secret, err := clientSet.CoreV1().Secrets("linkerd").Get(apiServerSecretName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Expected the certificate to be stored: %s", err)
+	}
+	if apiServerCertificateExpired(secret) {
+		t.Fatalf("Expected a valid certificate")
+	}
+
+	// other replicas reuse the stored certificate
+	_, reusedCAPEM, err := apiServerCertificate(clientSet, "linkerd")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if string(reusedCAPEM) != string(caPEM) {
+		t.Fatalf("Expected the stored certificate to be reused")
+	}
+
+	// certificates are replaced before they expire
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: time.Now().Add(24 * time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	secret.Data[apiv1.TLSCertKey] = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if !apiServerCertificateExpired(secret) {
+		t.Fatalf("Expected a certificate that expires within a day to need renewing")
+	}
+	if _, err := clientSet.CoreV1().Secrets("linkerd").Update(secret); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, renewedCAPEM, err := apiServerCertificate(clientSet, "linkerd")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if string(renewedCAPEM) == string(caPEM) {
+		t.Fatalf("Expected the expiring certificate to be replaced")
+	}
+}
@@ -0,0 +1,348 @@
+package tap
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
+	apiUtil "github.com/linkerd/linkerd2/controller/api/util"
+	public "github.com/linkerd/linkerd2/controller/gen/public"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// eventFilter applies the path and authority regexes of a TapByResource
+// request. The proxy tap API only supports exact and prefix matches, so
+// proxies are asked for the literal prefix of each regex and the full
+// expressions are applied to the events they return. Negated regexes are
+// only sent to proxies when they are entirely literal.
+type eventFilter struct {
+	paths               []*regexp.Regexp
+	authorities         []*regexp.Regexp
+	excludedPaths       []*regexp.Regexp
+	excludedAuthorities []*regexp.Regexp
+
+	// sampler, if set, drops a share of the requests that match.
+	sampler *sampler
+}
+
+type streamKey struct {
+	base   uint32
+	stream uint64
+}
+
+func (f *eventFilter) addPath(path string, negated bool) error {
+	re, err := apiUtil.CompileTapPathRegex(path)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid path regex [%s]: %s", path, err)
+	}
+	if negated {
+		f.excludedPaths = append(f.excludedPaths, re)
+	} else {
+		f.paths = append(f.paths, re)
+	}
+	return nil
+}
+
+func (f *eventFilter) addAuthority(authority string, negated bool) error {
+	re, err := apiUtil.CompileTapAuthorityRegex(authority)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid authority regex [%s]: %s", authority, err)
+	}
+	if negated {
+		f.excludedAuthorities = append(f.excludedAuthorities, re)
+	} else {
+		f.authorities = append(f.authorities, re)
+	}
+	return nil
+}
+
+func (f *eventFilter) empty() bool {
+	return len(f.paths) == 0 && len(f.authorities) == 0 &&
+		len(f.excludedPaths) == 0 && len(f.excludedAuthorities) == 0 &&
+		f.sampler == nil
+}
+
+// accept reports whether ev should be passed on to the client. Events from a
+// single proxy must be passed to accept in order, along with the same
+// streams map, which tracks the requests that matched so that their response
+// events are accepted too.
+func (f *eventFilter) accept(ev *public.TapEvent, streams map[streamKey]struct{}) bool {
+	if f.empty() {
+		return true
+	}
+
+	switch http := ev.GetHttp().GetEvent().(type) {
+	case *public.TapEvent_Http_RequestInit_:
+		req := http.RequestInit
+		for _, re := range f.paths {
+			if !re.MatchString(req.GetPath()) {
+				return false
+			}
+		}
+		for _, re := range f.authorities {
+			if !re.MatchString(req.GetAuthority()) {
+				return false
+			}
+		}
+		for _, re := range f.excludedPaths {
+			if re.MatchString(req.GetPath()) {
+				return false
+			}
+		}
+		for _, re := range f.excludedAuthorities {
+			if re.MatchString(req.GetAuthority()) {
+				return false
+			}
+		}
+		if f.sampler != nil && !f.sampler.sample(req.GetPath()) {
+			return false
+		}
+		streams[toStreamKey(req.GetId())] = struct{}{}
+		return true
+
+	case *public.TapEvent_Http_ResponseInit_:
+		_, ok := streams[toStreamKey(http.ResponseInit.GetId())]
+		return ok
+
+	case *public.TapEvent_Http_ResponseEnd_:
+		key := toStreamKey(http.ResponseEnd.GetId())
+		_, ok := streams[key]
+		delete(streams, key)
+		return ok
+
+	default:
+		return false
+	}
+}
+
+// sampler reports each request with a probability: that of the first path
+// override whose regex matches the request's path, or else rate.
+type sampler struct {
+	rate  float32
+	paths []pathSampleRate
+
+	// random returns a pseudo-random number in [0.0,1.0).
+	random func() float32
+}
+
+type pathSampleRate struct {
+	path *regexp.Regexp
+	rate float32
+}
+
+func (s *sampler) sample(path string) bool {
+	rate := s.rate
+	for _, override := range s.paths {
+		if override.path.MatchString(path) {
+			rate = override.rate
+			break
+		}
+	}
+	return rate >= 1 || s.random() < rate
+}
+
+// responseCriteria are the conditions that a response must meet for its
+// request to be reported. The zero value accepts every response.
+type responseCriteria struct {
+	minLatency time.Duration
+	minStatus  uint32
+	maxStatus  uint32
+
+	// grpcStatuses, if set, are the gRPC status codes that a response must
+	// end with.
+	grpcStatuses []uint32
+}
+
+func (c responseCriteria) empty() bool {
+	return c.minLatency == 0 && c.maxStatus == 0 && len(c.grpcStatuses) == 0
+}
+
+func (c responseCriteria) accept(rsp *public.TapEvent_Http_ResponseInit) bool {
+	if c.minLatency > 0 {
+		latency, err := ptypes.Duration(rsp.GetSinceRequestInit())
+		if err != nil || latency < c.minLatency {
+			return false
+		}
+	}
+	if c.maxStatus > 0 && (rsp.GetHttpStatus() < c.minStatus || rsp.GetHttpStatus() > c.maxStatus) {
+		return false
+	}
+	return true
+}
+
+// acceptEnd reports whether a response ended with one of the gRPC statuses
+// of the criteria. Responses that weren't gRPC never do.
+func (c responseCriteria) acceptEnd(end *public.TapEvent_Http_ResponseEnd) bool {
+	if len(c.grpcStatuses) == 0 {
+		return true
+	}
+	eos, ok := end.GetEos().GetEnd().(*public.Eos_GrpcStatusCode)
+	if !ok {
+		return false
+	}
+	for _, code := range c.grpcStatuses {
+		if eos.GrpcStatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// hungOnly reports whether a request that never gets a response can meet the
+// criteria, which is the case when only a minimum latency is set.
+func (c responseCriteria) hungOnly() bool {
+	return c.minLatency > 0 && c.maxStatus == 0 && len(c.grpcStatuses) == 0
+}
+
+// expireInterval returns how often a responseFilter's expire should be
+// called, or 0 if it never releases any request.
+func (c responseCriteria) expireInterval() time.Duration {
+	if !c.hungOnly() {
+		return 0
+	}
+	return c.minLatency / 4
+}
+
+// responseFilter drops the events of requests whose responses don't meet its
+// criteria. Each request is held until its response starts, since neither
+// the latency nor the status is known before then, and until its response
+// ends if it's filtered by gRPC status, which is only known at the end.
+// Requests filtered only by latency are also released by expire once they've
+// been waiting for longer than the minimum latency, so that hung requests
+// are reported.
+type responseFilter struct {
+	criteria responseCriteria
+	pending  map[streamKey][]*public.TapEvent
+	started  map[streamKey]time.Time
+	matched  map[streamKey]struct{}
+
+	// now returns the current time.
+	now func() time.Time
+}
+
+func newResponseFilter(criteria responseCriteria) *responseFilter {
+	return &responseFilter{
+		criteria: criteria,
+		pending:  make(map[streamKey][]*public.TapEvent),
+		started:  make(map[streamKey]time.Time),
+		matched:  make(map[streamKey]struct{}),
+		now:      time.Now,
+	}
+}
+
+// expire returns the held requests that have been waiting for a response for
+// at least the minimum latency. Their response events, if any arrive, are
+// passed on as they're seen.
+func (f *responseFilter) expire() []*public.TapEvent {
+	if !f.criteria.hungOnly() {
+		return nil
+	}
+
+	var events []*public.TapEvent
+	now := f.now()
+	for key, started := range f.started {
+		if now.Sub(started) < f.criteria.minLatency {
+			continue
+		}
+		events = append(events, f.pending[key]...)
+		delete(f.pending, key)
+		delete(f.started, key)
+		f.matched[key] = struct{}{}
+	}
+	return events
+}
+
+// filter returns the events that should be passed on to the client once ev
+// has been seen. Events from a single proxy must be passed to filter in
+// order.
+func (f *responseFilter) filter(ev *public.TapEvent) []*public.TapEvent {
+	if f.criteria.empty() {
+		return []*public.TapEvent{ev}
+	}
+
+	switch http := ev.GetHttp().GetEvent().(type) {
+	case *public.TapEvent_Http_RequestInit_:
+		key := toStreamKey(http.RequestInit.GetId())
+		f.pending[key] = []*public.TapEvent{ev}
+		if f.criteria.hungOnly() {
+			f.started[key] = f.now()
+		}
+		return nil
+
+	case *public.TapEvent_Http_ResponseInit_:
+		key := toStreamKey(http.ResponseInit.GetId())
+		if _, ok := f.matched[key]; ok {
+			return []*public.TapEvent{ev}
+		}
+		held, ok := f.pending[key]
+		delete(f.started, key)
+		if !ok || !f.criteria.accept(http.ResponseInit) {
+			delete(f.pending, key)
+			return nil
+		}
+		if len(f.criteria.grpcStatuses) > 0 {
+			f.pending[key] = append(held, ev)
+			return nil
+		}
+		delete(f.pending, key)
+		f.matched[key] = struct{}{}
+		return append(held, ev)
+
+	case *public.TapEvent_Http_ResponseEnd_:
+		key := toStreamKey(http.ResponseEnd.GetId())
+		held, pending := f.pending[key]
+		_, matched := f.matched[key]
+		delete(f.pending, key)
+		delete(f.started, key)
+		delete(f.matched, key)
+		switch {
+		case matched:
+			return []*public.TapEvent{ev}
+		case pending && len(held) > 1 && f.criteria.acceptEnd(http.ResponseEnd):
+			return append(held, ev)
+		default:
+			return nil
+		}
+
+	default:
+		return nil
+	}
+}
+
+func toStreamKey(id *public.TapEvent_Http_StreamId) streamKey {
+	return streamKey{base: id.GetBase(), stream: id.GetStream()}
+}
+
+// literalPrefix returns the literal string that every match of expr, anchored
+// at its start, begins with, and whether expr matches only that string. It
+// returns an empty prefix if expr can't be parsed or doesn't start with a
+// literal. An end anchor makes the prefix incomplete, since paths are matched
+// by prefix and the anchored expression matches fewer of them.
+func literalPrefix(expr string) (string, bool) {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	re = re.Simplify()
+
+	subs := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		subs = re.Sub
+	}
+
+	prefix := ""
+	complete := true
+	for _, sub := range subs {
+		switch {
+		case sub.Op == syntax.OpBeginText || sub.Op == syntax.OpEmptyMatch:
+		case sub.Op == syntax.OpLiteral && sub.Flags&syntax.FoldCase == 0 && complete:
+			prefix += string(sub.Rune)
+		default:
+			complete = false
+		}
+	}
+	return prefix, complete
+}
@@ -0,0 +1,310 @@
+package tap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
+	public "github.com/linkerd/linkerd2/controller/gen/public"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func requestInit(stream uint64, authority, path string) *public.TapEvent {
+	return &public.TapEvent{
+		Event: &public.TapEvent_Http_{
+			Http: &public.TapEvent_Http{
+				Event: &public.TapEvent_Http_RequestInit_{
+					RequestInit: &public.TapEvent_Http_RequestInit{
+						Id:        &public.TapEvent_Http_StreamId{Base: 1, Stream: stream},
+						Authority: authority,
+						Path:      path,
+					},
+				},
+			},
+		},
+	}
+}
+
+func responseEnd(stream uint64) *public.TapEvent {
+	return &public.TapEvent{
+		Event: &public.TapEvent_Http_{
+			Http: &public.TapEvent_Http{
+				Event: &public.TapEvent_Http_ResponseEnd_{
+					ResponseEnd: &public.TapEvent_Http_ResponseEnd{
+						Id: &public.TapEvent_Http_StreamId{Base: 1, Stream: stream},
+					},
+				},
+			},
+		},
+	}
+}
+
+func grpcResponseEnd(stream uint64, code codes.Code) *public.TapEvent {
+	ev := responseEnd(stream)
+	ev.GetHttp().GetResponseEnd().Eos = &public.Eos{
+		End: &public.Eos_GrpcStatusCode{GrpcStatusCode: uint32(code)},
+	}
+	return ev
+}
+
+func responseInit(stream uint64, latency time.Duration, httpStatus uint32) *public.TapEvent {
+	return &public.TapEvent{
+		Event: &public.TapEvent_Http_{
+			Http: &public.TapEvent_Http{
+				Event: &public.TapEvent_Http_ResponseInit_{
+					ResponseInit: &public.TapEvent_Http_ResponseInit{
+						Id:               &public.TapEvent_Http_StreamId{Base: 1, Stream: stream},
+						SinceRequestInit: ptypes.DurationProto(latency),
+						HttpStatus:       httpStatus,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestEventFilter(t *testing.T) {
+	t.Run("Matches paths as anchored prefixes and authorities exactly", func(t *testing.T) {
+		filter := &eventFilter{}
+		if err := filter.addPath("/books/[0-9]+", false); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := filter.addAuthority("web-svc\\.emojivoto(:80)?", false); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		testCases := []struct {
+			authority string
+			path      string
+			expected  bool
+		}{
+			{"web-svc.emojivoto", "/books/12", true},
+			{"web-svc.emojivoto:80", "/books/12/edit", true},
+			{"web-svc.emojivoto", "/api/books/12", false},
+			{"web-svc.emojivoto", "/books/new", false},
+			{"web-svc.emojivoto.svc.cluster.local", "/books/12", false},
+			{"my-web-svc.emojivoto", "/books/12", false},
+		}
+
+		streams := make(map[streamKey]struct{})
+		for i, tc := range testCases {
+			if accepted := filter.accept(requestInit(uint64(i), tc.authority, tc.path), streams); accepted != tc.expected {
+				t.Fatalf("Expected [%s%s] accepted to be %t, got %t", tc.authority, tc.path, tc.expected, accepted)
+			}
+		}
+	})
+
+	t.Run("Passes response events only for matching requests", func(t *testing.T) {
+		filter := &eventFilter{}
+		if err := filter.addPath("/books", false); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		streams := make(map[streamKey]struct{})
+		filter.accept(requestInit(1, "", "/books"), streams)
+		filter.accept(requestInit(2, "", "/authors"), streams)
+
+		if !filter.accept(responseEnd(1), streams) {
+			t.Fatalf("Expected response for a matching request to be accepted")
+		}
+		if filter.accept(responseEnd(2), streams) {
+			t.Fatalf("Expected response for a non-matching request to be dropped")
+		}
+		if len(streams) != 0 {
+			t.Fatalf("Expected completed streams to be forgotten, got %v", streams)
+		}
+	})
+
+	t.Run("Drops requests that match excluded paths", func(t *testing.T) {
+		filter := &eventFilter{}
+		if err := filter.addPath("/(healthz|metrics)$", true); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		streams := make(map[streamKey]struct{})
+		if filter.accept(requestInit(1, "", "/healthz"), streams) {
+			t.Fatalf("Expected request for an excluded path to be dropped")
+		}
+		if !filter.accept(requestInit(2, "", "/healthz/details"), streams) {
+			t.Fatalf("Expected request for a path that isn't excluded to be accepted")
+		}
+	})
+
+	t.Run("Drops the requests that aren't sampled, with their responses", func(t *testing.T) {
+		filter := &eventFilter{sampler: &sampler{rate: 0.5}}
+
+		streams := make(map[streamKey]struct{})
+		filter.sampler.random = func() float32 { return 0.4 }
+		if !filter.accept(requestInit(1, "", "/books"), streams) {
+			t.Fatalf("Expected a sampled request to be accepted")
+		}
+		filter.sampler.random = func() float32 { return 0.6 }
+		if filter.accept(requestInit(2, "", "/books"), streams) {
+			t.Fatalf("Expected a request that wasn't sampled to be dropped")
+		}
+		if !filter.accept(responseEnd(1), streams) || filter.accept(responseEnd(2), streams) {
+			t.Fatalf("Expected only the response of the sampled request to be accepted")
+		}
+	})
+
+	t.Run("Rejects invalid regular expressions", func(t *testing.T) {
+		filter := &eventFilter{}
+		err := filter.addPath("/books/[0-9", false)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("Expected InvalidArgument error, got %v", err)
+		}
+	})
+}
+
+func TestResponseFilter(t *testing.T) {
+	t.Run("Passes every event without criteria", func(t *testing.T) {
+		filter := newResponseFilter(responseCriteria{})
+		for _, ev := range []*public.TapEvent{requestInit(1, "", "/"), responseInit(1, time.Millisecond, 200), responseEnd(1)} {
+			if events := filter.filter(ev); len(events) != 1 || events[0] != ev {
+				t.Fatalf("Expected event %+v to be passed on, got %+v", ev, events)
+			}
+		}
+	})
+
+	t.Run("Holds requests until their response shows they were slow", func(t *testing.T) {
+		filter := newResponseFilter(responseCriteria{minLatency: 250 * time.Millisecond})
+
+		slowReq, slowRsp := requestInit(1, "", "/slow"), responseInit(1, 300*time.Millisecond, 200)
+		if events := filter.filter(slowReq); len(events) != 0 {
+			t.Fatalf("Expected the request to be held, got %+v", events)
+		}
+		filter.filter(requestInit(2, "", "/fast"))
+
+		if events := filter.filter(responseInit(2, 10*time.Millisecond, 200)); len(events) != 0 {
+			t.Fatalf("Expected a fast response to be dropped, got %+v", events)
+		}
+		if events := filter.filter(slowRsp); len(events) != 2 || events[0] != slowReq || events[1] != slowRsp {
+			t.Fatalf("Expected the slow request and response, got %+v", events)
+		}
+		if events := filter.filter(responseEnd(2)); len(events) != 0 {
+			t.Fatalf("Expected the end of a fast response to be dropped, got %+v", events)
+		}
+		if events := filter.filter(responseEnd(1)); len(events) != 1 {
+			t.Fatalf("Expected the end of a slow response to be passed on, got %+v", events)
+		}
+		if len(filter.pending) != 0 || len(filter.matched) != 0 {
+			t.Fatalf("Expected completed streams to be forgotten, got %v and %v", filter.pending, filter.matched)
+		}
+	})
+
+	t.Run("Releases requests that wait longer than the minimum latency", func(t *testing.T) {
+		now := time.Now()
+		filter := newResponseFilter(responseCriteria{minLatency: 250 * time.Millisecond})
+		filter.now = func() time.Time { return now }
+
+		hungReq := requestInit(1, "", "/hung")
+		filter.filter(hungReq)
+		now = now.Add(100 * time.Millisecond)
+		filter.filter(requestInit(2, "", "/fast"))
+
+		now = now.Add(200 * time.Millisecond)
+		if events := filter.expire(); len(events) != 1 || events[0] != hungReq {
+			t.Fatalf("Expected the hung request to be released, got %+v", events)
+		}
+		if events := filter.expire(); len(events) != 0 {
+			t.Fatalf("Expected a released request not to be released again, got %+v", events)
+		}
+		if events := filter.filter(responseInit(1, 400*time.Millisecond, 200)); len(events) != 1 {
+			t.Fatalf("Expected the response of a released request to be passed on, got %+v", events)
+		}
+		if events := filter.filter(responseInit(2, 10*time.Millisecond, 200)); len(events) != 0 {
+			t.Fatalf("Expected a fast response to be dropped, got %+v", events)
+		}
+		filter.filter(responseEnd(1))
+		filter.filter(responseEnd(2))
+		if len(filter.pending) != 0 || len(filter.started) != 0 || len(filter.matched) != 0 {
+			t.Fatalf("Expected completed streams to be forgotten, got %v, %v and %v", filter.pending, filter.started, filter.matched)
+		}
+	})
+
+	t.Run("Doesn't release requests filtered by status", func(t *testing.T) {
+		filter := newResponseFilter(responseCriteria{minLatency: time.Millisecond, minStatus: 500, maxStatus: 599})
+		filter.filter(requestInit(1, "", "/"))
+		filter.now = func() time.Time { return time.Now().Add(time.Second) }
+		if events := filter.expire(); len(events) != 0 {
+			t.Fatalf("Expected the request to be held, got %+v", events)
+		}
+	})
+
+	t.Run("Only passes responses with a status in range", func(t *testing.T) {
+		testCases := []struct {
+			httpStatus uint32
+			expected   bool
+		}{
+			{200, false},
+			{499, false},
+			{500, true},
+			{503, true},
+			{599, true},
+		}
+
+		filter := newResponseFilter(responseCriteria{minStatus: 500, maxStatus: 599})
+		for i, tc := range testCases {
+			filter.filter(requestInit(uint64(i), "", "/"))
+			events := filter.filter(responseInit(uint64(i), time.Millisecond, tc.httpStatus))
+			if passed := len(events) == 2; passed != tc.expected {
+				t.Fatalf("Expected response with status %d passed to be %t, got %t", tc.httpStatus, tc.expected, passed)
+			}
+		}
+	})
+
+	t.Run("Holds requests until their response ends with a gRPC status", func(t *testing.T) {
+		filter := newResponseFilter(responseCriteria{grpcStatuses: []uint32{uint32(codes.NotFound), uint32(codes.Unavailable)}})
+
+		testCases := []struct {
+			end      *public.TapEvent
+			expected bool
+		}{
+			{grpcResponseEnd(0, codes.OK), false},
+			{grpcResponseEnd(1, codes.NotFound), true},
+			{grpcResponseEnd(2, codes.Unavailable), true},
+			{responseEnd(3), false},
+		}
+
+		for i, tc := range testCases {
+			req, rsp := requestInit(uint64(i), "", "/"), responseInit(uint64(i), time.Millisecond, 200)
+			if events := append(filter.filter(req), filter.filter(rsp)...); len(events) != 0 {
+				t.Fatalf("Expected the request and response to be held, got %+v", events)
+			}
+			events := filter.filter(tc.end)
+			if passed := len(events) == 3 && events[0] == req && events[1] == rsp && events[2] == tc.end; passed != tc.expected {
+				t.Fatalf("Expected response ending with %+v passed to be %t, got %+v", tc.end, tc.expected, events)
+			}
+		}
+		if len(filter.pending) != 0 || len(filter.matched) != 0 {
+			t.Fatalf("Expected completed streams to be forgotten, got %v and %v", filter.pending, filter.matched)
+		}
+	})
+}
+
+func TestLiteralPrefix(t *testing.T) {
+	testCases := []struct {
+		expr     string
+		prefix   string
+		complete bool
+	}{
+		{"/books", "/books", true},
+		{"^/books", "/books", true},
+		{"/books$", "/books", false},
+		{"/books/[0-9]+", "/books/", false},
+		{"web-svc\\.emojivoto", "web-svc.emojivoto", true},
+		{"web-svc\\.emojivoto(:80)?", "web-svc.emojivoto", false},
+		{"(?i)/books", "", false},
+		{"/books|/authors", "/", false},
+		{".*", "", false},
+	}
+
+	for _, tc := range testCases {
+		prefix, complete := literalPrefix(tc.expr)
+		if prefix != tc.prefix || complete != tc.complete {
+			t.Fatalf("Expected literalPrefix(%q) to be (%q, %t), got (%q, %t)", tc.expr, tc.prefix, tc.complete, prefix, complete)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package tap
+
+import (
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// tapLimiter caps the number of tap streams that can be open at once, both
+// across the cluster and per target namespace, so that a runaway client
+// can't exhaust the tap service or overload every proxy it fans out to. A
+// limit of 0 disables that limit.
+type tapLimiter struct {
+	maxTaps             uint
+	maxTapsPerNamespace uint
+
+	mu         sync.Mutex
+	taps       uint
+	namespaces map[string]uint
+}
+
+func newTapLimiter(maxTaps, maxTapsPerNamespace uint) *tapLimiter {
+	return &tapLimiter{
+		maxTaps:             maxTaps,
+		maxTapsPerNamespace: maxTapsPerNamespace,
+		namespaces:          make(map[string]uint),
+	}
+}
+
+// acquire reserves a tap stream targeting namespace, returning a
+// ResourceExhausted error if a limit has been reached. Every successful call
+// must be paired with a call to release.
+func (l *tapLimiter) acquire(namespace string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxTaps > 0 && l.taps >= l.maxTaps {
+		return status.Errorf(codes.ResourceExhausted, "too many concurrent taps: the limit of %d has been reached, try again once other taps have finished", l.maxTaps)
+	}
+	if l.maxTapsPerNamespace > 0 && l.namespaces[namespace] >= l.maxTapsPerNamespace {
+		return status.Errorf(codes.ResourceExhausted, "too many concurrent taps in namespace [%s]: the limit of %d has been reached, try again once other taps have finished", namespace, l.maxTapsPerNamespace)
+	}
+
+	l.taps++
+	l.namespaces[namespace]++
+	return nil
+}
+
+func (l *tapLimiter) release(namespace string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.taps--
+	l.namespaces[namespace]--
+	if l.namespaces[namespace] == 0 {
+		delete(l.namespaces, namespace)
+	}
+}
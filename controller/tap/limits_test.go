@@ -0,0 +1,62 @@
+package tap
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTapLimiter(t *testing.T) {
+	t.Run("Enforces the per-namespace limit", func(t *testing.T) {
+		limiter := newTapLimiter(0, 2)
+
+		for i := 0; i < 2; i++ {
+			if err := limiter.acquire("emojivoto"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		assertResourceExhausted(t, limiter.acquire("emojivoto"))
+
+		if err := limiter.acquire("other"); err != nil {
+			t.Fatalf("Expected taps in other namespaces to be allowed, got: %v", err)
+		}
+
+		limiter.release("emojivoto")
+		if err := limiter.acquire("emojivoto"); err != nil {
+			t.Fatalf("Expected a released tap to free a slot, got: %v", err)
+		}
+	})
+
+	t.Run("Enforces the global limit", func(t *testing.T) {
+		limiter := newTapLimiter(2, 0)
+
+		for _, ns := range []string{"a", "b"} {
+			if err := limiter.acquire(ns); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		assertResourceExhausted(t, limiter.acquire("c"))
+
+		limiter.release("a")
+		if err := limiter.acquire("c"); err != nil {
+			t.Fatalf("Expected a released tap to free a slot, got: %v", err)
+		}
+	})
+
+	t.Run("Allows unlimited taps when limits are 0", func(t *testing.T) {
+		limiter := newTapLimiter(0, 0)
+
+		for i := 0; i < 100; i++ {
+			if err := limiter.acquire("emojivoto"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+	})
+}
+
+func assertResourceExhausted(t *testing.T, err error) {
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected a ResourceExhausted error, got: %v", err)
+	}
+}
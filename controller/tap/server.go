@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math/rand"
 	"net"
 	"strings"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	netpb "github.com/linkerd/linkerd2-proxy-api/go/net"
 	proxy "github.com/linkerd/linkerd2-proxy-api/go/tap"
 	apiUtil "github.com/linkerd/linkerd2/controller/api/util"
@@ -25,12 +27,25 @@ import (
 	"k8s.io/client-go/tools/cache"
 )
 
-const podIPIndex = "ip"
+const (
+	podIPIndex        = "ip"
+	svcClusterIPIndex = "clusterIP"
+
+	// maxSampledRpsScale and maxSampledRpsPerPod bound how many more
+	// requests each proxy is asked to report when a tap is sampled, since
+	// most of them are dropped here.
+	maxSampledRpsScale  = 10
+	maxSampledRpsPerPod = 1000
+)
 
 type (
 	server struct {
-		tapPort uint
-		k8sAPI  *k8s.API
+		tapPort             uint
+		controllerNamespace string
+		k8sAPI              *k8s.API
+		limiter             *tapLimiter
+		// podLabels are the pod label keys added to tap event metadata.
+		podLabels []string
 	}
 )
 
@@ -50,24 +65,34 @@ func (s *server) TapByResource(req *public.TapByResourceRequest, stream pb.Tap_T
 		return status.Errorf(codes.InvalidArgument, "TapByResource received nil target ResourceSelection: %+v", *req)
 	}
 
-	objects, err := s.k8sAPI.GetObjects(req.Target.Resource.Namespace, req.Target.Resource.Type, req.Target.Resource.Name)
+	pods, err := s.podsFor(req.Target.Resource)
 	if err != nil {
 		return apiUtil.GRPCError(err)
 	}
 
-	pods := []*apiv1.Pod{}
-	for _, object := range objects {
-		podsFor, err := s.k8sAPI.GetPodsFor(object, false)
-		if err != nil {
-			return apiUtil.GRPCError(err)
-		}
+	if len(pods) == 0 {
+		return status.Errorf(codes.NotFound, "no pods found for ResourceSelection: %+v", *req.Target)
+	}
 
-		pods = append(pods, podsFor...)
+	// only tap pods whose proxies are managed by this control plane, so that
+	// multiple control planes can share a cluster
+	meshedPods := []*apiv1.Pod{}
+	for _, pod := range pods {
+		if pkgK8s.IsMeshed(pod, s.controllerNamespace) {
+			meshedPods = append(meshedPods, pod)
+		}
+	}
+	if len(meshedPods) == 0 {
+		return status.Errorf(codes.NotFound, "no pods meshed by the control plane in namespace [%s] found for ResourceSelection: %+v", s.controllerNamespace, *req.Target)
 	}
+	pods = meshedPods
 
-	if len(pods) == 0 {
-		return status.Errorf(codes.NotFound, "no pods found for ResourceSelection: %+v", *req.Target)
+	namespace := req.Target.Resource.Namespace
+	if err := s.limiter.acquire(namespace); err != nil {
+		log.Warnf("Rejecting tap for target %+v: %s", *req.Target.Resource, err)
+		return err
 	}
+	defer s.limiter.release(namespace)
 
 	log.Infof("Tapping %d pods for target: %+v", len(pods), *req.Target.Resource)
 
@@ -84,14 +109,25 @@ func (s *server) TapByResource(req *public.TapByResourceRequest, stream pb.Tap_T
 		rpsPerPod = 1
 	}
 
-	match, err := makeByResourceMatch(req.Match)
+	match, filter, err := makeByResourceMatch(req.Match, s.podIPsFor)
+	if err != nil {
+		return apiUtil.GRPCError(err)
+	}
+
+	criteria, err := makeResponseCriteria(req)
 	if err != nil {
 		return apiUtil.GRPCError(err)
 	}
 
+	filter.sampler, err = makeSampler(req)
+	if err != nil {
+		return apiUtil.GRPCError(err)
+	}
+	rpsPerPod = sampledRpsPerPod(rpsPerPod, req.SampleRate)
+
 	for _, pod := range pods {
 		// initiate a tap on the pod
-		go s.tapProxy(stream.Context(), rpsPerPod, match, pod.Status.PodIP, events)
+		go s.tapProxy(stream.Context(), rpsPerPod, match, filter, criteria, pod.Status.PodIP, events)
 	}
 
 	// read events from the taps and send them back
@@ -138,91 +174,337 @@ func parseMethod(method string) *proxy.HttpMethod {
 	}
 }
 
-func makeByResourceMatch(match *public.TapByResourceRequest_Match) (*proxy.ObserveRequest_Match, error) {
+// makeResponseCriteria validates the minimum latency, the response status
+// range and the gRPC statuses of a TapByResource request. Requests without
+// any of them report every response.
+func makeResponseCriteria(req *public.TapByResourceRequest) (responseCriteria, error) {
+	criteria := responseCriteria{}
+
+	if req.MinLatency != nil {
+		minLatency, err := ptypes.Duration(req.MinLatency)
+		if err != nil {
+			return criteria, status.Errorf(codes.InvalidArgument, "invalid minimum latency: %s", err)
+		}
+		if minLatency < 0 {
+			return criteria, status.Errorf(codes.InvalidArgument, "minimum latency must not be negative: %s", minLatency)
+		}
+		criteria.minLatency = minLatency
+	}
+
+	if statusRange := req.ResponseStatus; statusRange != nil {
+		if statusRange.Min < 100 || statusRange.Max > 599 || statusRange.Min > statusRange.Max {
+			return criteria, status.Errorf(codes.InvalidArgument, "invalid response status range: %d-%d", statusRange.Min, statusRange.Max)
+		}
+		criteria.minStatus, criteria.maxStatus = statusRange.Min, statusRange.Max
+	}
+
+	for _, code := range req.GrpcStatuses {
+		if code > uint32(codes.Unauthenticated) {
+			return criteria, status.Errorf(codes.InvalidArgument, "invalid gRPC status: %d", code)
+		}
+	}
+	criteria.grpcStatuses = req.GrpcStatuses
+
+	return criteria, nil
+}
+
+// makeSampler validates the sample rates of a TapByResource request. It
+// returns nil if every request is to be reported.
+func makeSampler(req *public.TapByResourceRequest) (*sampler, error) {
+	if req.SampleRate < 0 || req.SampleRate > 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "sample rate must be between 0 and 1: %v", req.SampleRate)
+	}
+	if req.SampleRate == 0 && len(req.PathSampleRates) == 0 {
+		return nil, nil
+	}
+
+	s := &sampler{rate: 1, random: rand.Float32}
+	if req.SampleRate > 0 {
+		s.rate = req.SampleRate
+	}
+	for _, override := range req.PathSampleRates {
+		if override.Rate < 0 || override.Rate > 1 {
+			return nil, status.Errorf(codes.InvalidArgument, "sample rate for path [%s] must be between 0 and 1: %v", override.Path, override.Rate)
+		}
+		re, err := apiUtil.CompileTapPathRegex(override.Path)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid path regex [%s]: %s", override.Path, err)
+		}
+		s.paths = append(s.paths, pathSampleRate{path: re, rate: override.Rate})
+	}
+	return s, nil
+}
+
+// sampledRpsPerPod returns the rate of requests that each proxy is asked to
+// report when requests are sampled with sampleRate. Proxies report the first
+// requests of each interval, up to the limit, so sampled requests are drawn
+// from proportionally more of them to spread them over the interval. The
+// limit is raised by at most maxSampledRpsScale, and to at most
+// maxSampledRpsPerPod, so a sampled tap only puts a bounded extra load on
+// the proxies; on busier pods, samples are still biased to the start of
+// each interval.
+func sampledRpsPerPod(rpsPerPod, sampleRate float32) float32 {
+	if sampleRate <= 0 || sampleRate >= 1 {
+		return rpsPerPod
+	}
+
+	scaled := rpsPerPod / sampleRate
+	if limit := rpsPerPod * maxSampledRpsScale; scaled > limit {
+		scaled = limit
+	}
+	if scaled > maxSampledRpsPerPod {
+		scaled = maxSampledRpsPerPod
+	}
+	if scaled < rpsPerPod {
+		return rpsPerPod
+	}
+	return scaled
+}
+
+// podsFor returns the pods of a resource.
+func (s *server) podsFor(resource *public.Resource) ([]*apiv1.Pod, error) {
+	objects, err := s.k8sAPI.GetObjects(resource.Namespace, resource.Type, resource.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := []*apiv1.Pod{}
+	for _, object := range objects {
+		podsFor, err := s.k8sAPI.GetPodsFor(object, false)
+		if err != nil {
+			return nil, err
+		}
+
+		pods = append(pods, podsFor...)
+	}
+	return pods, nil
+}
+
+// podIPsFor returns the IPs of the pods of a resource that have one.
+func (s *server) podIPsFor(resource *public.Resource) ([]string, error) {
+	pods, err := s.podsFor(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	ips := []string{}
+	for _, pod := range pods {
+		if pod.Status.PodIP != "" {
+			ips = append(ips, pod.Status.PodIP)
+		}
+	}
+	return ips, nil
+}
+
+// sourceResolver returns the IPs of the pods of a source resource, see
+// podIPsFor.
+type sourceResolver func(resource *public.Resource) ([]string, error)
+
+// makeByResourceMatch translates a TapByResource match into the match sent to
+// each proxy, along with a filter for the regexes that the proxy can't apply
+// itself. Proxies are only sent the literal prefix of each regex.
+func makeByResourceMatch(match *public.TapByResourceRequest_Match, sources sourceResolver) (*proxy.ObserveRequest_Match, *eventFilter, error) {
 	// TODO: for now assume it's always a single, flat `All` match list
 	seq := match.GetAll()
 	if seq == nil {
-		return nil, status.Errorf(codes.Unimplemented, "unexpected match specified: %+v", match)
+		return nil, nil, status.Errorf(codes.Unimplemented, "unexpected match specified: %+v", match)
 	}
 
 	matches := []*proxy.ObserveRequest_Match{}
+	filter := &eventFilter{}
 
 	for _, reqMatch := range seq.Matches {
-		switch typed := reqMatch.Match.(type) {
-		case *public.TapByResourceRequest_Match_Destinations:
-
-			for k, v := range destinationLabels(typed.Destinations.Resource) {
-				matches = append(matches, &proxy.ObserveRequest_Match{
-					Match: &proxy.ObserveRequest_Match_DestinationLabel{
-						DestinationLabel: &proxy.ObserveRequest_Match_Label{
-							Key:   k,
-							Value: v,
-						},
-					},
-				})
-			}
+		translated, err := makeMatch(reqMatch, filter, sources, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		matches = append(matches, translated...)
+	}
 
-		case *public.TapByResourceRequest_Match_Http_:
+	return &proxy.ObserveRequest_Match{
+		Match: &proxy.ObserveRequest_Match_All{
+			All: &proxy.ObserveRequest_Match_Seq{
+				Matches: matches,
+			},
+		},
+	}, filter, nil
+}
 
-			httpMatch := proxy.ObserveRequest_Match_Http{}
+// makeMatch translates a single match from a TapByResource request's `All`
+// list. It returns no matches when the proxy can't apply the match itself,
+// and in that case the match is only applied by the filter.
+func makeMatch(reqMatch *public.TapByResourceRequest_Match, filter *eventFilter, sources sourceResolver, negated bool) ([]*proxy.ObserveRequest_Match, error) {
+	switch typed := reqMatch.Match.(type) {
+	case *public.TapByResourceRequest_Match_Not:
+		if negated {
+			return nil, status.Errorf(codes.Unimplemented, "nested negative matches are not supported: %+v", reqMatch)
+		}
 
-			switch httpTyped := typed.Http.Match.(type) {
-			case *public.TapByResourceRequest_Match_Http_Scheme:
-				httpMatch = proxy.ObserveRequest_Match_Http{
-					Match: &proxy.ObserveRequest_Match_Http_Scheme{
-						Scheme: parseScheme(httpTyped.Scheme),
-					},
-				}
-			case *public.TapByResourceRequest_Match_Http_Method:
-				httpMatch = proxy.ObserveRequest_Match_Http{
-					Match: &proxy.ObserveRequest_Match_Http_Method{
-						Method: parseMethod(httpTyped.Method),
-					},
-				}
-			case *public.TapByResourceRequest_Match_Http_Authority:
-				httpMatch = proxy.ObserveRequest_Match_Http{
-					Match: &proxy.ObserveRequest_Match_Http_Authority{
-						Authority: &proxy.ObserveRequest_Match_Http_StringMatch{
-							Match: &proxy.ObserveRequest_Match_Http_StringMatch_Exact{
-								Exact: httpTyped.Authority,
-							},
-						},
-					},
-				}
-			case *public.TapByResourceRequest_Match_Http_Path:
-				httpMatch = proxy.ObserveRequest_Match_Http{
-					Match: &proxy.ObserveRequest_Match_Http_Path{
-						Path: &proxy.ObserveRequest_Match_Http_StringMatch{
-							Match: &proxy.ObserveRequest_Match_Http_StringMatch_Prefix{
-								Prefix: httpTyped.Path,
-							},
-						},
+		inner, err := makeMatch(typed.Not, filter, sources, true)
+		if err != nil || len(inner) == 0 {
+			return nil, err
+		}
+		not := inner[0]
+		if len(inner) > 1 {
+			not = &proxy.ObserveRequest_Match{
+				Match: &proxy.ObserveRequest_Match_All{
+					All: &proxy.ObserveRequest_Match_Seq{
+						Matches: inner,
 					},
-				}
-			default:
-				return nil, status.Errorf(codes.Unimplemented, "unknown HTTP match type: %v", httpTyped)
+				},
 			}
+		}
+		return []*proxy.ObserveRequest_Match{
+			{
+				Match: &proxy.ObserveRequest_Match_Not{
+					Not: not,
+				},
+			},
+		}, nil
 
+	case *public.TapByResourceRequest_Match_Destinations:
+		matches := []*proxy.ObserveRequest_Match{}
+		for k, v := range destinationLabels(typed.Destinations.Resource) {
 			matches = append(matches, &proxy.ObserveRequest_Match{
-				Match: &proxy.ObserveRequest_Match_Http_{
-					Http: &httpMatch,
+				Match: &proxy.ObserveRequest_Match_DestinationLabel{
+					DestinationLabel: &proxy.ObserveRequest_Match_Label{
+						Key:   k,
+						Value: v,
+					},
 				},
 			})
+		}
+		return matches, nil
 
-		default:
-			return nil, status.Errorf(codes.Unimplemented, "unknown match type: %v", typed)
+	case *public.TapByResourceRequest_Match_Sources:
+		return makeSourceMatch(typed.Sources.Resource, sources)
+
+	case *public.TapByResourceRequest_Match_Http_:
+		httpMatch, err := makeHTTPMatch(typed.Http, filter, negated)
+		if err != nil || httpMatch == nil {
+			return nil, err
 		}
+		return []*proxy.ObserveRequest_Match{
+			{
+				Match: &proxy.ObserveRequest_Match_Http_{
+					Http: httpMatch,
+				},
+			},
+		}, nil
+
+	default:
+		return nil, status.Errorf(codes.Unimplemented, "unknown match type: %v", typed)
 	}
+}
 
-	return &proxy.ObserveRequest_Match{
-		Match: &proxy.ObserveRequest_Match_All{
-			All: &proxy.ObserveRequest_Match_Seq{
-				Matches: matches,
+// makeSourceMatch matches events sent from any of the pods of resource, by
+// their IPs, since proxies don't know the labels of the sources of inbound
+// requests.
+func makeSourceMatch(resource *public.Resource, sources sourceResolver) ([]*proxy.ObserveRequest_Match, error) {
+	ips, err := sources(resource)
+	if err != nil {
+		return nil, err
+	}
+	if len(ips) == 0 {
+		return nil, status.Errorf(codes.NotFound, "no pods found for source: %+v", *resource)
+	}
+
+	matches := []*proxy.ObserveRequest_Match{}
+	for _, ip := range ips {
+		proxyIP, err := addr.ParseProxyIPV4(ip)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "invalid IP of source pod [%s]: %s", ip, err)
+		}
+		matches = append(matches, &proxy.ObserveRequest_Match{
+			Match: &proxy.ObserveRequest_Match_Source{
+				Source: &proxy.ObserveRequest_Match_Tcp{
+					Match: &proxy.ObserveRequest_Match_Tcp_Netmask_{
+						Netmask: &proxy.ObserveRequest_Match_Tcp_Netmask{
+							Ip:   proxyIP,
+							Mask: 32,
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return []*proxy.ObserveRequest_Match{
+		{
+			Match: &proxy.ObserveRequest_Match_Any{
+				Any: &proxy.ObserveRequest_Match_Seq{
+					Matches: matches,
+				},
 			},
 		},
 	}, nil
 }
 
+func makeHTTPMatch(match *public.TapByResourceRequest_Match_Http, filter *eventFilter, negated bool) (*proxy.ObserveRequest_Match_Http, error) {
+	switch httpTyped := match.Match.(type) {
+	case *public.TapByResourceRequest_Match_Http_Scheme:
+		return &proxy.ObserveRequest_Match_Http{
+			Match: &proxy.ObserveRequest_Match_Http_Scheme{
+				Scheme: parseScheme(httpTyped.Scheme),
+			},
+		}, nil
+
+	case *public.TapByResourceRequest_Match_Http_Method:
+		return &proxy.ObserveRequest_Match_Http{
+			Match: &proxy.ObserveRequest_Match_Http_Method{
+				Method: parseMethod(httpTyped.Method),
+			},
+		}, nil
+
+	case *public.TapByResourceRequest_Match_Http_Authority:
+		if err := filter.addAuthority(httpTyped.Authority, negated); err != nil {
+			return nil, err
+		}
+		// A negated prefix would exclude more than the regex does, so negated
+		// regexes are only sent to the proxy when they're entirely literal.
+		prefix, complete := literalPrefix(httpTyped.Authority)
+		if prefix == "" || (negated && !complete) {
+			return nil, nil
+		}
+		stringMatch := &proxy.ObserveRequest_Match_Http_StringMatch{
+			Match: &proxy.ObserveRequest_Match_Http_StringMatch_Prefix{
+				Prefix: prefix,
+			},
+		}
+		if complete {
+			stringMatch.Match = &proxy.ObserveRequest_Match_Http_StringMatch_Exact{
+				Exact: prefix,
+			}
+		}
+		return &proxy.ObserveRequest_Match_Http{
+			Match: &proxy.ObserveRequest_Match_Http_Authority{
+				Authority: stringMatch,
+			},
+		}, nil
+
+	case *public.TapByResourceRequest_Match_Http_Path:
+		if err := filter.addPath(httpTyped.Path, negated); err != nil {
+			return nil, err
+		}
+		prefix, complete := literalPrefix(httpTyped.Path)
+		if prefix == "" || (negated && !complete) {
+			return nil, nil
+		}
+		return &proxy.ObserveRequest_Match_Http{
+			Match: &proxy.ObserveRequest_Match_Http_Path{
+				Path: &proxy.ObserveRequest_Match_Http_StringMatch{
+					Match: &proxy.ObserveRequest_Match_Http_StringMatch_Prefix{
+						Prefix: prefix,
+					},
+				},
+			},
+		}, nil
+
+	default:
+		return nil, status.Errorf(codes.Unimplemented, "unknown HTTP match type: %v", httpTyped)
+	}
+}
+
 // TODO: factor out with `promLabels` in public-api
 func destinationLabels(resource *public.Resource) map[string]string {
 	dstLabels := map[string]string{}
@@ -243,7 +525,10 @@ func destinationLabels(resource *public.Resource) map[string]string {
 // of maxRps * 10s at most once per 10s window.  If this limit is reached in
 // less than 10s, we sleep until the end of the window before calling Observe
 // again.
-func (s *server) tapProxy(ctx context.Context, maxRps float32, match *proxy.ObserveRequest_Match, addr string, events chan *public.TapEvent) {
+// Proxies only match literal prefixes, so requests that filter then drops for
+// not matching a regex, or for not being sampled, still count toward the
+// limit.
+func (s *server) tapProxy(ctx context.Context, maxRps float32, match *proxy.ObserveRequest_Match, filter *eventFilter, criteria responseCriteria, addr string, events chan *public.TapEvent) {
 	tapAddr := fmt.Sprintf("%s:%d", addr, s.tapPort)
 	log.Infof("Establishing tap on %s", tapAddr)
 	conn, err := grpc.DialContext(ctx, tapAddr, grpc.WithInsecure())
@@ -258,6 +543,15 @@ func (s *server) tapProxy(ctx context.Context, maxRps float32, match *proxy.Obse
 		Match: match,
 	}
 
+	// requests that are still waiting for a response are checked
+	// periodically, so that hung requests are reported too
+	var expire <-chan time.Time
+	if interval := criteria.expireInterval(); interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		expire = ticker.C
+	}
+
 	for { // Request loop
 		windowStart := time.Now()
 		windowEnd := windowStart.Add(tapInterval)
@@ -266,16 +560,47 @@ func (s *server) tapProxy(ctx context.Context, maxRps float32, match *proxy.Obse
 			log.Error(err)
 			return
 		}
-		for { // Stream loop
-			event, err := rsp.Recv()
-			if err == io.EOF {
-				break
+		// requests tracked by the event and response filters don't outlive the
+		// Observe call they were reported by, which bounds them by the limit
+		// even if their streams never end
+		streams := make(map[streamKey]struct{})
+		responses := newResponseFilter(criteria)
+
+		received := make(chan *proxy.TapEvent)
+		errs := make(chan error, 1)
+		go func() {
+			for {
+				event, err := rsp.Recv()
+				if err != nil {
+					errs <- err
+					return
+				}
+				received <- event
 			}
-			if err != nil {
+		}()
+
+	streamLoop:
+		for {
+			select {
+			case event := <-received:
+				translated := s.translateEvent(event)
+				if !filter.accept(translated, streams) {
+					continue
+				}
+				for _, ev := range responses.filter(translated) {
+					events <- ev
+				}
+			case <-expire:
+				for _, ev := range responses.expire() {
+					events <- ev
+				}
+			case err := <-errs:
+				if err == io.EOF {
+					break streamLoop
+				}
 				log.Error(err)
 				return
 			}
-			events <- s.translateEvent(event)
 		}
 		if time.Now().Before(windowEnd) {
 			time.Sleep(time.Until(windowEnd))
@@ -460,9 +785,14 @@ func (s *server) translateEvent(orig *proxy.TapEvent) *public.TapEvent {
 func NewServer(
 	addr string,
 	tapPort uint,
+	controllerNamespace string,
+	maxTaps uint,
+	maxTapsPerNamespace uint,
+	podLabels []string,
 	k8sAPI *k8s.API,
 ) (*grpc.Server, net.Listener, error) {
 	k8sAPI.Pod().Informer().AddIndexers(cache.Indexers{podIPIndex: indexPodByIP})
+	k8sAPI.Svc().Informer().AddIndexers(cache.Indexers{svcClusterIPIndex: indexServiceByClusterIP})
 
 	lis, err := net.Listen("tcp", addr)
 	if err != nil {
@@ -471,8 +801,11 @@ func NewServer(
 
 	s := prometheus.NewGrpcServer()
 	srv := server{
-		tapPort: tapPort,
-		k8sAPI:  k8sAPI,
+		tapPort:             tapPort,
+		controllerNamespace: controllerNamespace,
+		k8sAPI:              k8sAPI,
+		limiter:             newTapLimiter(maxTaps, maxTapsPerNamespace),
+		podLabels:           podLabels,
 	}
 	pb.RegisterTapServer(s, &srv)
 
@@ -486,6 +819,17 @@ func indexPodByIP(obj interface{}) ([]string, error) {
 	return []string{""}, fmt.Errorf("object is not a pod")
 }
 
+func indexServiceByClusterIP(obj interface{}) ([]string, error) {
+	if svc, ok := obj.(*apiv1.Service); ok {
+		// Headless services don't have a VIP to resolve.
+		if svc.Spec.ClusterIP == "" || svc.Spec.ClusterIP == apiv1.ClusterIPNone {
+			return []string{}, nil
+		}
+		return []string{svc.Spec.ClusterIP}, nil
+	}
+	return []string{""}, fmt.Errorf("object is not a service")
+}
+
 // hydrateEventLabels attempts to hydrate the metadata labels for an event's
 // source and (if the event was reported by an inbound proxy) destination,
 // and adds them to the event's `SourceMeta` and `DestinationMeta` fields.
@@ -493,21 +837,61 @@ func indexPodByIP(obj interface{}) ([]string, error) {
 // Since errors encountered while hydrating metadata are non-fatal and result
 // only in missing labels, any errors are logged at the WARN level.
 func (s *server) hydrateEventLabels(ev *public.TapEvent) {
+	if ev.SourceMeta.Labels == nil {
+		ev.SourceMeta.Labels = make(map[string]string)
+	}
+	if ev.DestinationMeta.Labels == nil {
+		ev.DestinationMeta.Labels = make(map[string]string)
+	}
+
 	err := s.hydrateIPLabels(ev.Source.Ip, ev.SourceMeta.Labels)
 	if err != nil {
 		log.Warnf("error hydrating source labels: %s", err)
 	}
 
-	if ev.ProxyDirection == public.TapEvent_INBOUND {
-		// Events emitted by an inbound proxies don't have destination labels,
-		// since the inbound proxy _is_ the destination, and proxies don't know
-		// their own labels.
-		err = s.hydrateIPLabels(ev.Destination.Ip, ev.DestinationMeta.Labels)
+	// Events emitted by an inbound proxies don't have destination labels,
+	// since the inbound proxy _is_ the destination, and proxies don't know
+	// their own labels. Outbound events have the labels that the destination
+	// service sent the proxy, which don't include the service account.
+	err = s.hydrateIPLabels(ev.Destination.Ip, ev.DestinationMeta.Labels)
+	if err != nil {
+		log.Warnf("error hydrating destination labels: %s", err)
+	}
+
+	// Requests that an outbound proxy couldn't resolve to an endpoint are
+	// addressed to a service VIP, for which there's no pod to label.
+	if ev.DestinationMeta.Labels["pod"] == "" {
+		err = s.hydrateServiceLabels(ev.Destination, ev.DestinationMeta.Labels)
 		if err != nil {
-			log.Warnf("error hydrating destination labels: %s", err)
+			log.Warnf("error hydrating destination service labels: %s", err)
 		}
 	}
+}
+
+// hydrateServiceLabels adds the name, namespace and port name of the service
+// whose cluster IP is `address` to `labels`, if there is one.
+func (s *server) hydrateServiceLabels(address *public.TcpAddress, labels map[string]string) error {
+	ipStr := addr.PublicIPToString(address.GetIp())
+	objs, err := s.k8sAPI.Svc().Informer().GetIndexer().ByIndex(svcClusterIPIndex, ipStr)
+	if err != nil {
+		return err
+	}
+	if len(objs) != 1 {
+		log.Debugf("found %d services for IP %s", len(objs), ipStr)
+		return nil
+	}
 
+	svc := objs[0].(*apiv1.Service)
+	labels["service"] = svc.Name
+	if labels["namespace"] == "" {
+		labels["namespace"] = svc.Namespace
+	}
+	for _, port := range svc.Spec.Ports {
+		if uint32(port.Port) == address.GetPort() && port.Name != "" {
+			labels["service_port"] = port.Name
+		}
+	}
+	return nil
 }
 
 // hydrateIPMeta attempts to determine the metadata labels for `ip` and, if
@@ -523,8 +907,20 @@ func (s *server) hydrateIPLabels(ip *public.IPAddress, labels map[string]string)
 	default:
 		ownerKind, ownerName := s.k8sAPI.GetOwnerKindAndName(pod)
 		podLabels := pkgK8s.GetPodLabels(ownerKind, ownerName, pod)
+		podLabels["namespace"] = pod.Namespace
+		if pod.Spec.ServiceAccountName != "" {
+			podLabels["serviceaccount"] = pod.Spec.ServiceAccountName
+		}
+		for _, key := range s.podLabels {
+			if value, ok := pod.Labels[key]; ok {
+				podLabels[pkgK8s.MetricLabelName(key)] = value
+			}
+		}
+		// Labels reported by the proxy take precedence.
 		for key, value := range podLabels {
-			labels[key] = value
+			if _, ok := labels[key]; !ok {
+				labels[key] = value
+			}
 		}
 		return nil
 	}
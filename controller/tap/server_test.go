@@ -2,12 +2,18 @@ package tap
 
 import (
 	"context"
+	"reflect"
 	"testing"
 	"time"
 
+	proxy "github.com/linkerd/linkerd2-proxy-api/go/tap"
+	apiUtil "github.com/linkerd/linkerd2/controller/api/util"
 	public "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/addr"
 	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type tapExpected struct {
@@ -21,7 +27,7 @@ func TestTapByResource(t *testing.T) {
 	t.Run("Returns expected response", func(t *testing.T) {
 		expectations := []tapExpected{
 			tapExpected{
-				msg:    "rpc error: code = InvalidArgument desc = TapByResource received nil target ResourceSelection: {Target:<nil> Match:<nil> MaxRps:0}",
+				msg:    "rpc error: code = InvalidArgument desc = TapByResource received nil target ResourceSelection: {Target:<nil> Match:<nil> MaxRps:0 MinLatency:<nil> ResponseStatus:<nil> SampleRate:0 PathSampleRates:[] GrpcStatuses:[]}",
 				k8sRes: []string{},
 				req:    public.TapByResourceRequest{},
 			},
@@ -35,6 +41,7 @@ metadata:
   namespace: emojivoto
   labels:
     app: emoji-svc
+    linkerd.io/control-plane-ns: controller-ns
   annotations:
     linkerd.io/proxy-version: testinjectversion
 status:
@@ -109,6 +116,33 @@ metadata:
     linkerd.io/proxy-version: testinjectversion
 status:
   phase: Finished
+`,
+				},
+				req: public.TapByResourceRequest{
+					Target: &public.ResourceSelection{
+						Resource: &public.Resource{
+							Namespace: "emojivoto",
+							Type:      pkgK8s.Pod,
+							Name:      "emojivoto-meshed",
+						},
+					},
+				},
+			},
+			tapExpected{
+				msg: "rpc error: code = NotFound desc = no pods meshed by the control plane in namespace [controller-ns] found for ResourceSelection: {Resource:namespace:\"emojivoto\" type:\"pod\" name:\"emojivoto-meshed\"  LabelSelector:}",
+				k8sRes: []string{`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: emojivoto-meshed
+  namespace: emojivoto
+  labels:
+    app: emoji-svc
+    linkerd.io/control-plane-ns: other-controller-ns
+  annotations:
+    linkerd.io/proxy-version: testinjectversion
+status:
+  phase: Running
 `,
 				},
 				req: public.TapByResourceRequest{
@@ -134,6 +168,7 @@ metadata:
   namespace: emojivoto
   labels:
     app: emoji-svc
+    linkerd.io/control-plane-ns: controller-ns
   annotations:
     linkerd.io/proxy-version: testinjectversion
 status:
@@ -163,7 +198,7 @@ status:
 				t.Fatalf("NewFakeAPI returned an error: %s", err)
 			}
 
-			server, listener, err := NewServer("localhost:0", 0, k8sAPI)
+			server, listener, err := NewServer("localhost:0", 0, "controller-ns", 0, 0, nil, k8sAPI)
 			if err != nil {
 				t.Fatalf("NewServer error: %s", err)
 			}
@@ -196,3 +231,396 @@ status:
 		}
 	})
 }
+
+func TestHydrateEventLabels(t *testing.T) {
+	t.Run("Resolves service VIPs and named ports for unlabeled destinations", func(t *testing.T) {
+		k8sAPI, err := k8s.NewFakeAPI(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: emojivoto
+spec:
+  clusterIP: 10.96.0.15
+  ports:
+  - name: http
+    port: 80
+`)
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+
+		_, listener, err := NewServer("localhost:0", 0, "controller-ns", 0, 0, nil, k8sAPI)
+		if err != nil {
+			t.Fatalf("NewServer error: %s", err)
+		}
+		listener.Close()
+		k8sAPI.Sync(nil)
+
+		s := &server{k8sAPI: k8sAPI}
+		ev := &public.TapEvent{
+			ProxyDirection:  public.TapEvent_OUTBOUND,
+			Source:          &public.TcpAddress{Ip: addr.PublicIPV4(10, 1, 1, 1), Port: 5555},
+			SourceMeta:      &public.TapEvent_EndpointMeta{},
+			Destination:     &public.TcpAddress{Ip: addr.PublicIPV4(10, 96, 0, 15), Port: 80},
+			DestinationMeta: &public.TapEvent_EndpointMeta{},
+		}
+		s.hydrateEventLabels(ev)
+
+		expectedLabels := map[string]string{
+			"service":      "web",
+			"service_port": "http",
+			"namespace":    "emojivoto",
+		}
+		if !reflect.DeepEqual(ev.DestinationMeta.Labels, expectedLabels) {
+			t.Fatalf("Expected destination labels %v, got %v", expectedLabels, ev.DestinationMeta.Labels)
+		}
+	})
+
+	t.Run("Adds the namespace and service account of outbound destination pods", func(t *testing.T) {
+		k8sAPI, err := k8s.NewFakeAPI(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: voting-6b8b9c6f5d-x7k2p
+  namespace: emojivoto
+spec:
+  serviceAccountName: voting
+status:
+  phase: Running
+  podIP: 10.1.1.2
+`)
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+
+		_, listener, err := NewServer("localhost:0", 0, "controller-ns", 0, 0, nil, k8sAPI)
+		if err != nil {
+			t.Fatalf("NewServer error: %s", err)
+		}
+		listener.Close()
+		k8sAPI.Sync(nil)
+
+		s := &server{k8sAPI: k8sAPI}
+		ev := &public.TapEvent{
+			ProxyDirection: public.TapEvent_OUTBOUND,
+			Source:         &public.TcpAddress{Ip: addr.PublicIPV4(10, 1, 1, 1), Port: 5555},
+			SourceMeta:     &public.TapEvent_EndpointMeta{},
+			Destination:    &public.TcpAddress{Ip: addr.PublicIPV4(10, 1, 1, 2), Port: 8080},
+			DestinationMeta: &public.TapEvent_EndpointMeta{
+				Labels: map[string]string{"deployment": "voting", "pod": "voting-6b8b9c6f5d-x7k2p", "tls": "true"},
+			},
+		}
+		s.hydrateEventLabels(ev)
+
+		expectedLabels := map[string]string{
+			"deployment":     "voting",
+			"pod":            "voting-6b8b9c6f5d-x7k2p",
+			"tls":            "true",
+			"namespace":      "emojivoto",
+			"serviceaccount": "voting",
+		}
+		if !reflect.DeepEqual(ev.DestinationMeta.Labels, expectedLabels) {
+			t.Fatalf("Expected destination labels %v, got %v", expectedLabels, ev.DestinationMeta.Labels)
+		}
+	})
+
+	t.Run("Adds the configured pod labels", func(t *testing.T) {
+		k8sAPI, err := k8s.NewFakeAPI(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: voting-6b8b9c6f5d-x7k2p
+  namespace: emojivoto
+  labels:
+    app: voting-svc
+    cost-center: "1234"
+    team: emojivoto
+status:
+  phase: Running
+  podIP: 10.1.1.2
+`)
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+
+		_, listener, err := NewServer("localhost:0", 0, "controller-ns", 0, 0, nil, k8sAPI)
+		if err != nil {
+			t.Fatalf("NewServer error: %s", err)
+		}
+		listener.Close()
+		k8sAPI.Sync(nil)
+
+		s := &server{k8sAPI: k8sAPI, podLabels: []string{"team", "cost-center", "missing"}}
+		ev := &public.TapEvent{
+			ProxyDirection:  public.TapEvent_OUTBOUND,
+			Source:          &public.TcpAddress{Ip: addr.PublicIPV4(10, 1, 1, 1), Port: 5555},
+			SourceMeta:      &public.TapEvent_EndpointMeta{},
+			Destination:     &public.TcpAddress{Ip: addr.PublicIPV4(10, 1, 1, 2), Port: 8080},
+			DestinationMeta: &public.TapEvent_EndpointMeta{},
+		}
+		s.hydrateEventLabels(ev)
+
+		expectedLabels := map[string]string{
+			"pod":         "voting-6b8b9c6f5d-x7k2p",
+			"namespace":   "emojivoto",
+			"team":        "emojivoto",
+			"cost_center": "1234",
+		}
+		if !reflect.DeepEqual(ev.DestinationMeta.Labels, expectedLabels) {
+			t.Fatalf("Expected destination labels %v, got %v", expectedLabels, ev.DestinationMeta.Labels)
+		}
+	})
+}
+
+func TestMakeResponseCriteria(t *testing.T) {
+	t.Run("Accepts every response without criteria", func(t *testing.T) {
+		criteria, err := makeResponseCriteria(&public.TapByResourceRequest{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !criteria.empty() {
+			t.Fatalf("Expected empty criteria, got %+v", criteria)
+		}
+	})
+
+	t.Run("Rejects invalid status ranges", func(t *testing.T) {
+		invalid := []*public.TapByResourceRequest_StatusRange{
+			{Min: 0, Max: 0},
+			{Min: 500, Max: 400},
+			{Min: 500, Max: 600},
+		}
+
+		for _, statusRange := range invalid {
+			_, err := makeResponseCriteria(&public.TapByResourceRequest{ResponseStatus: statusRange})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("Expected InvalidArgument error for %+v, got %v", statusRange, err)
+			}
+		}
+	})
+
+	t.Run("Rejects unknown gRPC statuses", func(t *testing.T) {
+		_, err := makeResponseCriteria(&public.TapByResourceRequest{GrpcStatuses: []uint32{uint32(codes.NotFound), 17}})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("Expected InvalidArgument error, got %v", err)
+		}
+	})
+}
+
+func TestSampledRpsPerPod(t *testing.T) {
+	testCases := []struct {
+		rpsPerPod  float32
+		sampleRate float32
+		expected   float32
+	}{
+		{10, 0, 10},
+		{10, 1, 10},
+		{10, 0.5, 20},
+		{10, 0.01, 100},
+		{200, 0.1, 1000},
+		{2000, 0.5, 2000},
+	}
+
+	for _, tc := range testCases {
+		if rps := sampledRpsPerPod(tc.rpsPerPod, tc.sampleRate); rps != tc.expected {
+			t.Errorf("Expected %v rps per pod for %v rps sampled at %v, got %v", tc.expected, tc.rpsPerPod, tc.sampleRate, rps)
+		}
+	}
+}
+
+func TestMakeSampler(t *testing.T) {
+	t.Run("Samples nothing without sample rates", func(t *testing.T) {
+		sampler, err := makeSampler(&public.TapByResourceRequest{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if sampler != nil {
+			t.Fatalf("Expected no sampler, got %+v", sampler)
+		}
+	})
+
+	t.Run("Applies the first matching path override", func(t *testing.T) {
+		sampler, err := makeSampler(&public.TapByResourceRequest{
+			SampleRate: 0.5,
+			PathSampleRates: []*public.TapByResourceRequest_PathSampleRate{
+				{Path: "/healthz", Rate: 0},
+				{Path: "/api/checkout", Rate: 1},
+				{Path: "/api", Rate: 0.1},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		sampler.random = func() float32 { return 0.3 }
+
+		testCases := []struct {
+			path     string
+			expected bool
+		}{
+			{"/healthz", false},
+			{"/api/checkout", true},
+			{"/api/books", false},
+			{"/books", true},
+		}
+		for _, tc := range testCases {
+			if sampled := sampler.sample(tc.path); sampled != tc.expected {
+				t.Fatalf("Expected [%s] sampled to be %t, got %t", tc.path, tc.expected, sampled)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid sample rates", func(t *testing.T) {
+		invalid := []*public.TapByResourceRequest{
+			{SampleRate: -0.1},
+			{SampleRate: 1.5},
+			{PathSampleRates: []*public.TapByResourceRequest_PathSampleRate{{Path: "/", Rate: 2}}},
+			{PathSampleRates: []*public.TapByResourceRequest_PathSampleRate{{Path: "/books/[0-9", Rate: 0.5}}},
+		}
+
+		for _, req := range invalid {
+			_, err := makeSampler(req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("Expected InvalidArgument error for %+v, got %v", req, err)
+			}
+		}
+	})
+}
+
+func TestMakeByResourceMatch(t *testing.T) {
+	t.Run("Sends proxies negated matches for exclusion filters", func(t *testing.T) {
+		req, err := apiUtil.BuildTapByResourceRequest(apiUtil.TapRequestParams{
+			Resource:  "deploy/web",
+			Namespace: "emojivoto",
+			NotMethod: "POST",
+			NotPath:   "/healthz",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		match, filter, err := makeByResourceMatch(req.Match, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		matches := match.GetAll().GetMatches()
+		if len(matches) != 2 {
+			t.Fatalf("Expected 2 proxy matches, got %d: %+v", len(matches), matches)
+		}
+		if method := matches[0].GetNot().GetHttp().GetMethod().GetRegistered(); method != proxy.HttpMethod_POST {
+			t.Fatalf("Expected negated method [POST], got %+v", matches[0])
+		}
+		if path := matches[1].GetNot().GetHttp().GetPath().GetPrefix(); path != "/healthz" {
+			t.Fatalf("Expected negated path prefix [/healthz], got %+v", matches[1])
+		}
+		if len(filter.excludedPaths) != 1 {
+			t.Fatalf("Expected 1 excluded path in the filter, got %d", len(filter.excludedPaths))
+		}
+	})
+
+	t.Run("Only filters negated regexes that aren't literal", func(t *testing.T) {
+		req, err := apiUtil.BuildTapByResourceRequest(apiUtil.TapRequestParams{
+			Resource:  "deploy/web",
+			Namespace: "emojivoto",
+			NotPath:   "/books/[0-9]+",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		match, filter, err := makeByResourceMatch(req.Match, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if matches := match.GetAll().GetMatches(); len(matches) != 0 {
+			t.Fatalf("Expected no proxy matches, got %+v", matches)
+		}
+		if len(filter.excludedPaths) != 1 {
+			t.Fatalf("Expected 1 excluded path in the filter, got %d", len(filter.excludedPaths))
+		}
+	})
+
+	t.Run("Only filters negated paths with an end anchor", func(t *testing.T) {
+		req, err := apiUtil.BuildTapByResourceRequest(apiUtil.TapRequestParams{
+			Resource:  "deploy/web",
+			Namespace: "emojivoto",
+			NotPath:   "/x$",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		match, filter, err := makeByResourceMatch(req.Match, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if matches := match.GetAll().GetMatches(); len(matches) != 0 {
+			t.Fatalf("Expected no proxy matches, got %+v", matches)
+		}
+		if len(filter.excludedPaths) != 1 {
+			t.Fatalf("Expected 1 excluded path in the filter, got %d", len(filter.excludedPaths))
+		}
+		for path, excluded := range map[string]bool{"/x": true, "/x/y": false, "/xy": false} {
+			if matched := filter.excludedPaths[0].MatchString(path); matched != excluded {
+				t.Fatalf("Expected path [%s] excluded to be %t, got %t", path, excluded, matched)
+			}
+		}
+	})
+
+	t.Run("Sends proxies the IPs of the pods of a source", func(t *testing.T) {
+		req, err := apiUtil.BuildTapByResourceRequest(apiUtil.TapRequestParams{
+			Resource:     "deploy/web",
+			Namespace:    "emojivoto",
+			FromResource: "svc/gateway",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		sources := func(resource *public.Resource) ([]string, error) {
+			if resource.Type != pkgK8s.Service || resource.Name != "gateway" || resource.Namespace != "emojivoto" {
+				t.Fatalf("Unexpected source resource: %+v", resource)
+			}
+			return []string{"10.0.0.1", "10.0.0.2"}, nil
+		}
+
+		match, _, err := makeByResourceMatch(req.Match, sources)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		matches := match.GetAll().GetMatches()
+		if len(matches) != 1 {
+			t.Fatalf("Expected 1 proxy match, got %d: %+v", len(matches), matches)
+		}
+		sourceMatches := matches[0].GetAny().GetMatches()
+		if len(sourceMatches) != 2 {
+			t.Fatalf("Expected 2 source matches, got %d: %+v", len(sourceMatches), sourceMatches)
+		}
+		for i, expected := range []string{"10.0.0.1", "10.0.0.2"} {
+			netmask := sourceMatches[i].GetSource().GetNetmask()
+			if ip := addr.ProxyIPToString(netmask.GetIp()); ip != expected || netmask.GetMask() != 32 {
+				t.Fatalf("Expected source netmask [%s/32], got %+v", expected, sourceMatches[i])
+			}
+		}
+	})
+
+	t.Run("Returns NotFound when a source has no pods", func(t *testing.T) {
+		req, err := apiUtil.BuildTapByResourceRequest(apiUtil.TapRequestParams{
+			Resource:     "deploy/web",
+			Namespace:    "emojivoto",
+			FromResource: "svc/gateway",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		sources := func(*public.Resource) ([]string, error) { return []string{}, nil }
+		_, _, err = makeByResourceMatch(req.Match, sources)
+		if status.Code(err) != codes.NotFound {
+			t.Fatalf("Expected NotFound error, got %v", err)
+		}
+	})
+}
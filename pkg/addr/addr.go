@@ -14,6 +14,17 @@ func PublicAddressToString(addr *public.TcpAddress) string {
 	return fmt.Sprintf("%d.%d.%d.%d:%d", octects[0], octects[1], octects[2], octects[3], addr.GetPort())
 }
 
+// PublicAddressToServiceString renders an address that was resolved to a
+// Kubernetes service as "svc/<name>:<port>", using the port's name when the
+// service gives it one.
+func PublicAddressToServiceString(addr *public.TcpAddress, service, portName string) string {
+	port := portName
+	if port == "" {
+		port = strconv.FormatUint(uint64(addr.GetPort()), 10)
+	}
+	return fmt.Sprintf("svc/%s:%s", service, port)
+}
+
 func PublicIPToString(ip *public.IPAddress) string {
 	octets := decodeIPToOctets(ip.GetIpv4())
 	return fmt.Sprintf("%d.%d.%d.%d", octets[0], octets[1], octets[2], octets[3])
@@ -1,38 +1,73 @@
 package admin
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
-	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
 )
 
-type handler struct {
-	promHandler http.Handler
-	ready       bool
-	sync.RWMutex
-}
+// dependencyCheckTimeout bounds how long /ready waits on each dependency.
+const dependencyCheckTimeout = 5 * time.Second
 
-func StartServer(addr string, readyCh <-chan struct{}) {
-	log.Infof("starting admin server on %s", addr)
+// Dependency is something a component needs to be ready to serve, such as
+// synced informer caches or a reachable Prometheus. Check returns nil if the
+// dependency is met. An Optional dependency that isn't met only degrades the
+// component: it's reported on /ready without making the component unready.
+type Dependency struct {
+	Name     string
+	Check    func(ctx context.Context) error
+	Optional bool
+}
 
-	h := &handler{
-		promHandler: promhttp.Handler(),
-		ready:       readyCh == nil,
+// WaitFor returns a Dependency that's met once ch is closed.
+func WaitFor(name string, ch <-chan struct{}) Dependency {
+	return Dependency{
+		Name: name,
+		Check: func(ctx context.Context) error {
+			select {
+			case <-ch:
+				return nil
+			default:
+				return errors.New("pending")
+			}
+		},
 	}
+}
 
-	if readyCh != nil {
-		go func() {
-			<-readyCh
-			h.setReady(true)
-		}()
-	}
+// Readiness is the body of the /ready response.
+type Readiness struct {
+	Ready        bool               `json:"ready"`
+	Degraded     bool               `json:"degraded,omitempty"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+// DependencyStatus is the status of a single Dependency.
+type DependencyStatus struct {
+	Name     string `json:"name"`
+	Ready    bool   `json:"ready"`
+	Optional bool   `json:"optional,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type handler struct {
+	promHandler  http.Handler
+	dependencies []Dependency
+}
+
+// StartServer serves metrics and the /live and /ready endpoints on addr. The
+// component is ready once all of its required dependencies are met; /ready
+// responds with the status of each of them.
+func StartServer(addr string, dependencies ...Dependency) {
+	log.Infof("starting admin server on %s", addr)
 
 	s := &http.Server{
 		Addr:         addr,
-		Handler:      h,
+		Handler:      newHandler(dependencies),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
@@ -40,12 +75,21 @@ func StartServer(addr string, readyCh <-chan struct{}) {
 	log.Fatal(s.ListenAndServe())
 }
 
+func newHandler(dependencies []Dependency) *handler {
+	return &handler{
+		promHandler:  promhttp.Handler(),
+		dependencies: dependencies,
+	}
+}
+
 func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	switch req.URL.Path {
 	case "/metrics":
 		h.promHandler.ServeHTTP(w, req)
 	case "/ping":
 		h.servePing(w, req)
+	case "/live":
+		h.serveLive(w, req)
 	case "/ready":
 		h.serveReady(w, req)
 	default:
@@ -57,22 +101,52 @@ func (h *handler) servePing(w http.ResponseWriter, req *http.Request) {
 	w.Write([]byte("pong\n"))
 }
 
+// serveLive only reports that the process is serving; a component whose
+// dependencies are down shouldn't be restarted for it.
+func (h *handler) serveLive(w http.ResponseWriter, req *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]bool{"live": true})
+}
+
 func (h *handler) serveReady(w http.ResponseWriter, req *http.Request) {
-	if h.getReady() {
-		w.Write([]byte("ok\n"))
-	} else {
-		http.Error(w, "unready", http.StatusServiceUnavailable)
+	readiness := h.readiness(req.Context())
+
+	status := http.StatusOK
+	if !readiness.Ready {
+		status = http.StatusServiceUnavailable
 	}
+	writeJSON(w, status, readiness)
 }
 
-func (h *handler) getReady() bool {
-	h.RLock()
-	defer h.RUnlock()
-	return h.ready
+func (h *handler) readiness(ctx context.Context) *Readiness {
+	readiness := &Readiness{
+		Ready:        true,
+		Dependencies: make([]DependencyStatus, 0),
+	}
+
+	for _, dependency := range h.dependencies {
+		checkCtx, cancel := context.WithTimeout(ctx, dependencyCheckTimeout)
+		err := dependency.Check(checkCtx)
+		cancel()
+
+		status := DependencyStatus{Name: dependency.Name, Ready: err == nil, Optional: dependency.Optional}
+		if err != nil {
+			status.Error = err.Error()
+			if dependency.Optional {
+				readiness.Degraded = true
+			} else {
+				readiness.Ready = false
+			}
+		}
+		readiness.Dependencies = append(readiness.Dependencies, status)
+	}
+
+	return readiness
 }
 
-func (h *handler) setReady(ready bool) {
-	h.Lock()
-	defer h.Unlock()
-	h.ready = ready
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Errorf("error writing admin response: %s", err)
+	}
 }
@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestServeReady(t *testing.T) {
+	synced := make(chan struct{})
+	prometheusErr := errors.New("connection refused")
+	h := newHandler([]Dependency{
+		WaitFor("kubernetes caches synced", synced),
+		{Name: "prometheus reachable", Check: func(ctx context.Context) error { return prometheusErr }},
+	})
+
+	ready := func(t *testing.T) (int, Readiness) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/ready", nil))
+		var readiness Readiness
+		if err := json.NewDecoder(rec.Body).Decode(&readiness); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return rec.Code, readiness
+	}
+
+	t.Run("Reports each unmet dependency", func(t *testing.T) {
+		code, readiness := ready(t)
+		if code != http.StatusServiceUnavailable {
+			t.Fatalf("Expected status %d, got %d", http.StatusServiceUnavailable, code)
+		}
+		expected := Readiness{
+			Ready: false,
+			Dependencies: []DependencyStatus{
+				{Name: "kubernetes caches synced", Ready: false, Error: "pending"},
+				{Name: "prometheus reachable", Ready: false, Error: "connection refused"},
+			},
+		}
+		if !reflect.DeepEqual(readiness, expected) {
+			t.Fatalf("Expected %+v, got %+v", expected, readiness)
+		}
+	})
+
+	t.Run("Is ready once all dependencies are met", func(t *testing.T) {
+		close(synced)
+		prometheusErr = nil
+
+		code, readiness := ready(t)
+		if code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, code)
+		}
+		if !readiness.Ready || len(readiness.Dependencies) != 2 {
+			t.Fatalf("Expected both dependencies to be ready, got %+v", readiness)
+		}
+	})
+
+	t.Run("Is degraded, but ready, when an optional dependency isn't met", func(t *testing.T) {
+		h := newHandler([]Dependency{
+			WaitFor("kubernetes caches synced", synced),
+			{Name: "prometheus reachable", Check: func(ctx context.Context) error { return errors.New("connection refused") }, Optional: true},
+		})
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/ready", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+
+		var readiness Readiness
+		if err := json.NewDecoder(rec.Body).Decode(&readiness); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := Readiness{
+			Ready:    true,
+			Degraded: true,
+			Dependencies: []DependencyStatus{
+				{Name: "kubernetes caches synced", Ready: true},
+				{Name: "prometheus reachable", Ready: false, Optional: true, Error: "connection refused"},
+			},
+		}
+		if !reflect.DeepEqual(readiness, expected) {
+			t.Fatalf("Expected %+v, got %+v", expected, readiness)
+		}
+	})
+
+	t.Run("Is live regardless of dependencies", func(t *testing.T) {
+		h := newHandler([]Dependency{WaitFor("kubernetes caches synced", make(chan struct{}))})
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/live", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+}
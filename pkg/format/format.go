@@ -0,0 +1,75 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeFormat describes how timestamps are rendered in CLI output.
+type TimeFormat string
+
+const (
+	// Relative renders a timestamp as the offset from a reference time,
+	// typically the start of the command, e.g. "+1.250s".
+	Relative TimeFormat = "relative"
+
+	// RFC3339 renders a timestamp as an RFC3339 string with millisecond
+	// precision, in UTC.
+	RFC3339 TimeFormat = "rfc3339"
+
+	// UnixMillis renders a timestamp as milliseconds since the Unix epoch.
+	UnixMillis TimeFormat = "unix-millis"
+
+	rfc3339Millis = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// TimeFormats lists all supported time formats.
+var TimeFormats = []TimeFormat{Relative, RFC3339, UnixMillis}
+
+// ParseTimeFormat validates a user-supplied time format name. Matching is
+// case-insensitive.
+func ParseTimeFormat(name string) (TimeFormat, error) {
+	for _, f := range TimeFormats {
+		if strings.EqualFold(name, string(f)) {
+			return f, nil
+		}
+	}
+
+	names := make([]string, len(TimeFormats))
+	for i, f := range TimeFormats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unsupported time format [%s], must be one of: %s", name, strings.Join(names, ", "))
+}
+
+// Time renders t according to the time format. The reference time is only
+// used by the Relative format.
+func (f TimeFormat) Time(t time.Time, reference time.Time) string {
+	switch f {
+	case Relative:
+		offset := t.Sub(reference)
+		sign := "+"
+		if offset < 0 {
+			sign = "-"
+			offset = -offset
+		}
+		return fmt.Sprintf("%s%.3fs", sign, offset.Seconds())
+	case UnixMillis:
+		return fmt.Sprintf("%d", t.UnixNano()/int64(time.Millisecond))
+	default:
+		return t.UTC().Format(rfc3339Millis)
+	}
+}
+
+// Micros renders a duration as an integer number of microseconds, e.g.
+// "1500µs". Tap events use this unit for latencies and stream durations.
+func Micros(d time.Duration) string {
+	return fmt.Sprintf("%dµs", d/time.Microsecond)
+}
+
+// Millis renders a duration as an integer number of milliseconds, e.g.
+// "12ms". Aggregated latencies, as reported by stat, use this unit.
+func Millis(d time.Duration) string {
+	return fmt.Sprintf("%dms", d/time.Millisecond)
+}
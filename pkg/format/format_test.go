@@ -0,0 +1,69 @@
+package format
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeFormat(t *testing.T) {
+	t.Run("Accepts all supported formats, case-insensitively", func(t *testing.T) {
+		expectations := map[string]TimeFormat{
+			"relative":    Relative,
+			"RFC3339":     RFC3339,
+			"unix-millis": UnixMillis,
+		}
+
+		for name, expected := range expectations {
+			f, err := ParseTimeFormat(name)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if f != expected {
+				t.Fatalf("Expected [%s] to parse as [%s], got [%s]", name, expected, f)
+			}
+		}
+	})
+
+	t.Run("Rejects unknown formats", func(t *testing.T) {
+		expectedError := "unsupported time format [iso], must be one of: relative, rfc3339, unix-millis"
+
+		_, err := ParseTimeFormat("iso")
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s], got [%v]", expectedError, err)
+		}
+	})
+}
+
+func TestTime(t *testing.T) {
+	reference := time.Date(2018, time.August, 1, 12, 0, 0, 0, time.UTC)
+
+	expectations := []struct {
+		format   TimeFormat
+		time     time.Time
+		expected string
+	}{
+		{Relative, reference.Add(1250 * time.Millisecond), "+1.250s"},
+		{Relative, reference.Add(-2 * time.Second), "-2.000s"},
+		{RFC3339, reference.Add(5 * time.Millisecond), "2018-08-01T12:00:00.005Z"},
+		{RFC3339, reference.In(time.FixedZone("PDT", -7*60*60)), "2018-08-01T12:00:00.000Z"},
+		{UnixMillis, reference.Add(42 * time.Millisecond), "1533124800042"},
+	}
+
+	for _, exp := range expectations {
+		actual := exp.format.Time(exp.time, reference)
+		if actual != exp.expected {
+			t.Fatalf("Expected %s format to render [%s], got [%s]", exp.format, exp.expected, actual)
+		}
+	}
+}
+
+func TestDurations(t *testing.T) {
+	d := 100*time.Second + 1500*time.Microsecond
+
+	if actual := Micros(d); actual != "100001500µs" {
+		t.Fatalf("Unexpected microsecond rendering: %s", actual)
+	}
+	if actual := Millis(d); actual != "100001ms" {
+		t.Fatalf("Unexpected millisecond rendering: %s", actual)
+	}
+}
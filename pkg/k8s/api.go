@@ -12,6 +12,7 @@ import (
 	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
 	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	// Load all the auth plugins for the cloud providers.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -171,3 +172,14 @@ func NewAPI(configPath string) (KubernetesApi, error) {
 
 	return &kubernetesApi{Config: config}, nil
 }
+
+// NewClientSet returns a Kubernetes client configured from the kubeconfig at
+// configPath, or from the default kubeconfig if configPath is empty.
+func NewClientSet(configPath string) (kubernetes.Interface, error) {
+	config, err := getConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("error configuring Kubernetes API client: %v", err)
+	}
+
+	return kubernetes.NewForConfig(config)
+}
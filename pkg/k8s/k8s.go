@@ -12,6 +12,7 @@ const (
 	All                   = "all"
 	Authority             = "authority"
 	Deployment            = "deployment"
+	Ingress               = "ingress"
 	Namespace             = "namespace"
 	Pod                   = "pod"
 	ReplicationController = "replicationcontroller"
@@ -73,6 +74,8 @@ func CanonicalResourceNameFromFriendlyName(friendlyName string) (string, error)
 	switch friendlyName {
 	case "deploy", "deployment", "deployments":
 		return Deployment, nil
+	case "ing", "ingress", "ingresses":
+		return Ingress, nil
 	case "ns", "namespace", "namespaces":
 		return Namespace, nil
 	case "po", "pod", "pods":
@@ -96,6 +99,8 @@ func ShortNameFromCanonicalResourceName(canonicalName string) string {
 	switch canonicalName {
 	case Deployment:
 		return "deploy"
+	case Ingress:
+		return "ing"
 	case Namespace:
 		return "ns"
 	case Pod:
@@ -110,3 +115,14 @@ func ShortNameFromCanonicalResourceName(canonicalName string) string {
 		return ""
 	}
 }
+
+// PluralResourceName returns the plural of a k8s canonical name, as used in
+// Kubernetes API paths and RBAC rules.
+func PluralResourceName(canonicalName string) string {
+	switch canonicalName {
+	case Authority:
+		return "authorities"
+	default:
+		return canonicalName + "s"
+	}
+}
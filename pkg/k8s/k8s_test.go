@@ -88,6 +88,8 @@ func TestCanonicalResourceNameFromFriendlyName(t *testing.T) {
 			"deployments": Deployment,
 			"au":          Authority,
 			"authorities": Authority,
+			"ing":         Ingress,
+			"ingresses":   Ingress,
 		}
 
 		for input, expectedName := range expectations {
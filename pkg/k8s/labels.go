@@ -7,6 +7,7 @@ package k8s
 
 import (
 	"fmt"
+	"regexp"
 
 	"github.com/linkerd/linkerd2/pkg/version"
 	appsV1 "k8s.io/api/apps/v1"
@@ -50,6 +51,14 @@ const (
 	// StatefulSet that this proxy belongs to.
 	ProxyStatefulSetLabel = "linkerd.io/proxy-statefulset"
 
+	// ProxyRolloutLabel is injected into mesh-enabled apps, identifying the
+	// Argo Rollout that this proxy belongs to.
+	ProxyRolloutLabel = "linkerd.io/proxy-rollout"
+
+	// ProxyDeploymentConfigLabel is injected into mesh-enabled apps,
+	// identifying the OpenShift DeploymentConfig that this proxy belongs to.
+	ProxyDeploymentConfigLabel = "linkerd.io/proxy-deploymentconfig"
+
 	/*
 	 * Annotations
 	 */
@@ -62,6 +71,14 @@ const (
 	// (e.g. v0.1.3).
 	ProxyVersionAnnotation = "linkerd.io/proxy-version"
 
+	// SeccompContainerAnnotationPrefix is joined with a container name to form
+	// the annotation that sets that container's seccomp profile.
+	SeccompContainerAnnotationPrefix = "container.seccomp.security.alpha.kubernetes.io/"
+
+	// AppArmorContainerAnnotationPrefix is joined with a container name to form
+	// the annotation that sets that container's AppArmor profile.
+	AppArmorContainerAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
 	/*
 	 * Component Names
 	 */
@@ -104,6 +121,27 @@ func GetPodLabels(ownerKind, ownerName string, pod *coreV1.Pod) map[string]strin
 	return labels
 }
 
+var invalidMetricLabelChars = regexp.MustCompile("[^a-zA-Z0-9_]")
+
+// MetricLabelName returns the name of the Prometheus label for the pod label
+// key, e.g. "app.kubernetes.io/team" => "app_kubernetes_io_team". Label names
+// can't start with a digit, so those are prefixed with "_", e.g.
+// "3scale.net/team" => "_3scale_net_team".
+func MetricLabelName(key string) string {
+	name := invalidMetricLabelChars.ReplaceAllString(key, "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// PodLabelMetaLabel returns the meta label that Prometheus' Kubernetes service
+// discovery names after the pod label key, e.g. "app.kubernetes.io/team" =>
+// "__meta_kubernetes_pod_label_app_kubernetes_io_team".
+func PodLabelMetaLabel(key string) string {
+	return "__meta_kubernetes_pod_label_" + invalidMetricLabelChars.ReplaceAllString(key, "_")
+}
+
 func IsMeshed(pod *coreV1.Pod, controllerNS string) bool {
 	return pod.Labels[ControllerNSLabel] == controllerNS
 }
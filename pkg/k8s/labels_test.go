@@ -38,3 +38,18 @@ func TestGetPodLabels(t *testing.T) {
 		}
 	})
 }
+
+func TestMetricLabelName(t *testing.T) {
+	testCases := map[string]string{
+		"team":                   "team",
+		"cost-center":            "cost_center",
+		"app.kubernetes.io/team": "app_kubernetes_io_team",
+		"3scale.net/team":        "_3scale_net_team",
+	}
+
+	for key, expected := range testCases {
+		if name := MetricLabelName(key); name != expected {
+			t.Fatalf("Expected label name [%s] for [%s], got [%s]", expected, key, name)
+		}
+	}
+}
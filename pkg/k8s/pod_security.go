@@ -0,0 +1,100 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+	"github.com/linkerd/linkerd2/pkg/healthcheck"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+const (
+	PodSecuritySubsystemName           = "kubernetes-pod-security"
+	PodSecurityEnforceCheckDescription = "control plane namespace allows linkerd-init"
+
+	// PodSecurityEnforceLabel is the namespace label that sets the Pod Security
+	// Standard enforced by the Pod Security admission controller.
+	PodSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+)
+
+type podSecurityStatusChecker struct {
+	kubeapi   KubernetesApi
+	namespace string
+}
+
+// NewPodSecurityStatusChecker returns a StatusChecker that verifies that the
+// given namespace doesn't enforce a Pod Security Standard that would reject
+// the proxy init container, which requires the NET_ADMIN capability.
+func NewPodSecurityStatusChecker(kubeapi KubernetesApi, namespace string) healthcheck.StatusChecker {
+	return &podSecurityStatusChecker{
+		kubeapi:   kubeapi,
+		namespace: namespace,
+	}
+}
+
+func (c *podSecurityStatusChecker) SelfCheck() []*healthcheckPb.CheckResult {
+	checkResult := &healthcheckPb.CheckResult{
+		Status:           healthcheckPb.CheckStatus_OK,
+		SubsystemName:    PodSecuritySubsystemName,
+		CheckDescription: PodSecurityEnforceCheckDescription,
+	}
+
+	namespace, err := c.getNamespace()
+	if err != nil {
+		checkResult.Status = healthcheckPb.CheckStatus_ERROR
+		checkResult.FriendlyMessageToUser = err.Error()
+		return []*healthcheckPb.CheckResult{checkResult}
+	}
+
+	// Both the baseline and restricted standards disallow adding capabilities
+	// beyond the default set.
+	switch level := namespace.Labels[PodSecurityEnforceLabel]; level {
+	case "baseline", "restricted":
+		checkResult.Status = healthcheckPb.CheckStatus_FAIL
+		checkResult.FriendlyMessageToUser = fmt.Sprintf(
+			"Namespace [%s] enforces the [%s] Pod Security Standard, which rejects the NET_ADMIN capability required by the linkerd-init container; label it with %s=privileged",
+			c.namespace, level, PodSecurityEnforceLabel)
+	}
+
+	return []*healthcheckPb.CheckResult{checkResult}
+}
+
+func (c *podSecurityStatusChecker) getNamespace() (*coreV1.Namespace, error) {
+	client, err := c.kubeapi.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("Error connecting to the API. Error message is [%s]", err)
+	}
+
+	endpoint, err := c.kubeapi.UrlFor(c.namespace, "/")
+	if err != nil {
+		return nil, fmt.Errorf("Error generating URL for namespace [%s]: %s", c.namespace, err)
+	}
+	// UrlFor only builds URLs for resources within a namespace; trim the
+	// trailing slash to address the namespace itself.
+	endpoint.Path = strings.TrimSuffix(endpoint.Path, "/")
+
+	req, _ := http.NewRequest("GET", endpoint.String(), nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("Error calling the Kubernetes API: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected Kubernetes API response for namespace [%s]: %s", c.namespace, resp.Status)
+	}
+
+	var namespace coreV1.Namespace
+	if err := json.NewDecoder(resp.Body).Decode(&namespace); err != nil {
+		return nil, fmt.Errorf("Error decoding namespace [%s]: %s", c.namespace, err)
+	}
+	return &namespace, nil
+}
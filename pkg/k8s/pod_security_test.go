@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+)
+
+func TestPodSecurityStatusChecker(t *testing.T) {
+	testCases := []struct {
+		labels         string
+		expectedStatus healthcheckPb.CheckStatus
+	}{
+		{``, healthcheckPb.CheckStatus_OK},
+		{`"pod-security.kubernetes.io/enforce": "privileged"`, healthcheckPb.CheckStatus_OK},
+		{`"pod-security.kubernetes.io/enforce": "baseline"`, healthcheckPb.CheckStatus_FAIL},
+		{`"pod-security.kubernetes.io/enforce": "restricted"`, healthcheckPb.CheckStatus_FAIL},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d: %s", i, tc.labels), func(t *testing.T) {
+			var requestedPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				requestedPath = req.URL.Path
+				fmt.Fprintf(w, `{"metadata": {"name": "linkerd", "labels": {%s}}}`, tc.labels)
+			}))
+			defer server.Close()
+
+			u, _ := url.Parse(server.URL + "/api/v1/namespaces/linkerd/")
+			api := &MockKubeApi{
+				UrlForUrlToReturn:       u,
+				NewClientClientToReturn: server.Client(),
+			}
+
+			results := NewPodSecurityStatusChecker(api, "linkerd").SelfCheck()
+			if len(results) != 1 {
+				t.Fatalf("Expected 1 check result, got %d", len(results))
+			}
+			if results[0].Status != tc.expectedStatus {
+				t.Fatalf("Expected status [%s], got [%s]: %s", tc.expectedStatus, results[0].Status, results[0].FriendlyMessageToUser)
+			}
+			if requestedPath != "/api/v1/namespaces/linkerd" {
+				t.Fatalf("Expected request for the namespace, got [%s]", requestedPath)
+			}
+		})
+	}
+
+	t.Run("Returns an error when the namespace can't be fetched", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		u, _ := url.Parse(server.URL + "/api/v1/namespaces/linkerd/")
+		api := &MockKubeApi{
+			UrlForUrlToReturn:       u,
+			NewClientClientToReturn: server.Client(),
+		}
+
+		results := NewPodSecurityStatusChecker(api, "linkerd").SelfCheck()
+		if len(results) != 1 || results[0].Status != healthcheckPb.CheckStatus_ERROR {
+			t.Fatalf("Expected a single ERROR result, got %+v", results)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package k8s
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForward provides a port-forward connection into a Kubernetes cluster,
+// from a local port to a port on a pod.
+type PortForward struct {
+	method     string
+	url        *url.URL
+	config     *rest.Config
+	localPort  int
+	remotePort int
+	stopCh     chan struct{}
+	stopOnce   sync.Once
+	readyCh    chan struct{}
+}
+
+// NewPortForward returns a PortForward to remotePort on a running pod of the
+// deployment deployName in namespace. If localPort is 0, an ephemeral port is
+// chosen when the port-forward starts listening. Call Run to start
+// forwarding.
+func NewPortForward(configPath, namespace, deployName string, localPort, remotePort int) (*PortForward, error) {
+	config, err := getConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("error configuring Kubernetes API client: %v", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error configuring Kubernetes API client: %v", err)
+	}
+
+	pod, err := runningPodForDeployment(clientset, namespace, deployName)
+	if err != nil {
+		return nil, err
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod.Name).
+		SubResource("portforward")
+
+	return &PortForward{
+		method:     "POST",
+		url:        req.URL(),
+		config:     config,
+		localPort:  localPort,
+		remotePort: remotePort,
+		stopCh:     make(chan struct{}, 1),
+		readyCh:    make(chan struct{}),
+	}, nil
+}
+
+// Run starts forwarding connections, and blocks until Stop is called or the
+// connection to the Kubernetes API fails. It returns an error without
+// signalling Ready if the forwarded local port can't be determined.
+func (pf *PortForward) Run() error {
+	transport, upgrader, err := spdy.RoundTripperFor(pf.config)
+	if err != nil {
+		return fmt.Errorf("error configuring port-forward transport: %v", err)
+	}
+
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, pf.method, pf.url)
+	ports := []string{fmt.Sprintf("%d:%d", pf.localPort, pf.remotePort)}
+
+	listening := make(chan struct{})
+	fw, err := portforward.New(dialer, ports, pf.stopCh, listening, ioutil.Discard, ioutil.Discard)
+	if err != nil {
+		return fmt.Errorf("error creating port-forward: %v", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fw.ForwardPorts()
+	}()
+
+	select {
+	case <-listening:
+	case err := <-errCh:
+		return err
+	}
+
+	// The port-forward binds the local port itself, so an ephemeral port is
+	// only known once it's listening.
+	forwarded, err := fw.GetPorts()
+	if err == nil && len(forwarded) != 1 {
+		err = fmt.Errorf("expected 1 forwarded port, got %d", len(forwarded))
+	}
+	if err != nil {
+		pf.Stop()
+		<-errCh
+		return fmt.Errorf("error getting the forwarded port: %v", err)
+	}
+	pf.localPort = int(forwarded[0].Local)
+	close(pf.readyCh)
+
+	return <-errCh
+}
+
+// Ready returns a channel that's closed once the local port accepts
+// connections, after which AddressAndPort returns the local port.
+func (pf *PortForward) Ready() <-chan struct{} {
+	return pf.readyCh
+}
+
+// Stop terminates the port-forward. It's safe to call more than once.
+func (pf *PortForward) Stop() {
+	pf.stopOnce.Do(func() {
+		close(pf.stopCh)
+	})
+}
+
+// AddressAndPort returns the local address that's forwarded to the pod.
+func (pf *PortForward) AddressAndPort() string {
+	return fmt.Sprintf("127.0.0.1:%d", pf.localPort)
+}
+
+func runningPodForDeployment(clientset kubernetes.Interface, namespace, deployName string) (*coreV1.Pod, error) {
+	deploy, err := clientset.AppsV1().Deployments(namespace).Get(deployName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting deployment [%s/%s]: %v", namespace, deployName, err)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector for deployment [%s/%s]: %v", namespace, deployName, err)
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("error listing pods for deployment [%s/%s]: %v", namespace, deployName, err)
+	}
+
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == coreV1.PodRunning {
+			return &pods.Items[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("no running pods found for deployment [%s/%s]", namespace, deployName)
+}
@@ -0,0 +1,62 @@
+package k8s
+
+import (
+	"testing"
+
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRunningPodForDeployment(t *testing.T) {
+	deploy := &appsV1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "controller", Namespace: "linkerd"},
+		Spec: appsV1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{ControllerComponentLabel: "controller"},
+			},
+		},
+	}
+	pod := func(name string, labels map[string]string, phase coreV1.PodPhase) *coreV1.Pod {
+		return &coreV1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "linkerd", Labels: labels},
+			Status:     coreV1.PodStatus{Phase: phase},
+		}
+	}
+	controllerLabels := map[string]string{ControllerComponentLabel: "controller"}
+
+	t.Run("Returns a running pod of the deployment", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset([]runtime.Object{
+			deploy,
+			pod("controller-pending", controllerLabels, coreV1.PodPending),
+			pod("web-running", map[string]string{ControllerComponentLabel: "web"}, coreV1.PodRunning),
+			pod("controller-running", controllerLabels, coreV1.PodRunning),
+		}...)
+
+		found, err := runningPodForDeployment(clientset, "linkerd", "controller")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if found.Name != "controller-running" {
+			t.Fatalf("Expected pod [controller-running], got [%s]", found.Name)
+		}
+	})
+
+	t.Run("Returns an error when no pods are running", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset(deploy, pod("controller-pending", controllerLabels, coreV1.PodPending))
+
+		if _, err := runningPodForDeployment(clientset, "linkerd", "controller"); err == nil {
+			t.Fatalf("Expected an error, got nil")
+		}
+	})
+
+	t.Run("Returns an error when the deployment doesn't exist", func(t *testing.T) {
+		clientset := fake.NewSimpleClientset()
+
+		if _, err := runningPodForDeployment(clientset, "linkerd", "controller"); err == nil {
+			t.Fatalf("Expected an error, got nil")
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+	"github.com/linkerd/linkerd2/pkg/admin"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	ReadinessSubsystemName = "linkerd-readiness"
+
+	// ReadinessPath is the path of the admin server's readiness endpoint, see
+	// pkg/admin.
+	ReadinessPath = "/ready"
+
+	readinessTimeout = 5 * time.Second
+)
+
+// ReadinessPort returns the port of the container's admin server, if its
+// readiness probe is the admin server's /ready endpoint.
+func ReadinessPort(container coreV1.Container) (int32, bool) {
+	probe := container.ReadinessProbe
+	if probe == nil || probe.HTTPGet == nil || probe.HTTPGet.Path != ReadinessPath {
+		return 0, false
+	}
+
+	if probe.HTTPGet.Port.Type == intstr.Int {
+		return probe.HTTPGet.Port.IntVal, true
+	}
+	for _, port := range container.Ports {
+		if port.Name == probe.HTTPGet.Port.StrVal {
+			return port.ContainerPort, true
+		}
+	}
+	return 0, false
+}
+
+// GetReadiness fetches the readiness of a component from its admin server's
+// /ready endpoint.
+func GetReadiness(ctx context.Context, client *http.Client, endpoint *url.URL) (*admin.Readiness, error) {
+	req, _ := http.NewRequest("GET", endpoint.String(), nil)
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// unready components respond with their readiness too
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+
+	var readiness admin.Readiness
+	if err := json.NewDecoder(resp.Body).Decode(&readiness); err != nil {
+		return nil, err
+	}
+	return &readiness, nil
+}
+
+// ReadinessCheckResult returns the result of checking a container's readiness,
+// as returned by GetReadiness. A container that's ready but degraded passes,
+// with its unmet optional dependencies in the message.
+func ReadinessCheckResult(pod, container string, readiness *admin.Readiness, err error) *healthcheckPb.CheckResult {
+	result := &healthcheckPb.CheckResult{
+		Status:           healthcheckPb.CheckStatus_OK,
+		SubsystemName:    ReadinessSubsystemName,
+		CheckDescription: fmt.Sprintf("%s is ready", container),
+	}
+
+	switch {
+	case err != nil:
+		result.Status = healthcheckPb.CheckStatus_ERROR
+		result.FriendlyMessageToUser = fmt.Sprintf("Error fetching the readiness of container [%s] of pod [%s]: %s", container, pod, err)
+	case !readiness.Ready:
+		result.Status = healthcheckPb.CheckStatus_FAIL
+		result.FriendlyMessageToUser = fmt.Sprintf("Container [%s] of pod [%s] is waiting for: %s", container, pod, unmetDependencies(readiness, false))
+	case readiness.Degraded:
+		result.FriendlyMessageToUser = fmt.Sprintf("Container [%s] of pod [%s] is degraded without: %s", container, pod, unmetDependencies(readiness, true))
+	}
+	return result
+}
+
+func unmetDependencies(readiness *admin.Readiness, optional bool) string {
+	var unmet []string
+	for _, dependency := range readiness.Dependencies {
+		if !dependency.Ready && dependency.Optional == optional {
+			unmet = append(unmet, fmt.Sprintf("%s (%s)", dependency.Name, dependency.Error))
+		}
+	}
+	return strings.Join(unmet, ", ")
+}
@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+	"github.com/linkerd/linkerd2/pkg/admin"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestReadinessPort(t *testing.T) {
+	probe := func(path string, port intstr.IntOrString) *coreV1.Probe {
+		return &coreV1.Probe{Handler: coreV1.Handler{HTTPGet: &coreV1.HTTPGetAction{Path: path, Port: port}}}
+	}
+
+	testCases := []struct {
+		container coreV1.Container
+		port      int32
+		ok        bool
+	}{
+		{coreV1.Container{ReadinessProbe: probe("/ready", intstr.FromInt(9995))}, 9995, true},
+		{coreV1.Container{
+			Ports:          []coreV1.ContainerPort{{Name: "admin-http", ContainerPort: 9999}},
+			ReadinessProbe: probe("/ready", intstr.FromString("admin-http")),
+		}, 9999, true},
+		{coreV1.Container{ReadinessProbe: probe("/metrics", intstr.FromInt(4191))}, 0, false},
+		{coreV1.Container{}, 0, false},
+	}
+
+	for i, tc := range testCases {
+		port, ok := ReadinessPort(tc.container)
+		if port != tc.port || ok != tc.ok {
+			t.Errorf("Test case %d: expected (%d, %t), got (%d, %t)", i, tc.port, tc.ok, port, ok)
+		}
+	}
+}
+
+func TestGetReadiness(t *testing.T) {
+	get := func(status int, body string) (*admin.Readiness, error) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.URL.Path != ReadinessPath {
+				http.NotFound(w, req)
+				return
+			}
+			w.WriteHeader(status)
+			fmt.Fprint(w, body)
+		}))
+		defer server.Close()
+
+		endpoint, _ := url.Parse(server.URL + ReadinessPath)
+		return GetReadiness(context.Background(), server.Client(), endpoint)
+	}
+
+	t.Run("Returns the readiness of unready components", func(t *testing.T) {
+		readiness, err := get(http.StatusServiceUnavailable, `{"ready": false, "dependencies": [{"name": "kubernetes caches synced", "ready": false, "error": "pending"}]}`)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if readiness.Ready || len(readiness.Dependencies) != 1 {
+			t.Fatalf("Expected an unready component with one dependency, got %+v", readiness)
+		}
+	})
+
+	t.Run("Returns an error on unexpected responses", func(t *testing.T) {
+		_, err := get(http.StatusBadGateway, "")
+		if err == nil || err.Error() != "unexpected response: 502 Bad Gateway" {
+			t.Fatalf("Expected an unexpected response error, got %v", err)
+		}
+	})
+}
+
+func TestReadinessCheckResult(t *testing.T) {
+	testCases := []struct {
+		readiness *admin.Readiness
+		err       error
+		status    healthcheckPb.CheckStatus
+		message   string
+	}{
+		{
+			&admin.Readiness{Ready: true, Dependencies: []admin.DependencyStatus{{Name: "kubernetes caches synced", Ready: true}}},
+			nil,
+			healthcheckPb.CheckStatus_OK,
+			"",
+		},
+		{
+			&admin.Readiness{Ready: false, Dependencies: []admin.DependencyStatus{{Name: "kubernetes caches synced", Ready: false, Error: "pending"}}},
+			nil,
+			healthcheckPb.CheckStatus_FAIL,
+			"Container [public-api] of pod [controller-1234] is waiting for: kubernetes caches synced (pending)",
+		},
+		{
+			&admin.Readiness{Ready: true, Degraded: true, Dependencies: []admin.DependencyStatus{
+				{Name: "kubernetes caches synced", Ready: true},
+				{Name: "prometheus reachable", Ready: false, Optional: true, Error: "connection refused"},
+			}},
+			nil,
+			healthcheckPb.CheckStatus_OK,
+			"Container [public-api] of pod [controller-1234] is degraded without: prometheus reachable (connection refused)",
+		},
+		{
+			nil,
+			errors.New("connection refused"),
+			healthcheckPb.CheckStatus_ERROR,
+			"Error fetching the readiness of container [public-api] of pod [controller-1234]: connection refused",
+		},
+	}
+
+	for i, tc := range testCases {
+		r := ReadinessCheckResult("controller-1234", "public-api", tc.readiness, tc.err)
+		if r.SubsystemName != ReadinessSubsystemName || r.CheckDescription != "public-api is ready" || r.Status != tc.status || r.FriendlyMessageToUser != tc.message {
+			t.Errorf("Test case %d: expected [%s] %s, got %+v", i, tc.status, tc.message, r)
+		}
+	}
+}
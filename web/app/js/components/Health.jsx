@@ -0,0 +1,128 @@
+import _ from 'lodash';
+import ErrorBanner from './ErrorBanner.jsx';
+import PageHeader from './PageHeader.jsx';
+import PropTypes from 'prop-types';
+import React from 'react';
+import { withContext } from './util/AppContext.jsx';
+import { Icon, Spin, Table } from 'antd';
+
+const statusIcons = {
+  OK: <Icon type="check-circle" style={{ color: "#52c41a" }} />,
+  FAIL: <Icon type="close-circle" style={{ color: "#f5222d" }} />,
+  ERROR: <Icon type="exclamation-circle" style={{ color: "#f5222d" }} />
+};
+
+const checkColumns = [
+  {
+    title: "Status",
+    dataIndex: "Status",
+    key: "Status",
+    width: 80,
+    render: status => statusIcons[status]
+  },
+  {
+    title: "Subsystem",
+    dataIndex: "SubsystemName",
+    key: "SubsystemName"
+  },
+  {
+    title: "Check",
+    dataIndex: "CheckDescription",
+    key: "CheckDescription"
+  },
+  {
+    title: "Details",
+    dataIndex: "FriendlyMessageToUser",
+    key: "FriendlyMessageToUser"
+  }
+];
+
+class Health extends React.Component {
+  static propTypes = {
+    api: PropTypes.shape({
+      cancelCurrentRequests: PropTypes.func.isRequired,
+      fetch: PropTypes.func.isRequired,
+      getCurrentPromises: PropTypes.func.isRequired,
+      setCurrentRequests: PropTypes.func.isRequired,
+    }).isRequired,
+  }
+
+  constructor(props) {
+    super(props);
+    this.loadFromServer = this.loadFromServer.bind(this);
+    this.handleApiError = this.handleApiError.bind(this);
+    this.api = this.props.api;
+
+    this.state = {
+      pollingInterval: 5000,
+      results: [],
+      pendingRequests: false,
+      loaded: false,
+      error: null
+    };
+  }
+
+  componentDidMount() {
+    this.loadFromServer();
+    this.timerId = window.setInterval(this.loadFromServer, this.state.pollingInterval);
+  }
+
+  componentWillUnmount() {
+    window.clearInterval(this.timerId);
+    this.api.cancelCurrentRequests();
+  }
+
+  loadFromServer() {
+    if (this.state.pendingRequests) {
+      return; // don't make more requests if the ones we sent haven't completed
+    }
+    this.setState({ pendingRequests: true });
+
+    this.api.setCurrentRequests([
+      this.api.fetch("/api/check")
+    ]);
+
+    this.serverPromise = Promise.all(this.api.getCurrentPromises())
+      .then(([check]) => {
+        this.setState({
+          results: _.map(check.results, (r, i) => _.merge({ key: i }, r)),
+          pendingRequests: false,
+          loaded: true,
+          error: null
+        });
+      })
+      .catch(this.handleApiError);
+  }
+
+  handleApiError(e) {
+    if (e.isCanceled) {
+      return;
+    }
+
+    this.setState({
+      pendingRequests: false,
+      error: e
+    });
+  }
+
+  render() {
+    return (
+      <div className="page-content">
+        { !this.state.error ? null : <ErrorBanner message={this.state.error} /> }
+        { !this.state.loaded ? <Spin size="large" /> : (
+          <div>
+            <PageHeader header="Control plane health" hideButtons={true} />
+            <Table
+              className="metric-table"
+              dataSource={this.state.results}
+              columns={checkColumns}
+              pagination={false}
+              size="middle" />
+          </div>
+        )}
+      </div>
+    );
+  }
+}
+
+export default withContext(Health);
@@ -0,0 +1,82 @@
+import _ from 'lodash';
+import { Alert } from 'antd';
+import PropTypes from 'prop-types';
+import React from 'react';
+import { withContext } from './util/AppContext.jsx';
+
+const pollingInterval = 10000;
+
+/*
+ * Polls the web server for deployments that are violating the alert
+ * thresholds configured at install time, and renders a banner for each.
+ * Requests are made directly rather than through withREST, so that they
+ * are not cancelled along with the current page's requests.
+ */
+class NotificationBanner extends React.Component {
+  static propTypes = {
+    api: PropTypes.shape({
+      fetch: PropTypes.func.isRequired,
+    }).isRequired,
+  }
+
+  constructor(props) {
+    super(props);
+    this.state = {
+      alerts: [],
+      dismissed: {}
+    };
+  }
+
+  componentDidMount() {
+    this.loadFromServer();
+    this.timerId = window.setInterval(this.loadFromServer, pollingInterval);
+  }
+
+  componentWillUnmount() {
+    window.clearInterval(this.timerId);
+    if (this.request) {
+      this.request.cancel();
+    }
+  }
+
+  loadFromServer = () => {
+    if (this.request && !this.request.status()) {
+      this.request.cancel();
+    }
+
+    this.request = this.props.api.fetch("/api/notifications");
+    this.request.promise
+      .then(rsp => this.setState({ alerts: _.get(rsp, "alerts", []) }))
+      .catch(e => {
+        // notifications are best-effort; page-level errors are shown elsewhere
+        if (!e.isCanceled) { this.setState({ alerts: [] }); }
+      });
+  }
+
+  alertKey = alert => `${alert.namespace}/${alert.type}/${alert.name}/${alert.since}`;
+
+  dismiss = alert => {
+    this.setState({ dismissed: { ...this.state.dismissed, [this.alertKey(alert)]: true } });
+  }
+
+  render() {
+    const visible = _.reject(this.state.alerts, alert => this.state.dismissed[this.alertKey(alert)]);
+    if (_.isEmpty(visible)) { return null; }
+
+    return (
+      <div className="notification-banner">
+        {_.map(visible, alert => (
+          <Alert
+            key={this.alertKey(alert)}
+            type="warning"
+            showIcon
+            closable
+            onClose={() => this.dismiss(alert)}
+            message={`${alert.namespace}/${alert.name}: ${alert.message}`} />
+        ))}
+      </div>
+    );
+  }
+}
+
+export default withContext(NotificationBanner);
@@ -169,6 +169,13 @@ class Sidebar extends React.Component {
               </PrefixedLink>
             </Menu.Item>
 
+            <Menu.Item className="sidebar-menu-item" key="/health">
+              <PrefixedLink to="/health">
+                <Icon type="medicine-box" />
+                <span>Health</span>
+              </PrefixedLink>
+            </Menu.Item>
+
             {
               _.map(_.take(this.state.namespaces, this.state.maxNsItemsToShow), ns => {
                 return (
@@ -1,8 +1,10 @@
 import ApiHelpers from './components/util/ApiHelpers.jsx';
 import AppContext from './components/util/AppContext.jsx';
+import Health from './components/Health.jsx';
 import { Layout } from 'antd';
 import Namespace from './components/Namespace.jsx';
 import NoMatch from './components/NoMatch.jsx';
+import NotificationBanner from './components/NotificationBanner.jsx';
 import React from 'react';
 import ReactDOM from 'react-dom';
 import ResourceList from './components/ResourceList.jsx';
@@ -37,11 +39,13 @@ let applicationHtml = (
         <Layout>
           <Layout.Content style={{ margin: '0 0', padding: 0, background: '#fff' }}>
             <div className="main-content">
+              <NotificationBanner />
               <Switch>
                 <Redirect exact from={`${pathPrefix}/`} to={`${pathPrefix}/servicemesh`} />
                 <Route path={`${pathPrefix}/servicemesh`} component={ServiceMesh} />
                 <Route path={`${pathPrefix}/namespaces/:namespace`} component={Namespace} />
                 <Route path={`${pathPrefix}/tap`} component={Tap} />
+                <Route path={`${pathPrefix}/health`} component={Health} />
                 <Route
                   path={`${pathPrefix}/namespaces`}
                   render={() => <ResourceList resource="namespace" />} />
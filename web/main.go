@@ -3,9 +3,12 @@ package main
 import (
 	"context"
 	"flag"
+	"io/ioutil"
 	"net"
+	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -26,6 +29,20 @@ func main() {
 	reload := flag.Bool("reload", true, "reloading set to true or false")
 	webpackDevServer := flag.String("webpack-dev-server", "", "use webpack to serve static assets; frontend will use this instead of static-dir")
 	controllerNamespace := flag.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
+	notifyMinSuccessRate := flag.Float64("notify-min-success-rate", 0, "raise an alert when a deployment's success rate falls below this percentage; 0 disables")
+	notifyMaxLatencyP99 := flag.Duration("notify-max-latency-p99", 0, "raise an alert when a deployment's P99 latency exceeds this duration; 0 disables")
+	notifyWebhookURLFile := flag.String("notify-webhook-url-file", "", "file containing a Slack-compatible webhook URL to deliver alerts to; a missing file disables delivery")
+	notifyInterval := flag.Duration("notify-interval", 30*time.Second, "how often to evaluate alert thresholds")
+	auditWebhookURLFile := flag.String("audit-webhook-url-file", "", "file containing a URL that each audit entry of a dashboard action is POSTed to as JSON; a missing file disables delivery")
+	oidcIssuerURL := flag.String("oidc-issuer-url", "", "OpenID Connect issuer to require dashboard users to log in with; empty disables login")
+	oidcClientID := flag.String("oidc-client-id", "", "OpenID Connect client ID")
+	oidcClientSecretFile := flag.String("oidc-client-secret-file", "", "file containing the OpenID Connect client secret")
+	oidcRedirectURL := flag.String("oidc-redirect-url", "", "dashboard URL of /auth/callback, as registered with the OpenID Connect provider")
+	oidcGroupsClaim := flag.String("oidc-groups-claim", "groups", "ID token claim that lists the user's groups")
+	oidcScopes := flag.String("oidc-scopes", "email,groups", "comma-separated scopes to request in addition to openid")
+	oidcReadOnlyGroups := flag.String("oidc-read-only-groups", "", "comma-separated groups that may view the dashboard; empty allows any user who logs in")
+	oidcTapGroups := flag.String("oidc-tap-groups", "", "comma-separated groups that may also tap")
+	oidcAdminGroups := flag.String("oidc-admin-groups", "", "comma-separated groups that may also tap and view the audit log")
 	flags.ConfigureAndParse()
 
 	_, _, err := net.SplitHostPort(*kubernetesApiHost) // Verify kubernetesApiHost is of the form host:port.
@@ -40,19 +57,83 @@ func main() {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
-	server := srv.NewServer(*addr, *templateDir, *staticDir, *uuid, *controllerNamespace, *webpackDevServer, *reload, client)
+	notifier := srv.NewNotifier(client, srv.NotificationConfig{
+		MinSuccessRate: *notifyMinSuccessRate,
+		MaxLatencyP99:  *notifyMaxLatencyP99,
+		WebhookURL:     readWebhookURL(*notifyWebhookURLFile),
+		Interval:       *notifyInterval,
+	})
+	stopNotifier := make(chan struct{})
+	go notifier.Run(stopNotifier)
+
+	auditLog := srv.NewAuditLog(srv.AuditConfig{WebhookURL: readWebhookURL(*auditWebhookURLFile)})
+
+	var authenticator *srv.Authenticator
+	if *oidcIssuerURL != "" {
+		clientSecret := ""
+		if *oidcClientSecretFile != "" {
+			secret, err := ioutil.ReadFile(*oidcClientSecretFile)
+			if err != nil {
+				log.Fatalf("failed to read OIDC client secret: %s", err)
+			}
+			clientSecret = strings.TrimSpace(string(secret))
+		}
+
+		authenticator, err = srv.NewAuthenticator(srv.OIDCConfig{
+			IssuerURL:      *oidcIssuerURL,
+			ClientID:       *oidcClientID,
+			ClientSecret:   clientSecret,
+			RedirectURL:    *oidcRedirectURL,
+			Scopes:         srv.SplitList(*oidcScopes),
+			GroupsClaim:    *oidcGroupsClaim,
+			ReadOnlyGroups: srv.SplitList(*oidcReadOnlyGroups),
+			TapGroups:      srv.SplitList(*oidcTapGroups),
+			AdminGroups:    srv.SplitList(*oidcAdminGroups),
+		})
+		if err != nil {
+			log.Fatalf("failed to configure OIDC login: %s", err)
+		}
+	}
+
+	server := srv.NewServer(*addr, *templateDir, *staticDir, *uuid, *controllerNamespace, *webpackDevServer, *reload, client, notifier, auditLog, authenticator)
 
 	go func() {
 		log.Infof("starting HTTP server on %+v", *addr)
 		server.ListenAndServe()
 	}()
 
-	go admin.StartServer(*metricsAddr, nil)
+	go admin.StartServer(*metricsAddr)
 
 	<-stop
 
+	close(stopNotifier)
+
 	log.Infof("shutting down HTTP server on %+v", *addr)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	server.Shutdown(ctx)
 }
+
+// readWebhookURL returns the webhook URL in file, which is mounted from a
+// Secret. The Secret may not have a key for every webhook, so a missing file
+// disables the webhook.
+func readWebhookURL(file string) string {
+	if file == "" {
+		return ""
+	}
+
+	contents, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		log.Infof("webhook URL file %s doesn't exist, webhook disabled", file)
+		return ""
+	}
+	if err != nil {
+		log.Fatalf("failed to read webhook URL: %s", err)
+	}
+
+	webhookURL := strings.TrimSpace(string(contents))
+	if _, err := url.ParseRequestURI(webhookURL); err != nil {
+		log.Fatalf("%s must contain a valid URL: %s", file, err)
+	}
+	return webhookURL
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/julienschmidt/httprouter"
 	"github.com/linkerd/linkerd2/controller/api/util"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	log "github.com/sirupsen/logrus"
@@ -81,6 +82,16 @@ func (h *handler) handleApiPods(w http.ResponseWriter, req *http.Request, p http
 	renderJsonPb(w, pods)
 }
 
+func (h *handler) handleApiCheck(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	check, err := h.apiClient.SelfCheck(req.Context(), &healthcheckPb.SelfCheckRequest{})
+	if err != nil {
+		renderJsonError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	renderJsonPb(w, check)
+}
+
 func (h *handler) handleApiStat(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
 	allNs := false
 	if req.FormValue("all_namespaces") == "true" {
@@ -119,6 +130,26 @@ func (h *handler) handleApiStat(w http.ResponseWriter, req *http.Request, p http
 	renderJsonPb(w, result)
 }
 
+func (h *handler) handleApiNotifications(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	alerts := []Alert{}
+	if h.notifier != nil {
+		alerts = h.notifier.Alerts()
+	}
+	renderJson(w, map[string]interface{}{
+		"alerts": alerts,
+	})
+}
+
+func (h *handler) handleApiAudit(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
+	entries := []AuditEntry{}
+	if h.auditLog != nil {
+		entries = h.auditLog.Entries(req.FormValue("user"), req.FormValue("action"))
+	}
+	renderJson(w, map[string]interface{}{
+		"entries": entries,
+	})
+}
+
 func (h *handler) handleApiTap(w http.ResponseWriter, req *http.Request, p httprouter.Params) {
 	ws, err := websocketUpgrader.Upgrade(w, req, nil)
 	if err != nil {
@@ -155,6 +186,10 @@ func (h *handler) handleApiTap(w http.ResponseWriter, req *http.Request, p httpr
 		return
 	}
 
+	if h.auditLog != nil {
+		h.auditLog.Record(req, "tap", requestParams)
+	}
+
 	tapClient, err := h.apiClient.TapByResource(req.Context(), tapReq)
 	if err != nil {
 		ws.WriteMessage(websocket.CloseMessage, []byte(err.Error()))
@@ -9,6 +9,7 @@ import (
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/linkerd/linkerd2/controller/api/public"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 )
 
@@ -53,3 +54,34 @@ func TestHandleApiVersion(t *testing.T) {
 		t.Errorf("Expected to find: %+v", expectedVersionJson)
 	}
 }
+
+func TestHandleApiCheck(t *testing.T) {
+	mockApiClient := &public.MockApiClient{
+		SelfCheckResponseToReturn: &healthcheckPb.SelfCheckResponse{
+			Results: []*healthcheckPb.CheckResult{
+				{
+					SubsystemName:         "linkerd-readiness",
+					CheckDescription:      "public-api is ready",
+					Status:                healthcheckPb.CheckStatus_OK,
+					FriendlyMessageToUser: "Container [public-api] of pod [controller-1234] is degraded without: prometheus reachable (connection refused)",
+				},
+			},
+		},
+	}
+	handler := &handler{
+		apiClient: mockApiClient,
+	}
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/check", nil)
+	handler.handleApiCheck(recorder, req, httprouter.Params{})
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	expectedJson := `{"results":[{"SubsystemName":"linkerd-readiness","CheckDescription":"public-api is ready","Status":"OK","FriendlyMessageToUser":"Container [public-api] of pod [controller-1234] is degraded without: prometheus reachable (connection refused)"}]}`
+	if jsonResult := recorder.Body.String(); jsonResult != expectedJson {
+		t.Fatalf("Expected %s, got %s", expectedJson, jsonResult)
+	}
+}
@@ -0,0 +1,127 @@
+package srv
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultMaxAuditEntries = 1000
+
+	// anonymousUser is recorded for actions taken when login is disabled.
+	anonymousUser = "anonymous"
+)
+
+type (
+	// AuditConfig configures the AuditLog.
+	AuditConfig struct {
+		// WebhookURL, if set, receives each entry as JSON.
+		WebhookURL string
+		// MaxEntries is the number of entries kept in memory; older entries
+		// are dropped.
+		MaxEntries int
+	}
+
+	// AuditEntry records an action that a user initiated from the dashboard.
+	AuditEntry struct {
+		Time       time.Time   `json:"time"`
+		User       string      `json:"user"`
+		Action     string      `json:"action"`
+		Parameters interface{} `json:"parameters"`
+	}
+
+	// AuditLog keeps the most recent dashboard actions in memory so that they
+	// can be queried through the API.
+	AuditLog struct {
+		config     AuditConfig
+		httpClient *http.Client
+
+		mu      sync.RWMutex
+		entries []AuditEntry
+	}
+)
+
+// NewAuditLog returns an AuditLog for the given config.
+func NewAuditLog(config AuditConfig) *AuditLog {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = defaultMaxAuditEntries
+	}
+	return &AuditLog{
+		config:     config,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Record adds an entry for action by the user who made req. The entry is also
+// logged, and delivered to the webhook in the background.
+func (l *AuditLog) Record(req *http.Request, action string, parameters interface{}) {
+	user := UserFromRequest(req)
+	if user == "" {
+		user = anonymousUser
+	}
+	entry := AuditEntry{
+		Time:       time.Now(),
+		User:       user,
+		Action:     action,
+		Parameters: parameters,
+	}
+
+	l.mu.Lock()
+	l.entries = append(l.entries, entry)
+	if len(l.entries) > l.config.MaxEntries {
+		l.entries = l.entries[len(l.entries)-l.config.MaxEntries:]
+	}
+	l.mu.Unlock()
+
+	log.WithFields(log.Fields{
+		"user":       entry.User,
+		"action":     entry.Action,
+		"parameters": entry.Parameters,
+	}).Info("audit")
+
+	if l.config.WebhookURL != "" {
+		go l.deliver(entry)
+	}
+}
+
+// Entries returns the recorded entries, newest first. A non-empty user or
+// action only returns entries that match it.
+func (l *AuditLog) Entries(user, action string) []AuditEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entries := []AuditEntry{}
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		entry := l.entries[i]
+		if user != "" && entry.User != user {
+			continue
+		}
+		if action != "" && entry.Action != action {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (l *AuditLog) deliver(entry AuditEntry) {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		log.Errorf("failed to encode audit entry: %s", err)
+		return
+	}
+	rsp, err := l.httpClient.Post(l.config.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Errorf("failed to deliver audit entry: %s", err)
+		return
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		log.Errorf("audit webhook responded with unexpected status: %s", rsp.Status)
+	}
+}
@@ -0,0 +1,107 @@
+package srv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func requestAs(user string) *http.Request {
+	req := httptest.NewRequest("GET", "/api/tap", nil)
+	if user == "" {
+		return req
+	}
+	return req.WithContext(context.WithValue(req.Context(), userContextKey{}, user))
+}
+
+func TestAuditLog(t *testing.T) {
+	t.Run("Returns entries newest first, filtered by user and action", func(t *testing.T) {
+		auditLog := NewAuditLog(AuditConfig{})
+		auditLog.Record(requestAs("alice@example.com"), "tap", map[string]string{"resource": "deploy/web"})
+		auditLog.Record(requestAs("bob@example.com"), "tap", map[string]string{"resource": "deploy/voting"})
+		auditLog.Record(requestAs(""), "tap", nil)
+
+		entries := auditLog.Entries("", "")
+		if len(entries) != 3 {
+			t.Fatalf("Expected 3 entries, got %d", len(entries))
+		}
+		if entries[0].User != anonymousUser || entries[2].User != "alice@example.com" {
+			t.Fatalf("Expected entries newest first, got %+v", entries)
+		}
+
+		entries = auditLog.Entries("bob@example.com", "")
+		if len(entries) != 1 || entries[0].User != "bob@example.com" {
+			t.Fatalf("Expected one entry for bob, got %+v", entries)
+		}
+
+		if entries = auditLog.Entries("", "profile-edit"); len(entries) != 0 {
+			t.Fatalf("Expected no entries, got %+v", entries)
+		}
+	})
+
+	t.Run("Drops the oldest entries beyond MaxEntries", func(t *testing.T) {
+		auditLog := NewAuditLog(AuditConfig{MaxEntries: 2})
+		auditLog.Record(requestAs("first"), "tap", nil)
+		auditLog.Record(requestAs("second"), "tap", nil)
+		auditLog.Record(requestAs("third"), "tap", nil)
+
+		entries := auditLog.Entries("", "")
+		if len(entries) != 2 || entries[0].User != "third" || entries[1].User != "second" {
+			t.Fatalf("Expected the two newest entries, got %+v", entries)
+		}
+	})
+
+	t.Run("Delivers entries to the webhook", func(t *testing.T) {
+		delivered := make(chan AuditEntry, 1)
+		webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			var entry AuditEntry
+			if err := json.NewDecoder(req.Body).Decode(&entry); err != nil {
+				t.Errorf("Unexpected error decoding audit entry: %v", err)
+			}
+			delivered <- entry
+		}))
+		defer webhook.Close()
+
+		auditLog := NewAuditLog(AuditConfig{WebhookURL: webhook.URL})
+		auditLog.Record(requestAs("alice@example.com"), "tap", nil)
+
+		select {
+		case entry := <-delivered:
+			if entry.User != "alice@example.com" || entry.Action != "tap" {
+				t.Fatalf("Unexpected entry delivered: %+v", entry)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for the webhook")
+		}
+	})
+}
+
+func TestHandleApiAudit(t *testing.T) {
+	auditLog := NewAuditLog(AuditConfig{})
+	auditLog.Record(requestAs("alice@example.com"), "tap", nil)
+	auditLog.Record(requestAs("bob@example.com"), "tap", nil)
+
+	handler := &handler{auditLog: auditLog}
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/audit?user=alice@example.com", nil)
+	handler.handleApiAudit(recorder, req, httprouter.Params{})
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	var rsp struct {
+		Entries []AuditEntry `json:"entries"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &rsp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rsp.Entries) != 1 || rsp.Entries[0].User != "alice@example.com" {
+		t.Fatalf("Expected one entry for alice, got %+v", rsp.Entries)
+	}
+}
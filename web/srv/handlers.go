@@ -21,6 +21,8 @@ type (
 		apiClient           pb.ApiClient
 		uuid                string
 		controllerNamespace string
+		notifier            *Notifier
+		auditLog            *AuditLog
 	}
 )
 
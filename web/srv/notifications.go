@@ -0,0 +1,245 @@
+package srv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/util"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	// NotificationConfig holds the thresholds that the Notifier evaluates
+	// against each deployment's stats. A zero threshold disables that check.
+	NotificationConfig struct {
+		// MinSuccessRate is the lowest acceptable success rate, as a percentage.
+		MinSuccessRate float64
+		// MaxLatencyP99 is the highest acceptable P99 latency.
+		MaxLatencyP99 time.Duration
+		// WebhookURL, if set, receives a Slack-compatible message whenever an
+		// alert starts firing or resolves.
+		WebhookURL string
+		// Interval is how often stats are evaluated.
+		Interval time.Duration
+		// TimeWindow is the stats window that thresholds are evaluated over.
+		TimeWindow string
+	}
+
+	// Alert describes a deployment that is currently violating one of the
+	// configured thresholds.
+	Alert struct {
+		Namespace string    `json:"namespace"`
+		Name      string    `json:"name"`
+		Type      string    `json:"type"`
+		Message   string    `json:"message"`
+		Since     time.Time `json:"since"`
+	}
+
+	// Notifier periodically evaluates stats from the public API and tracks
+	// the set of alerts that are currently firing.
+	Notifier struct {
+		apiClient  pb.ApiClient
+		config     NotificationConfig
+		httpClient *http.Client
+
+		mu     sync.RWMutex
+		alerts map[string]Alert
+	}
+
+	webhookMessage struct {
+		Text string `json:"text"`
+	}
+)
+
+// NewNotifier returns a Notifier for the given config. Call Run to start
+// evaluating thresholds.
+func NewNotifier(apiClient pb.ApiClient, config NotificationConfig) *Notifier {
+	if config.Interval == 0 {
+		config.Interval = 30 * time.Second
+	}
+	if config.TimeWindow == "" {
+		config.TimeWindow = "1m"
+	}
+	return &Notifier{
+		apiClient:  apiClient,
+		config:     config,
+		httpClient: &http.Client{Timeout: timeout},
+		alerts:     make(map[string]Alert),
+	}
+}
+
+// Enabled returns true if at least one threshold is configured.
+func (n *Notifier) Enabled() bool {
+	return n.config.MinSuccessRate > 0 || n.config.MaxLatencyP99 > 0
+}
+
+// Run evaluates thresholds every Interval until stop is closed.
+func (n *Notifier) Run(stop <-chan struct{}) {
+	if !n.Enabled() {
+		log.Info("no notification thresholds configured, notifications disabled")
+		return
+	}
+
+	ticker := time.NewTicker(n.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		if err := n.evaluate(ctx, time.Now()); err != nil {
+			log.Errorf("failed to evaluate notification thresholds: %s", err)
+		}
+		cancel()
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Alerts returns the alerts that are currently firing, sorted by namespace
+// and name.
+func (n *Notifier) Alerts() []Alert {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	alerts := make([]Alert, 0, len(n.alerts))
+	for _, alert := range n.alerts {
+		alerts = append(alerts, alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Namespace != alerts[j].Namespace {
+			return alerts[i].Namespace < alerts[j].Namespace
+		}
+		return alerts[i].Name < alerts[j].Name
+	})
+	return alerts
+}
+
+func (n *Notifier) evaluate(ctx context.Context, now time.Time) error {
+	req, err := util.BuildStatSummaryRequest(util.StatSummaryRequestParams{
+		TimeWindow:    n.config.TimeWindow,
+		ResourceType:  k8s.Deployment,
+		AllNamespaces: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	rsp, err := n.apiClient.StatSummary(ctx, req)
+	if err != nil {
+		return err
+	}
+	if e := rsp.GetError(); e != nil {
+		return errors.New(e.Error)
+	}
+
+	firing := make(map[string]Alert)
+	for _, table := range rsp.GetOk().GetStatTables() {
+		for _, row := range table.GetPodGroup().GetRows() {
+			message := n.check(row.Stats)
+			if message == "" {
+				continue
+			}
+			res := row.GetResource()
+			firing[alertKey(res)] = Alert{
+				Namespace: res.GetNamespace(),
+				Name:      res.GetName(),
+				Type:      res.GetType(),
+				Message:   message,
+				Since:     now,
+			}
+		}
+	}
+
+	n.mu.Lock()
+	var started, resolved []Alert
+	for key, alert := range firing {
+		if previous, ok := n.alerts[key]; ok {
+			alert.Since = previous.Since
+			firing[key] = alert
+		} else {
+			started = append(started, alert)
+		}
+	}
+	for key, alert := range n.alerts {
+		if _, ok := firing[key]; !ok {
+			resolved = append(resolved, alert)
+		}
+	}
+	n.alerts = firing
+	n.mu.Unlock()
+
+	for _, alert := range started {
+		n.notify(fmt.Sprintf("[firing] %s/%s %s: %s", alert.Namespace, alert.Type, alert.Name, alert.Message))
+	}
+	for _, alert := range resolved {
+		n.notify(fmt.Sprintf("[resolved] %s/%s %s", alert.Namespace, alert.Type, alert.Name))
+	}
+	return nil
+}
+
+// check returns a description of the thresholds that stats violate, or an
+// empty string if there are none. Resources without traffic never alert.
+func (n *Notifier) check(stats *pb.BasicStats) string {
+	if stats == nil {
+		return ""
+	}
+	total := stats.SuccessCount + stats.FailureCount
+	if total == 0 {
+		return ""
+	}
+
+	var messages []string
+	if n.config.MinSuccessRate > 0 {
+		sr := 100 * float64(stats.SuccessCount) / float64(total)
+		if sr < n.config.MinSuccessRate {
+			messages = append(messages, fmt.Sprintf("success rate %.2f%% is below %.2f%%", sr, n.config.MinSuccessRate))
+		}
+	}
+	if n.config.MaxLatencyP99 > 0 {
+		p99 := time.Duration(stats.LatencyMsP99) * time.Millisecond
+		if p99 > n.config.MaxLatencyP99 {
+			messages = append(messages, fmt.Sprintf("P99 latency %s is above %s", p99, n.config.MaxLatencyP99))
+		}
+	}
+
+	return strings.Join(messages, ", ")
+}
+
+func (n *Notifier) notify(text string) {
+	log.Info(text)
+	if n.config.WebhookURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(webhookMessage{Text: text})
+	if err != nil {
+		log.Errorf("failed to encode webhook message: %s", err)
+		return
+	}
+	rsp, err := n.httpClient.Post(n.config.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Errorf("failed to deliver webhook message: %s", err)
+		return
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		log.Errorf("webhook responded with unexpected status: %s", rsp.Status)
+	}
+}
+
+func alertKey(res *pb.Resource) string {
+	return res.GetNamespace() + "/" + res.GetType() + "/" + res.GetName()
+}
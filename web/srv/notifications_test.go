@@ -0,0 +1,173 @@
+package srv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/linkerd/linkerd2/controller/api/public/publictest"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+)
+
+func statSummaryResponse(name string, success, failure, p99 uint64) *pb.StatSummaryResponse {
+	return &pb.StatSummaryResponse{
+		Response: &pb.StatSummaryResponse_Ok_{
+			Ok: &pb.StatSummaryResponse_Ok{
+				StatTables: []*pb.StatTable{
+					{
+						Table: &pb.StatTable_PodGroup_{
+							PodGroup: &pb.StatTable_PodGroup{
+								Rows: []*pb.StatTable_PodGroup_Row{
+									{
+										Resource: &pb.Resource{
+											Namespace: "emojivoto",
+											Type:      k8s.Deployment,
+											Name:      name,
+										},
+										Stats: &pb.BasicStats{
+											SuccessCount: success,
+											FailureCount: failure,
+											LatencyMsP99: p99,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	t.Run("Fires and resolves alerts, delivering each to the webhook", func(t *testing.T) {
+		var mu sync.Mutex
+		var messages []string
+		webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			var msg webhookMessage
+			if err := json.NewDecoder(req.Body).Decode(&msg); err != nil {
+				t.Errorf("Unexpected error decoding webhook message: %v", err)
+			}
+			mu.Lock()
+			messages = append(messages, msg.Text)
+			mu.Unlock()
+		}))
+		defer webhook.Close()
+
+		client := publictest.NewMockApiClient()
+		client.SetStatSummaryResponses(
+			statSummaryResponse("voting", 90, 10, 100),
+			statSummaryResponse("voting", 90, 10, 100),
+			statSummaryResponse("voting", 100, 0, 100),
+		)
+		notifier := NewNotifier(client, NotificationConfig{
+			MinSuccessRate: 95,
+			MaxLatencyP99:  time.Second,
+			WebhookURL:     webhook.URL,
+		})
+
+		start := time.Now()
+		if err := notifier.evaluate(context.Background(), start); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		alerts := notifier.Alerts()
+		if len(alerts) != 1 {
+			t.Fatalf("Expected 1 alert, got %d", len(alerts))
+		}
+		expectedMessage := "success rate 90.00% is below 95.00%"
+		if alerts[0].Name != "voting" || alerts[0].Message != expectedMessage {
+			t.Fatalf("Expected alert for [voting] with message [%s], got %+v", expectedMessage, alerts[0])
+		}
+
+		if err := notifier.evaluate(context.Background(), start.Add(time.Minute)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		alerts = notifier.Alerts()
+		if len(alerts) != 1 || !alerts[0].Since.Equal(start) {
+			t.Fatalf("Expected the still-firing alert to keep its start time, got %+v", alerts)
+		}
+
+		if err := notifier.evaluate(context.Background(), start.Add(2*time.Minute)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if alerts = notifier.Alerts(); len(alerts) != 0 {
+			t.Fatalf("Expected no alerts after recovery, got %+v", alerts)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(messages) != 2 {
+			t.Fatalf("Expected 2 webhook messages, got %d: %v", len(messages), messages)
+		}
+		if !strings.HasPrefix(messages[0], "[firing] emojivoto/deployment voting") {
+			t.Fatalf("Expected a firing message, got [%s]", messages[0])
+		}
+		if !strings.HasPrefix(messages[1], "[resolved] emojivoto/deployment voting") {
+			t.Fatalf("Expected a resolved message, got [%s]", messages[1])
+		}
+	})
+
+	t.Run("Alerts on P99 latency", func(t *testing.T) {
+		client := publictest.NewMockApiClient()
+		client.SetStatSummaryResponses(statSummaryResponse("web", 100, 0, 1500))
+		notifier := NewNotifier(client, NotificationConfig{MaxLatencyP99: time.Second})
+
+		if err := notifier.evaluate(context.Background(), time.Now()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		alerts := notifier.Alerts()
+		expectedMessage := "P99 latency 1.5s is above 1s"
+		if len(alerts) != 1 || alerts[0].Message != expectedMessage {
+			t.Fatalf("Expected one alert with message [%s], got %+v", expectedMessage, alerts)
+		}
+	})
+
+	t.Run("Ignores resources without traffic", func(t *testing.T) {
+		client := publictest.NewMockApiClient()
+		client.SetStatSummaryResponses(statSummaryResponse("web", 0, 0, 0))
+		notifier := NewNotifier(client, NotificationConfig{MinSuccessRate: 99})
+
+		if err := notifier.evaluate(context.Background(), time.Now()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if alerts := notifier.Alerts(); len(alerts) != 0 {
+			t.Fatalf("Expected no alerts, got %+v", alerts)
+		}
+	})
+}
+
+func TestHandleApiNotifications(t *testing.T) {
+	client := publictest.NewMockApiClient()
+	client.SetStatSummaryResponses(statSummaryResponse("voting", 50, 50, 0))
+	notifier := NewNotifier(client, NotificationConfig{MinSuccessRate: 95})
+	if err := notifier.evaluate(context.Background(), time.Now()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	handler := &handler{notifier: notifier}
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/notifications", nil)
+	handler.handleApiNotifications(recorder, req, httprouter.Params{})
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	var rsp struct {
+		Alerts []Alert `json:"alerts"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &rsp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rsp.Alerts) != 1 || rsp.Alerts[0].Name != "voting" {
+		t.Fatalf("Expected one alert for [voting], got %+v", rsp.Alerts)
+	}
+}
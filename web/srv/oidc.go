@@ -0,0 +1,484 @@
+package srv
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+)
+
+const (
+	authLoginPath    = "/auth/login"
+	authCallbackPath = "/auth/callback"
+	authLogoutPath   = "/auth/logout"
+
+	sessionCookieName = "linkerd-session"
+	stateCookieName   = "linkerd-oidc-state"
+	sessionDuration   = 12 * time.Hour
+	stateDuration     = 10 * time.Minute
+
+	// loggedInPage loads the dashboard after login. The login flow started on
+	// the provider's site, so redirecting to the dashboard would still be a
+	// cross-site navigation, which the SameSite=Strict session cookie isn't
+	// sent with.
+	loggedInPage = `<!DOCTYPE html>
+<html><head><meta http-equiv="refresh" content="0;url=/"></head>
+<body><a href="/">Continue to the dashboard</a></body></html>
+`
+)
+
+type (
+	// OIDCConfig configures dashboard login through an OpenID Connect
+	// provider.
+	OIDCConfig struct {
+		// IssuerURL is the provider's issuer, which serves its configuration
+		// at /.well-known/openid-configuration.
+		IssuerURL    string
+		ClientID     string
+		ClientSecret string
+		// RedirectURL is the dashboard's /auth/callback URL, as registered
+		// with the provider.
+		RedirectURL string
+		// Scopes are requested in addition to "openid".
+		Scopes []string
+		// GroupsClaim is the ID token claim that lists the user's groups.
+		GroupsClaim string
+		// ReadOnlyGroups may view the dashboard, TapGroups may also tap, and
+		// AdminGroups may also view the audit log. If ReadOnlyGroups is empty,
+		// any user who logs in may view the dashboard.
+		ReadOnlyGroups []string
+		TapGroups      []string
+		AdminGroups    []string
+	}
+
+	// Authenticator requires users to log in through an OpenID Connect
+	// provider, and restricts tap and the audit log to the configured groups.
+	Authenticator struct {
+		config     OIDCConfig
+		oauth2     oauth2.Config
+		issuer     string
+		jwksURL    string
+		httpClient *http.Client
+		sessionKey []byte
+
+		mu   sync.Mutex
+		keys map[string]*rsa.PublicKey
+	}
+
+	role int
+
+	userContextKey struct{}
+
+	sessionClaims struct {
+		Role role `json:"role"`
+		jwt.StandardClaims
+	}
+
+	providerConfig struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+
+	jsonWebKeySet struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+)
+
+const (
+	roleNone role = iota
+	roleReadOnly
+	roleTap
+	roleAdmin
+)
+
+// NewAuthenticator fetches the provider's configuration and returns an
+// Authenticator for it. Sessions are signed with a key generated at startup,
+// so users log in again when the web server restarts.
+func NewAuthenticator(config OIDCConfig) (*Authenticator, error) {
+	if config.ClientID == "" || config.RedirectURL == "" {
+		return nil, errors.New("OIDC login requires a client ID and redirect URL")
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = "groups"
+	}
+
+	a := &Authenticator{
+		config:     config,
+		httpClient: &http.Client{Timeout: timeout},
+		sessionKey: make([]byte, 32),
+		keys:       make(map[string]*rsa.PublicKey),
+	}
+	if _, err := rand.Read(a.sessionKey); err != nil {
+		return nil, err
+	}
+
+	discoveryURL := strings.TrimSuffix(config.IssuerURL, "/") + "/.well-known/openid-configuration"
+	var provider providerConfig
+	if err := a.getJSON(discoveryURL, &provider); err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC provider configuration: %s", err)
+	}
+	if provider.Issuer != config.IssuerURL {
+		return nil, fmt.Errorf("OIDC provider issuer [%s] doesn't match [%s]", provider.Issuer, config.IssuerURL)
+	}
+
+	a.issuer = provider.Issuer
+	a.jwksURL = provider.JWKSURI
+	a.oauth2 = oauth2.Config{
+		ClientID:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+		RedirectURL:  config.RedirectURL,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  provider.AuthorizationEndpoint,
+			TokenURL: provider.TokenEndpoint,
+		},
+		Scopes: append([]string{"openid"}, config.Scopes...),
+	}
+
+	return a, nil
+}
+
+// Wrap returns a handler that serves the login routes, and otherwise only
+// passes requests from logged in users on to next.
+func (a *Authenticator) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case authLoginPath:
+			a.handleLogin(w, req)
+			return
+		case authCallbackPath:
+			a.handleCallback(w, req)
+			return
+		case authLogoutPath:
+			a.handleLogout(w, req)
+			return
+		}
+
+		// Static assets don't contain any cluster data.
+		if strings.HasPrefix(req.URL.Path, "/dist/") {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		session, err := a.session(req)
+		if err != nil {
+			log.Debugf("rejecting request without a valid session: %s", err)
+			if strings.HasPrefix(req.URL.Path, "/api/") {
+				http.Error(w, "login required", http.StatusUnauthorized)
+			} else {
+				http.Redirect(w, req, authLoginPath, http.StatusFound)
+			}
+			return
+		}
+
+		if req.URL.Path == "/api/tap" && session.Role < roleTap {
+			http.Error(w, "tap is not permitted for your groups", http.StatusForbidden)
+			return
+		}
+		if req.URL.Path == "/api/audit" && session.Role < roleAdmin {
+			http.Error(w, "the audit log is not permitted for your groups", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), userContextKey{}, session.Subject)))
+	})
+}
+
+func (a *Authenticator) handleLogin(w http.ResponseWriter, req *http.Request) {
+	state, err := randomString()
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, a.cookie(stateCookieName, state, stateDuration))
+	http.Redirect(w, req, a.oauth2.AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", state)), http.StatusFound)
+}
+
+func (a *Authenticator) handleCallback(w http.ResponseWriter, req *http.Request) {
+	stateCookie, err := req.Cookie(stateCookieName)
+	if err != nil || stateCookie.Value == "" || req.URL.Query().Get("state") != stateCookie.Value {
+		http.Error(w, "invalid login state; try logging in again", http.StatusBadRequest)
+		return
+	}
+	http.SetCookie(w, a.cookie(stateCookieName, "", -1))
+
+	if e := req.URL.Query().Get("error"); e != "" {
+		http.Error(w, fmt.Sprintf("login failed: %s", e), http.StatusUnauthorized)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	defer cancel()
+	token, err := a.oauth2.Exchange(context.WithValue(ctx, oauth2.HTTPClient, a.httpClient), req.URL.Query().Get("code"))
+	if err != nil {
+		log.Errorf("failed to exchange OIDC authorization code: %s", err)
+		http.Error(w, "login failed", http.StatusUnauthorized)
+		return
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		http.Error(w, "login failed: no ID token in response", http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := a.verifyIDToken(rawIDToken, stateCookie.Value)
+	if err != nil {
+		log.Errorf("failed to verify OIDC ID token: %s", err)
+		http.Error(w, "login failed", http.StatusUnauthorized)
+		return
+	}
+
+	r := a.roleFor(groupsFromClaims(claims, a.config.GroupsClaim))
+	if r == roleNone {
+		http.Error(w, "your groups don't grant access to the dashboard", http.StatusForbidden)
+		return
+	}
+
+	session, err := a.newSession(claims, r)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	a.setSessionCookie(w, session, sessionDuration)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, loggedInPage)
+}
+
+func (a *Authenticator) handleLogout(w http.ResponseWriter, req *http.Request) {
+	a.setSessionCookie(w, "", -1)
+	http.Redirect(w, req, authLoginPath, http.StatusFound)
+}
+
+// verifyIDToken checks the ID token's signature against the provider's keys,
+// and its issuer, audience, expiry and nonce.
+func (a *Authenticator) verifyIDToken(rawIDToken, nonce string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method [%s]", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return a.publicKey(kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !claims.VerifyIssuer(a.issuer, true) {
+		return nil, fmt.Errorf("unexpected issuer [%v]", claims["iss"])
+	}
+	if !audienceContains(claims["aud"], a.config.ClientID) {
+		return nil, fmt.Errorf("unexpected audience [%v]", claims["aud"])
+	}
+	if claims["nonce"] != nonce {
+		return nil, errors.New("unexpected nonce")
+	}
+	return claims, nil
+}
+
+// publicKey returns the provider's signing key with the given ID, refreshing
+// the cached keys if it isn't known, e.g. after the provider rotates keys.
+func (a *Authenticator) publicKey(kid string) (*rsa.PublicKey, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if key, ok := a.keys[kid]; ok {
+		return key, nil
+	}
+
+	var jwks jsonWebKeySet
+	if err := a.getJSON(a.jwksURL, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC provider keys: %s", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	a.keys = keys
+
+	key, ok := a.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key [%s]", kid)
+	}
+	return key, nil
+}
+
+func (a *Authenticator) roleFor(groups []string) role {
+	if containsAny(a.config.AdminGroups, groups) {
+		return roleAdmin
+	}
+	if containsAny(a.config.TapGroups, groups) {
+		return roleTap
+	}
+	if len(a.config.ReadOnlyGroups) == 0 || containsAny(a.config.ReadOnlyGroups, groups) {
+		return roleReadOnly
+	}
+	return roleNone
+}
+
+func (a *Authenticator) newSession(claims jwt.MapClaims, r role) (string, error) {
+	subject, _ := claims["email"].(string)
+	if subject == "" {
+		subject, _ = claims["sub"].(string)
+	}
+	session := sessionClaims{
+		Role: r,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   subject,
+			ExpiresAt: time.Now().Add(sessionDuration).Unix(),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, session).SignedString(a.sessionKey)
+}
+
+func (a *Authenticator) session(req *http.Request) (*sessionClaims, error) {
+	cookie, err := req.Cookie(sessionCookieName)
+	if err != nil {
+		return nil, err
+	}
+
+	var session sessionClaims
+	_, err = jwt.ParseWithClaims(cookie.Value, &session, func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method [%s]", token.Header["alg"])
+		}
+		return a.sessionKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// UserFromRequest returns the email or subject of the user who made req, or
+// an empty string if login is disabled.
+func UserFromRequest(req *http.Request) string {
+	user, _ := req.Context().Value(userContextKey{}).(string)
+	return user
+}
+
+func (a *Authenticator) cookie(name, value string, maxAge time.Duration) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(maxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(a.config.RedirectURL, "https://"),
+	}
+}
+
+// setSessionCookie sets the session cookie with SameSite=Strict, so that it
+// isn't sent with requests that other sites make to the dashboard.
+// http.Cookie has no SameSite field before Go 1.11, so the attribute is
+// appended to the header.
+func (a *Authenticator) setSessionCookie(w http.ResponseWriter, value string, maxAge time.Duration) {
+	w.Header().Add("Set-Cookie", a.cookie(sessionCookieName, value, maxAge).String()+"; SameSite=Strict")
+}
+
+func (a *Authenticator) getJSON(url string, v interface{}) error {
+	rsp, err := a.httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response from %s: %s", url, rsp.Status)
+	}
+	return json.NewDecoder(rsp.Body).Decode(v)
+}
+
+// SplitList splits a comma-separated flag value, such as a list of groups,
+// dropping empty entries.
+func SplitList(list string) []string {
+	parsed := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			parsed = append(parsed, item)
+		}
+	}
+	return parsed
+}
+
+func groupsFromClaims(claims jwt.MapClaims, groupsClaim string) []string {
+	groups := []string{}
+	switch value := claims[groupsClaim].(type) {
+	case string:
+		groups = append(groups, value)
+	case []interface{}:
+		for _, group := range value {
+			if g, ok := group.(string); ok {
+				groups = append(groups, g)
+			}
+		}
+	}
+	return groups
+}
+
+func audienceContains(aud interface{}, clientID string) bool {
+	switch value := aud.(type) {
+	case string:
+		return value == clientID
+	case []interface{}:
+		for _, a := range value {
+			if a == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsAny(list, values []string) bool {
+	for _, l := range list {
+		for _, v := range values {
+			if l == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,287 @@
+package srv
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+type fakeProvider struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	claims jwt.MapClaims
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Unexpected error generating key: %v", err)
+	}
+
+	p := &fakeProvider{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode(providerConfig{
+			Issuer:                p.URL,
+			AuthorizationEndpoint: p.URL + "/authorize",
+			TokenEndpoint:         p.URL + "/token",
+			JWKSURI:               p.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, `{"keys": [{"kid": "test", "kty": "RSA", "n": "%s", "e": "%s"}]}`,
+			base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()))
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     p.idToken(t, p.claims),
+		})
+	})
+	p.Server = httptest.NewServer(mux)
+	return p
+}
+
+func (p *fakeProvider) idToken(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test"
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		t.Fatalf("Unexpected error signing ID token: %v", err)
+	}
+	return signed
+}
+
+func newTestAuthenticator(t *testing.T, p *fakeProvider) *Authenticator {
+	a, err := NewAuthenticator(OIDCConfig{
+		IssuerURL:      p.URL,
+		ClientID:       "linkerd-web",
+		ClientSecret:   "secret",
+		RedirectURL:    "http://dashboard.example.com/auth/callback",
+		ReadOnlyGroups: []string{"viewers"},
+		TapGroups:      []string{"tappers"},
+		AdminGroups:    []string{"admins"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return a
+}
+
+// login runs the login flow against the fake provider, and returns the
+// response to the callback.
+func login(t *testing.T, p *fakeProvider, handler http.Handler, groups ...string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", authLoginPath, nil))
+	if recorder.Code != http.StatusFound {
+		t.Fatalf("Expected login to redirect, got %d", recorder.Code)
+	}
+	location, err := url.Parse(recorder.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing redirect: %v", err)
+	}
+	state := location.Query().Get("state")
+	if location.Query().Get("nonce") != state {
+		t.Fatalf("Expected the nonce to match the state [%s], got [%s]", state, location.Query().Get("nonce"))
+	}
+
+	p.claims = jwt.MapClaims{
+		"iss":    p.URL,
+		"aud":    []string{"linkerd-web"},
+		"sub":    "1234",
+		"email":  "user@example.com",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"nonce":  state,
+		"groups": groups,
+	}
+
+	req := httptest.NewRequest("GET", authCallbackPath+"?code=abc&state="+state, nil)
+	for _, cookie := range recorder.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func sessionRequest(path string, rsp *httptest.ResponseRecorder) *http.Request {
+	req := httptest.NewRequest("GET", path, nil)
+	for _, cookie := range rsp.Result().Cookies() {
+		if cookie.Name == sessionCookieName {
+			req.AddCookie(cookie)
+		}
+	}
+	return req
+}
+
+func TestAuthenticator(t *testing.T) {
+	p := newFakeProvider(t)
+	defer p.Close()
+
+	handler := newTestAuthenticator(t, p).Wrap(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-User", UserFromRequest(req))
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("Redirects pages and rejects API requests without a session", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/deployments", nil))
+		if recorder.Code != http.StatusFound || recorder.Header().Get("Location") != authLoginPath {
+			t.Fatalf("Expected a redirect to %s, got %d %s", authLoginPath, recorder.Code, recorder.Header().Get("Location"))
+		}
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/api/version", nil))
+		if recorder.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected status %d, got %d", http.StatusUnauthorized, recorder.Code)
+		}
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/dist/index_bundle.js", nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected static assets to be served, got %d", recorder.Code)
+		}
+	})
+
+	t.Run("Allows read-only users to view the dashboard but not tap", func(t *testing.T) {
+		rsp := login(t, p, handler, "viewers")
+		if rsp.Code != http.StatusOK || !strings.Contains(rsp.Body.String(), `url=/`) {
+			t.Fatalf("Expected a page that loads the dashboard after login, got %d: %s", rsp.Code, rsp.Body.String())
+		}
+		sameSite := false
+		for _, header := range rsp.Header()["Set-Cookie"] {
+			if strings.HasPrefix(header, sessionCookieName+"=") {
+				sameSite = strings.HasSuffix(header, "; SameSite=Strict")
+			}
+		}
+		if !sameSite {
+			t.Fatalf("Expected a SameSite=Strict session cookie, got %v", rsp.Header()["Set-Cookie"])
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, sessionRequest("/api/version", rsp))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, recorder.Code)
+		}
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, sessionRequest("/api/tap", rsp))
+		if recorder.Code != http.StatusForbidden {
+			t.Fatalf("Expected status %d, got %d", http.StatusForbidden, recorder.Code)
+		}
+	})
+
+	t.Run("Allows tap users to tap", func(t *testing.T) {
+		rsp := login(t, p, handler, "tappers")
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, sessionRequest("/api/tap", rsp))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, recorder.Code)
+		}
+		if user := recorder.Header().Get("X-User"); user != "user@example.com" {
+			t.Fatalf("Expected the request to be made as [user@example.com], got [%s]", user)
+		}
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, sessionRequest("/api/audit", rsp))
+		if recorder.Code != http.StatusForbidden {
+			t.Fatalf("Expected status %d, got %d", http.StatusForbidden, recorder.Code)
+		}
+	})
+
+	t.Run("Allows admin users to tap and view the audit log", func(t *testing.T) {
+		rsp := login(t, p, handler, "admins")
+
+		for _, path := range []string{"/api/tap", "/api/audit"} {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, sessionRequest(path, rsp))
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("Expected status %d for %s, got %d", http.StatusOK, path, recorder.Code)
+			}
+		}
+	})
+
+	t.Run("Rejects users outside the configured groups", func(t *testing.T) {
+		rsp := login(t, p, handler, "others")
+		if rsp.Code != http.StatusForbidden {
+			t.Fatalf("Expected status %d, got %d", http.StatusForbidden, rsp.Code)
+		}
+	})
+
+	t.Run("Rejects callbacks with a mismatched state", func(t *testing.T) {
+		req := httptest.NewRequest("GET", authCallbackPath+"?code=abc&state=forged", nil)
+		req.AddCookie(&http.Cookie{Name: stateCookieName, Value: "expected"})
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, recorder.Code)
+		}
+	})
+
+	t.Run("Rejects forged session cookies", func(t *testing.T) {
+		forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, sessionClaims{Role: roleTap}).SignedString([]byte("guessed"))
+		req := httptest.NewRequest("GET", "/api/tap", nil)
+		req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: forged})
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected status %d, got %d", http.StatusUnauthorized, recorder.Code)
+		}
+	})
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	p := newFakeProvider(t)
+	defer p.Close()
+	a := newTestAuthenticator(t, p)
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   p.URL,
+			"aud":   "linkerd-web",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"nonce": "nonce",
+		}
+	}
+
+	testCases := []struct {
+		desc   string
+		modify func(jwt.MapClaims)
+		valid  bool
+	}{
+		{"Accepts a valid token", func(jwt.MapClaims) {}, true},
+		{"Rejects another issuer", func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }, false},
+		{"Rejects another audience", func(c jwt.MapClaims) { c["aud"] = "other" }, false},
+		{"Rejects an expired token", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }, false},
+		{"Rejects another nonce", func(c jwt.MapClaims) { c["nonce"] = "replayed" }, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			claims := valid()
+			tc.modify(claims)
+			_, err := a.verifyIDToken(p.idToken(t, claims), "nonce")
+			if tc.valid && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatalf("Expected an error, got nil")
+			}
+		})
+	}
+}
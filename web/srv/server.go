@@ -51,7 +51,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	s.router.ServeHTTP(w, req)
 }
 
-func NewServer(addr, templateDir, staticDir, uuid, controllerNamespace, webpackDevServer string, reload bool, apiClient pb.ApiClient) *http.Server {
+func NewServer(addr, templateDir, staticDir, uuid, controllerNamespace, webpackDevServer string, reload bool, apiClient pb.ApiClient, notifier *Notifier, auditLog *AuditLog, authenticator *Authenticator) *http.Server {
 	server := &Server{
 		templateDir:     templateDir,
 		staticDir:       staticDir,
@@ -65,13 +65,19 @@ func NewServer(addr, templateDir, staticDir, uuid, controllerNamespace, webpackD
 		HandleMethodNotAllowed: false, // disable 405s
 	}
 
-	wrappedServer := prometheus.WithTelemetry(server)
+	var wrappedServer http.Handler = server
+	if authenticator != nil {
+		wrappedServer = authenticator.Wrap(wrappedServer)
+	}
+	wrappedServer = prometheus.WithTelemetry(wrappedServer)
 	handler := &handler{
 		apiClient:           apiClient,
 		render:              server.RenderTemplate,
 		serveFile:           server.serveFile,
 		uuid:                uuid,
 		controllerNamespace: controllerNamespace,
+		notifier:            notifier,
+		auditLog:            auditLog,
 	}
 
 	httpServer := &http.Server{
@@ -91,6 +97,7 @@ func NewServer(addr, templateDir, staticDir, uuid, controllerNamespace, webpackD
 	server.router.GET("/pods", handler.handleIndex)
 	server.router.GET("/authorities", handler.handleIndex)
 	server.router.GET("/tap", handler.handleIndex)
+	server.router.GET("/health", handler.handleIndex)
 	server.router.ServeFiles(
 		"/dist/*filepath", // add catch-all parameter to match all files in dir
 		filesonly.FileSystem(server.staticDir))
@@ -100,6 +107,9 @@ func NewServer(addr, templateDir, staticDir, uuid, controllerNamespace, webpackD
 	server.router.GET("/api/tps-reports", handler.handleApiStat)
 	server.router.GET("/api/pods", handler.handleApiPods)
 	server.router.GET("/api/tap", handler.handleApiTap)
+	server.router.GET("/api/check", handler.handleApiCheck)
+	server.router.GET("/api/notifications", handler.handleApiNotifications)
+	server.router.GET("/api/audit", handler.handleApiAudit)
 
 	return httpServer
 }